@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,11 +16,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/anonymize"
 	"github.com/renatogalera/ai-commit/pkg/changelog"
 	"github.com/renatogalera/ai-commit/pkg/committypes"
 	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
 	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/gitlab"
+	"github.com/renatogalera/ai-commit/pkg/guard"
+	"github.com/renatogalera/ai-commit/pkg/health"
 	"github.com/renatogalera/ai-commit/pkg/hook"
+	"github.com/renatogalera/ai-commit/pkg/notify"
+	"github.com/renatogalera/ai-commit/pkg/plugin"
 	"github.com/renatogalera/ai-commit/pkg/prompt"
     _ "github.com/renatogalera/ai-commit/pkg/provider/anthropic"
     _ "github.com/renatogalera/ai-commit/pkg/provider/deepseek"
@@ -26,10 +36,13 @@ import (
     _ "github.com/renatogalera/ai-commit/pkg/provider/openai"
     _ "github.com/renatogalera/ai-commit/pkg/provider/openrouter"
 	"github.com/renatogalera/ai-commit/pkg/provider/registry"
+	"github.com/renatogalera/ai-commit/pkg/report"
+	"github.com/renatogalera/ai-commit/pkg/review"
 	"github.com/renatogalera/ai-commit/pkg/summarizer"
 	"github.com/renatogalera/ai-commit/pkg/template"
 	"github.com/renatogalera/ai-commit/pkg/ui"
 	"github.com/renatogalera/ai-commit/pkg/ui/splitter"
+	"github.com/renatogalera/ai-commit/pkg/usage"
 	"github.com/renatogalera/ai-commit/pkg/versioner"
 )
 
@@ -46,14 +59,72 @@ var (
     templateFlag         string
     languageFlag         string
 	forceFlag            bool
+	editFlag             bool
+	allFlag              bool
 	semanticReleaseFlag  bool
 	interactiveSplitFlag bool
+	splitByFlag          string
+	autoSplitFlag        bool
 	emojiFlag            bool
 	manualSemverFlag     bool
+	prereleaseFlag       string
+	buildMetadataFlag    string
+	forceTagFlag         bool
+	dryRunFlag           bool
+	releaseNotesFlag     bool
+	githubReleaseFlag    bool
+	pushTagFlag          bool
+	bumpStrategyFlag     string
 	providerFlag         string
 	modelFlag            string
 	reviewMessageFlag    bool
+	fixMessageFlag       bool
 	msgOnlyFlag          bool
+	printFlag            bool
+	allowEmptyFlag       bool
+	amendFlag            bool
+	intentFlag           string
+	contextFileFlag      string
+	todosFlag            bool
+	shareFlag            bool
+	noCommentFilterFlag  bool
+	showFilteredFlag     bool
+	consensusFlag        string
+	judgeFlag            string
+	checkDuplicatesFlag  bool
+	reviewPresetFlag     string
+	reviewFormatFlag     string
+	reviewOutputFlag     string
+	reviewersFlag        bool
+	explainReviewersFlag bool
+	postToGitLabFlag     bool
+	noArchCheckFlag      bool
+	overrideBudgetFlag   bool
+	signoffFlag          bool
+	signFlag             bool
+	noVerifyFlag         bool
+	footerFlag           []string
+	authorFlag           string
+	dateFlag             string
+	provenanceFlag       bool
+	deterministicFlag    bool
+	seedFlag             int64
+	temperatureFlag      float64
+	tutorialFlag         bool
+	notifyFlag           bool
+	maxWaitFlag          time.Duration
+	fallbackFlag         string
+	candidatesFlag       int
+	relatedCommitsFlag   int
+	quietFlag            bool
+	outputFormatFlag     string
+	jsonOutputFlag       bool
+	noDraftFlag          bool
+	verboseFlag          bool
+	diffFlag             string
+	diffFileFlag         string
+	againstFlag          string
+	betweenFlag          []string
 )
 
 var rootCmd = &cobra.Command{
@@ -75,24 +146,99 @@ var reviewCmd = &cobra.Command{
 
 func init() {
     rootCmd.PersistentFlags().StringVar(&languageFlag, "language", "english", "Language for commit message/review")
+	rootCmd.PersistentFlags().BoolVar(&overrideBudgetFlag, "override-budget", false, "Use the configured provider even when a budget limit is exceeded")
+	rootCmd.PersistentFlags().BoolVar(&signoffFlag, "signoff", false, "Add a Signed-off-by trailer for the author identity (DCO)")
+	rootCmd.PersistentFlags().BoolVarP(&signFlag, "sign", "S", false, "Sign the commit with the GPG or SSH key of the git config (user.signingKey, gpg.format)")
+	rootCmd.PersistentFlags().BoolVarP(&noVerifyFlag, "no-verify", "n", false, "Skip the repository's pre-commit and commit-msg hooks")
+	rootCmd.PersistentFlags().StringArrayVar(&footerFlag, "footer", nil, "Add or replace a trailer (key=value, repeatable), e.g. --footer Refs=PROJ-1 --footer Co-authored-by=\"Pat <pat@example.com>\"")
+	rootCmd.PersistentFlags().StringVar(&authorFlag, "author", "", "Override the commit author (\"Name <email>\")")
+	rootCmd.PersistentFlags().StringVar(&dateFlag, "date", "", "Override the author date (RFC 3339, RFC 2822, or \"<unix seconds> <+hhmm>\")")
+	rootCmd.PersistentFlags().BoolVar(&provenanceFlag, "provenance", false, "Add an X-AI-Commit trailer recording the provider, model, prompt template hash, and version")
+	rootCmd.PersistentFlags().BoolVar(&deterministicFlag, "deterministic", false, "Send every request with temperature 0 and a fixed seed, and attach a reproducibility report (provider, model, parameters, prompt hash) to the commit as a git note under refs/notes/ai-commit")
+	rootCmd.PersistentFlags().Int64Var(&seedFlag, "seed", 0, "Sampling seed sent with every request, for providers that support one (OpenAI-compatible, Gemini, Ollama)")
+	rootCmd.PersistentFlags().Float64Var(&temperatureFlag, "temperature", 0, "Sampling temperature (0-2) sent with every request; 0 gives the most repeatable output")
+	rootCmd.PersistentFlags().BoolVar(&shareFlag, "share", false, "After committing, print a short snippet of the commit (hash, subject, bullets, link) for chat and copy it to the clipboard")
+	rootCmd.PersistentFlags().BoolVar(&noCommentFilterFlag, "no-comment-filter", false, "Keep comment-only changes in the diff sent to the AI")
     rootCmd.Flags().StringVar(&apiKeyFlag, "apiKey", "", "API key for the selected provider (or env ${PROVIDER}_API_KEY)")
     rootCmd.Flags().StringVar(&baseURLFlag, "baseURL", "", "Base URL for the selected provider (or env ${PROVIDER}_BASE_URL)")
     rootCmd.Flags().StringVar(&commitTypeFlag, "commit-type", "", "Commit type (e.g., feat, fix)")
     rootCmd.Flags().StringVar(&templateFlag, "template", "", "Commit message template")
     rootCmd.Flags().BoolVar(&forceFlag, "force", false, "Bypass interactive UI and commit directly")
+	rootCmd.Flags().BoolVarP(&allFlag, "all", "a", false, "Stage every modified and deleted tracked file first, like git commit -a (asks for confirmation unless --force)")
+	rootCmd.Flags().BoolVar(&editFlag, "edit", false, "Open the generated message in your editor (GIT_EDITOR, core.editor, VISUAL, or EDITOR), as git commit does, instead of the interactive UI, and commit it")
     rootCmd.Flags().BoolVar(&semanticReleaseFlag, "semantic-release", false, "Perform semantic release")
     rootCmd.Flags().BoolVar(&interactiveSplitFlag, "interactive-split", false, "Launch interactive commit splitting")
+	rootCmd.Flags().BoolVar(&autoSplitFlag, "auto-split", false, "Let the AI group the staged hunks into logical commits with draft messages, then review, regroup, and edit the plan in the splitter before committing")
+	rootCmd.Flags().StringVar(&splitByFlag, "split-by", "", "With --interactive-split or --auto-split, select hunks (hunk, default) or whole files (file); 'f' switches in the splitter")
     rootCmd.Flags().BoolVar(&emojiFlag, "emoji", false, "Include emoji in commit message")
     rootCmd.Flags().BoolVar(&manualSemverFlag, "manual-semver", false, "Manually select semantic version bump")
+	rootCmd.Flags().StringVar(&prereleaseFlag, "prerelease", "", "With --semantic-release, tag a pre-release on this channel (e.g. rc -> v1.4.0-rc.1)")
+	rootCmd.Flags().StringVar(&buildMetadataFlag, "build-metadata", "", "With --semantic-release, append build metadata to the tag (e.g. v1.4.0+ci.42; {COMMIT} = short HEAD hash)")
+	rootCmd.Flags().BoolVar(&forceTagFlag, "force-tag", false, "With --semantic-release, tag even with uncommitted changes or a stale branch, and move an existing tag")
+	rootCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Print the generated message without committing, like --print; with --semantic-release, print the release plan for HEAD (commits, bump, reasoning, tag, changelog) without committing or tagging")
+	rootCmd.Flags().BoolVar(&releaseNotesFlag, "release-notes", false, "With --semantic-release, annotate the tag with AI release notes of the commits since the previous tag")
+	rootCmd.Flags().StringVar(&bumpStrategyFlag, "bump-strategy", "", "With --semantic-release, how to pick the bump: ai, conventional (from commit types, no AI), or hybrid (AI only for untyped commits)")
+	rootCmd.Flags().BoolVar(&pushTagFlag, "push-tag", false, "With --semantic-release, push the new tag to release.remote (default origin) via the SSH agent or $AI_COMMIT_GIT_TOKEN")
+	rootCmd.Flags().BoolVar(&githubReleaseFlag, "github-release", false, "With --semantic-release, also publish the release notes as a GitHub release ($GITHUB_TOKEN; implies --release-notes and --push-tag)")
     rootCmd.Flags().StringVar(&providerFlag, "provider", "", "AI provider: openai, google, anthropic, deepseek, ollama, openrouter")
     rootCmd.Flags().StringVar(&modelFlag, "model", "", "Sub-model for the chosen provider")
     rootCmd.Flags().BoolVar(&reviewMessageFlag, "review-message", false, "Review and enforce commit message style using AI")
+	rootCmd.Flags().BoolVar(&fixMessageFlag, "fix-message", false, "Regenerate the message with the style review suggestions until the review passes (implies --review-message)")
     rootCmd.Flags().BoolVar(&msgOnlyFlag, "msg-only", false, "Generate commit message and print to stdout (for hook usage)")
+	rootCmd.Flags().BoolVar(&printFlag, "print", false, "Print the generated message to stdout without the TUI or a commit, e.g. for \"git commit -F -\" (like --msg-only)")
+	rootCmd.Flags().BoolVar(&allowEmptyFlag, "allow-empty", false, "Allow a commit without staged changes (requires --intent)")
+	rootCmd.Flags().BoolVar(&amendFlag, "amend", false, "Refine the HEAD commit's message for its changes plus the staged ones, and amend the commit")
+	rootCmd.Flags().BoolVar(&showFilteredFlag, "show-filtered", false, "Print which files and lines were filtered out of the prompt")
+	rootCmd.Flags().StringVar(&consensusFlag, "consensus", "", "Query several providers in parallel and compare their messages (e.g. openai,anthropic:claude-3-5-haiku)")
+	rootCmd.Flags().StringVar(&judgeFlag, "judge", "", "With --consensus, provider[:model] that merges the candidates into one message")
+	rootCmd.Flags().BoolVar(&checkDuplicatesFlag, "check-duplicates", false, "Warn when the staged changes closely resemble a recent commit (uses embeddings)")
+	rootCmd.Flags().BoolVar(&noArchCheckFlag, "no-arch-check", false, "Skip the architecture rule check configured under architecture.rules")
+	rootCmd.Flags().BoolVar(&notifyFlag, "notify", false, "Send a desktop notification and ring the terminal bell when the message is ready or a forced commit completes")
+	rootCmd.Flags().DurationVar(&maxWaitFlag, "max-wait", 0, "Once the provider has run this long (e.g. 10s), offer the partial message or switch to the fallback provider")
+	rootCmd.Flags().StringVar(&fallbackFlag, "fallback", "", "provider[:model] used when --max-wait passes without any output (default: budget.fallbackProvider)")
+	rootCmd.Flags().IntVar(&relatedCommitsFlag, "related-commits", 0, "Add the subjects of the last N commits touching the same files to the prompt, for consistent messages")
+	rootCmd.Flags().IntVar(&candidatesFlag, "candidates", 0, "Ask for this many alternative messages in one request and switch between them in the TUI with ←/→ (at most 10)")
+	rootCmd.Flags().BoolVar(&tutorialFlag, "tutorial", false, "Learn the interactive UI keys on a sample diff with a mock provider (no API key or repository needed)")
+	rootCmd.Flags().StringVar(&contextFileFlag, "context-file", "", "Add the contents of a file (e.g. design notes) to the prompt as additional context, after .ai-commit-context.md")
+	rootCmd.Flags().BoolVar(&todosFlag, "todos", false, "List the TODO, FIXME, HACK, and XXX markers the commit adds (see todos in config.yaml to append them to the body or a tracking file)")
+	rootCmd.Flags().StringVar(&intentFlag, "intent", "", "Describe why you are committing (e.g. \"implement retry backoff for the phind client\"); it leads the prompt so the message explains it")
+	rootCmd.Flags().BoolVar(&quietFlag, "quiet", false, "With --force, --msg-only, or --dry-run, print only the commit hash, message, or tags (errors still go to stderr)")
+	rootCmd.Flags().BoolVar(&noDraftFlag, "no-draft", false, "Generate a new message even when a MERGE_MSG or a draft saved from a failed commit exists")
+	rootCmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Print provider, model, token usage, and timings to stderr")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.Flags().StringVar(&outputFormatFlag, "output", "text", "Output format: text, or json to print the message, type, provider, model, token usage, and elapsed time as one JSON document (implies --print unless --force, --edit, or --msg-only)")
+	rootCmd.Flags().StringVar(&diffFlag, "diff", "", "Generate a message for a unified diff read from stdin (\"-\") or a file instead of the staged changes; prints it without touching the repository")
+	rootCmd.Flags().StringVar(&diffFileFlag, "diff-file", "", "Like --diff, reading the diff from this file")
+	rootCmd.Flags().StringVar(&againstFlag, "against", "", "Generate a message for the changes from this ref to the working tree (staged or not) and print it")
+	rootCmd.Flags().StringSliceVar(&betweenFlag, "between", nil, "Generate a message for the changes between two refs (A,B or A..B) and print it")
+	rootCmd.MarkFlagsMutuallyExclusive("diff", "diff-file", "against", "between")
 
 	rootCmd.AddCommand(newSummarizeCmd(setupAIEnvironment))
 	rootCmd.AddCommand(newChangelogCmd(setupAIEnvironment))
+	reviewCmd.Flags().StringVar(&reviewPresetFlag, "preset", "", "Focus the review: \"performance\" checks complexity, hot-path allocations, N+1 queries, and locking")
+	reviewCmd.Flags().StringVar(&againstFlag, "against", "", "Review the changes from this ref to the working tree instead of the staged ones")
+	reviewCmd.Flags().StringSliceVar(&betweenFlag, "between", nil, "Review the changes between two refs (A,B or A..B) instead of the staged ones")
+	reviewCmd.MarkFlagsMutuallyExclusive("against", "between")
+	reviewCmd.Flags().StringVar(&reviewFormatFlag, "format", "", "Print the review as markdown, json, sarif (for code scanning), or html instead of styled text")
+	reviewCmd.Flags().StringVar(&reviewOutputFlag, "output", "text", "Output format: text, or json (same as --format json) for the findings with provider, model, token usage, and elapsed time")
+	reviewCmd.MarkFlagsMutuallyExclusive("format", "output")
+	reviewCmd.Flags().BoolVar(&reviewersFlag, "reviewers", false, "Suggest reviewers for the changed files from CODEOWNERS and git blame")
+	reviewCmd.Flags().BoolVar(&explainReviewersFlag, "explain-reviewers", false, "Ask the AI why each suggested reviewer fits the change (implies --reviewers)")
+	reviewCmd.Flags().BoolVar(&postToGitLabFlag, "post-to-gitlab", false, "In a GitLab merge request pipeline, review the merge request and post findings as discussion notes")
 	rootCmd.AddCommand(reviewCmd)
 	rootCmd.AddCommand(newHookCmd())
+	rootCmd.AddCommand(newRevertCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newFixupCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newSessionCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newEvalCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newLintCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newLintHistoryCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newAnnotateCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newRewriteCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newEstimateCmd())
+	rootCmd.AddCommand(newCIReviewCmd(setupAIEnvironment))
+	rootCmd.AddCommand(newVerifyServerCmd())
+	rootCmd.AddCommand(newVerifyPushCmd())
 }
 
 func main() {
@@ -108,7 +254,7 @@ func main() {
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
 }
 
@@ -117,10 +263,12 @@ func setupLogger() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 }
 
-func setupAIEnvironment() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error) {
+// loadConfig loads the merged configuration, validates it, and registers its
+// commit types.
+func loadConfig() (*config.Config, error) {
 	cfg, err := config.LoadOrCreateConfig()
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 	cm := config.NewConfigManager(cfg)
 	mergedCfg := cm.MergeConfiguration()
@@ -129,40 +277,203 @@ func setupAIEnvironment() (context.Context, context.CancelFunc, *config.Config,
 		mergedCfg.Provider = config.DefaultProvider
 	}
     if !registry.Has(mergedCfg.Provider) {
-        return nil, nil, nil, nil, fmt.Errorf("invalid provider: %s", mergedCfg.Provider)
+        return nil, fmt.Errorf("invalid provider: %s", mergedCfg.Provider)
     }
 	if err := mergedCfg.Validate(); err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("config validation failed: %w", err)
+		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
+	committypes.InitCommitTypes(mergedCfg.CommitTypes)
+	git.ConfigurePrivacy(mergedCfg.Privacy)
+	return mergedCfg, nil
+}
 
+func setupAIEnvironment() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error) {
+	mergedCfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	ctx, cancel, aiClient, err := startAIEnvironment(mergedCfg)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return ctx, cancel, mergedCfg, aiClient, nil
+}
+
+// startAIEnvironment applies the loaded mergedCfg and starts the generation
+// deadline of the returned context, so runs that must ask the user something
+// first load the config, ask, and only then call it.
+func startAIEnvironment(mergedCfg *config.Config) (context.Context, context.CancelFunc, ai.AIClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	committypes.InitCommitTypes(mergedCfg.CommitTypes)
+
+	commentFilter := mergedCfg.CommentFilter
+	if noCommentFilterFlag {
+		commentFilter.Disabled = true
+	}
+	git.ConfigureCommentFilter(commentFilter)
+	prompt.ConfigureLanguageHints(mergedCfg.LanguageHints)
+	if err := ui.ConfigureKeys(mergedCfg.Keys); err != nil {
+		cancel()
+		return nil, nil, nil, fmt.Errorf("invalid keys config: %w", err)
+	}
 
 	aiClient, err := initAIClient(ctx, mergedCfg)
 	if err != nil {
 		cancel()
-		return nil, nil, nil, nil, fmt.Errorf("failed to initialize AI client: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to initialize AI client: %w", err)
 	}
 
-	if !git.IsGitRepository(ctx) {
+	if diffInputPath() == "" && !git.IsGitRepository(ctx) {
 		cancel()
-		return nil, nil, nil, nil, fmt.Errorf("not a valid Git repository")
+		return nil, nil, nil, git.ErrNotARepo
 	}
 
 	config.DefaultAuthorName = mergedCfg.AuthorName
 	config.DefaultAuthorEmail = mergedCfg.AuthorEmail
 
-	return ctx, cancel, mergedCfg, aiClient, nil
+	return ctx, cancel, aiClient, nil
 }
 
 func isValidProvider(provider string) bool { return registry.Has(provider) }
 
+// newNotifier returns the notifier configured under notify, with --notify
+// enabling both desktop notifications and the bell.
+func newNotifier(cfg *config.Config) *notify.Notifier {
+	settings := cfg.Notify
+	if notifyFlag {
+		settings.Desktop, settings.Bell = true, true
+	}
+	return notify.New(settings)
+}
+
+// newWebhook returns the webhook configured under notify.url, naming the
+// repository by its root directory.
+func newWebhook(cfg *config.Config) *notify.Webhook {
+	if cfg.Notify.URL == "" {
+		return nil
+	}
+	var repo string
+	if root, err := git.RepoRoot(); err == nil {
+		repo = filepath.Base(root)
+	}
+	branch, _ := git.GetCurrentBranch(context.Background())
+	return notify.NewWebhook(cfg.Notify, repo, branch)
+}
+
+// commitOptions returns the options shared by every command that commits:
+// --signoff (or the signoff config default), --no-verify, --footer, --author,
+// --date, the footers of the repository's commit.template, and the pre-commit
+// plugins.
+func commitOptions(cfg *config.Config) (git.CommitOptions, error) {
+	opts := git.CommitOptions{Signoff: signoffFlag || cfg.Signoff, ChangeID: cfg.Gerrit, Sign: signFlag || cfg.Sign, NoVerify: noVerifyFlag}
+	opts.Verify = verifyWithPlugins(pluginRunner(cfg))
+	opts.Trailers = append(opts.Trailers, commitTemplate(cfg).Footers...)
+	footers, err := parseFooterFlags()
+	if err != nil {
+		return opts, err
+	}
+	opts.Footers = footers
+	if strings.TrimSpace(authorFlag) != "" {
+		name, email, err := git.ParseAuthor(authorFlag)
+		if err != nil {
+			return opts, err
+		}
+		opts.AuthorName, opts.AuthorEmail = name, email
+	}
+	if strings.TrimSpace(dateFlag) != "" {
+		date, err := git.ParseDate(dateFlag)
+		if err != nil {
+			return opts, err
+		}
+		opts.AuthorDate = date
+	}
+	return opts, nil
+}
+
+// ruleCommitType applies the typeRules to diff when no commit type was given. In
+// override mode the rule's type becomes the commit type; otherwise it is
+// returned as categoryType, a hint for the prompt.
+func ruleCommitType(cfg *config.Config, diff, commitType string) (string, string) {
+	if commitType != "" || cfg.TypeRules.Disabled {
+		return commitType, ""
+	}
+	t, ok := git.CategoryCommitType(diff, cfg.TypeRules.Rules)
+	switch {
+	case !ok:
+		return commitType, ""
+	case cfg.TypeRules.Mode == "override":
+		return t, ""
+	default:
+		return commitType, t
+	}
+}
+
+// commitPrompt builds the prompt for a commit of diff, with the context files,
+// the intent, and the type rule and commit template hints.
+func commitPrompt(cfg *config.Config, diff, commitType, categoryType, scopeHint string) string {
+	promptText := prompt.WithIntent(prompt.BuildCommitPrompt(diff, languageFlag, commitType, additionalContext(), cfg.PromptTemplate, scopeHint), intentFlag)
+	if categoryType != "" {
+		promptText += prompt.TypeRuleHint(categoryType)
+	}
+	if skeleton := commitTemplate(cfg).Skeleton; skeleton != "" {
+		promptText += prompt.CommitTemplateHint(skeleton)
+	}
+	return promptText
+}
+
+// loadDraft returns the draft the interactive UI starts from: a MERGE_MSG, the
+// message autosaved for rawDiff, or a message saved when a commit failed.
+// Forced, hook, consensus, amended, and empty commits always generate a new
+// message, as does --no-draft.
+func loadDraft(ctx context.Context, emptyCommit bool, rawDiff string) (git.Draft, bool) {
+	if noDraftFlag || forceFlag || msgOnlyFlag || amendFlag || emptyCommit || strings.TrimSpace(consensusFlag) != "" {
+		return git.Draft{}, false
+	}
+	var diffHash string
+	if rawDiff != "" {
+		diffHash = git.DiffHash(rawDiff)
+	}
+	draft, ok, err := git.LoadDraft(ctx, diffHash)
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring the commit message draft")
+		return git.Draft{}, false
+	}
+	return draft, ok
+}
+
+// parseFooterFlags parses the --footer values.
+func parseFooterFlags() ([]git.Footer, error) {
+	footers := make([]git.Footer, 0, len(footerFlag))
+	for _, value := range footerFlag {
+		footer, err := git.ParseFooter(value)
+		if err != nil {
+			return nil, fmt.Errorf("--footer: %w", err)
+		}
+		footers = append(footers, footer)
+	}
+	return footers, nil
+}
+
 func initAIClient(ctx context.Context, cfg *config.Config) (ai.AIClient, error) {
 	provider := cfg.Provider
 	if providerFlag != "" {
 		provider = providerFlag
 	}
+	if fallback, ok := circuitFallback(cfg, provider); ok {
+		return newProviderClient(ctx, cfg, fallback.Provider, fallback.Model, false)
+	}
+	return newProviderClient(ctx, cfg, provider, modelFlag, true)
+}
 
+// newProviderClient builds a client for provider, or for the budget fallback
+// once a budget limit is exceeded. The --apiKey/--baseURL flags only apply when
+// applyFlags is set, so secondary providers (e.g. consensus members) resolve
+// credentials from their own env vars and config.
+func newProviderClient(ctx context.Context, cfg *config.Config, provider, model string, applyFlags bool) (ai.AIClient, error) {
+	if fallback, ok, err := budgetFallback(cfg); err != nil {
+		return nil, err
+	} else if ok {
+		provider, model, applyFlags = fallback.Provider, fallback.Model, false
+	}
 	if !registry.Has(provider) {
 		return nil, fmt.Errorf("provider não suportado: %s", provider)
 	}
@@ -175,13 +486,17 @@ func initAIClient(ctx context.Context, cfg *config.Config) (ai.AIClient, error)
     }
 
 	// Apply generic overrides
-	if modelFlag != "" {
-		ps.Model = modelFlag
+	if model != "" {
+		ps.Model = model
 	}
-    if override := baseURLOverrideFor(provider); override != "" {
+	urlFlag, keyFlag := "", ""
+	if applyFlags {
+		urlFlag, keyFlag = baseURLFlag, apiKeyFlag
+	}
+    if override := baseURLOverrideFor(provider, urlFlag); override != "" {
         ps.BaseURL = override
     }
-if key, err := apiKeyFor(provider, ps.APIKey); err == nil {
+if key, err := apiKeyFor(provider, keyFlag, ps.APIKey); err == nil {
     ps.APIKey = key
 } else if requiresAPIKey(provider) {
     return nil, err
@@ -191,12 +506,33 @@ if key, err := apiKeyFor(provider, ps.APIKey); err == nil {
 }
 
     factory, _ := registry.Get(provider)
-    return factory(ctx, provider, ps)
+    client, err := factory(ctx, provider, ps)
+    if err != nil {
+        return nil, err
+    }
+    sampling, err := samplingFor(cfg, ps)
+    if err != nil {
+        return nil, err
+    }
+    if err := ai.CheckSampling(client, sampling); err != nil {
+        return nil, err
+    }
+    if !sampling.IsZero() && !ai.ApplySampling(client, sampling) {
+        log.Debug().Str("provider", provider).Msg("Provider ignores the sampling parameters")
+    }
+    if a := runAnonymizer(cfg); a != nil {
+        client = anonymize.Wrap(client, a)
+    }
+    if b := providerBreaker(cfg); b != nil {
+        client = health.Track(client, b, provider)
+    }
+    client = usage.Track(client, eventBus(cfg), provider, ps.Model)
+    return client, nil
 }
 
-func baseURLOverrideFor(provider string) string {
-    if strings.TrimSpace(baseURLFlag) != "" {
-        return baseURLFlag
+func baseURLOverrideFor(provider, flagVal string) string {
+    if strings.TrimSpace(flagVal) != "" {
+        return flagVal
     }
     env := strings.ToUpper(provider) + "_BASE_URL"
     if v := strings.TrimSpace(os.Getenv(env)); v != "" {
@@ -205,10 +541,10 @@ func baseURLOverrideFor(provider string) string {
     return ""
 }
 
-func apiKeyFor(provider, configVal string) (string, error) {
+func apiKeyFor(provider, flagVal, configVal string) (string, error) {
     // Priority: flag > env > config value
     env := strings.ToUpper(provider) + "_API_KEY"
-    return config.ResolveAPIKey(apiKeyFlag, env, configVal, provider)
+    return config.ResolveAPIKey(flagVal, env, configVal, provider)
 }
 
 func requiresAPIKey(provider string) bool { return registry.RequiresAPIKey(provider) }
@@ -237,36 +573,164 @@ func formatReviewOutput(title, content string) string {
 }
 
 func runAICommit(cmd *cobra.Command, args []string) {
-	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
-	if err != nil {
-		log.Fatal().Err(err).Msg("Setup AI environment error")
+	if tutorialFlag {
+		if cfg, err := config.LoadOrCreateConfig(); err == nil {
+			if err := ui.ConfigureKeys(cfg.Keys); err != nil {
+				log.Fatal().Err(err).Msg("Invalid keys config")
+			}
+		}
+		if _, err := ui.NewProgram(ui.NewTutorialModel()).Run(); err != nil {
+			log.Fatal().Err(err).Msg("UI encountered an error")
+		}
 		return
 	}
+
+	if err := applyJSONOutput(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	applyOutputMode()
+	if err := applyPrintMode(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if err := checkDiffSourceFlags(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if err := checkAmendFlags(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if err := checkEditFlags(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if err := checkSplitFlags(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if _, err := loadContextFiles(); err != nil {
+		exitWith(exitConfig, err, "Invalid flags")
+	}
+	if bumpStrategyFlag != "" && !slices.Contains(versioner.Strategies, bumpStrategyFlag) {
+		exitWith(exitConfig, fmt.Errorf("unknown bump strategy %q (valid: %s)", bumpStrategyFlag, strings.Join(versioner.Strategies, ", ")), "Invalid flags")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		exitWith(exitConfig, err, "Setup AI environment error")
+	}
+	if allFlag && !(semanticReleaseFlag && dryRunFlag) {
+		// Settle staging before the generation deadline starts: the question waits
+		// on the user, and a run without a terminal first degrades to --force.
+		if !forceFlag && !msgOnlyFlag && !editFlag && !interactiveTerminal() {
+			degradeWithoutTerminal(cfg)
+		}
+		stageTracked(context.Background())
+	}
+	ctx, cancel, aiClient, err := startAIEnvironment(cfg)
+	if err != nil {
+		exitWith(exitConfig, err, "Setup AI environment error")
+	}
 	defer cancel()
+	if path := diffInputPath(); path != "" {
+		runDiffInput(ctx, cfg, aiClient, path)
+		return
+	}
+	if from, to, ok := refRange(); ok {
+		runRefDiff(ctx, cfg, aiClient, from, to)
+		return
+	}
+
+	commitOpts, err := commitOptions(cfg)
+	if err != nil {
+		exitWith(exitConfig, err, "Invalid commit options")
+	}
+	commitOpts.Amend = amendFlag
 
-	if interactiveSplitFlag {
-		runInteractiveSplit(ctx, aiClient, semanticReleaseFlag, manualSemverFlag)
+	releaseOpts := releaseOptions(ctx, cfg)
+	if semanticReleaseFlag && dryRunFlag {
+		runReleaseDryRun(ctx, aiClient, releaseOpts)
 		return
 	}
 
-    diff, err := git.GetGitDiffIgnoringMoves(ctx)
+	if !forceFlag && !msgOnlyFlag && !editFlag && !interactiveTerminal() {
+		degradeWithoutTerminal(cfg)
+	}
+
+	if interactiveSplitFlag || autoSplitFlag {
+		if provenanceEnabled(cfg) {
+			commitOpts.Trailers = append(commitOpts.Trailers, clientProvenance(cfg, aiClient))
+		}
+		runInteractiveSplit(ctx, aiClient, commitOpts, semanticReleaseFlag, manualSemverFlag, releaseOpts)
+		return
+	}
+
+    diff, filterReport, err := commitPromptDiff(ctx, cfg.LockFiles)
     if err != nil {
         log.Fatal().Err(err).Msg("Failed to get Git diff (ignoring moves)")
         return
     }
-    diff = git.FilterLockFiles(diff, cfg.LockFiles)
     if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
         if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+            filterReport.AddTruncation(len(diff), len(summarized))
             diff = summarized
         }
     }
+	commitType := commitTypeFlag
+	if strings.TrimSpace(diff) == "" && !allowEmptyFlag {
+		// Everything was filtered (comments, formatting, lock files). Fall back to the
+		// unfiltered diff with a type hint instead of refusing a legitimate commit.
+		if raw, rawErr := commitRawDiff(ctx); rawErr == nil && strings.TrimSpace(raw) != "" {
+			if commitType == "" {
+				commitType = git.FallbackCommitType(raw, cfg.LockFiles)
+			}
+			diff = raw
+			// The raw diff carries everything the filters dropped, so start a fresh report.
+			filterReport = &git.FilterReport{}
+			if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+				if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+					filterReport.AddTruncation(len(diff), len(summarized))
+					diff = summarized
+				}
+			}
+		}
+	}
+	if showFilteredFlag {
+		fmt.Fprintln(os.Stderr, formatReviewOutput("Filtered from the prompt", filterReport.String()))
+	}
+
+	emptyCommit := false
 	if strings.TrimSpace(diff) == "" {
-		fmt.Println("No staged changes after filtering lock files.")
-		return
+		if !allowEmptyFlag {
+			if !quietFlag {
+				fmt.Println("No staged changes.")
+			}
+			os.Exit(exitNoChanges)
+		}
+		if strings.TrimSpace(intentFlag) == "" {
+			exitWith(exitConfig, nil, "--allow-empty requires --intent describing why the empty commit is needed")
+		}
+		emptyCommit = true
+	}
+	commitOpts.AllowEmpty = emptyCommit
+	if !emptyCommit {
+		aiClient = applyRouting(ctx, cfg, aiClient, diff)
+	}
+	if !emptyCommit && (checkDuplicatesFlag || cfg.DuplicateCheck.Enabled) {
+		warnDuplicateWork(ctx, cfg, aiClient, diff)
+	}
+	if !emptyCommit && !noArchCheckFlag && checkArchitecture(ctx, cfg, aiClient, diff) {
+		log.Error().Msg("Commit blocked by architecture rules (use --no-arch-check to bypass)")
+		os.Exit(1)
+	}
+
+	categoryType := ""
+	if !emptyCommit {
+		commitType, categoryType = ruleCommitType(cfg, diff, commitType)
 	}
 
-    scopeHint := git.SuggestScope(diff)
-    promptText := prompt.BuildCommitPrompt(diff, languageFlag, commitTypeFlag, "", cfg.PromptTemplate, scopeHint)
+	var scopeHint, promptText string
+	if emptyCommit {
+		promptText = prompt.BuildEmptyCommitPrompt(intentFlag, languageFlag, commitType)
+	} else {
+		scopeHint = git.SuggestScope(diff)
+		promptText = commitPrompt(cfg, diff, commitType, categoryType, scopeHint) + relatedHint(ctx, cfg, diff) + amendHint(ctx)
+	}
     if cfg.Limits.Prompt.Enabled && cfg.Limits.Prompt.MaxChars > 0 {
         if len(promptText) > cfg.Limits.Prompt.MaxChars {
             // hard truncate with marker
@@ -274,64 +738,262 @@ func runAICommit(cmd *cobra.Command, args []string) {
             if limit > 3 {
                 limit -= 3
             }
+            filterReport.AddTruncation(len(promptText), limit)
             promptText = promptText[:limit] + "..."
         }
     }
+    plugins := pluginRunner(cfg)
+    if plugins.Has(plugin.PrePrompt) {
+        in, pluginErr := plugins.Run(ctx, plugin.Input{Point: plugin.PrePrompt, Prompt: promptText, Diff: diff})
+        if pluginErr != nil {
+            exitWith(exitFailure, pluginErr, "Pre-prompt plugin failed")
+        }
+        promptText = in.Prompt
+    }
     var commitMsg string
-    if forceFlag || msgOnlyFlag || !supportsStreaming(aiClient) {
+    // provenance stays empty for messages that were not generated by AI.
+    var provenance string
+    bus := eventBus(cfg)
+    wait, err := maxWait(cfg)
+    if err != nil {
+        exitWith(exitConfig, err, "Invalid --max-wait")
+    }
+    candidates, err := candidateCount(cfg)
+    if err != nil {
+        exitWith(exitConfig, err, "Invalid --candidates")
+    }
+    // choices holds the alternatives of --candidates, commitMsg the first one.
+    var choices []string
+    var fallbackClient ai.AIClient
+    if wait > 0 {
+        if fallbackClient, err = maxWaitFallback(ctx, cfg); err != nil {
+            exitWith(exitConfig, err, "Fallback provider setup failed")
+        }
+    }
+    verbosef("diff: %d chars, prompt: %d chars", len(diff), len(promptText))
+    // The diff view offers per-hunk overrides on top of the unfiltered diff, whose
+    // hash also keys the autosaved message.
+    var rawDiff string
+    if !emptyCommit {
+        if raw, rawErr := commitRawDiff(ctx); rawErr == nil {
+            rawDiff = raw
+        }
+    }
+    draft, hasDraft := loadDraft(ctx, emptyCommit, rawDiff)
+    genStart := time.Now()
+    if hasDraft {
+        // The TUI starts from the draft and offers to improve it.
+        verbosef("starting from the %s draft", draft.Source)
+        provenance = clientProvenance(cfg, aiClient)
+        commitMsg = draft.Message
+    } else if renames, ok := git.ParseRenameOnlyDiff(diff); ok {
+        // Pure moves need no AI round-trip; describe them from the rename list.
+        renameType := commitType
+        if renameType == "" {
+            renameType = "refactor"
+        }
+        var finErr error
+        commitMsg, finErr = finalizeCommitMessage(git.RenameCommitMessage(renames), renameType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+        if finErr != nil {
+            exitWith(exitConfig, finErr, "Commit message template error")
+        }
+        verbosef("rename-only diff: message built without a provider request")
+    } else if strings.TrimSpace(consensusFlag) != "" {
+        var consErr error
+        specs := parseProviderSpecs(consensusFlag)
+        commitMsg, consErr = runConsensus(ctx, cfg, specs, judgeFlag, promptText, diff, commitType, !forceFlag && !msgOnlyFlag)
+        if consErr != nil {
+            exitWith(providerExitCode(consErr), consErr, "Consensus generation error")
+        }
+        verbosef("consensus of %d providers in %s", len(specs), time.Since(genStart).Round(time.Millisecond))
+        provenance = consensusProvenance(cfg, specs, judgeFlag)
+    } else if candidates > 1 && !forceFlag && !msgOnlyFlag && !editFlag {
+        var genErr error
+        choices, aiClient, genErr = withMaxWait(ctx, aiClient, fallbackClient, wait, func(ctx context.Context, client ai.AIClient) ([]string, error) {
+            return generateCandidates(ctx, client, promptText, candidates, commitType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+        })
+        if genErr != nil {
+            exitWith(providerExitCode(genErr), genErr, "Commit message generation error")
+        }
+        choices = guardCandidates(cfg, choices)
+        commitMsg = choices[0]
+        verbosef("%d candidates generated in %s (%s)", len(choices), time.Since(genStart).Round(time.Millisecond), describeLastCall(aiClient))
+        provenance = clientProvenance(cfg, aiClient)
+    } else if forceFlag || msgOnlyFlag || editFlag || !supportsStreaming(aiClient) {
         var genErr error
-        commitMsg, genErr = generateCommitMessage(ctx, aiClient, promptText, commitTypeFlag, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+        commitMsg, aiClient, genErr = generateWithMaxWait(ctx, aiClient, fallbackClient, wait, promptText, commitType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
         if genErr != nil {
-            log.Error().Err(genErr).Msg("Commit message generation error")
-            os.Exit(1)
+            exitWith(providerExitCode(genErr), genErr, "Commit message generation error")
         }
+        verbosef("generated in %s (%s)", time.Since(genStart).Round(time.Millisecond), describeLastCall(aiClient))
+        provenance = clientProvenance(cfg, aiClient)
     } else {
+        provenance = clientProvenance(cfg, aiClient)
         commitMsg = ""
     }
+    if provenance != "" && provenanceEnabled(cfg) {
+        commitOpts.Trailers = append(commitOpts.Trailers, provenance)
+    }
 
-	if msgOnlyFlag {
-		if strings.TrimSpace(commitMsg) == "" {
-			os.Exit(1)
+	if commitMsg != "" && !hasDraft && plugins.Has(plugin.PostGenerate) {
+		var pluginErr error
+		if commitMsg, choices, pluginErr = postGenerate(ctx, plugins, diff, commitMsg, choices); pluginErr != nil {
+			exitWith(exitFailure, pluginErr, "Post-generate plugin failed")
+		}
+	}
+	if commitMsg != "" && !hasDraft {
+		var guardErr error
+		commitMsg, guardErr = enforceGuardrails(ctx, cfg, aiClient, promptText, commitType, commitMsg)
+		if guardErr != nil {
+			exitWith(providerExitCode(guardErr), guardErr, "Commit message guardrails")
 		}
-		fmt.Print(commitMsg)
-		return
 	}
+	todos := addedTodos(cfg, rawDiff)
 
 	var styleReviewSuggestions string
-    if reviewMessageFlag && commitMsg != "" {
+    if (reviewMessageFlag || fixMessageFlag) && commitMsg != "" {
         suggestions, errReview := enforceCommitMessageStyle(ctx, aiClient, commitMsg, languageFlag, cfg.PromptTemplate)
         if errReview != nil {
-            log.Error().Err(errReview).Msg("Commit message style enforcement failed")
-            os.Exit(1)
+            exitWith(providerExitCode(errReview), errReview, "Commit message style enforcement failed")
         }
         styleReviewSuggestions = suggestions
+        if fixMessageFlag {
+            commitMsg, styleReviewSuggestions, errReview = fixCommitMessageStyle(ctx, cfg, aiClient, promptText, commitType, commitMsg, suggestions)
+            if errReview != nil {
+                exitWith(providerExitCode(errReview), errReview, "Commit message style fix failed")
+            }
+        }
     }
 
-	if forceFlag {
-		if reviewMessageFlag && strings.TrimSpace(styleReviewSuggestions) != "" &&
-			!strings.Contains(strings.ToLower(styleReviewSuggestions), "no issues found") {
+	if msgOnlyFlag {
+		if strings.TrimSpace(commitMsg) == "" {
+			os.Exit(1)
+		}
+		// stdout carries only the message.
+		if !quietFlag && prompt.HasStyleIssues(styleReviewSuggestions) {
+			fmt.Fprintln(os.Stderr, "\n"+formatReviewOutput("AI Commit Message Style Review Suggestions", styleReviewSuggestions))
+		}
+		if cfg.Todos.Body {
+			commitMsg = git.AppendTodoSection(commitMsg, todos)
+		}
+		commitMsg = git.ApplyFooters(commitMsg, commitOpts.Footers)
+		for _, trailer := range commitOpts.Trailers {
+			commitMsg = git.AppendTrailer(commitMsg, trailer)
+		}
+		if commitOpts.ChangeID {
+			if commitMsg, err = git.AppendChangeID(commitMsg, commitOpts); err != nil {
+				log.Fatal().Err(err).Msg("Failed to add Change-Id")
+			}
+		}
+		if commitOpts.Signoff {
+			if commitMsg, err = git.AppendSignoffFor(commitMsg, commitOpts); err != nil {
+				log.Fatal().Err(err).Msg("Failed to add Signed-off-by")
+			}
+		}
+		// Should git or a commit-msg hook reject the commit, the next
+		// interactive run offers the message again. --print, --dry-run, and
+		// JSON output only show the message, so they leave no draft behind.
+		if !printFlag && !dryRunFlag && !jsonOutputFlag {
+			if err := git.SaveDraft(ctx, commitMsg); err != nil {
+				log.Debug().Err(err).Msg("Cannot save the draft")
+			}
+		}
+		if jsonOutputFlag {
+			writeCommitResult(aiClient, commitMsg, "", time.Since(genStart))
+		} else {
+			fmt.Print(commitMsg)
+		}
+		return
+	}
+
+	if forceFlag || editFlag {
+		// ctx carries the generation deadline, which must neither kill the
+		// editor while the user types nor fail the commit after a slow edit.
+		ctx := context.WithoutCancel(ctx)
+		if !quietFlag && prompt.HasStyleIssues(styleReviewSuggestions) {
 			formattedStyleReview := formatReviewOutput("AI Commit Message Style Review Suggestions", styleReviewSuggestions)
 			fmt.Println("\n" + formattedStyleReview)
 		}
 		if strings.TrimSpace(commitMsg) == "" {
 			log.Fatal().Msg("Generated commit message is empty; aborting commit.")
 		}
-		if err := git.CommitChanges(ctx, commitMsg); err != nil {
-			log.Fatal().Err(err).Msg("Commit failed")
+		if cfg.Todos.Body {
+			commitMsg = git.AppendTodoSection(commitMsg, todos)
+		}
+		if editFlag {
+			commitMsg = editCommitMessage(ctx, commitMsg)
+		}
+		if err := git.CommitChangesWithOptions(ctx, commitMsg, commitOpts); err != nil {
+			if saveErr := git.SaveDraft(ctx, commitMsg); saveErr != nil {
+				log.Debug().Err(saveErr).Msg("Cannot save the draft")
+			}
+			exitWith(commitExitCode(err), err, "Commit failed")
+		}
+		if err := git.ClearDraft(ctx); err != nil {
+			log.Debug().Err(err).Msg("Cannot remove the saved draft")
+		}
+		hash, err := git.GetHeadCommitHash(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot read the new commit")
+		}
+		switch {
+		case jsonOutputFlag:
+			writeCommitResult(aiClient, commitMsg, hash, time.Since(genStart))
+		case quietFlag:
+			fmt.Println(hash)
+		case editFlag:
+			fmt.Println("Commit created successfully.")
+		default:
+			fmt.Println("Commit created successfully (forced).")
 		}
-		fmt.Println("Commit created successfully (forced).")
+		verbosef("commit %s created %s after generation started", hash, time.Since(genStart).Round(time.Millisecond))
+		bus.Publish(events.CommitCreated{Hash: hash, Message: commitMsg, Elapsed: time.Since(genStart)})
+		printTodos(todos)
 		if semanticReleaseFlag {
-			if err := versioner.PerformSemanticRelease(ctx, aiClient, commitMsg, manualSemverFlag); err != nil {
+			if err := versioner.PerformSemanticRelease(ctx, aiClient, manualSemverFlag, releaseOpts); err != nil {
 				log.Fatal().Err(err).Msg("Semantic release failed")
 			}
 		}
 		return
 	}
 
-	runInteractiveUI(ctx, commitMsg, diff, promptText, styleReviewSuggestions, cfg.EnableEmoji, aiClient, cfg.PromptTemplate, cfg.TicketPattern, scopeHint)
+	if len(choices) > 0 {
+		// Guardrails and --fix-message may have replaced the first candidate.
+		choices[0] = commitMsg
+	}
+	if commitMsg != "" {
+		bus.Publish(events.MessageReady{Message: commitMsg, Elapsed: time.Since(genStart)})
+	}
+	runInteractiveUI(ctx, uiSession{
+		cfg:          cfg,
+		commitMsg:    commitMsg,
+		diff:         diff,
+		rawDiff:      rawDiff,
+		promptText:   promptText,
+		commitType:   commitType,
+		scopeHint:    scopeHint,
+		styleReview:  styleReviewSuggestions,
+		aiClient:     aiClient,
+		fallback:     fallbackClient,
+		maxWait:      wait,
+		commitOpts:   commitOpts,
+		filterReport: filterReport,
+		bus:          bus,
+		plugins:      plugins,
+		releaseOpts:  releaseOpts,
+		draft:        draft,
+		choices:      choices,
+		candidates:   candidates,
+		todos:        todos,
+	})
 }
 
 func runAICodeReview(cmd *cobra.Command, args []string) {
+	if on, err := isJSONOutput(reviewOutputFlag); err != nil {
+		log.Fatal().Err(err).Msg("Invalid --output")
+	} else if on {
+		reviewFormatFlag = "json"
+	}
 	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Setup AI environment error")
@@ -339,15 +1001,58 @@ func runAICodeReview(cmd *cobra.Command, args []string) {
 	}
 	defer cancel()
 
-	diff, err := git.GetGitDiffIgnoringMoves(ctx)
-	if err != nil {
+	var formatter report.Formatter
+	if reviewFormatFlag != "" {
+		if formatter, err = report.New(reviewFormatFlag); err != nil {
+			log.Fatal().Err(err).Msg("Invalid --format")
+		}
+	}
+
+	// In a merge request pipeline nothing is staged; the merge request is reviewed instead.
+	var mr *gitlab.MergeRequest
+	var glClient *gitlab.Client
+	var mrDiff *gitlab.Diff
+	var diff string
+	if postToGitLabFlag {
+		if mr, err = gitlab.MergeRequestFromEnv(); err != nil {
+			log.Fatal().Err(err).Msg("Cannot determine the merge request")
+		}
+		if glClient, err = gitlab.NewClientFromEnv(); err != nil {
+			log.Fatal().Err(err).Msg("Cannot reach the GitLab API")
+		}
+		if mrDiff, err = glClient.MergeRequestDiff(ctx, *mr); err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch merge request diff")
+		}
+		diff = git.PromptDiff(mrDiff.Text, cfg.LockFiles)
+	} else if from, to, ok := refRange(); ok {
+		if err := checkDiffSourceFlags(); err != nil {
+			log.Fatal().Err(err).Msg("Invalid flags")
+		}
+		if diff, _, err = git.GetRefPromptDiff(ctx, from, to, cfg.LockFiles); err != nil {
+			log.Fatal().Err(err).Msg("Git diff error")
+		}
+	} else if diff, err = git.GetGitDiffIgnoringMoves(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Git diff error")
 		return
 	}
 	if strings.TrimSpace(diff) == "" {
-		fmt.Println("No staged changes for code review.")
+		if postToGitLabFlag {
+			fmt.Println("No reviewable changes in the merge request.")
+		} else if _, _, ok := refRange(); ok {
+			fmt.Println("No changes between the refs for code review.")
+		} else {
+			fmt.Println("No staged changes for code review.")
+		}
 		return
 	}
+	var reviewers []review.Reviewer
+	if reviewersFlag || explainReviewersFlag {
+		rev := "HEAD"
+		if from, _, ok := refRange(); ok {
+			rev = from
+		}
+		reviewers = suggestReviewers(ctx, diff, rev)
+	}
 
     // Optionally summarize/truncate diff for code review as well.
     if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
@@ -355,7 +1060,21 @@ func runAICodeReview(cmd *cobra.Command, args []string) {
             diff = summarized
         }
     }
-    reviewPrompt := prompt.BuildCodeReviewPrompt(diff, languageFlag, cfg.PromptTemplate)
+    var reviewPrompt string
+    switch strings.ToLower(strings.TrimSpace(reviewPresetFlag)) {
+    case "":
+        if postToGitLabFlag || structuredFormat(reviewFormatFlag) {
+            // Findings need file:line locations to be attached to the diff.
+            reviewPrompt = prompt.BuildCIReviewPrompt(diff, languageFlag, review.NoFindings)
+        } else {
+            reviewPrompt = prompt.BuildCodeReviewPrompt(diff, languageFlag, cfg.PromptTemplate)
+        }
+    case "performance", "perf":
+        heuristics := git.FormatPerfHeuristics(git.PerfHeuristics(diff, git.ReadWorktreeFile))
+        reviewPrompt = prompt.BuildPerformanceReviewPrompt(diff, languageFlag, heuristics)
+    default:
+        log.Fatal().Str("preset", reviewPresetFlag).Msg("Unknown review preset (supported: performance)")
+    }
     if cfg.Limits.Prompt.Enabled && cfg.Limits.Prompt.MaxChars > 0 {
         if len(reviewPrompt) > cfg.Limits.Prompt.MaxChars {
             limit := cfg.Limits.Prompt.MaxChars
@@ -370,24 +1089,94 @@ func runAICodeReview(cmd *cobra.Command, args []string) {
 		log.Fatal().Err(err).Msg("Code review generation error")
 		return
 	}
+	source := report.SourceOf(aiClient)
+	var reviewerText string
+	switch {
+	case explainReviewersFlag:
+		reviewerText = explainReviewers(ctx, aiClient, diff, reviewers)
+	case reviewersFlag:
+		reviewerText = formatReviewers(reviewers)
+	}
+
+	if formatter != nil {
+		r := report.Report{
+			Title:    "AI Code Review",
+			Fields:   []report.Field{{Name: "Changes", Value: reviewedChanges(mr)}},
+			Body:     strings.TrimSpace(reviewResult),
+			Findings: review.ParseFindings(reviewResult),
+			Version:  version,
+			Source:   source,
+		}
+		if reviewerText != "" {
+			r.Fields = append(r.Fields, report.Field{Name: "Suggested reviewers", Value: reviewerNames(reviewers)})
+			r.Body += "\n\n### Suggested reviewers\n\n" + reviewerText
+		}
+		if err := formatter.Format(os.Stdout, r); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write the review")
+		}
+	} else {
+		formattedReview := formatReviewOutput("AI Code Review Suggestions", withSource(strings.TrimSpace(reviewResult), source))
+		fmt.Println("\n" + formattedReview)
+		if reviewerText != "" {
+			fmt.Println("\n" + formatReviewOutput("Suggested Reviewers", reviewerText))
+		}
+	}
 
-	formattedReview := formatReviewOutput("AI Code Review Suggestions", strings.TrimSpace(reviewResult))
-	fmt.Println("\n" + formattedReview)
+	if postToGitLabFlag {
+		findings := review.ParseFindings(reviewResult)
+		posted, err := glClient.PostFindings(ctx, *mr, findings, mrDiff)
+		if err != nil {
+			log.Fatal().Err(err).Int("posted", posted).Msg("Failed to post findings to the merge request")
+		}
+		fmt.Fprintf(os.Stderr, "Posted %d finding(s) to merge request !%d.\n", posted, mr.IID)
+	}
 }
 
 func newSummarizeCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var formatFlag, outputFlag string
+	var noCacheFlag, refreshFlag bool
 	cmd := &cobra.Command{
 		Use:   "summarize",
 		Short: "List commits via fzf, pick one, and summarize the commit with AI",
 		Long:  "Displays all commits in a fuzzy finder interface, picks one, and calls the AI provider to produce a summary.",
 		Run: func(cmd *cobra.Command, args []string) {
-			runSummarizeCommand(setupAIEnvironment)
+			cache := summarizer.CacheUse
+			if noCacheFlag {
+				cache = summarizer.CacheOff
+			} else if refreshFlag {
+				cache = summarizer.CacheRefresh
+			}
+			if on, err := isJSONOutput(outputFlag); err != nil {
+				log.Fatal().Err(err).Msg("Invalid --output")
+			} else if on {
+				formatFlag = "json"
+			}
+			runSummarizeCommand(setupAIEnvironment, formatFlag, cache)
 		},
 	}
+	cmd.Flags().StringVar(&formatFlag, "format", "", "Print the summary as markdown, json, or html instead of styled text")
+	cmd.Flags().StringVar(&outputFlag, "output", "text", "Output format: text, or json (same as --format json) for the summary with provider, model, token usage, and elapsed time")
+	cmd.MarkFlagsMutuallyExclusive("format", "output")
+	cmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Neither use nor store a cached summary")
+	cmd.Flags().BoolVar(&refreshFlag, "refresh", false, "Regenerate the summary and replace the cached one")
+	cmd.MarkFlagsMutuallyExclusive("no-cache", "refresh")
 	return cmd
 }
 
-func runSummarizeCommand(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) {
+func runSummarizeCommand(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error), format string, cache summarizer.CacheMode) {
+	var formatter report.Formatter
+	if format != "" {
+		var err error
+		if strings.EqualFold(strings.TrimSpace(format), "sarif") {
+			err = errors.New("sarif holds review findings; use markdown, json, or html")
+		} else {
+			formatter, err = report.New(format)
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid --format")
+		}
+	}
+
 	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Setup environment error for summarize command")
@@ -395,56 +1184,98 @@ func runSummarizeCommand(setupAIEnvironment func() (context.Context, context.Can
 	}
 	defer cancel()
 
-	if err := summarizer.SummarizeCommits(ctx, aiClient, cfg, languageFlag); err != nil {
+	if formatter == nil {
+		if err := summarizer.SummarizeCommits(ctx, aiClient, cfg, languageFlag, cache); err != nil {
+			log.Fatal().Err(err).Msg("Failed to summarize commits")
+		}
+		return
+	}
+	summary, err := summarizer.PickAndSummarize(ctx, aiClient, cfg, languageFlag, cache)
+	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to summarize commits")
 	}
+	if summary == nil {
+		fmt.Fprintln(os.Stderr, "No diff found for this commit (maybe an empty or merge commit).")
+		return
+	}
+	if err := formatter.Format(os.Stdout, summaryReport(summary)); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write the summary")
+	}
 }
 
-func runInteractiveUI(
-    ctx context.Context,
-    commitMsg string,
-    diff string,
-    promptText string,
-    styleReviewSuggestions string,
-    enableEmoji bool,
-    aiClient ai.AIClient,
-    promptTemplate string,
-    ticketPattern string,
-    scopeHint string,
-) {
+// uiSession is what runInteractiveUI opens the TUI with.
+type uiSession struct {
+	cfg *config.Config
+	// commitMsg is the generated message, empty when the TUI streams it.
+	commitMsg    string
+	diff         string
+	rawDiff      string
+	promptText   string
+	commitType   string
+	scopeHint    string
+	styleReview  string
+	aiClient     ai.AIClient
+	fallback     ai.AIClient
+	maxWait      time.Duration
+	commitOpts   git.CommitOptions
+	filterReport *git.FilterReport
+	bus          *events.Bus
+	plugins      *plugin.Runner
+	releaseOpts  versioner.ReleaseOptions
+	draft        git.Draft
+	choices      []string
+	candidates   int
+	todos        []git.Todo
+}
+
+func runInteractiveUI(ctx context.Context, s uiSession) {
     // Start with streaming if the client supports it and we have a prompt
     startStreaming := false
-    if _, ok := aiClient.(ai.StreamingAIClient); ok && strings.TrimSpace(promptText) != "" {
+    commitMsg := s.commitMsg
+    if _, ok := s.aiClient.(ai.StreamingAIClient); ok && strings.TrimSpace(s.promptText) != "" && strings.TrimSpace(commitMsg) == "" {
         startStreaming = true
         // When streaming, start with empty commit message; the TUI will fill it in.
         commitMsg = ""
     }
 
+    cfg := s.cfg
     uiModel := ui.NewUIModel(
         commitMsg,
-        diff,
+        s.diff,
         languageFlag,
-        promptText,
-        commitTypeFlag,
+        s.promptText,
+        s.commitType,
         templateFlag,
-        styleReviewSuggestions,
-        enableEmoji,
-        aiClient,
+        s.styleReview,
+        cfg.EnableEmoji,
+        s.aiClient,
         startStreaming,
-        promptTemplate,
-        ticketPattern,
-        scopeHint,
-    )
+        cfg.PromptTemplate,
+        cfg.TicketPattern,
+        s.scopeHint,
+    ).WithCommitOptions(s.commitOpts).WithFilterReport(s.filterReport).WithRawDiff(s.rawDiff).WithContextFiles(additionalContext()).WithIntent(intentFlag).WithGuard(guard.NewScanner(cfg.Guardrails)).WithEvents(s.bus).WithPlugins(s.plugins).WithMaxWait(s.maxWait, s.fallback).WithInstantQuit(cfg.InstantQuit).WithTodos(s.todos, cfg.Todos.Body)
+	if s.draft.Message != "" {
+		uiModel = uiModel.WithDraft(s.draft)
+	}
+	if s.candidates > 1 {
+		uiModel = uiModel.WithChoices(s.choices, s.candidates)
+	}
+	if s.rawDiff != "" {
+		uiModel = uiModel.WithAutosave(git.DiffHash(s.rawDiff))
+	}
 	program := ui.NewProgram(uiModel)
+	releaseShare := holdShare()
 	if _, err := program.Run(); err != nil {
 		log.Fatal().Err(err).Msg("UI encountered an error")
 	}
+	releaseShare()
 	if semanticReleaseFlag {
+		// The user may have spent longer in the UI than the generation deadline of ctx.
 		if err := versioner.PerformSemanticRelease(
-			ctx,
+			context.WithoutCancel(ctx),
 			uiModel.GetAIClient(),
-			uiModel.GetCommitMsg(),
 			manualSemverFlag,
+			s.releaseOpts,
 		); err != nil {
 			log.Fatal().Err(err).Msg("Semantic release failed")
 		}
@@ -464,16 +1295,37 @@ func generateCommitMessage(
 	if err != nil {
 		return "", err
 	}
+	return finishCommitMessage(client, msg, commitType, tmpl, enableEmoji, ticketPattern)
+}
 
+// finishCommitMessage turns a raw reply of client into the commit message:
+// sanitized, typed, templated, and validated.
+func finishCommitMessage(client ai.AIClient, msg, commitType, tmpl string, enableEmoji bool, ticketPattern string) (string, error) {
 	if commitType == "" {
 		commitType = committypes.GuessCommitType(msg)
 	}
-	msg = client.SanitizeResponse(msg, commitType)
+	// finalizeCommitMessage replaces the model's type prefix, keeping its scope.
+	msg = client.SanitizeResponse(msg, "")
+	msg, err := finalizeCommitMessage(msg, commitType, tmpl, enableEmoji, ticketPattern)
+	if err != nil {
+		return "", err
+	}
+	if err := ai.ValidateCommitMessage(msg); err != nil {
+		return "", fmt.Errorf("rejected AI output: %w", err)
+	}
+	if warning := ai.MessageWarning(msg); warning != "" {
+		log.Warn().Msgf("Check the AI output: %s", warning)
+	}
+	return msg, nil
+}
 
+// finalizeCommitMessage prepends the commit type and applies the user template.
+func finalizeCommitMessage(msg, commitType, tmpl string, enableEmoji bool, ticketPattern string) (string, error) {
 	if commitType != "" {
 		msg = git.PrependCommitType(msg, commitType, enableEmoji)
 	}
 	if tmpl != "" {
+		var err error
 		msg, err = template.ApplyTemplate(tmpl, msg, ticketPattern)
 		if err != nil {
 			return "", err
@@ -500,19 +1352,44 @@ func enforceCommitMessageStyle(
 func newChangelogCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
 	var sinceFlag string
 	var outputFlag string
+	var exportFlag string
+	var fromFlag, toFlag string
+	var prependFlag bool
 
 	cmd := &cobra.Command{
 		Use:   "changelog [fromRef..toRef]",
 		Short: "Generate a changelog between two refs using AI",
-		Long:  "Generates a polished changelog by listing commits between two Git references, grouping by type, and using AI to produce formatted markdown.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: "Generates a polished changelog by listing commits between two Git references, grouping by type, and using AI to produce formatted markdown. " +
+			"With --prepend, the changelog becomes a new release section at the top of CHANGELOG.md (or --output). " +
+			"With --export, no AI is used: the commits are normalized (gitmoji and CI markers stripped, type aliases resolved) and written as JSON or CSV for release tooling.",
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			runChangelogCommand(setupAIEnvironment, args, sinceFlag, outputFlag)
+			if fromFlag != "" || toFlag != "" {
+				if len(args) > 0 {
+					log.Fatal().Msg("Give the range either as fromRef..toRef or with --from/--to, not both")
+				}
+				if fromFlag == "" {
+					log.Fatal().Msg("--to needs --from")
+				}
+				args = []string{fromFlag + ".." + toFlag}
+			}
+			if exportFlag != "" {
+				if prependFlag {
+					log.Fatal().Msg("--prepend cannot be combined with --export")
+				}
+				runChangelogExport(args, sinceFlag, outputFlag, exportFlag)
+				return
+			}
+			runChangelogCommand(setupAIEnvironment, args, sinceFlag, outputFlag, prependFlag)
 		},
 	}
 
 	cmd.Flags().StringVar(&sinceFlag, "since", "", "Generate changelog for commits since a time (e.g., '2 weeks ago')")
 	cmd.Flags().StringVar(&outputFlag, "output", "", "Write changelog to file instead of stdout")
+	cmd.Flags().StringVar(&exportFlag, "export", "", "Export the normalized commit log as \"json\" or \"csv\" instead (default range: the whole history)")
+	cmd.Flags().StringVar(&fromFlag, "from", "", "Start of the range, exclusive (e.g. v1.2.0); same as the fromRef of fromRef..toRef")
+	cmd.Flags().StringVar(&toFlag, "to", "", "End of the range (default HEAD)")
+	cmd.Flags().BoolVar(&prependFlag, "prepend", false, "Prepend the changelog as a release section to CHANGELOG.md, or to the --output file")
 
 	return cmd
 }
@@ -522,6 +1399,7 @@ func runChangelogCommand(
 	args []string,
 	sinceFlag string,
 	outputFlag string,
+	prepend bool,
 ) {
 	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
 	if err != nil {
@@ -554,12 +1432,27 @@ func runChangelogCommand(
 		log.Fatal().Err(err).Msg("Failed to generate changelog")
 	}
 
-	if outputFlag != "" {
+	switch {
+	case prepend:
+		path := outputFlag
+		if path == "" {
+			path = "CHANGELOG.md"
+		}
+		_, toRef, err := changelog.Range(opts)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to resolve the changelog range")
+		}
+		title := changelog.SectionTitle(toRef)
+		if err := changelog.Prepend(path, changelog.Section(title, time.Now(), result)); err != nil {
+			log.Fatal().Err(err).Msg("Failed to update changelog file")
+		}
+		fmt.Printf("Added the %s section to %s\n", title, path)
+	case outputFlag != "":
 		if err := os.WriteFile(outputFlag, []byte(result+"\n"), 0o644); err != nil {
 			log.Fatal().Err(err).Msg("Failed to write changelog to file")
 		}
 		fmt.Printf("Changelog written to %s\n", outputFlag)
-	} else {
+	default:
 		fmt.Println(result)
 	}
 }
@@ -616,16 +1509,25 @@ func newHookCmd() *cobra.Command {
 func runInteractiveSplit(
 	ctx context.Context,
 	aiClient ai.AIClient,
+	commitOpts git.CommitOptions,
 	semanticReleaseFlag bool,
 	manualSemverFlag bool,
+	releaseOpts versioner.ReleaseOptions,
 ) {
-	if err := splitter.RunInteractiveSplit(ctx, aiClient); err != nil {
+	byFile := splitByFlag == "file"
+	var err error
+	if autoSplitFlag {
+		err = splitter.RunAutoSplit(ctx, aiClient, commitOpts, byFile, languageFlag)
+	} else {
+		err = splitter.RunInteractiveSplit(ctx, aiClient, commitOpts, byFile)
+	}
+	if err != nil {
 		log.Error().Err(err).Msg("Interactive split failed")
 		return
 	}
 	if semanticReleaseFlag {
-		headMsg, _ := git.GetHeadCommitMessage(ctx)
-		if err := versioner.PerformSemanticRelease(ctx, aiClient, headMsg, manualSemverFlag); err != nil {
+		// The user may have spent longer in the splitter than the generation deadline of ctx.
+		if err := versioner.PerformSemanticRelease(context.WithoutCancel(ctx), aiClient, manualSemverFlag, releaseOpts); err != nil {
 			log.Error().Err(err).Msg("Semantic release failed")
 		}
 	}
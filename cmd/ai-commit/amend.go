@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// checkAmendFlags rejects flags that create new commits alongside --amend.
+func checkAmendFlags() error {
+	if !amendFlag {
+		return nil
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--interactive-split", interactiveSplitFlag},
+		{"--auto-split", autoSplitFlag},
+		{"--allow-empty", allowEmptyFlag},
+	} {
+		if f.set {
+			return fmt.Errorf("%s creates new commits and cannot be combined with --amend", f.name)
+		}
+	}
+	return nil
+}
+
+// commitPromptDiff returns the prompt diff of what will be committed: the
+// staged changes or, with --amend, the HEAD commit's changes plus them.
+func commitPromptDiff(ctx context.Context, lockFiles []string) (string, *git.FilterReport, error) {
+	if amendFlag {
+		return git.GetAmendPromptDiff(ctx, lockFiles)
+	}
+	return git.GetPromptDiff(ctx, lockFiles)
+}
+
+// commitRawDiff is commitPromptDiff without the prompt filters.
+func commitRawDiff(ctx context.Context) (string, error) {
+	if amendFlag {
+		return git.AmendDiff(ctx)
+	}
+	return git.GetStagedDiff(ctx)
+}
+
+// amendHint asks the AI to refine the message of the amended commit rather than
+// write a new one; it is empty without --amend.
+func amendHint(ctx context.Context) string {
+	if !amendFlag {
+		return ""
+	}
+	message, err := git.GetHeadCommitMessage(ctx)
+	if err != nil || message == "" {
+		return ""
+	}
+	return prompt.DraftHint(message)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// annotateSubjectWidth cuts the subjects in the annotate table so two fit side
+// by side in a terminal.
+const annotateSubjectWidth = 60
+
+func newAnnotateCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var rangeFlag string
+	var annotateDryRunFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "annotate --range from..to",
+		Short: "Propose improved messages for every commit in a range",
+		Long: "Asks the AI to improve the message of each commit in a range from its diff, keeping what the current message says, " +
+			"and prints the current and proposed subjects side by side. With --dry-run nothing else happens; otherwise, after confirmation, " +
+			"the range is rewritten with the proposed messages (trees are unchanged; the old tip is kept in ORIG_HEAD).",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runAnnotate(setupAIEnvironment, rangeFlag, annotateDryRunFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&rangeFlag, "range", "", "Commits to annotate, e.g. origin/main..HEAD")
+	cmd.Flags().BoolVar(&annotateDryRunFlag, "dry-run", false, "Only print the proposals; do not offer to rewrite history")
+	_ = cmd.MarkFlagRequired("range")
+
+	return cmd
+}
+
+func runAnnotate(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	rangeSpec string,
+	dryRun bool,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for annotate command")
+		return
+	}
+	defer cancel()
+
+	if !dryRun && rangeEnd(rangeSpec) != "HEAD" {
+		log.Fatal().Msg("Rewording rewrites history up to HEAD; use a range ending at HEAD or --dry-run")
+	}
+	commits, err := git.RangeCommits(ctx, rangeSpec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to list commits")
+	}
+	if len(commits) == 0 {
+		fmt.Println("No commits in the range.")
+		return
+	}
+
+	rewrites := make(map[string]string)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tCURRENT\tPROPOSED")
+	// RangeCommits lists the newest commit first; the table reads oldest first,
+	// as the branch was written.
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		proposed, err := proposeMessage(cfg, aiClient, c, true)
+		switch {
+		case err != nil:
+			log.Warn().Err(err).Str("commit", c.ShortHash).Msg("Skipping commit")
+			fmt.Fprintf(w, "%s\t%s\t(failed: %v)\n", c.ShortHash, truncateSubject(c.Subject), err)
+		case proposed == strings.TrimSpace(c.Message):
+			fmt.Fprintf(w, "%s\t%s\t(unchanged)\n", c.ShortHash, truncateSubject(c.Subject))
+		default:
+			rewrites[c.Hash] = proposed
+			fmt.Fprintf(w, "%s\t%s\t%s\n", c.ShortHash, truncateSubject(c.Subject), describeProposal(c.Message, proposed))
+		}
+	}
+	w.Flush()
+
+	if dryRun || len(rewrites) == 0 {
+		return
+	}
+	fmt.Printf("\nRewrite %d commit message(s)? This changes the hashes of those commits and every later one;\n", len(rewrites))
+	fmt.Print("already-pushed branches will need a force push. (y/N): ")
+	var answer string
+	fmt.Scanln(&answer)
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Println("Aborted; history is unchanged.")
+		return
+	}
+	newHead, err := git.RewordCommits(ctx, rewrites)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to rewrite history")
+	}
+	fmt.Printf("Rewrote %d message(s); HEAD is now %s (previous tip saved in ORIG_HEAD).\n", len(rewrites), newHead[:7])
+}
+
+// proposeMessage asks the AI for a new message for commit from its diff. With
+// draft, the current message is treated as a draft whose intent and references
+// are kept; otherwise the message is written from the diff alone. Either way
+// the trailers of the current message, such as Signed-off-by and Change-Id,
+// are carried over.
+func proposeMessage(cfg *config.Config, aiClient ai.AIClient, commit git.CommitInfo, draft bool) (string, error) {
+	reqCtx, cancel := context.WithTimeout(context.Background(), evalRequestTimeout)
+	defer cancel()
+	info, err := git.GetCommitInfo(reqCtx, commit.Hash)
+	if err != nil {
+		return "", err
+	}
+	diff := info.Diff
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diff = summarized
+		}
+	}
+	promptText := prompt.BuildCommitPrompt(diff, languageFlag, "", "", cfg.PromptTemplate, git.SuggestScope(diff))
+	if draft {
+		promptText += prompt.DraftHint(commit.Message)
+	}
+	msg, err := generateCommitMessage(reqCtx, aiClient, promptText, "", "", cfg.EnableEmoji, cfg.TicketPattern)
+	if err != nil {
+		return "", err
+	}
+	return git.KeepTrailers(msg, commit.Message), nil
+}
+
+// describeProposal is the PROPOSED cell: the new subject, noting a rewritten
+// body when the subject alone does not show the change.
+func describeProposal(current, proposed string) string {
+	subject, body, _ := strings.Cut(proposed, "\n")
+	cell := truncateSubject(subject)
+	_, currentBody, _ := strings.Cut(strings.TrimSpace(current), "\n")
+	if body = strings.TrimSpace(body); body != strings.TrimSpace(currentBody) {
+		if body == "" {
+			cell += " (body removed)"
+		} else {
+			cell += fmt.Sprintf(" (+%d body lines)", len(strings.Split(body, "\n")))
+		}
+	}
+	return cell
+}
+
+func truncateSubject(subject string) string {
+	r := []rune(subject)
+	if len(r) <= annotateSubjectWidth {
+		return subject
+	}
+	return string(r[:annotateSubjectWidth-1]) + "…"
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/renatogalera/ai-commit/pkg/anonymize"
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+var (
+	anonymizerOnce sync.Once
+	anonymizer     *anonymize.Anonymizer
+)
+
+// runAnonymizer returns the anonymizer shared by every provider client of the
+// run, so an identifier keeps its pseudonym across prompts, or nil when
+// privacy.anonymize is off.
+func runAnonymizer(cfg *config.Config) *anonymize.Anonymizer {
+	anonymizerOnce.Do(func() {
+		settings := cfg.Privacy.Anonymize
+		if settings.Enabled {
+			anonymizer = anonymize.New(settings.Domains, settings.Usernames)
+		}
+	})
+	return anonymizer
+}
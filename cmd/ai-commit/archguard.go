@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/archguard"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// checkArchitecture runs the configured dependency rules against the staged Go files
+// and, if enabled, asks the AI for conceptual violations. Findings are printed to
+// stderr; it returns true when the commit should be blocked (mode "block").
+func checkArchitecture(ctx context.Context, cfg *config.Config, aiClient ai.AIClient, diff string) bool {
+	settings := cfg.Architecture
+	if len(settings.Rules) == 0 {
+		return false
+	}
+
+	var findings []string
+	if goMod, err := git.GetStagedFile(ctx, "go.mod"); err != nil {
+		log.Warn().Err(err).Msg("Architecture check: cannot read go.mod; skipping import rules")
+	} else if modulePath := archguard.ModulePath(goMod); modulePath != "" {
+		files := make(map[string][]byte)
+		for _, name := range archguard.ChangedGoFiles(diff) {
+			if data, err := git.GetStagedFile(ctx, name); err == nil {
+				files[name] = data
+			}
+		}
+		for _, v := range archguard.Check(files, modulePath, settings.Rules) {
+			findings = append(findings, "- "+v.String())
+		}
+	}
+
+	if settings.AIReview {
+		archPrompt := prompt.BuildArchitectureReviewPrompt(diff, languageFlag, archguard.FormatRules(settings.Rules), archguard.NoViolations)
+		resp, err := aiClient.GetCommitMessage(ctx, archPrompt)
+		if err != nil {
+			log.Warn().Err(err).Msg("Architecture check: AI review failed")
+		} else if archguard.HasAIFindings(resp) {
+			findings = append(findings, "AI review:\n"+strings.TrimSpace(resp))
+		}
+	}
+
+	if len(findings) == 0 {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, formatReviewOutput("Architecture rule violations", strings.Join(findings, "\n")))
+	return settings.Mode == "block"
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/health"
+)
+
+var (
+	breakerOnce sync.Once
+	breaker     *health.Breaker
+)
+
+// providerBreaker returns the per-user provider circuit breaker, or nil when it
+// is disabled or its location cannot be determined.
+func providerBreaker(cfg *config.Config) *health.Breaker {
+	breakerOnce.Do(func() {
+		settings := cfg.CircuitBreaker
+		if settings.Disabled {
+			return
+		}
+		var cooldown time.Duration
+		if raw := strings.TrimSpace(settings.Cooldown); raw != "" {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				log.Warn().Msgf("Invalid circuitBreaker.cooldown %q; using %s", settings.Cooldown, health.DefaultCooldown)
+			} else {
+				cooldown = d
+			}
+		}
+		path, err := health.DefaultPath()
+		if err != nil {
+			log.Debug().Err(err).Msg("Provider circuit breaker disabled")
+			return
+		}
+		breaker = health.NewBreaker(path, settings.Threshold, cooldown)
+	})
+	return breaker
+}
+
+// circuitFallback returns the fallback provider to use while the breaker of
+// provider is open. Without a fallback the provider is tried anyway, since
+// there is nothing else to ask.
+func circuitFallback(cfg *config.Config, provider string) (providerSpec, bool) {
+	b := providerBreaker(cfg)
+	if b == nil || !b.Open(provider) {
+		return providerSpec{}, false
+	}
+	status := b.Status(provider)
+	reason := fmt.Sprintf("%s failed %d requests in a row (last: %s)", provider, status.Failures, status.LastError)
+	fallback, ok := fallbackSpec(cfg)
+	if !ok || fallback.Provider == provider {
+		fmt.Fprintf(os.Stderr, "%s; trying it anyway since no fallback provider is configured.\n", reason)
+		return providerSpec{}, false
+	}
+	fmt.Fprintf(os.Stderr, "%s; using %s until %s.\n", reason, fallback, status.OpenUntil.Format("15:04"))
+	return fallback, true
+}
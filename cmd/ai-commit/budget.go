@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/usage"
+)
+
+var (
+	ledgerOnce sync.Once
+	ledger     *usage.Ledger
+
+	budgetOnce     sync.Once
+	budgetSpec     providerSpec
+	budgetExceeded bool
+	budgetErr      error
+)
+
+// usageLedger returns the per-user usage ledger, or nil if its location cannot be determined.
+func usageLedger() *usage.Ledger {
+	ledgerOnce.Do(func() {
+		path, err := usage.DefaultPath()
+		if err != nil {
+			log.Debug().Err(err).Msg("Usage ledger disabled")
+			return
+		}
+		ledger = usage.NewLedger(path)
+	})
+	return ledger
+}
+
+// budgetFallback checks the configured budget against the usage ledger. When a
+// limit is exceeded it returns the fallback provider to use instead, or an error
+// if no fallback is configured and --override-budget was not given. The ledger is
+// checked once per run, so every provider client gets the same answer.
+func budgetFallback(cfg *config.Config) (providerSpec, bool, error) {
+	budgetOnce.Do(func() {
+		budgetSpec, budgetExceeded, budgetErr = checkBudget(cfg)
+	})
+	return budgetSpec, budgetExceeded, budgetErr
+}
+
+func checkBudget(cfg *config.Config) (providerSpec, bool, error) {
+	if overrideBudgetFlag || !usage.Enabled(cfg.Budget) {
+		return providerSpec{}, false, nil
+	}
+	l := usageLedger()
+	if l == nil {
+		return providerSpec{}, false, nil
+	}
+	now := time.Now()
+	entries, err := l.Since(usage.MonthStart(now))
+	if err != nil {
+		log.Warn().Err(err).Msg("Cannot read usage ledger; budget not enforced")
+		return providerSpec{}, false, nil
+	}
+	exceeded, reason := usage.Exceeded(entries, cfg.Budget, now)
+	if !exceeded {
+		return providerSpec{}, false, nil
+	}
+	if cfg.Budget.FallbackProvider == "" {
+		return providerSpec{}, false, fmt.Errorf("budget exceeded: %s; rerun with --override-budget to continue", reason)
+	}
+	fallback := providerSpec{Provider: cfg.Budget.FallbackProvider, Model: cfg.Budget.FallbackModel}
+	fmt.Fprintf(os.Stderr, "Budget exceeded (%s); using %s instead. Pass --override-budget to use the configured provider.\n", reason, fallback)
+	return fallback, true, nil
+}
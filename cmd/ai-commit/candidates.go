@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/guard"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// maxCandidates bounds --candidates; longer replies rarely add useful variety.
+const maxCandidates = 10
+
+// candidateCount returns --candidates or, when it is not given, the candidates
+// config value. Values below 2 mean a single message.
+func candidateCount(cfg *config.Config) (int, error) {
+	n := candidatesFlag
+	if n == 0 {
+		n = cfg.Candidates
+	}
+	if n < 0 || n > maxCandidates {
+		return 0, fmt.Errorf("candidates must be between 0 and %d, got %d", maxCandidates, n)
+	}
+	return n, nil
+}
+
+// generateCandidates asks client for n alternative commit messages in one
+// request and finishes each like generateCommitMessage.
+func generateCandidates(
+	ctx context.Context,
+	client ai.AIClient,
+	promptText string,
+	n int,
+	commitType string,
+	tmpl string,
+	enableEmoji bool,
+	ticketPattern string,
+) ([]string, error) {
+	return prompt.GenerateCandidates(ctx, client, promptText, n, func(reply string) (string, error) {
+		return finishCommitMessage(client, reply, commitType, tmpl, enableEmoji, ticketPattern)
+	})
+}
+
+// guardCandidates drops the candidates that violate the guardrails. When all
+// do, the first is kept so that enforceGuardrails regenerates it.
+func guardCandidates(cfg *config.Config, choices []string) []string {
+	scanner := guard.NewScanner(cfg.Guardrails)
+	var clean []string
+	for _, msg := range choices {
+		if len(scanner.Scan(msg)) == 0 {
+			clean = append(clean, msg)
+		}
+	}
+	if len(clean) == 0 {
+		return choices[:1]
+	}
+	if dropped := len(choices) - len(clean); dropped > 0 {
+		fmt.Fprintf(os.Stderr, "Dropped %d candidate(s) blocked by guardrails.\n", dropped)
+	}
+	return clean
+}
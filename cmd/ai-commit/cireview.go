@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/github"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+func newCIReviewCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var patchFlag, eventFlag string
+	var noCommentFlag, failOnFindingsFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "ci-review",
+		Short: "Review a pull request in CI and post the findings as review comments",
+		Long: "Reads the pull request from the GitHub Actions event payload ($GITHUB_EVENT_PATH), fetches its diff " +
+			"(or reads --patch), and asks the AI for a review. Findings on lines of the diff are posted as line comments " +
+			"of a pull request review through the GitHub API ($GITHUB_TOKEN); the rest go into the review body. " +
+			"The step outputs \"findings\" (count), \"review\" (markdown), and \"review-url\" are written to $GITHUB_OUTPUT.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runCIReview(setupAIEnvironment, patchFlag, eventFlag, !noCommentFlag, failOnFindingsFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "AI provider: openai, google, anthropic, deepseek, ollama, openrouter")
+	cmd.Flags().StringVar(&modelFlag, "model", "", "Sub-model for the chosen provider")
+	cmd.Flags().StringVar(&patchFlag, "patch", "", "Review this .patch/.diff file instead of fetching the pull request diff")
+	cmd.Flags().StringVar(&eventFlag, "event", os.Getenv("GITHUB_EVENT_PATH"), "Workflow event payload naming the pull request")
+	cmd.Flags().BoolVar(&noCommentFlag, "no-comment", false, "Print the review and set outputs without posting to the pull request")
+	cmd.Flags().BoolVar(&failOnFindingsFlag, "fail-on-findings", false, "Exit with status 1 when the review has findings")
+
+	return cmd
+}
+
+func runCIReview(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	patchPath string,
+	eventPath string,
+	post bool,
+	failOnFindings bool,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for ci-review command")
+		return
+	}
+	defer cancel()
+
+	var pr *github.PullRequest
+	if strings.TrimSpace(eventPath) != "" {
+		pr, err = github.LoadPullRequest(eventPath, os.Getenv("GITHUB_REPOSITORY"))
+		if err != nil && patchPath == "" {
+			log.Fatal().Err(err).Msg("Cannot determine the pull request")
+		}
+	}
+	client := github.NewClient(os.Getenv("GITHUB_TOKEN"), os.Getenv("GITHUB_API_URL"))
+
+	var diff string
+	switch {
+	case patchPath != "":
+		data, err := os.ReadFile(patchPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to read patch")
+		}
+		diff = string(data)
+	case pr != nil:
+		if diff, err = client.PullRequestDiff(ctx, *pr); err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch pull request diff")
+		}
+	default:
+		log.Fatal().Msg("Nothing to review: run in a pull_request workflow or pass --patch")
+	}
+
+	// The full diff is kept for placing comments; the prompt gets the filtered one.
+	promptDiff := git.PromptDiff(diff, cfg.LockFiles)
+	if strings.TrimSpace(promptDiff) == "" {
+		fmt.Println("No reviewable changes (after filtering lock files).")
+		return
+	}
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(promptDiff, cfg.Limits.Diff.MaxChars); did {
+			promptDiff = summarized
+		}
+	}
+	resp, err := aiClient.GetCommitMessage(ctx, prompt.BuildCIReviewPrompt(promptDiff, languageFlag, review.NoFindings))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Code review generation error")
+	}
+
+	findings := review.ParseFindings(resp)
+	body, comments := github.BuildReview(findings, diff)
+	var out strings.Builder
+	for _, f := range findings {
+		out.WriteString("- " + f.String() + "\n")
+	}
+	if len(findings) == 0 {
+		out.WriteString(review.NoFindings)
+	}
+	fmt.Println(formatReviewOutput("AI Code Review Findings", withSource(strings.TrimSpace(out.String()), report.SourceOf(aiClient))))
+
+	var reviewURL string
+	switch {
+	case !post:
+	case pr == nil:
+		log.Warn().Msg("No pull request in the event payload; the review is not posted")
+	case os.Getenv("GITHUB_TOKEN") == "":
+		log.Warn().Msg("GITHUB_TOKEN is not set; the review is not posted")
+	default:
+		if reviewURL, err = client.CreateReview(ctx, *pr, body, comments); err != nil {
+			log.Fatal().Err(err).Msg("Failed to post the review")
+		}
+		fmt.Println("Review posted: " + reviewURL)
+	}
+
+	if outputPath := os.Getenv("GITHUB_OUTPUT"); outputPath != "" {
+		outputs := map[string]string{
+			"findings":   strconv.Itoa(len(findings)),
+			"review":     strings.TrimSpace(out.String()),
+			"review-url": reviewURL,
+		}
+		if err := github.SetOutputs(outputPath, outputs); err != nil {
+			log.Fatal().Err(err).Msg("Failed to set step outputs")
+		}
+	}
+	if failOnFindings && len(findings) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// loadCommitTemplate reads the repository's commit.template once per run. A
+// template that cannot be read is ignored with a warning.
+var loadCommitTemplate = sync.OnceValue(func() git.CommitTemplate {
+	tmpl, err := git.LoadCommitTemplate(context.Background())
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring commit.template")
+	}
+	return tmpl
+})
+
+// commitTemplate returns the repository's commit.template, or the zero
+// CommitTemplate when there is none or ignoreCommitTemplate is set.
+func commitTemplate(cfg *config.Config) git.CommitTemplate {
+	if cfg.IgnoreCommitTemplate {
+		return git.CommitTemplate{}
+	}
+	return loadCommitTemplate()
+}
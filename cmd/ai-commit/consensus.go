@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/template"
+)
+
+// providerSpec is one "provider[:model]" entry of --consensus or --judge.
+type providerSpec struct {
+	Provider string
+	Model    string
+}
+
+func (s providerSpec) String() string {
+	if s.Model == "" {
+		return s.Provider
+	}
+	return s.Provider + ":" + s.Model
+}
+
+func parseProviderSpec(raw string) providerSpec {
+	name, model, _ := strings.Cut(strings.TrimSpace(raw), ":")
+	return providerSpec{Provider: strings.TrimSpace(name), Model: strings.TrimSpace(model)}
+}
+
+func parseProviderSpecs(raw string) []providerSpec {
+	var specs []providerSpec
+	for _, part := range strings.Split(raw, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		specs = append(specs, parseProviderSpec(part))
+	}
+	return specs
+}
+
+type consensusResult struct {
+	Spec    providerSpec
+	Message string
+	Err     error
+}
+
+// runConsensus asks every provider in specs for a message in parallel, shows the
+// candidates side by side, and returns either the judge's merged message or the
+// candidate picked by the user (the first successful one in non-interactive modes).
+// The --template is applied once, to that message, so the candidates and the
+// judge's prompt carry no ticket or footer of their own.
+func runConsensus(
+	ctx context.Context,
+	cfg *config.Config,
+	specs []providerSpec,
+	judge string,
+	promptText string,
+	diff string,
+	commitType string,
+	interactive bool,
+) (string, error) {
+	msg, err := selectConsensus(ctx, cfg, specs, judge, promptText, diff, commitType, interactive)
+	if err != nil || templateFlag == "" {
+		return msg, err
+	}
+	msg, err = template.ApplyTemplate(templateFlag, msg, cfg.TicketPattern)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(msg), nil
+}
+
+// selectConsensus returns the untemplated message runConsensus settles on.
+func selectConsensus(
+	ctx context.Context,
+	cfg *config.Config,
+	specs []providerSpec,
+	judge string,
+	promptText string,
+	diff string,
+	commitType string,
+	interactive bool,
+) (string, error) {
+	if len(specs) < 2 {
+		return "", fmt.Errorf("--consensus needs at least two providers, got %d", len(specs))
+	}
+
+	results := make([]consensusResult, len(specs))
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		results[i].Spec = spec
+		client, err := newProviderClient(ctx, cfg, spec.Provider, spec.Model, false)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		wg.Add(1)
+		go func(i int, client ai.AIClient) {
+			defer wg.Done()
+			results[i].Message, results[i].Err = generateCommitMessage(ctx, client, promptText, commitType, "", cfg.EnableEmoji, cfg.TicketPattern)
+		}(i, client)
+	}
+	wg.Wait()
+
+	var candidates []prompt.Candidate
+	for _, r := range results {
+		if r.Err == nil && strings.TrimSpace(r.Message) != "" {
+			candidates = append(candidates, prompt.Candidate{Source: r.Spec.String(), Message: r.Message})
+		}
+	}
+	if !quietFlag {
+		fmt.Fprintln(os.Stderr, renderConsensus(results))
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("all consensus providers failed")
+	}
+
+	if strings.TrimSpace(judge) != "" && len(candidates) > 1 {
+		spec := parseProviderSpec(judge)
+		client, err := newProviderClient(ctx, cfg, spec.Provider, spec.Model, false)
+		if err != nil {
+			return "", fmt.Errorf("judge provider: %w", err)
+		}
+		merged, err := client.GetCommitMessage(ctx, prompt.BuildConsensusPrompt(diff, languageFlag, candidates))
+		if err != nil {
+			return "", fmt.Errorf("judge provider: %w", err)
+		}
+		judgeType := commitType
+		if judgeType == "" {
+			judgeType = committypes.GuessCommitType(merged)
+		}
+		merged = client.SanitizeResponse(merged, "")
+		merged, err = finalizeCommitMessage(merged, judgeType, "", cfg.EnableEmoji, cfg.TicketPattern)
+		if err != nil {
+			return "", err
+		}
+		if err := ai.ValidateCommitMessage(merged); err != nil {
+			return "", fmt.Errorf("judge provider: rejected output: %w", err)
+		}
+		if warning := ai.MessageWarning(merged); warning != "" {
+			log.Warn().Msgf("Check the judge's output: %s", warning)
+		}
+		return merged, nil
+	}
+
+	if !interactive || len(candidates) == 1 {
+		return candidates[0].Message, nil
+	}
+	fmt.Fprintf(os.Stderr, "Pick a candidate [1-%d] (default 1): ", len(candidates))
+	var answer string
+	fmt.Scanln(&answer)
+	if n, err := strconv.Atoi(strings.TrimSpace(answer)); err == nil && n >= 1 && n <= len(candidates) {
+		return candidates[n-1].Message, nil
+	}
+	return candidates[0].Message, nil
+}
+
+// renderConsensus lays the candidates out in columns, numbering the successful ones.
+func renderConsensus(results []consensusResult) string {
+	const columnWidth = 44
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(columnWidth)
+	errStyle := boxStyle.BorderForeground(lipgloss.Color("196"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+	var boxes []string
+	n := 0
+	for _, r := range results {
+		if r.Err != nil || strings.TrimSpace(r.Message) == "" {
+			reason := "empty response"
+			if r.Err != nil {
+				reason = r.Err.Error()
+			}
+			boxes = append(boxes, errStyle.Render(titleStyle.Render(r.Spec.String())+"\n\nfailed: "+reason))
+			continue
+		}
+		n++
+		boxes = append(boxes, boxStyle.Render(titleStyle.Render(fmt.Sprintf("[%d] %s", n, r.Spec))+"\n\n"+r.Message))
+	}
+	if len(boxes) > 3 {
+		return lipgloss.JoinVertical(lipgloss.Left, boxes...)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, boxes...)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// repoContextFile, at the top of the work tree, holds notes (design decisions,
+// ADR excerpts) added to every commit prompt of the repository.
+const repoContextFile = ".ai-commit-context.md"
+
+// loadContextFiles reads .ai-commit-context.md and --context-file once per run
+// and joins them, the repository's notes first, fenced as untrusted content
+// since anyone who can commit can edit them. A repository file that cannot be
+// read is ignored with a warning; a --context-file that cannot be read is an
+// error.
+var loadContextFiles = sync.OnceValues(func() (string, error) {
+	var parts []string
+	data, err := git.ReadWorktreeFile(repoContextFile)
+	switch {
+	case err == nil:
+		parts = append(parts, strings.TrimSpace(string(data)))
+	case !errors.Is(err, os.ErrNotExist) && !errors.Is(err, git.ErrNotARepo):
+		log.Warn().Err(err).Msgf("Ignoring %s", repoContextFile)
+	}
+	if contextFileFlag != "" {
+		data, err := os.ReadFile(contextFileFlag)
+		if err != nil {
+			return "", fmt.Errorf("cannot read --context-file: %w", err)
+		}
+		parts = append(parts, strings.TrimSpace(string(data)))
+	}
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return "", nil
+	}
+	text := strings.Join(nonEmpty, "\n\n")
+	verbosef("additional context: %d chars", len(text))
+	return prompt.FenceUntrusted(text), nil
+})
+
+// additionalContext returns the context files' text for the
+// {ADDITIONAL_CONTEXT} section of the commit prompt ("" when there are none).
+func additionalContext() string {
+	text, err := loadContextFiles()
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring the additional context")
+	}
+	return text
+}
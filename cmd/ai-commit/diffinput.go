@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// diffInputPath returns where --diff or --diff-file reads the diff from ("-"
+// for stdin), or "" when the diff comes from the repository's staged changes.
+func diffInputPath() string {
+	if diffFileFlag != "" {
+		return diffFileFlag
+	}
+	return diffFlag
+}
+
+// refRange returns the refs of --against (to is "", the working tree) or
+// --between, and whether either flag was given.
+func refRange() (from, to string, ok bool) {
+	switch {
+	case againstFlag != "":
+		return againstFlag, "", true
+	case len(betweenFlag) == 1 && strings.Contains(betweenFlag[0], ".."):
+		from, to, _ = strings.Cut(betweenFlag[0], "..")
+		return from, to, true
+	case len(betweenFlag) > 0:
+		from = betweenFlag[0]
+		if len(betweenFlag) > 1 {
+			to = betweenFlag[1]
+		}
+		return from, to, true
+	}
+	return "", "", false
+}
+
+// checkDiffSourceFlags validates --diff/--diff-file and --against/--between,
+// and rejects flags that commit or tag, since the message then describes
+// something other than the index.
+func checkDiffSourceFlags() error {
+	from, to, refs := refRange()
+	if !refs && diffInputPath() == "" {
+		return nil
+	}
+	if refs && len(betweenFlag) > 0 && (len(betweenFlag) > 2 || from == "" || to == "") {
+		return fmt.Errorf("--between needs two refs, e.g. --between v1.0.0,v1.1.0 or --between v1.0.0..v1.1.0")
+	}
+	repoFlags := []struct {
+		name string
+		set  bool
+	}{
+		{"--force", forceFlag},
+		{"--edit", editFlag},
+		{"--all", allFlag},
+		{"--semantic-release", semanticReleaseFlag},
+		{"--interactive-split", interactiveSplitFlag},
+		{"--auto-split", autoSplitFlag},
+		{"--allow-empty", allowEmptyFlag},
+		{"--amend", amendFlag},
+	}
+	for _, f := range repoFlags {
+		if f.set {
+			return fmt.Errorf("%s commits the index and cannot be combined with --diff, --diff-file, --against, or --between", f.name)
+		}
+	}
+	return nil
+}
+
+// readDiffInput reads the unified diff at path, or from stdin when path is "-".
+func readDiffInput(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the diff from stdin: %w", err)
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the diff: %w", err)
+	}
+	return string(data), nil
+}
+
+// runDiffInput generates a commit message for a diff given on stdin or in a file
+// and prints it, without opening the repository or committing.
+func runDiffInput(ctx context.Context, cfg *config.Config, client ai.AIClient, path string) {
+	diff, err := readDiffInput(path)
+	if err != nil {
+		exitWith(exitFailure, err, "Cannot read the diff")
+	}
+	printMessageForDiff(ctx, cfg, client, git.PromptDiff(diff, cfg.LockFiles), path)
+}
+
+// runRefDiff generates a commit message for the changes between two refs (or a
+// ref and the working tree) and prints it without committing.
+func runRefDiff(ctx context.Context, cfg *config.Config, client ai.AIClient, from, to string) {
+	diff, _, err := git.GetRefPromptDiff(ctx, from, to, cfg.LockFiles)
+	if err != nil {
+		exitWith(exitConfig, err, "Cannot diff the refs")
+	}
+	if to == "" {
+		to = "working tree"
+	}
+	printMessageForDiff(ctx, cfg, client, diff, from+".."+to)
+}
+
+// printMessageForDiff generates a commit message for diff, read from source,
+// and prints it.
+func printMessageForDiff(ctx context.Context, cfg *config.Config, client ai.AIClient, diff, source string) {
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		diff, _ = client.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars)
+	}
+	if strings.TrimSpace(diff) == "" {
+		if !quietFlag {
+			fmt.Println("The diff has no changes.")
+		}
+		os.Exit(exitNoChanges)
+	}
+
+	commitType := commitTypeFlag
+	categoryType := ""
+	if commitType == "" && !cfg.TypeRules.Disabled {
+		if t, ok := git.CategoryCommitType(diff, cfg.TypeRules.Rules); ok {
+			if cfg.TypeRules.Mode == "override" {
+				commitType = t
+			} else {
+				categoryType = t
+			}
+		}
+	}
+	promptText := prompt.WithIntent(prompt.BuildCommitPrompt(diff, languageFlag, commitType, additionalContext(), cfg.PromptTemplate, git.SuggestScope(diff)), intentFlag)
+	if categoryType != "" {
+		promptText += prompt.TypeRuleHint(categoryType)
+	}
+	if skeleton := commitTemplate(cfg).Skeleton; skeleton != "" {
+		promptText += prompt.CommitTemplateHint(skeleton)
+	}
+	if limit := cfg.Limits.Prompt.MaxChars; cfg.Limits.Prompt.Enabled && limit > 3 && len(promptText) > limit {
+		promptText = promptText[:limit-3] + "..."
+	}
+	verbosef("diff: %d chars from %s, prompt: %d chars", len(diff), source, len(promptText))
+
+	start := time.Now()
+	msg, err := generateCommitMessage(ctx, client, promptText, commitType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+	if err != nil {
+		exitWith(providerExitCode(err), err, "Commit message generation error")
+	}
+	verbosef("generated in %s (%s)", time.Since(start).Round(time.Millisecond), describeLastCall(client))
+	if msg, err = enforceGuardrails(ctx, cfg, client, promptText, commitType, msg); err != nil {
+		exitWith(providerExitCode(err), err, "Commit message guardrails")
+	}
+	footers, err := parseFooterFlags()
+	if err != nil {
+		exitWith(exitConfig, err, "Invalid footer")
+	}
+	msg = git.ApplyFooters(msg, footers)
+	if jsonOutputFlag {
+		writeCommitResult(client, msg, "", time.Since(start))
+		return
+	}
+	fmt.Println(msg)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/duplicate"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+const (
+	defaultDuplicateCommits   = 50
+	defaultDuplicateThreshold = 0.9
+)
+
+// warnDuplicateWork embeds the staged diff and warns on stderr when it closely
+// resembles one of the recent commits. Any failure is logged and otherwise ignored:
+// the check is advisory and must never block a commit.
+func warnDuplicateWork(ctx context.Context, cfg *config.Config, aiClient ai.AIClient, diff string) {
+	settings := cfg.DuplicateCheck
+	n := settings.Commits
+	if n <= 0 {
+		n = defaultDuplicateCommits
+	}
+	threshold := settings.Threshold
+	if threshold <= 0 {
+		threshold = defaultDuplicateThreshold
+	}
+
+	client := aiClient
+	if strings.TrimSpace(settings.Provider) != "" {
+		var err error
+		client, err = newProviderClient(ctx, cfg, settings.Provider, "", false)
+		if err != nil {
+			log.Warn().Err(err).Msg("Duplicate check: failed to initialize embedding provider")
+			return
+		}
+	}
+	embedder, ok := client.(ai.EmbeddingAIClient)
+	if !ok {
+		log.Warn().Str("provider", client.ProviderName()).Msg("Duplicate check: provider does not support embeddings")
+		return
+	}
+
+	matches, err := findDuplicateWork(ctx, embedder, client.ProviderName()+":"+settings.Model, settings.Model, diff, n, threshold)
+	if err != nil {
+		log.Warn().Err(err).Msg("Duplicate check failed")
+		return
+	}
+	now := time.Now()
+	for _, m := range matches {
+		fmt.Fprintf(os.Stderr, "Warning: these changes %s\n", duplicate.Describe(m, now))
+	}
+}
+
+// findDuplicateWork embeds the staged diff together with any recent commits missing
+// from the cache, in a single request, and returns the commits above threshold.
+func findDuplicateWork(
+	ctx context.Context,
+	embedder ai.EmbeddingAIClient,
+	cacheKey string,
+	model string,
+	diff string,
+	n int,
+	threshold float64,
+) ([]duplicate.Match, error) {
+	stagedText := duplicate.Text(diff)
+	if stagedText == "" {
+		return nil, nil
+	}
+	commits, err := git.RecentCommits(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	path, err := duplicate.CachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := duplicate.LoadCache(path, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := []string{stagedText}
+	var missing []git.CommitInfo
+	for _, c := range commits {
+		if _, ok := cache.Entries[c.Hash]; ok {
+			continue
+		}
+		if text := duplicate.Text(c.Diff); text != "" {
+			missing = append(missing, c)
+			texts = append(texts, text)
+		}
+	}
+
+	vectors, err := embedder.Embed(ctx, model, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range missing {
+		cache.Entries[c.Hash] = duplicate.Entry{Hash: c.Hash, Subject: c.Subject, Date: c.Date, Vector: vectors[i+1]}
+	}
+
+	hashes := make([]string, 0, len(commits))
+	entries := make([]duplicate.Entry, 0, len(commits))
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+		if e, ok := cache.Entries[c.Hash]; ok {
+			entries = append(entries, e)
+		}
+	}
+	if err := cache.Save(path, hashes); err != nil {
+		log.Warn().Err(err).Msg("Duplicate check: failed to update embedding cache")
+	}
+	return duplicate.FindSimilar(vectors[0], entries, threshold), nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// checkEditFlags rejects flags that skip the editor --edit opens.
+func checkEditFlags() error {
+	if !editFlag {
+		return nil
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--force", forceFlag},
+		{"--msg-only", msgOnlyFlag},
+		{"--interactive-split", interactiveSplitFlag},
+		{"--auto-split", autoSplitFlag},
+	} {
+		if f.set {
+			return fmt.Errorf("%s cannot be combined with --edit", f.name)
+		}
+	}
+	return nil
+}
+
+// editCommitMessage opens msg in the commit message editor for --edit and
+// returns the edited message, exiting as git does when it was emptied.
+func editCommitMessage(ctx context.Context, msg string) string {
+	edited, err := git.EditMessage(ctx, msg)
+	if err != nil {
+		exitWith(exitFailure, err, "Cannot edit the commit message")
+	}
+	if edited == "" {
+		exitWith(exitFailure, errors.New("empty commit message"), "Aborting commit")
+	}
+	return edited
+}
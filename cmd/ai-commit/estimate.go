@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/provider/registry"
+	"github.com/renatogalera/ai-commit/pkg/usage"
+)
+
+// estimateOutputTokens is the assumed length of a generated commit message: a
+// subject and a short body.
+const estimateOutputTokens = 100
+
+func newEstimateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate the size and cost of generating a message for the staged changes",
+		Long: "Builds the prompt for the staged changes as a commit would, without calling the AI, and reports the staged diff size, " +
+			"what the filters and size limits would remove, the estimated tokens, and the estimated cost for each configured provider " +
+			"(from budget.prices).",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runEstimate()
+		},
+	}
+}
+
+func runEstimate() {
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for estimate command")
+	}
+	commentFilter := cfg.CommentFilter
+	if noCommentFilterFlag {
+		commentFilter.Disabled = true
+	}
+	git.ConfigureCommentFilter(commentFilter)
+	prompt.ConfigureLanguageHints(cfg.LanguageHints)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if !git.IsGitRepository(ctx) {
+		log.Fatal().Err(git.ErrNotARepo).Msg("Cannot estimate")
+	}
+
+	raw, err := commitRawDiff(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get the staged diff")
+	}
+	if strings.TrimSpace(raw) == "" {
+		fmt.Println("No staged changes.")
+		os.Exit(exitNoChanges)
+	}
+	diff, filterReport, err := commitPromptDiff(ctx, cfg.LockFiles)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get Git diff (ignoring moves)")
+	}
+
+	var notes []string
+	commitType := ""
+	if strings.TrimSpace(diff) == "" {
+		// A commit falls back to the unfiltered diff, as in runAICommit.
+		commitType = git.FallbackCommitType(raw, cfg.LockFiles)
+		diff, filterReport = raw, &git.FilterReport{}
+		notes = append(notes, "Every change is filtered, so the unfiltered diff would be sent.")
+	}
+	if renames, ok := git.ParseRenameOnlyDiff(diff); ok {
+		fmt.Printf("The staged changes only rename %d file(s); the message is built without an AI request.\n", len(renames))
+		return
+	}
+
+	diffLimit := "off"
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		var base ai.BaseAIClient
+		if truncated, did := base.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diffLimit = fmt.Sprintf("%d chars: the %d-char diff would be truncated", cfg.Limits.Diff.MaxChars, len(diff))
+			filterReport.AddTruncation(len(diff), len(truncated))
+			diff = truncated
+		} else {
+			diffLimit = fmt.Sprintf("%d chars: not reached", cfg.Limits.Diff.MaxChars)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Staged diff\t%d chars, %d file(s)\n", len(raw), strings.Count("\n"+raw, "\ndiff --git "))
+	filtered := "nothing"
+	if !filterReport.Empty() {
+		filtered = fmt.Sprintf("%d item(s); see --show-filtered", len(filterReport.Items))
+	}
+	fmt.Fprintf(w, "Filtered\t%s\n", filtered)
+	fmt.Fprintf(w, "Prompt diff\t%d chars\n", len(diff))
+	fmt.Fprintf(w, "Diff limit\t%s\n", diffLimit)
+
+	commitType, categoryType := ruleCommitType(cfg, diff, commitType)
+	promptText := commitPrompt(cfg, diff, commitType, categoryType, git.SuggestScope(diff)) + relatedHint(ctx, cfg, diff)
+	promptLimit := "off"
+	if cfg.Limits.Prompt.Enabled && cfg.Limits.Prompt.MaxChars > 0 {
+		if len(promptText) > cfg.Limits.Prompt.MaxChars {
+			promptLimit = fmt.Sprintf("%d chars: the %d-char prompt would be cut", cfg.Limits.Prompt.MaxChars, len(promptText))
+			promptText = promptText[:cfg.Limits.Prompt.MaxChars]
+		} else {
+			promptLimit = fmt.Sprintf("%d chars: not reached", cfg.Limits.Prompt.MaxChars)
+		}
+	}
+	inputTokens := usage.EstimateTokens(promptText)
+	fmt.Fprintf(w, "Prompt\t%d chars, ~%d tokens\n", len(promptText), inputTokens)
+	fmt.Fprintf(w, "Prompt limit\t%s\n", promptLimit)
+	w.Flush()
+	for _, note := range notes {
+		fmt.Println(note)
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tMODEL\tINPUT TOKENS\tOUTPUT TOKENS\tCOST")
+	for _, spec := range estimateProviders(cfg) {
+		cost := "no price in budget.prices"
+		if _, ok := usage.PriceFor(cfg.Budget.Prices, spec.Provider, spec.Model); ok {
+			cost = fmt.Sprintf("$%.4f", usage.EstimateCost(cfg.Budget.Prices, spec.Provider, spec.Model, inputTokens, estimateOutputTokens))
+		}
+		fmt.Fprintf(w, "%s\t%s\t~%d\t~%d\t%s\n", spec.Provider, spec.Model, inputTokens, estimateOutputTokens, cost)
+	}
+	w.Flush()
+}
+
+// estimateProviders lists the default provider, then the others configured
+// under providers, each with the model it would use.
+func estimateProviders(cfg *config.Config) []providerSpec {
+	names := []string{cfg.Provider}
+	var others []string
+	for name := range cfg.Providers {
+		if name != cfg.Provider && registry.Has(name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	var specs []providerSpec
+	for _, name := range append(names, others...) {
+		model := cfg.GetProviderSettings(name).Model
+		if def, ok := registry.GetDefaults(name); ok && model == "" {
+			model = def.Model
+		}
+		specs = append(specs, providerSpec{Provider: name, Model: model})
+	}
+	return specs
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/eval"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// evalRequestTimeout bounds each generation or grading call; the setup context is
+// too short for replaying dozens of commits.
+const evalRequestTimeout = 60 * time.Second
+
+// evalProfile is one provider and prompt template combination being evaluated.
+type evalProfile struct {
+	Name     string
+	Client   ai.AIClient
+	Template string
+}
+
+func newEvalCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var commitsFlag int
+	var evalProvidersFlag string
+	var evalTemplatesFlag string
+	var gradeFlag string
+
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Replay past commits through prompt profiles and score the results",
+		Long:  "Regenerates messages for recent commits with each provider/prompt template combination, compares them to the messages actually written, and prints a scoreboard to guide prompt tuning.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runEvalCommand(setupAIEnvironment, commitsFlag, evalProvidersFlag, evalTemplatesFlag, gradeFlag)
+		},
+	}
+
+	cmd.Flags().IntVar(&commitsFlag, "commits", 20, "Number of recent non-merge commits to replay")
+	cmd.Flags().StringVar(&evalProvidersFlag, "providers", "", "Comma-separated provider[:model] list to evaluate (default: the configured provider)")
+	cmd.Flags().StringVar(&evalTemplatesFlag, "templates", "", "Comma-separated prompt template files to evaluate (default: the configured promptTemplate)")
+	cmd.Flags().StringVar(&gradeFlag, "grade", "", "provider[:model] that grades each generated message from 1 to 10")
+
+	return cmd
+}
+
+func runEvalCommand(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	n int,
+	providers string,
+	templates string,
+	grader string,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for eval command")
+		return
+	}
+	defer cancel()
+
+	if n <= 0 {
+		log.Fatal().Msg("--commits must be greater than zero")
+	}
+
+	profiles, err := buildEvalProfiles(ctx, cfg, aiClient, providers, templates)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to prepare eval profiles")
+	}
+
+	var gradeClient ai.AIClient
+	if strings.TrimSpace(grader) != "" {
+		spec := parseProviderSpec(grader)
+		gradeClient, err = newProviderClient(ctx, cfg, spec.Provider, spec.Model, false)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize grading provider")
+		}
+	}
+
+	commits, err := git.RecentCommits(ctx, n)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read commit history")
+	}
+	if len(commits) == 0 {
+		fmt.Println("No commits to evaluate.")
+		return
+	}
+
+	var results []eval.Result
+	for i, c := range commits {
+		diff := c.Diff
+		if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+			if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+				diff = summarized
+			}
+		}
+		if strings.TrimSpace(diff) == "" {
+			continue
+		}
+		actual := strings.TrimSpace(c.Subject + "\n\n" + c.Body)
+		for _, p := range profiles {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s %s\n", i+1, len(commits), c.ShortHash, p.Name)
+			results = append(results, evalCommit(p, gradeClient, diff, actual, c.ShortHash, cfg))
+		}
+	}
+
+	fmt.Println(eval.FormatScoreboard(eval.Summarize(results)))
+}
+
+// evalCommit generates a message for one commit with one profile and scores it.
+func evalCommit(p evalProfile, gradeClient ai.AIClient, diff, actual, hash string, cfg *config.Config) eval.Result {
+	result := eval.Result{Profile: p.Name, Commit: hash}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), evalRequestTimeout)
+	defer cancel()
+
+	promptText := prompt.BuildCommitPrompt(diff, languageFlag, "", "", p.Template, git.SuggestScope(diff))
+	generated, err := generateCommitMessage(reqCtx, p.Client, promptText, "", "", cfg.EnableEmoji, cfg.TicketPattern)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Score = eval.Compare(generated, actual)
+
+	if gradeClient != nil {
+		gradeCtx, gradeCancel := context.WithTimeout(context.Background(), evalRequestTimeout)
+		defer gradeCancel()
+		if resp, err := gradeClient.GetCommitMessage(gradeCtx, prompt.BuildEvalGradePrompt(diff, actual, generated)); err == nil {
+			result.Score.Grade = eval.ParseGrade(resp)
+		} else {
+			log.Warn().Err(err).Str("commit", hash).Msg("Grading failed")
+		}
+	}
+	return result
+}
+
+// buildEvalProfiles combines every requested provider with every requested template.
+func buildEvalProfiles(ctx context.Context, cfg *config.Config, defaultClient ai.AIClient, providers, templates string) ([]evalProfile, error) {
+	type namedTemplate struct {
+		Name    string
+		Content string
+	}
+	tmpls := []namedTemplate{{Name: "default", Content: cfg.PromptTemplate}}
+	if strings.TrimSpace(templates) != "" {
+		tmpls = nil
+		for _, path := range strings.Split(templates, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read prompt template %s: %w", path, err)
+			}
+			tmpls = append(tmpls, namedTemplate{Name: filepath.Base(path), Content: string(data)})
+		}
+	}
+
+	type namedClient struct {
+		Name   string
+		Client ai.AIClient
+	}
+	defaultProvider := cfg.Provider
+	if providerFlag != "" {
+		defaultProvider = providerFlag
+	}
+	clients := []namedClient{{Name: providerSpec{Provider: defaultProvider, Model: modelFlag}.String(), Client: defaultClient}}
+	if specs := parseProviderSpecs(providers); len(specs) > 0 {
+		clients = nil
+		for _, spec := range specs {
+			client, err := newProviderClient(ctx, cfg, spec.Provider, spec.Model, false)
+			if err != nil {
+				return nil, fmt.Errorf("provider %s: %w", spec, err)
+			}
+			clients = append(clients, namedClient{Name: spec.String(), Client: client})
+		}
+	}
+
+	var profiles []evalProfile
+	for _, c := range clients {
+		for _, t := range tmpls {
+			profiles = append(profiles, evalProfile{Name: c.Name + " / " + t.Name, Client: c.Client, Template: t.Content})
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+var (
+	eventsOnce sync.Once
+	eventsBus  *events.Bus
+)
+
+// eventBus returns the event bus of this run, with the usage ledger, the
+// notifier and webhook configured under notify, and the share snippets
+// subscribed.
+func eventBus(cfg *config.Config) *events.Bus {
+	eventsOnce.Do(func() {
+		eventsBus = events.New()
+		eventsBus.Subscribe(func(e events.Event) {
+			log.Debug().Str("event", e.Name()).Msg("Event published")
+		})
+		if l := usageLedger(); l != nil {
+			l.Subscribe(eventsBus, cfg.Budget.Prices)
+		}
+		newNotifier(cfg).Subscribe(eventsBus)
+		newWebhook(cfg).Subscribe(eventsBus)
+		pluginRunner(cfg).Subscribe(eventsBus)
+		subscribeShare(cfg, eventsBus)
+		subscribeRepro(cfg, eventsBus)
+	})
+	return eventsBus
+}
+
+// publishCommit announces the commit just created with msg at HEAD.
+// interactive is set when the user confirmed the commit.
+func publishCommit(ctx context.Context, cfg *config.Config, msg string, elapsed time.Duration, interactive bool) {
+	hash, err := git.GetHeadCommitHash(ctx)
+	if err != nil {
+		log.Debug().Err(err).Msg("Cannot read the new commit")
+	}
+	eventBus(cfg).Publish(events.CommitCreated{Hash: hash, Message: msg, Elapsed: elapsed, Interactive: interactive})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// Exit codes of the commit flow, documented in the README so wrapper scripts
+// and hooks can branch on the cause of a failure.
+const (
+	exitFailure         = 1 // any failure not listed below
+	exitConfig          = 2 // invalid config, flags, or provider setup; not a repository
+	exitNoChanges       = 3 // nothing staged to commit
+	exitProviderAuth    = 4 // the provider rejected the API key
+	exitProviderTimeout = 5 // the provider did not answer in time
+	exitCommitFailed    = 6 // git refused to create the commit
+	exitRateLimited     = 7 // the provider refused the request for its rate limit or quota
+	exitContextTooLarge = 8 // the prompt does not fit the model's context window
+)
+
+// providerExitCode returns the exit code for an error of a provider request.
+func providerExitCode(err error) int {
+	switch {
+	case errors.Is(err, ai.ErrProviderAuth):
+		return exitProviderAuth
+	case errors.Is(err, ai.ErrProviderRateLimited):
+		return exitRateLimited
+	case errors.Is(err, ai.ErrContextTooLarge):
+		return exitContextTooLarge
+	case ai.IsTimeout(err):
+		return exitProviderTimeout
+	default:
+		return exitFailure
+	}
+}
+
+// commitExitCode returns the exit code for an error of creating the commit.
+func commitExitCode(err error) int {
+	if errors.Is(err, git.ErrNoStagedChanges) {
+		return exitNoChanges
+	}
+	return exitCommitFailed
+}
+
+// exitWith logs err with msg, and the advice ai.Hint has for it, and exits
+// with code.
+func exitWith(code int, err error, msg string) {
+	event := log.Error().Err(err)
+	if hint := ai.Hint(err); hint != "" {
+		event = event.Str("hint", hint)
+	}
+	event.Msg(msg)
+	os.Exit(code)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/changelog"
+)
+
+// runChangelogExport writes the normalized commit log of the range in args (or
+// --since) as JSON or CSV. It needs no AI provider, only the configured commit types.
+func runChangelogExport(args []string, since, output, format string) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "json" && format != "csv" {
+		log.Fatal().Msgf("Unsupported export format %q (use json or csv)", format)
+	}
+	if _, err := loadConfig(); err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for changelog export")
+	}
+
+	opts := changelog.Options{Since: since}
+	if len(args) == 1 {
+		from, to, ok := strings.Cut(args[0], "..")
+		if !ok {
+			log.Fatal().Msg("Invalid range format. Use: v0.10.0..v0.11.0 or v0.10.0..")
+		}
+		opts.FromRef, opts.ToRef = from, to
+	}
+	entries, err := changelog.Export(opts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to export history")
+	}
+
+	var buf bytes.Buffer
+	if format == "csv" {
+		err = changelog.WriteCSV(&buf, entries)
+	} else {
+		err = changelog.WriteJSON(&buf, entries)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to encode history")
+	}
+	if output == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write export to file")
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d commits to %s\n", len(entries), output)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ktr0731/go-fuzzyfinder"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// fixupNone is the reply of the model when no recent commit fits.
+const fixupNone = "NONE"
+
+func newFixupCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var squashFlag, fixupForceFlag bool
+	var limitFlag int
+
+	cmd := &cobra.Command{
+		Use:   "fixup",
+		Short: "Commit the staged changes as a fixup of the recent commit they belong to",
+		Long: "Asks the AI which of the recent commits the staged changes belong to, lets you confirm or pick another in a fuzzy finder, " +
+			"and creates a \"fixup!\" (or, with --squash, \"squash!\") commit for it, ready for git rebase -i --autosquash.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			kind := git.FixupKind
+			if squashFlag {
+				kind = git.SquashKind
+			}
+			runFixupCommand(setupAIEnvironment, kind, limitFlag, fixupForceFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&squashFlag, "squash", false, "Create a squash! commit, whose AI-written description is added to the target's message")
+	cmd.Flags().BoolVar(&fixupForceFlag, "force", false, "Use the AI's pick without opening the fuzzy finder")
+	cmd.Flags().IntVar(&limitFlag, "limit", 20, "Number of recent commits to choose from")
+
+	return cmd
+}
+
+func runFixupCommand(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	kind string,
+	limit int,
+	force bool,
+) {
+	start := time.Now()
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		exitWith(exitConfig, err, "Setup environment error for fixup command")
+	}
+	defer cancel()
+
+	commitOpts, err := commitOptions(cfg)
+	if err != nil {
+		exitWith(exitConfig, err, "Invalid commit options")
+	}
+
+	raw, err := git.GetStagedDiff(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get Git diff")
+	}
+	if strings.TrimSpace(raw) == "" {
+		fmt.Println("No staged changes.")
+		os.Exit(exitNoChanges)
+	}
+	diff, _, err := git.GetPromptDiff(ctx, cfg.LockFiles)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get Git diff")
+	}
+	if strings.TrimSpace(diff) == "" {
+		diff = raw
+	}
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diff = summarized
+		}
+	}
+
+	commits, err := git.RecentCommits(ctx, max(limit, 1))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read recent commits")
+	}
+	if len(commits) == 0 {
+		log.Fatal().Msg("No commits to fix up")
+	}
+	candidates := make([]prompt.FixupCandidate, len(commits))
+	for i, c := range commits {
+		candidates[i] = prompt.FixupCandidate{ShortHash: c.ShortHash, Subject: c.Subject, Files: c.Files()}
+	}
+
+	reply, err := aiClient.GetCommitMessage(ctx, prompt.BuildFixupPrompt(diff, languageFlag, fixupNone, candidates))
+	if err != nil {
+		exitWith(providerExitCode(err), err, "Fixup target selection error")
+	}
+	reply = aiClient.SanitizeResponse(reply, "")
+	suggested, ok := git.MatchFixupTarget(reply, commits)
+	description := git.FixupDescription(reply)
+
+	if !force && !interactiveTerminal() {
+		if cfg.NoTTY == "fail" {
+			exitWith(exitFailure, errors.New("no terminal for the fuzzy finder"), "Use --force outside a terminal")
+		}
+		log.Warn().Msg("No terminal for the fuzzy finder; using the AI's pick as with --force")
+		force = true
+	}
+	var target int
+	switch {
+	case force && ok:
+		target = suggested
+	case force:
+		answer, _, _ := strings.Cut(strings.TrimSpace(reply), "\n")
+		exitWith(exitFailure, fmt.Errorf("no recent commit matches the staged changes (reply: %q)", answer), "Run without --force to pick the commit")
+	default:
+		target = pickFixupTarget(commits, suggested, ok)
+		if target < 0 {
+			fmt.Println("Aborted. The changes remain staged.")
+			return
+		}
+	}
+
+	info := commits[target]
+	if target != suggested || !ok {
+		// The description was written for the AI's pick, not this commit.
+		description = ""
+	}
+	msg := git.FixupMessage(kind, info, description)
+	if err := git.CommitChangesWithOptions(ctx, msg, commitOpts); err != nil {
+		exitWith(commitExitCode(err), err, "Commit failed")
+	}
+	publishCommit(ctx, cfg, msg, time.Since(start), !force)
+	fmt.Printf("Created %s! commit for %s %s.\n", kind, info.ShortHash, info.Subject)
+	fmt.Printf("Fold it in with: git rebase -i --autosquash %s~1\n", info.ShortHash)
+}
+
+// pickFixupTarget lets the user choose the target among commits in a fuzzy
+// finder, listing the AI's suggestion first. It returns -1 when aborted.
+func pickFixupTarget(commits []git.CommitInfo, suggested int, ok bool) int {
+	order := make([]int, 0, len(commits))
+	if ok {
+		order = append(order, suggested)
+	}
+	for i := range commits {
+		if !ok || i != suggested {
+			order = append(order, i)
+		}
+	}
+	idx, err := fuzzyfinder.Find(
+		order,
+		func(i int) string {
+			c := commits[order[i]]
+			line := fmt.Sprintf("%s | %s | %s", c.ShortHash, c.Subject, humanize.Time(c.Date))
+			if ok && order[i] == suggested {
+				line += " | suggested"
+			}
+			return line
+		},
+		fuzzyfinder.WithPromptString("Fix up which commit> "),
+		fuzzyfinder.WithPreviewWindow(func(i, width, height int) string {
+			if i < 0 {
+				return ""
+			}
+			c := commits[order[i]]
+			return fmt.Sprintf("%s %s\n\n%s\n\nFiles:\n  %s", c.ShortHash, c.Subject, c.Body, strings.Join(c.Files(), "\n  "))
+		}),
+	)
+	if errors.Is(err, fuzzyfinder.ErrAbort) {
+		return -1
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fuzzy finder error")
+	}
+	return order[idx]
+}
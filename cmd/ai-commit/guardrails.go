@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/guard"
+)
+
+// enforceGuardrails scans a generated message for secrets, denied hostnames, and
+// profanity. On a hit it explains the violation on stderr and regenerates once with
+// the violation appended to the prompt; it fails if the replacement still violates.
+func enforceGuardrails(ctx context.Context, cfg *config.Config, aiClient ai.AIClient, promptText, commitType, commitMsg string) (string, error) {
+	scanner := guard.NewScanner(cfg.Guardrails)
+	findings := scanner.Scan(commitMsg)
+	if len(findings) == 0 {
+		return commitMsg, nil
+	}
+	fmt.Fprintln(os.Stderr, formatReviewOutput("Commit message blocked by guardrails; regenerating", guard.Explain(findings)))
+
+	regenerated, err := generateCommitMessage(ctx, aiClient, promptText+guard.RegenerationHint(findings), commitType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+	if err != nil {
+		return "", fmt.Errorf("regeneration after guardrail violation failed: %w", err)
+	}
+	if findings := scanner.Scan(regenerated); len(findings) > 0 {
+		return "", fmt.Errorf("regenerated commit message still violates guardrails:\n%s", guard.Explain(findings))
+	}
+	return regenerated, nil
+}
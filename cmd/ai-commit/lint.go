@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/lint"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+func newLintCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var reviewFlag, imperativeFlag bool
+	var maxSubjectFlag int
+
+	cmd := &cobra.Command{
+		Use:   "lint <msgfile>",
+		Short: "Check a commit message file, e.g. from a commit-msg hook",
+		Long: "Checks the commit message in <msgfile> (\"-\" for stdin) against the configured Conventional Commits types and emoji, " +
+			"and the subject length, after stripping comments as git does; --imperative also checks the mood of the description. " +
+			"With --review, the AI style review runs as well. It exits 1 with the problems and a suggested fix when the message does not conform, " +
+			"so a commit-msg hook running \"ai-commit lint \\\"$1\\\"\" rejects the commit. Merge, revert, fixup!, and squash! messages are not checked.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runLint(setupAIEnvironment, args[0], reviewFlag, imperativeFlag, maxSubjectFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&reviewFlag, "review", false, "Also run the AI style review and fail on its suggestions")
+	cmd.Flags().BoolVar(&imperativeFlag, "imperative", false, "Also require the description to start with an imperative verb (\"add\", not \"added\"); the check guesses from word endings")
+	cmd.Flags().IntVar(&maxSubjectFlag, "max-subject", lint.DefaultMaxSubject, "Maximum subject length in characters")
+
+	return cmd
+}
+
+func runLint(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	path string,
+	review bool,
+	imperative bool,
+	maxSubject int,
+) {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		cfg      *config.Config
+		aiClient ai.AIClient
+		err      error
+	)
+	if review {
+		ctx, cancel, cfg, aiClient, err = setupAIEnvironment()
+	} else if cfg, err = loadConfig(); err == nil {
+		ctx, cancel = context.WithTimeout(context.Background(), 60*time.Second)
+	}
+	if err != nil {
+		exitWith(exitConfig, err, "Setup environment error for lint command")
+	}
+	defer cancel()
+
+	data, err := readMessageFile(path)
+	if err != nil {
+		exitWith(exitConfig, err, "Cannot read the commit message")
+	}
+	msg := git.CleanMessage(string(data), git.CommentChar(ctx))
+	if lint.Exempt(msg) {
+		return
+	}
+
+	opts := lint.Options{EnableEmoji: cfg.EnableEmoji, MaxSubject: maxSubject, Imperative: imperative}
+	problems := lint.Check(msg, opts)
+	var suggestions string
+	if review && strings.TrimSpace(msg) != "" {
+		if suggestions, err = enforceCommitMessageStyle(ctx, aiClient, msg, languageFlag, cfg.PromptTemplate); err != nil {
+			exitWith(providerExitCode(err), err, "Commit message style review failed")
+		}
+	}
+	if len(problems) == 0 && !prompt.HasStyleIssues(suggestions) {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "ai-commit lint: the commit message does not follow the conventions:")
+	for _, p := range problems {
+		fmt.Fprintf(&b, "  - %s\n", p)
+	}
+	if prompt.HasStyleIssues(suggestions) {
+		fmt.Fprintf(&b, "\nStyle review:\n%s\n", strings.TrimSpace(suggestions))
+	}
+	fmt.Fprintf(&b, "\nValid types: %s\n", strings.Join(committypes.GetAllTypes(), ", "))
+	if fixed, ok := lint.Fix(msg, opts); ok && fixed != msg {
+		fmt.Fprintf(&b, "Suggested fix:\n\n    %s\n", strings.ReplaceAll(fixed, "\n", "\n    "))
+	}
+	if path != "-" {
+		fmt.Fprintf(&b, "\nEdit the message and commit again: git commit -e -F %s\n", path)
+	}
+	fmt.Fprint(os.Stderr, b.String())
+	os.Exit(exitFailure)
+}
+
+// readMessageFile reads the message file at path, or stdin for "-".
+func readMessageFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
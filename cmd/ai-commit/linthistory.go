@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/lint"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+func newLintHistoryCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var rangeFlag string
+	var fixFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "lint-history",
+		Short: "Report (and optionally fix) commit messages that do not follow the configured types",
+		Long: "Checks the commit messages in a range against the configured Conventional Commits types and emoji. " +
+			"With --fix, formatting problems are fixed directly, other messages are reworded by the AI from each commit's diff, " +
+			"and after confirmation the range is rewritten with the new messages (trees are unchanged; the old tip is kept in ORIG_HEAD).",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runLintHistory(setupAIEnvironment, rangeFlag, fixFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&rangeFlag, "range", "", "Commits to check, e.g. main..HEAD or v1.0.0.. (default: the last 50 commits)")
+	cmd.Flags().BoolVar(&fixFlag, "fix", false, "Rewrite non-conforming messages after confirmation (rewrites history up to HEAD)")
+
+	return cmd
+}
+
+type lintFinding struct {
+	commit   git.CommitInfo
+	problems []string
+}
+
+func runLintHistory(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	rangeSpec string,
+	fix bool,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for lint-history command")
+		return
+	}
+	defer cancel()
+
+	commits, err := git.RangeCommits(ctx, rangeSpec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to list commits")
+	}
+	opts := lint.Options{EnableEmoji: cfg.EnableEmoji}
+	var findings []lintFinding
+	for _, c := range commits {
+		if problems := lint.Check(c.Message, opts); len(problems) > 0 {
+			findings = append(findings, lintFinding{commit: c, problems: problems})
+		}
+	}
+
+	if len(findings) == 0 {
+		fmt.Printf("All %d commits conform.\n", len(commits))
+		return
+	}
+	var report strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&report, "%s %s\n", f.commit.ShortHash, f.commit.Subject)
+		for _, p := range f.problems {
+			fmt.Fprintf(&report, "    - %s\n", p)
+		}
+	}
+	fmt.Println(formatReviewOutput(fmt.Sprintf("%d of %d commits do not conform", len(findings), len(commits)), strings.TrimRight(report.String(), "\n")))
+	if !fix {
+		os.Exit(1)
+	}
+
+	if rangeEnd(rangeSpec) != "HEAD" {
+		log.Fatal().Msg("--fix rewrites history up to HEAD; use a range ending at HEAD")
+	}
+	rewrites := proposeLintFixes(cfg, aiClient, findings, opts)
+	if len(rewrites) == 0 {
+		log.Fatal().Msg("No fixes could be produced")
+	}
+
+	fmt.Printf("\nRewrite %d commit message(s)? This changes the hashes of those commits and every later one;\n", len(rewrites))
+	fmt.Print("already-pushed branches will need a force push. (y/N): ")
+	var answer string
+	fmt.Scanln(&answer)
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Println("Aborted; history is unchanged.")
+		return
+	}
+	newHead, err := git.RewordCommits(ctx, rewrites)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to rewrite history")
+	}
+	fmt.Printf("Rewrote %d message(s); HEAD is now %s (previous tip saved in ORIG_HEAD).\n", len(rewrites), newHead[:7])
+	if len(rewrites) < len(findings) {
+		os.Exit(1)
+	}
+}
+
+// proposeLintFixes returns the new message per commit hash and prints each change.
+// Formatting problems are fixed locally; other messages are reworded by the AI
+// from the commit's diff. Commits whose fix still does not conform are skipped.
+func proposeLintFixes(cfg *config.Config, aiClient ai.AIClient, findings []lintFinding, opts lint.Options) map[string]string {
+	rewrites := make(map[string]string)
+	for _, f := range findings {
+		fixed, ok := lint.Fix(f.commit.Message, opts)
+		if !ok {
+			var err error
+			fixed, err = rewordWithAI(cfg, aiClient, f.commit, opts)
+			if err != nil {
+				log.Warn().Err(err).Str("commit", f.commit.ShortHash).Msg("Skipping commit")
+				continue
+			}
+		}
+		rewrites[f.commit.Hash] = fixed
+		fmt.Printf("\n%s\n  - %s\n  + %s\n", f.commit.ShortHash, f.commit.Subject, strings.SplitN(fixed, "\n", 2)[0])
+	}
+	return rewrites
+}
+
+// rewordWithAI asks the AI for a conforming message from the commit's diff, using
+// the original message as context.
+func rewordWithAI(cfg *config.Config, aiClient ai.AIClient, commit git.CommitInfo, opts lint.Options) (string, error) {
+	reqCtx, cancel := context.WithTimeout(context.Background(), evalRequestTimeout)
+	defer cancel()
+	info, err := git.GetCommitInfo(reqCtx, commit.Hash)
+	if err != nil {
+		return "", err
+	}
+	diff := info.Diff
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diff = summarized
+		}
+	}
+	userContext := "Rewrite the existing commit message below so it follows the format; keep its meaning.\n" + strings.TrimSpace(commit.Message)
+	promptText := prompt.BuildCommitPrompt(diff, languageFlag, "", userContext, cfg.PromptTemplate, git.SuggestScope(diff))
+	msg, err := generateCommitMessage(reqCtx, aiClient, promptText, "", "", cfg.EnableEmoji, cfg.TicketPattern)
+	if err != nil {
+		return "", err
+	}
+	if fixed, ok := lint.Fix(msg, opts); ok {
+		return fixed, nil
+	}
+	return "", fmt.Errorf("AI suggestion still does not conform: %s", strings.Join(lint.Check(msg, opts), "; "))
+}
+
+// rangeEnd returns the end of a "from..to" range, "HEAD" when it is omitted.
+func rangeEnd(rangeSpec string) string {
+	if _, to, ok := strings.Cut(rangeSpec, ".."); ok && strings.TrimSpace(to) != "" {
+		return strings.TrimSpace(to)
+	}
+	return "HEAD"
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// maxWait returns --max-wait or, when it is not given, the maxWait config value.
+// Zero means no limit.
+func maxWait(cfg *config.Config) (time.Duration, error) {
+	if maxWaitFlag > 0 {
+		return maxWaitFlag, nil
+	}
+	raw := strings.TrimSpace(cfg.MaxWait)
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid maxWait %q: want a duration such as \"10s\"", cfg.MaxWait)
+	}
+	return d, nil
+}
+
+// maxWaitFallback returns the client used once maxWait passes without an answer:
+// --fallback, else the fallback config value, else the budget fallback. It
+// returns nil when none is configured.
+func maxWaitFallback(ctx context.Context, cfg *config.Config) (ai.AIClient, error) {
+	spec, ok := fallbackSpec(cfg)
+	if !ok {
+		return nil, nil
+	}
+	client, err := newProviderClient(ctx, cfg, spec.Provider, spec.Model, false)
+	if err != nil {
+		return nil, fmt.Errorf("fallback provider %s: %w", spec, err)
+	}
+	return client, nil
+}
+
+// fallbackSpec returns the fallback provider: --fallback, else the fallback
+// config value, else the budget fallback.
+func fallbackSpec(cfg *config.Config) (providerSpec, bool) {
+	raw := strings.TrimSpace(fallbackFlag)
+	if raw == "" {
+		raw = strings.TrimSpace(cfg.Fallback)
+	}
+	switch {
+	case raw != "":
+		return parseProviderSpec(raw), true
+	case cfg.Budget.FallbackProvider != "":
+		return providerSpec{Provider: cfg.Budget.FallbackProvider, Model: cfg.Budget.FallbackModel}, true
+	default:
+		return providerSpec{}, false
+	}
+}
+
+// generateWithMaxWait is generateCommitMessage for the non-streaming paths: when
+// client has not answered within wait and a fallback is set, the request is
+// abandoned and the fallback asked instead. It returns the client that produced
+// the message.
+func generateWithMaxWait(
+	ctx context.Context,
+	client ai.AIClient,
+	fallback ai.AIClient,
+	wait time.Duration,
+	promptText string,
+	commitType string,
+	tmpl string,
+	enableEmoji bool,
+	ticketPattern string,
+) (string, ai.AIClient, error) {
+	return withMaxWait(ctx, client, fallback, wait, func(ctx context.Context, client ai.AIClient) (string, error) {
+		return generateCommitMessage(ctx, client, promptText, commitType, tmpl, enableEmoji, ticketPattern)
+	})
+}
+
+// withMaxWait runs generate with client, switching to fallback as described
+// for generateWithMaxWait.
+func withMaxWait[T any](
+	ctx context.Context,
+	client ai.AIClient,
+	fallback ai.AIClient,
+	wait time.Duration,
+	generate func(context.Context, ai.AIClient) (T, error),
+) (T, ai.AIClient, error) {
+	if wait <= 0 || fallback == nil {
+		out, err := generate(ctx, client)
+		return out, client, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	out, err := generate(waitCtx, client)
+	timedOut := errors.Is(waitCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	cancel()
+	if err == nil || !timedOut {
+		return out, client, err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s did not answer within %s; using %s instead.\n", client.ProviderName(), wait, fallback.ProviderName())
+	out, err = generate(ctx, fallback)
+	return out, fallback, err
+}
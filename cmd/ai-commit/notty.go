@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// interactiveTerminal reports whether stdin and stdout are terminals, which the
+// TUIs need; in git hooks and CI jobs they are usually not.
+func interactiveTerminal() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// degradeWithoutTerminal handles a run that would open a TUI without a terminal.
+// With noTTY "fail" it exits; otherwise it warns and continues as with --force,
+// committing every change at once instead of splitting interactively.
+func degradeWithoutTerminal(cfg *config.Config) {
+	if cfg.NoTTY == "fail" {
+		exitWith(exitFailure, errors.New("no terminal for the interactive UI"), "Use --force or --msg-only outside a terminal")
+	}
+	log.Warn().Msg("No terminal for the interactive UI; committing as with --force (set noTTY: fail to stop instead)")
+	forceFlag = true
+	interactiveSplitFlag = false
+	autoSplitFlag = false
+}
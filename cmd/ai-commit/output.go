@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/changelog"
+	"github.com/renatogalera/ai-commit/pkg/report"
+)
+
+// applyOutputMode silences warnings and progress logs for --quiet; errors are
+// still written to stderr.
+func applyOutputMode() {
+	if quietFlag {
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+	}
+}
+
+// isJSONOutput reports whether an --output value asks for JSON; "text", the
+// default, asks for the usual output.
+func isJSONOutput(output string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(output)) {
+	case "", "text":
+		return false, nil
+	case "json":
+		return true, nil
+	}
+	return false, fmt.Errorf("--output must be text or json, got %q", output)
+}
+
+// applyJSONOutput prepares --output json: the result is written to stdout as
+// one JSON document, so nothing else may be. Without --force, --edit, or
+// --msg-only the message is only printed, as with --print.
+func applyJSONOutput() error {
+	on, err := isJSONOutput(outputFormatFlag)
+	if err != nil || !on {
+		return err
+	}
+	if semanticReleaseFlag || interactiveSplitFlag || autoSplitFlag {
+		return fmt.Errorf("--output json cannot be combined with --semantic-release, --interactive-split, or --auto-split")
+	}
+	jsonOutputFlag = true
+	quietFlag = true
+	if !forceFlag && !msgOnlyFlag && !editFlag {
+		printFlag = true
+	}
+	return nil
+}
+
+// commitResult is the --output json document of a generated message.
+type commitResult struct {
+	Message   string         `json:"message"`
+	Type      string         `json:"type,omitempty"`
+	Scope     string         `json:"scope,omitempty"`
+	Breaking  bool           `json:"breaking,omitempty"`
+	Provider  string         `json:"provider,omitempty"`
+	Model     string         `json:"model,omitempty"`
+	Tokens    *report.Tokens `json:"tokens,omitempty"`
+	ElapsedMS int64          `json:"elapsedMs"`
+	Committed bool           `json:"committed"`
+	Hash      string         `json:"hash,omitempty"`
+}
+
+// writeCommitResult writes msg, generated by client in elapsed, to stdout as
+// JSON. hash is the commit created with it, if any.
+func writeCommitResult(client ai.AIClient, msg, hash string, elapsed time.Duration) {
+	entry := changelog.NormalizeMessage(msg)
+	source := report.SourceOf(client)
+	result := commitResult{
+		Message:   strings.TrimSpace(msg),
+		Scope:     entry.Scope,
+		Breaking:  entry.Breaking,
+		Provider:  source.Provider,
+		Model:     source.Model,
+		Tokens:    source.Tokens,
+		ElapsedMS: elapsed.Milliseconds(),
+		Committed: hash != "",
+		Hash:      hash,
+	}
+	if entry.Type != "other" {
+		result.Type = entry.Type
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		exitWith(exitFailure, err, "Cannot write the JSON output")
+	}
+}
+
+// applyPrintMode makes --print, and --dry-run without --semantic-release, act
+// as --msg-only: the message goes to stdout, with no TUI and no commit.
+func applyPrintMode() error {
+	if !printFlag && (!dryRunFlag || semanticReleaseFlag) {
+		return nil
+	}
+	if printFlag && semanticReleaseFlag {
+		return fmt.Errorf("--print cannot be combined with --semantic-release; use --dry-run for the release plan")
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--force", forceFlag},
+		{"--edit", editFlag},
+		{"--all", allFlag},
+		{"--interactive-split", interactiveSplitFlag},
+		{"--auto-split", autoSplitFlag},
+	} {
+		if f.set {
+			return fmt.Errorf("%s commits and cannot be combined with --print or --dry-run", f.name)
+		}
+	}
+	msgOnlyFlag = true
+	return nil
+}
+
+// verbosef writes a --verbose diagnostic line to stderr.
+func verbosef(format string, args ...any) {
+	if verboseFlag {
+		fmt.Fprintf(os.Stderr, "[verbose] "+format+"\n", args...)
+	}
+}
+
+// describeLastCall summarizes the provider, model, latency, and token usage of
+// client's last request for --verbose.
+func describeLastCall(client ai.AIClient) string {
+	name := client.ProviderName()
+	if model := ai.ModelOf(client); model != "" {
+		name += "/" + model
+	}
+	fields := []string{name}
+	if stats, ok := ai.StatsOf(client); ok {
+		tokens := fmt.Sprintf("%d in / %d out tokens", stats.InputTokens, stats.OutputTokens)
+		if stats.Estimated {
+			tokens += " (est.)"
+		} else if stats.CachedTokens > 0 {
+			tokens += fmt.Sprintf(", %d cached", stats.CachedTokens)
+		}
+		fields = append(fields, stats.Latency.Round(time.Millisecond).String(), tokens)
+	}
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/plugin"
+)
+
+// pluginRunner returns the runner of the plugins configured under plugins, or
+// nil when there are none.
+func pluginRunner(cfg *config.Config) *plugin.Runner {
+	return plugin.New(cfg.Plugins)
+}
+
+// verifyWithPlugins returns a CommitOptions.Verify that runs the pre-commit
+// plugins of r on the final message and the staged diff, or nil without any.
+func verifyWithPlugins(r *plugin.Runner) func(context.Context, string) (string, error) {
+	if !r.Has(plugin.PreCommit) {
+		return nil
+	}
+	return func(ctx context.Context, msg string) (string, error) {
+		diff, err := git.GetStagedDiff(ctx)
+		if err != nil {
+			log.Debug().Err(err).Msg("Cannot read the staged diff for the pre-commit plugins")
+		}
+		out, err := r.Run(ctx, plugin.Input{Point: plugin.PreCommit, Message: msg, Diff: diff})
+		return out.Message, err
+	}
+}
+
+// postGenerate runs the post-generate plugins on the generated message, or on
+// choices when --candidates produced several, and returns both updated.
+func postGenerate(ctx context.Context, r *plugin.Runner, diff, commitMsg string, choices []string) (string, []string, error) {
+	msgs := choices
+	if len(msgs) == 0 {
+		msgs = []string{commitMsg}
+	}
+	msgs, err := r.PostGenerate(ctx, diff, msgs)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(choices) == 0 {
+		return msgs[0], nil, nil
+	}
+	return msgs[0], msgs, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// provenanceEnabled reports whether AI-generated messages get an X-AI-Commit trailer.
+func provenanceEnabled(cfg *config.Config) bool { return provenanceFlag || cfg.Provenance }
+
+// clientProvenance returns the provenance trailer for messages generated by client.
+func clientProvenance(cfg *config.Config, client ai.AIClient) string {
+	return git.Provenance{
+		Provider:     client.ProviderName(),
+		Model:        ai.ModelOf(client),
+		TemplateHash: prompt.TemplateHash(cfg.PromptTemplate),
+		Version:      version,
+	}.Trailer()
+}
+
+// consensusProvenance returns the provenance trailer for a --consensus run, listing
+// every candidate provider (and the judge, if any) joined by "+".
+func consensusProvenance(cfg *config.Config, specs []providerSpec, judge string) string {
+	names := make([]string, 0, len(specs)+1)
+	for _, spec := range specs {
+		names = append(names, specProvenanceName(spec))
+	}
+	if strings.TrimSpace(judge) != "" {
+		names = append(names, specProvenanceName(parseProviderSpec(judge)))
+	}
+	return git.Provenance{
+		Provider:     strings.Join(names, "+"),
+		TemplateHash: prompt.TemplateHash(cfg.PromptTemplate),
+		Version:      version,
+	}.Trailer()
+}
+
+func specProvenanceName(spec providerSpec) string {
+	if spec.Model == "" {
+		return spec.Provider
+	}
+	return spec.Provider + "/" + spec.Model
+}
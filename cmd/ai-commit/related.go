@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// relatedHint adds the subjects of the last --related-commits (or
+// relatedCommits) commits touching the files of diff to the prompt. It is empty
+// when the setting is zero or no earlier commit touched them. With --amend the
+// amended commit itself is left out.
+func relatedHint(ctx context.Context, cfg *config.Config, diff string) string {
+	n := relatedCommitsFlag
+	if n == 0 {
+		n = cfg.RelatedCommits
+	}
+	if n <= 0 {
+		return ""
+	}
+	var paths []string
+	for _, s := range git.DiffFileStats(diff) {
+		if s.Path != "" {
+			paths = append(paths, s.Path)
+		}
+	}
+	rev := "HEAD"
+	if amendFlag {
+		rev = "HEAD~1"
+	}
+	commits, err := git.RelatedCommits(ctx, rev, paths, n)
+	if err != nil {
+		log.Debug().Err(err).Msg("Skipping related commits")
+		return ""
+	}
+	if len(commits) == 0 {
+		return ""
+	}
+	subjects := make([]string, len(commits))
+	for i, c := range commits {
+		subjects[i] = c.Subject
+	}
+	verbosef("related commits: %d", len(commits))
+	return prompt.RelatedCommitsHint(subjects)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/github"
+	"github.com/renatogalera/ai-commit/pkg/versioner"
+)
+
+// releaseOptions returns the pre-release channel and build metadata for
+// --semantic-release: the flags when set, otherwise release.channels for the
+// current branch and release.buildMetadata. It also carries the tag prefix, the
+// monorepo packages, whether to write release notes and push the tags, and
+// subscribes the GitHub release publisher when asked for. A GitHub release
+// always pushes its tag first, so the release points at a commit the remote has.
+func releaseOptions(ctx context.Context, cfg *config.Config) versioner.ReleaseOptions {
+	githubRelease := githubReleaseFlag || cfg.Release.GitHubRelease
+	opts := versioner.ReleaseOptions{
+		Prerelease:    prereleaseFlag,
+		BuildMetadata: buildMetadataFlag,
+		TagPrefix:     cfg.Release.TagPrefix,
+		Packages:      releasePackages(cfg.Packages),
+		Force:         forceTagFlag,
+		Quiet:         quietFlag,
+		Events:        eventBus(cfg),
+		Notes:         releaseNotesFlag || cfg.Release.Notes || githubRelease,
+		Language:      languageFlag,
+		Push:          pushTagFlag || cfg.Release.Push || githubRelease,
+		Remote:        cfg.Release.Remote,
+		Token:         pushToken(cfg.Release.Remote),
+		Strategy:      bumpStrategyFlag,
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = cfg.Release.Strategy
+	}
+	if opts.BuildMetadata == "" {
+		opts.BuildMetadata = cfg.Release.BuildMetadata
+	}
+	if opts.Prerelease == "" && len(cfg.Release.Channels) > 0 {
+		if branch, err := git.GetCurrentBranch(ctx); err == nil {
+			opts.Prerelease = releaseChannel(cfg.Release.Channels, branch)
+		}
+	}
+	if githubRelease && semanticReleaseFlag && !dryRunFlag {
+		// The release runs after the UI or editor, past the generation deadline of ctx.
+		releaseCtx := context.WithoutCancel(ctx)
+		events.On(opts.Events, func(e events.ReleaseTagged) { publishGitHubRelease(releaseCtx, opts.Remote, e) })
+	}
+	return opts
+}
+
+// pushToken is the token that authenticates tag pushes to the HTTP(S) remote
+// named remote: $AI_COMMIT_GIT_TOKEN, or $GITHUB_TOKEN as set in GitHub Actions
+// when the remote is on github.com or $GITHUB_SERVER_URL, so the GitHub token
+// is never sent to another host.
+func pushToken(remote string) string {
+	if token := os.Getenv("AI_COMMIT_GIT_TOKEN"); token != "" {
+		return token
+	}
+	if remote == "" {
+		remote = "origin"
+	}
+	url, err := git.RemoteURL(remote)
+	if err != nil {
+		return ""
+	}
+	host := git.RemoteHost(url)
+	if host == "" {
+		return ""
+	}
+	if host == "github.com" || host == git.RemoteHost(os.Getenv("GITHUB_SERVER_URL")) {
+		return os.Getenv("GITHUB_TOKEN")
+	}
+	return ""
+}
+
+// publishGitHubRelease creates a GitHub release for the tag of e, already
+// pushed to remote, with its notes as the body. The repository is
+// $GITHUB_REPOSITORY or the one of remote; the tag is already created, so
+// failures only warn.
+func publishGitHubRelease(ctx context.Context, remote string, e events.ReleaseTagged) {
+	if remote == "" {
+		remote = "origin"
+	}
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if repo == "" {
+		if url, err := git.RemoteURL(remote); err == nil {
+			repo, _ = github.RepoFromRemote(url)
+		}
+	}
+	switch {
+	case repo == "":
+		log.Warn().Str("tag", e.Tag).Msg("Cannot tell the GitHub repository (set GITHUB_REPOSITORY); the GitHub release is not created")
+		return
+	case os.Getenv("GITHUB_TOKEN") == "":
+		log.Warn().Str("tag", e.Tag).Msg("GITHUB_TOKEN is not set; the GitHub release is not created")
+		return
+	}
+	client := github.NewClient(os.Getenv("GITHUB_TOKEN"), os.Getenv("GITHUB_API_URL"))
+	url, err := client.CreateRelease(ctx, repo, github.Release{
+		TagName:         e.Tag,
+		TargetCommitish: e.Commit,
+		Name:            e.Tag,
+		Body:            e.Notes,
+		Prerelease:      e.Prerelease,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("tag", e.Tag).Msg("GitHub release failed")
+		return
+	}
+	if !quietFlag {
+		fmt.Printf("GitHub release: %s\n", url)
+	}
+}
+
+// releaseChannel returns the pre-release identifier configured for branch. An
+// exact branch name wins over patterns, which are tried in sorted order.
+func releaseChannel(channels map[string]string, branch string) string {
+	if channel, ok := channels[branch]; ok {
+		return channel
+	}
+	patterns := make([]string, 0, len(channels))
+	for pattern := range channels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return channels[pattern]
+		}
+	}
+	return ""
+}
+
+// releasePackages turns the packages config into versioner packages, sorted by
+// name; a package's tags default to "<name>/vX.Y.Z".
+func releasePackages(packages map[string]config.PackageSettings) []versioner.Package {
+	out := make([]versioner.Package, 0, len(packages))
+	for name, p := range packages {
+		prefix := p.TagPrefix
+		if prefix == "" {
+			prefix = name + "/v"
+		}
+		out = append(out, versioner.Package{Name: name, Path: p.Path, TagPrefix: prefix})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// runReleaseDryRun prints what --semantic-release would tag for HEAD, including
+// whether the pre-tagging checks would stop it.
+func runReleaseDryRun(ctx context.Context, client ai.AIClient, opts versioner.ReleaseOptions) {
+	start := time.Now()
+	plans, err := versioner.PlanSemanticRelease(ctx, client, manualSemverFlag, opts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to plan the release")
+	}
+	verbosef("planned %d release(s) in %s (last request: %s)", len(plans), time.Since(start).Round(time.Millisecond), describeLastCall(client))
+	if quietFlag {
+		// Only the tags that would be created, one per line.
+		for _, plan := range plans {
+			if plan.Tag != "" {
+				fmt.Println(plan.Tag)
+			}
+		}
+		if !opts.Force {
+			if err := versioner.CheckReleasable(); err != nil {
+				log.Error().Err(err).Msg("The release would stop")
+			}
+		}
+		return
+	}
+	for i, plan := range plans {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(plan)
+	}
+	fmt.Println()
+	if opts.Force {
+		fmt.Println("Checks: skipped (--force-tag)")
+	} else if err := versioner.CheckReleasable(); err != nil {
+		fmt.Printf("Checks: the release would stop: %v\n", err)
+	} else {
+		fmt.Println("Checks: passed")
+	}
+	fmt.Println("Dry run: nothing was tagged.")
+}
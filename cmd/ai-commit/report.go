@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/gitlab"
+	"github.com/renatogalera/ai-commit/pkg/report"
+	"github.com/renatogalera/ai-commit/pkg/summarizer"
+)
+
+// structuredFormat reports whether --format needs findings with file and line
+// locations, which the CI review prompt asks the AI for.
+func structuredFormat(format string) bool {
+	format = strings.ToLower(strings.TrimSpace(format))
+	return format == "json" || format == "sarif"
+}
+
+// reviewedChanges describes what `ai-commit review` looked at, for the report.
+func reviewedChanges(mr *gitlab.MergeRequest) string {
+	if mr != nil {
+		return fmt.Sprintf("merge request !%d", mr.IID)
+	}
+	if from, to, ok := refRange(); ok {
+		return from + ".." + to
+	}
+	return "staged changes"
+}
+
+// withSource appends a "Generated by" line naming source to terminal output.
+func withSource(content string, source report.Source) string {
+	return content + "\n\nGenerated by " + source.String()
+}
+
+// summaryReport turns a commit summary into a report.
+func summaryReport(s *summarizer.Summary) report.Report {
+	return report.Report{
+		Title: "Commit Summary",
+		Fields: []report.Field{
+			{Name: "Commit", Value: s.Commit.Hash.String()[:7]},
+			{Name: "Subject", Value: strings.TrimSpace(strings.SplitN(s.Commit.Message, "\n", 2)[0])},
+			{Name: "Author", Value: s.Commit.Author.Name},
+			{Name: "Date", Value: s.Commit.Author.When.Format("Mon Jan 2 15:04:05 MST 2006")},
+		},
+		Body:    s.Text,
+		Version: version,
+		Source:  s.Source,
+	}
+}
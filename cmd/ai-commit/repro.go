@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// deterministicSeed is the seed --deterministic sends when none is configured.
+const deterministicSeed int64 = 42
+
+// deterministicEnabled reports whether --deterministic or deterministic asks
+// for repeatable requests and reproducibility reports.
+func deterministicEnabled(cfg *config.Config) bool {
+	return deterministicFlag || cfg.Deterministic
+}
+
+// samplingFor returns the generation parameters of a provider configured with
+// ps: --temperature and --seed first, then the provider settings, then the
+// temperature 0 and fixed seed of deterministic mode.
+func samplingFor(cfg *config.Config, ps config.ProviderSettings) (ai.Sampling, error) {
+	var s ai.Sampling
+	if deterministicEnabled(cfg) {
+		temperature, seed := 0.0, deterministicSeed
+		s = ai.Sampling{Temperature: &temperature, Seed: &seed}
+	}
+	if ps.Temperature != nil {
+		s.Temperature = ps.Temperature
+	}
+	if ps.Seed != nil {
+		s.Seed = ps.Seed
+	}
+	if f := rootCmd.PersistentFlags().Lookup("temperature"); f != nil && f.Changed {
+		if temperatureFlag < 0 || temperatureFlag > 2 {
+			return ai.Sampling{}, fmt.Errorf("--temperature must be between 0 and 2, got %g", temperatureFlag)
+		}
+		temperature := temperatureFlag
+		s.Temperature = &temperature
+	}
+	if f := rootCmd.PersistentFlags().Lookup("seed"); f != nil && f.Changed {
+		seed := seedFlag
+		s.Seed = &seed
+	}
+	return s, nil
+}
+
+// subscribeRepro collects every request made on bus and, in deterministic
+// mode, attaches them to each new commit as a reproducibility report under
+// refs/notes/ai-commit.
+func subscribeRepro(cfg *config.Config, bus *events.Bus) {
+	if !deterministicEnabled(cfg) {
+		return
+	}
+	var (
+		mu       sync.Mutex
+		requests []git.ReproRequest
+	)
+	events.On(bus, func(e events.GenerationFinished) {
+		// Embeddings carry no prompt, and failed requests produced nothing.
+		if e.Prompt == "" || e.Err != nil {
+			return
+		}
+		s := e.Stats
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, git.ReproRequest{
+			Provider:     s.Provider,
+			Model:        s.Model,
+			Temperature:  s.Sampling.Temperature,
+			Seed:         s.Sampling.Seed,
+			PromptSHA256: git.HashText(e.Prompt),
+			OutputSHA256: git.HashText(e.Output),
+		})
+	})
+	events.On(bus, func(e events.CommitCreated) {
+		mu.Lock()
+		report := git.ReproReport{Version: version, TemplateHash: prompt.TemplateHash(cfg.PromptTemplate), Requests: requests}
+		requests = nil
+		mu.Unlock()
+		// Commits whose message was not generated (e.g. renames) get no report.
+		if e.Hash == "" || len(report.Requests) == 0 {
+			return
+		}
+		if err := git.AddReproNote(context.Background(), e.Hash, report); err != nil {
+			log.Warn().Err(err).Msg("Cannot attach the reproducibility report")
+			return
+		}
+		verbosef("reproducibility report of %d request(s) attached to %s under %s", len(report.Requests), e.Hash, git.ReproNotesRef)
+	})
+}
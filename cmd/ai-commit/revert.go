@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+func newRevertCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var reasonFlag string
+	var revertForceFlag bool
+	var mainlineFlag int
+
+	cmd := &cobra.Command{
+		Use:   "revert <commit>",
+		Short: "Revert a commit with an AI-written explanation",
+		Long:  "Generates a message describing what is being reverted and why, instead of git's default revert message, then stages the inverse of the given commit and commits it. Nothing is staged until the message is accepted, and a failed commit undoes the revert. Merge commits need --mainline.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runRevertCommand(setupAIEnvironment, args[0], reasonFlag, revertForceFlag, mainlineFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&reasonFlag, "reason", "", "Why the commit is being reverted (included in the prompt)")
+	cmd.Flags().BoolVar(&revertForceFlag, "force", false, "Commit the revert without asking for confirmation")
+	cmd.Flags().IntVarP(&mainlineFlag, "mainline", "m", 0, "For a merge commit, the parent number (from 1) whose side is kept, as with git revert -m")
+
+	return cmd
+}
+
+func runRevertCommand(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	rev string,
+	reason string,
+	force bool,
+	mainline int,
+) {
+	start := time.Now()
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for revert command")
+		return
+	}
+	defer cancel()
+
+	commitOpts, err := commitOptions(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid commit options")
+	}
+
+	info, err := git.GetCommitInfo(ctx, rev)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read commit to revert")
+	}
+	switch {
+	case info.Parents > 1 && mainline == 0:
+		exitWith(exitConfig, fmt.Errorf("%s is a merge commit", info.ShortHash), "Pass --mainline <parent> to choose the side to keep, as with git revert -m")
+	case info.Parents <= 1 && mainline != 0:
+		exitWith(exitConfig, fmt.Errorf("%s is not a merge commit", info.ShortHash), "--mainline only applies to merge commits")
+	case mainline > 1:
+		if info.Diff, err = git.ParentDiff(info.Hash, mainline); err != nil {
+			exitWith(exitConfig, err, "Invalid --mainline")
+		}
+	case mainline < 0 || mainline > info.Parents:
+		exitWith(exitConfig, fmt.Errorf("%s has no parent %d", info.ShortHash, mainline), "Invalid --mainline")
+	}
+	// Fail before asking the AI when the revert could not be staged anyway.
+	if err := git.CheckCleanIndex(); err != nil {
+		exitWith(exitFailure, err, "Cannot revert")
+	}
+
+	diff := info.Diff
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diff = summarized
+		}
+	}
+	revertPrompt := prompt.BuildRevertPrompt(info.ShortHash, info.Subject, info.Body, diff, reason, languageFlag)
+
+	msg, err := aiClient.GetCommitMessage(ctx, revertPrompt)
+	if err != nil {
+		exitWith(providerExitCode(err), err, "Revert message generation error (nothing was staged)")
+	}
+	msg = aiClient.SanitizeResponse(msg, "")
+	if err := ai.ValidateCommitMessage(msg); err != nil {
+		log.Warn().Err(err).Msg("Rejected AI output; using the default revert message")
+		msg = ""
+	} else if warning := ai.MessageWarning(msg); warning != "" {
+		log.Warn().Msgf("Check the AI output: %s", warning)
+	}
+	aiGenerated := strings.TrimSpace(msg) != ""
+	if !aiGenerated {
+		msg = fmt.Sprintf("Revert %q", info.Subject)
+	}
+	msg = git.EnsureRevertTrailer(msg, info.Hash)
+	if aiGenerated && provenanceEnabled(cfg) {
+		commitOpts.Trailers = append(commitOpts.Trailers, clientProvenance(cfg, aiClient))
+	}
+
+	if !force {
+		fmt.Println(formatReviewOutput("Revert Commit Message", msg))
+		fmt.Print("Commit with this message? (y/N): ")
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Println("Aborted; nothing was reverted.")
+			return
+		}
+	}
+
+	// The user may have taken longer to answer than the generation deadline of ctx.
+	ctx = context.WithoutCancel(ctx)
+	if err := git.RevertCommit(ctx, info.Hash, mainline); err != nil {
+		exitWith(exitFailure, err, "Failed to revert commit")
+	}
+	if err := git.CommitChangesWithOptions(ctx, msg, commitOpts); err != nil {
+		if undoErr := git.AbortRevert(ctx); undoErr != nil {
+			log.Error().Err(undoErr).Msg("The revert is still staged")
+		}
+		exitWith(commitExitCode(err), err, "Commit failed; the revert was undone")
+	}
+	publishCommit(ctx, cfg, msg, time.Since(start), !force)
+	fmt.Printf("Reverted %s successfully.\n", info.ShortHash)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+// maxReviewers caps the reviewers `ai-commit review --reviewers` suggests.
+const maxReviewers = 5
+
+// suggestReviewers ranks reviewers for the files diff changes from the
+// CODEOWNERS file and git blame at rev, leaving out the current user.
+func suggestReviewers(ctx context.Context, diff, rev string) []review.Reviewer {
+	paths := git.DiffPaths(diff)
+	var rules []review.OwnerRule
+	for _, p := range review.CodeownersPaths {
+		if data, err := git.ReadWorktreeFile(p); err == nil {
+			rules = review.ParseCodeowners(string(data))
+			break
+		}
+	}
+	blame := make(map[string]map[string]int)
+	for _, p := range paths {
+		authors, err := git.BlameAuthors(ctx, rev, p)
+		if err != nil {
+			// New files have no history at rev.
+			log.Debug().Err(err).Str("path", p).Msg("No blame for the changed file")
+			continue
+		}
+		blame[p] = authors
+	}
+	var exclude []string
+	if email := git.UserEmail(ctx); email != "" {
+		exclude = append(exclude, email)
+	}
+	return review.SuggestReviewers(paths, rules, blame, exclude, maxReviewers)
+}
+
+// formatReviewers lists reviewers as bullets with the reason for each.
+func formatReviewers(reviewers []review.Reviewer) string {
+	if len(reviewers) == 0 {
+		return "No reviewers found in CODEOWNERS or git blame for the changed files."
+	}
+	var b strings.Builder
+	for _, r := range reviewers {
+		fmt.Fprintf(&b, "- %s: %s\n", r.Name, r.Reason())
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// reviewerNames joins the names of reviewers for the report fields.
+func reviewerNames(reviewers []review.Reviewer) string {
+	names := make([]string, len(reviewers))
+	for i, r := range reviewers {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// explainReviewers asks client why each of reviewers fits the change in diff.
+// On failure it warns and keeps the reasons found locally.
+func explainReviewers(ctx context.Context, client ai.AIClient, diff string, reviewers []review.Reviewer) string {
+	listed := formatReviewers(reviewers)
+	if len(reviewers) == 0 {
+		return listed
+	}
+	resp, err := client.GetCommitMessage(ctx, prompt.BuildReviewerPrompt(diff, languageFlag, listed))
+	if err != nil || strings.TrimSpace(resp) == "" {
+		log.Warn().Err(err).Msg("Cannot explain the suggested reviewers")
+		return listed
+	}
+	return strings.TrimSpace(resp)
+}
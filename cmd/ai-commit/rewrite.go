@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// rewriteColumnWidth is the width of each message box in the rewrite preview,
+// so the current and proposed messages fit side by side in a terminal.
+const rewriteColumnWidth = 56
+
+func newRewriteCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	var rewriteDryRunFlag, rewriteYesFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "rewrite <from..HEAD>",
+		Short: "Regenerate the message of every commit in a range and rewrite history",
+		Long: "Walks the commits of a range, oldest first, and asks the AI for a fresh message for each from its diff alone, " +
+			"keeping trailers such as Signed-off-by, Change-Id, and Co-authored-by. " +
+			"Every proposal is previewed next to the current message, and you accept or skip it; the accepted messages are then " +
+			"written back with a rebase that keeps every tree unchanged (the old tip is kept in ORIG_HEAD). " +
+			"Unlike annotate, the current messages are not used as drafts.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runRewrite(setupAIEnvironment, args[0], rewriteDryRunFlag, rewriteYesFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&rewriteDryRunFlag, "dry-run", false, "Only preview the proposals; do not rewrite history")
+	cmd.Flags().BoolVarP(&rewriteYesFlag, "yes", "y", false, "Accept every proposal without asking")
+
+	return cmd
+}
+
+func runRewrite(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	rangeSpec string,
+	dryRun, yes bool,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		exitWith(exitConfig, err, "Setup environment error for rewrite command")
+	}
+	defer cancel()
+
+	if !strings.Contains(rangeSpec, "..") {
+		exitWith(exitFailure, fmt.Errorf("invalid range %q", rangeSpec), "Give a range such as main..HEAD")
+	}
+	if !dryRun && rangeEnd(rangeSpec) != "HEAD" {
+		exitWith(exitFailure, fmt.Errorf("range %q does not end at HEAD", rangeSpec), "Rewriting changes history up to HEAD; use a range ending at HEAD or --dry-run")
+	}
+	if !dryRun && !yes && !interactiveTerminal() {
+		exitWith(exitFailure, errors.New("no terminal to confirm the proposals"), "Use --yes to accept them all or --dry-run to preview")
+	}
+	commits, err := git.RangeCommits(ctx, rangeSpec)
+	if err != nil {
+		exitWith(exitFailure, err, "Failed to list commits")
+	}
+	if len(commits) == 0 {
+		fmt.Println("No commits in the range.")
+		return
+	}
+
+	rewrites := make(map[string]string)
+	for i, c := range commits {
+		fmt.Printf("Regenerating %s (%d/%d)...\n", c.ShortHash, i+1, len(commits))
+		proposed, err := proposeMessage(cfg, aiClient, c, false)
+		if err != nil {
+			if ctx.Err() != nil {
+				exitWith(providerExitCode(err), err, "Rewrite interrupted; history is unchanged")
+			}
+			log.Warn().Err(err).Str("commit", c.ShortHash).Msg("Skipping commit")
+			continue
+		}
+		if proposed == strings.TrimSpace(c.Message) {
+			fmt.Println("  unchanged")
+			continue
+		}
+		fmt.Println(renderRewrite(c, proposed))
+		if dryRun {
+			continue
+		}
+		accept, quit := yes, false
+		if !yes {
+			accept, quit = askRewrite()
+		}
+		if quit {
+			break
+		}
+		if accept {
+			rewrites[c.Hash] = proposed
+		}
+	}
+
+	if dryRun || len(rewrites) == 0 {
+		if !dryRun {
+			fmt.Println("No message accepted; history is unchanged.")
+		}
+		return
+	}
+	if !yes {
+		fmt.Printf("\nRewrite %d commit message(s)? This changes the hashes of those commits and every later one;\n", len(rewrites))
+		fmt.Print("already-pushed branches will need a force push. (y/N): ")
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Println("Aborted; history is unchanged.")
+			return
+		}
+	}
+	newHead, err := git.RewordCommits(ctx, rewrites)
+	if err != nil {
+		exitWith(exitFailure, err, "Failed to rewrite history")
+	}
+	fmt.Printf("Rewrote %d message(s); HEAD is now %s (previous tip saved in ORIG_HEAD).\n", len(rewrites), newHead[:7])
+}
+
+// askRewrite asks whether to accept the proposal just shown. Quitting keeps the
+// proposals accepted so far and skips the remaining commits.
+func askRewrite() (accept, quit bool) {
+	fmt.Print("Use the proposed message? [Y]es / [n]o / [q]uit: ")
+	var answer string
+	fmt.Scanln(&answer)
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true, false
+	case "q", "quit":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// renderRewrite shows the current and proposed messages of c side by side.
+func renderRewrite(c git.CommitInfo, proposed string) string {
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(rewriteColumnWidth)
+	newStyle := boxStyle.BorderForeground(lipgloss.Color("63"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+	current := boxStyle.Render(titleStyle.Render("Current "+c.ShortHash) + "\n\n" + strings.TrimSpace(c.Message))
+	next := newStyle.Render(titleStyle.Render("Proposed") + "\n\n" + proposed)
+	return lipgloss.JoinHorizontal(lipgloss.Top, current, next)
+}
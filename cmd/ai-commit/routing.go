@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
+	"github.com/renatogalera/ai-commit/pkg/routing"
+)
+
+// applyRouting returns the client selected by the routing rules for diff, or
+// aiClient when no rule applies. An explicit --provider or --model always wins.
+// If the selected rule asks for it, a code review is printed with the routed client.
+func applyRouting(ctx context.Context, cfg *config.Config, aiClient ai.AIClient, diff string) ai.AIClient {
+	if providerFlag != "" || modelFlag != "" {
+		return aiClient
+	}
+	decision, ok := routing.Select(diff, cfg.Routing)
+	if !ok {
+		return aiClient
+	}
+
+	client := aiClient
+	rule := decision.Rule
+	if rule.Provider != "" || rule.Model != "" {
+		provider := rule.Provider
+		if provider == "" {
+			provider = cfg.Provider
+		}
+		routed, err := newProviderClient(ctx, cfg, provider, rule.Model, false)
+		if err != nil {
+			log.Warn().Err(err).Str("provider", provider).Msg("Routing: failed to initialize provider; using the default")
+			return aiClient
+		}
+		client = routed
+		fmt.Fprintf(os.Stderr, "Routing to %s (%s covers %.0f%% of the diff)\n",
+			providerSpec{Provider: provider, Model: rule.Model}, decision.Pattern, decision.Share*100)
+	}
+
+	if rule.CodeReview {
+		review, err := client.GetCommitMessage(ctx, prompt.BuildCodeReviewPrompt(diff, languageFlag, cfg.PromptTemplate))
+		if err != nil {
+			log.Warn().Err(err).Msg("Routing: code review failed")
+		} else {
+			fmt.Fprintln(os.Stderr, formatReviewOutput("AI Code Review Suggestions", withSource(strings.TrimSpace(review), report.SourceOf(client))))
+		}
+	}
+	return client
+}
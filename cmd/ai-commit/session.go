@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/guard"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/session"
+	"github.com/renatogalera/ai-commit/pkg/ui"
+)
+
+func newSessionCmd(setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error)) *cobra.Command {
+	sessionCmd := &cobra.Command{
+		Use:   "session",
+		Short: "Save and resume commit-crafting sessions",
+		Long:  "Sessions store the diff snapshot, prompt, generated candidates, and edits of a commit so it can be resumed later. Press 's' in the TUI to save the current one.",
+	}
+
+	var contextFlag string
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Snapshot the staged changes and prompt without calling the AI",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runSessionSave(setupAIEnvironment, args[0], contextFlag)
+		},
+	}
+	saveCmd.Flags().StringVar(&contextFlag, "context", "", "Additional context to include in the saved prompt")
+
+	loadCmd := &cobra.Command{
+		Use:   "load <name>",
+		Short: "Resume a saved session in the interactive UI",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runSessionLoad(setupAIEnvironment, args[0])
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved sessions",
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, err := session.Dir()
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to locate session directory")
+			}
+			sessions, err := session.List(dir)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to list sessions")
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No saved sessions.")
+				return
+			}
+			for _, s := range sessions {
+				subject := strings.SplitN(s.Message, "\n", 2)[0]
+				if subject == "" {
+					subject = "(no message yet)"
+				}
+				fmt.Printf("%-30s %s  %s\n", s.Name, s.SavedAt.Format("2006-01-02 15:04"), subject)
+			}
+		},
+	}
+
+	sessionCmd.AddCommand(saveCmd)
+	sessionCmd.AddCommand(loadCmd)
+	sessionCmd.AddCommand(listCmd)
+	return sessionCmd
+}
+
+func runSessionSave(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	name string,
+	userContext string,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for session command")
+		return
+	}
+	defer cancel()
+
+	diff, _, err := git.GetPromptDiff(ctx, cfg.LockFiles)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get Git diff")
+	}
+	raw, err := git.GetStagedDiff(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get Git diff")
+	}
+	if strings.TrimSpace(raw) == "" {
+		fmt.Println("No staged changes.")
+		return
+	}
+	if strings.TrimSpace(diff) == "" {
+		diff = raw
+	}
+	if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
+		if summarized, did := aiClient.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
+			diff = summarized
+		}
+	}
+
+	dir, err := session.Dir()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to locate session directory")
+	}
+	path, err := session.Save(dir, session.Session{
+		Name:        name,
+		Diff:        diff,
+		BaseDiff:    diff,
+		RawDiff:     raw,
+		Prompt:      prompt.BuildCommitPrompt(diff, languageFlag, "", userContext, cfg.PromptTemplate, git.SuggestScope(diff)),
+		Language:    languageFlag,
+		UserContext: userContext,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to save session")
+	}
+	fmt.Printf("Session %q saved to %s\n", name, path)
+}
+
+func runSessionLoad(
+	setupAIEnvironment func() (context.Context, context.CancelFunc, *config.Config, ai.AIClient, error),
+	name string,
+) {
+	ctx, cancel, cfg, aiClient, err := setupAIEnvironment()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for session command")
+		return
+	}
+	defer cancel()
+
+	dir, err := session.Dir()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to locate session directory")
+	}
+	s, err := session.Load(dir, name)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load session")
+	}
+
+	if s.RawDiff != "" {
+		if current, err := git.GetStagedDiff(ctx); err == nil && current != s.RawDiff {
+			fmt.Fprintln(os.Stderr, "Warning: the staged changes differ from the session snapshot; committing uses what is staged now.")
+		}
+	}
+
+	language := s.Language
+	if language == "" {
+		language = languageFlag
+	}
+	startStreaming := strings.TrimSpace(s.Message) == "" && supportsStreaming(aiClient)
+	uiModel := ui.NewUIModel(
+		s.Message,
+		s.Diff,
+		language,
+		s.Prompt,
+		s.CommitType,
+		templateFlag,
+		"",
+		cfg.EnableEmoji,
+		aiClient,
+		startStreaming,
+		cfg.PromptTemplate,
+		cfg.TicketPattern,
+		git.SuggestScope(s.Diff),
+	).WithSession(s).WithGuard(guard.NewScanner(cfg.Guardrails)).WithEvents(eventBus(cfg)).WithPlugins(pluginRunner(cfg)).WithInstantQuit(cfg.InstantQuit)
+	program := ui.NewProgram(uiModel)
+	releaseShare := holdShare()
+	if _, err := program.Run(); err != nil {
+		log.Fatal().Err(err).Msg("UI encountered an error")
+	}
+	releaseShare()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/share"
+)
+
+// While the TUI runs, share snippets are held in sharePending and printed once
+// it exits, so they are not drawn over.
+var (
+	shareMu      sync.Mutex
+	shareHeld    bool
+	sharePending []string
+)
+
+// shareEnabled reports whether --share, share, or shareTemplate asks for share
+// snippets.
+func shareEnabled(cfg *config.Config) bool {
+	return shareFlag || cfg.Share || cfg.ShareTemplate != ""
+}
+
+// subscribeShare prints a share snippet of every commit published on bus and
+// copies it to the clipboard. The link points at the commit on the origin
+// remote, when that is a web host.
+func subscribeShare(cfg *config.Config, bus *events.Bus) {
+	if !shareEnabled(cfg) {
+		return
+	}
+	events.On(bus, func(e events.CommitCreated) {
+		if e.Hash == "" {
+			return
+		}
+		remote, err := git.RemoteURL("origin")
+		if err != nil {
+			log.Debug().Err(err).Msg("No origin remote for the share link")
+		}
+		snippet := share.Render(cfg.ShareTemplate, share.Commit{Hash: e.Hash, Message: e.Message, URL: share.CommitURL(remote, e.Hash)})
+		note := "(copied to the clipboard)"
+		if err := share.Copy(snippet); err != nil {
+			log.Debug().Err(err).Msg("Cannot copy the share snippet")
+			note = "(not copied: " + err.Error() + ")"
+		}
+		if quietFlag {
+			return
+		}
+		out := fmt.Sprintf("\nShare %s:\n%s\n", note, snippet)
+		shareMu.Lock()
+		defer shareMu.Unlock()
+		if shareHeld {
+			sharePending = append(sharePending, out)
+			return
+		}
+		fmt.Print(out)
+	})
+}
+
+// holdShare holds share snippets until the returned function prints them.
+func holdShare() (release func()) {
+	shareMu.Lock()
+	shareHeld = true
+	shareMu.Unlock()
+	return func() {
+		shareMu.Lock()
+		defer shareMu.Unlock()
+		shareHeld = false
+		for _, out := range sharePending {
+			fmt.Print(out)
+		}
+		sharePending = nil
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// checkSplitFlags validates --split-by, which only shapes the splitter of
+// --interactive-split and --auto-split.
+func checkSplitFlags() error {
+	switch splitByFlag {
+	case "", "hunk", "file":
+	default:
+		return fmt.Errorf("invalid --split-by %q (want hunk or file)", splitByFlag)
+	}
+	if splitByFlag != "" && !interactiveSplitFlag && !autoSplitFlag {
+		return errors.New("--split-by requires --interactive-split or --auto-split")
+	}
+	return nil
+}
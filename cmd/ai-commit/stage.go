@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// stageTracked stages every modified and deleted tracked file for --all, as
+// "git commit -a" does. Unless --force is set it lists the files and asks
+// first; the prompt goes to stderr so it stays out of --print output. Without a
+// terminal to answer it (e.g. --msg-only in a hook), it stops instead of asking.
+func stageTracked(ctx context.Context) {
+	files, err := git.UnstagedTrackedFiles(ctx)
+	if err != nil {
+		exitWith(exitFailure, err, "Cannot list the modified files")
+	}
+	if len(files) == 0 {
+		return
+	}
+	if !forceFlag && !interactiveTerminal() {
+		exitWith(exitConfig, errors.New("no terminal to confirm staging"), "Use --force with --all to stage without asking")
+	}
+	if !forceFlag {
+		fmt.Fprintf(os.Stderr, "Stage %d modified tracked file(s)?\n", len(files))
+		for _, f := range files {
+			fmt.Fprintf(os.Stderr, "  %s\n", f)
+		}
+		fmt.Fprint(os.Stderr, "(y/N): ")
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Fprintln(os.Stderr, "Aborted; nothing was staged.")
+			os.Exit(exitFailure)
+		}
+	}
+	if err := git.StageTracked(ctx); err != nil {
+		exitWith(exitFailure, err, "Cannot stage the modified files")
+	}
+	verbosef("staged %d modified tracked file(s)", len(files))
+}
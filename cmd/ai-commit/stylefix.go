@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// maxStyleFixRounds bounds how often --fix-message regenerates, since a
+// reviewer can keep finding something to improve.
+const maxStyleFixRounds = 2
+
+// fixCommitMessageStyle feeds the style review suggestions back into the commit
+// prompt and reviews the corrected message again, until the review finds no
+// issues or maxStyleFixRounds is reached. It returns the message and the review
+// of it, which lists what is left to fix.
+func fixCommitMessageStyle(ctx context.Context, cfg *config.Config, aiClient ai.AIClient, promptText, commitType, commitMsg, suggestions string) (string, string, error) {
+	for round := 0; round < maxStyleFixRounds && prompt.HasStyleIssues(suggestions); round++ {
+		if !quietFlag {
+			fmt.Fprintln(os.Stderr, formatReviewOutput("Style review found issues; regenerating", suggestions))
+		}
+		fixed, err := generateCommitMessage(ctx, aiClient, promptText+prompt.StyleFixHint(commitMsg, suggestions), commitType, templateFlag, cfg.EnableEmoji, cfg.TicketPattern)
+		if err != nil {
+			return "", "", fmt.Errorf("regeneration with the style review failed: %w", err)
+		}
+		if commitMsg, err = enforceGuardrails(ctx, cfg, aiClient, promptText, commitType, fixed); err != nil {
+			return "", "", err
+		}
+		if suggestions, err = enforceCommitMessageStyle(ctx, aiClient, commitMsg, languageFlag, cfg.PromptTemplate); err != nil {
+			return "", "", err
+		}
+	}
+	return commitMsg, suggestions, nil
+}
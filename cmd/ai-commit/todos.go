@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// todosEnabled reports whether --todos or any todos setting asks for the
+// markers a commit adds.
+func todosEnabled(cfg *config.Config) bool {
+	return todosFlag || cfg.Todos.Enabled || cfg.Todos.Body || cfg.Todos.File != ""
+}
+
+// addedTodos returns the TODO-style markers rawDiff adds when they are enabled,
+// and subscribes the tracking file of todos.file to the commit.
+func addedTodos(cfg *config.Config, rawDiff string) []git.Todo {
+	if !todosEnabled(cfg) {
+		return nil
+	}
+	todos := git.AddedTodos(rawDiff)
+	verbosef("TODO markers added: %d", len(todos))
+	if len(todos) > 0 && cfg.Todos.File != "" {
+		events.On(eventBus(cfg), func(e events.CommitCreated) {
+			if err := appendTodoFile(cfg.Todos.File, e.Hash, e.Message, todos); err != nil {
+				log.Warn().Err(err).Msg("Cannot update the TODO tracking file")
+			}
+		})
+	}
+	return todos
+}
+
+// appendTodoFile appends todos as unchecked checklist items naming the commit
+// to the tracking file at path, relative to the repository root.
+func appendTodoFile(path, hash, message string, todos []git.Todo) error {
+	if !filepath.IsAbs(path) {
+		root, err := git.RepoRoot()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(root, path)
+	}
+	short := hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	var b strings.Builder
+	for _, t := range todos {
+		fmt.Fprintf(&b, "- [ ] %s (%s %s)\n", t, short, subject)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printTodos lists todos after a non-interactive commit.
+func printTodos(todos []git.Todo) {
+	if len(todos) == 0 || quietFlag {
+		return
+	}
+	fmt.Printf("Follow-up markers added by this commit (%d):\n", len(todos))
+	for _, t := range todos {
+		fmt.Printf("  - %s\n", t)
+	}
+}
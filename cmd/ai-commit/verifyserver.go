@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/lint"
+	"github.com/renatogalera/ai-commit/pkg/verify"
+)
+
+// verifyPushTimeout bounds a whole pre-receive verification round trip.
+const verifyPushTimeout = 5 * time.Minute
+
+// verifyToken returns the --token flag, or AI_COMMIT_VERIFY_TOKEN so the secret
+// need not appear on the command line (or in --help defaults).
+func verifyToken(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv("AI_COMMIT_VERIFY_TOKEN")
+}
+
+func newVerifyServerCmd() *cobra.Command {
+	var addrFlag, tokenFlag string
+	var noAIFlag, failOpenFlag, noAuthFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "verify-server",
+		Short: "Serve commit verification for pre-receive hooks and CI",
+		Long: "Starts an HTTP service that verifies pushed commits. POST /verify takes {\"commits\":[{\"hash\",\"message\",\"diff\"}]} " +
+			"and answers with a pass/fail verdict per commit: each message must follow the configured Conventional Commits types " +
+			"(the lint-history rules) and, unless --no-ai is set, the AI must confirm that it describes its diff. " +
+			"Pair it with \"ai-commit verify-push\" in a pre-receive hook to enforce the rules on every push.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runVerifyServer(addrFlag, verifyToken(tokenFlag), noAuthFlag, !noAIFlag, failOpenFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "AI provider: openai, google, anthropic, deepseek, ollama, openrouter")
+	cmd.Flags().StringVar(&modelFlag, "model", "", "Sub-model for the chosen provider")
+	cmd.Flags().StringVar(&addrFlag, "addr", "127.0.0.1:8080", "Address to listen on (e.g. :8080 for every interface)")
+	cmd.Flags().StringVar(&tokenFlag, "token", "", "Bearer token clients must send (default $AI_COMMIT_VERIFY_TOKEN)")
+	cmd.Flags().BoolVar(&noAuthFlag, "no-auth", false, "Serve without a token, letting anyone who can reach the server use the AI provider")
+	cmd.Flags().BoolVar(&noAIFlag, "no-ai", false, "Only check the commit conventions; skip the message-vs-diff check")
+	cmd.Flags().BoolVar(&failOpenFlag, "fail-open", false, "Accept commits whose message-vs-diff check fails to run (e.g. the provider is down)")
+
+	return cmd
+}
+
+func runVerifyServer(addr, token string, noAuth, useAI, failOpen bool) {
+	switch {
+	case token == "" && !noAuth:
+		log.Fatal().Msg("verify-server needs a token: pass --token or set AI_COMMIT_VERIFY_TOKEN (or --no-auth to serve without one)")
+	case token != "" && noAuth:
+		log.Fatal().Msg("--no-auth cannot be combined with a token")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for verify-server command")
+	}
+	verifier := &verify.Verifier{
+		Lint:     lint.Options{EnableEmoji: cfg.EnableEmoji},
+		Timeout:  evalRequestTimeout,
+		FailOpen: failOpen,
+	}
+	if useAI {
+		setupCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		verifier.Client, err = initAIClient(setupCtx, cfg)
+		cancel()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize AI client")
+		}
+		if cfg.Limits.Diff.Enabled {
+			verifier.MaxDiffChars = cfg.Limits.Diff.MaxChars
+		}
+	}
+	if noAuth {
+		log.Warn().Msg("--no-auth: anyone who can reach the server can use it")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           verifier.Handler(token),
+		ReadHeaderTimeout: 10 * time.Second,
+		// A request runs its AI checks one commit at a time, so writing the
+		// verdict may take as long as a whole push verification.
+		WriteTimeout: verifyPushTimeout,
+		IdleTimeout:  2 * time.Minute,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info().Str("addr", addr).Bool("ai", useAI).Msg("verify-server listening")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal().Err(err).Msg("verify-server stopped")
+	}
+}
+
+func newVerifyPushCmd() *cobra.Command {
+	var serverFlag, tokenFlag string
+
+	cmd := &cobra.Command{
+		Use:   "verify-push",
+		Short: "Check a push against a verify-server (run from a pre-receive hook)",
+		Long: "Reads \"<old> <new> <ref>\" lines from stdin, as git passes them to a pre-receive hook, collects the commits " +
+			"the push introduces, and sends them to a verify-server. Failing commits are reported to the pusher and the " +
+			"command exits with status 1, which makes git reject the push.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runVerifyPush(serverFlag, verifyToken(tokenFlag))
+		},
+	}
+
+	cmd.Flags().StringVar(&serverFlag, "server", os.Getenv("AI_COMMIT_VERIFY_URL"), "verify-server base URL (default $AI_COMMIT_VERIFY_URL)")
+	cmd.Flags().StringVar(&tokenFlag, "token", "", "Bearer token for the server (default $AI_COMMIT_VERIFY_TOKEN)")
+
+	return cmd
+}
+
+func runVerifyPush(serverURL, token string) {
+	if strings.TrimSpace(serverURL) == "" {
+		log.Fatal().Msg("No verify-server URL: pass --server or set AI_COMMIT_VERIFY_URL")
+	}
+	// Loading the config sets privacy.neverSendPaths, so the diffs sent to the
+	// server leave out private files.
+	if _, err := loadConfig(); err != nil {
+		log.Fatal().Err(err).Msg("Setup environment error for verify-push command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), verifyPushTimeout)
+	defer cancel()
+
+	var req verify.Request
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.Trim(fields[1], "0") == "" {
+			continue // malformed line or branch deletion
+		}
+		commits, err := git.PushedCommits(ctx, fields[1])
+		if err != nil {
+			log.Fatal().Err(err).Str("ref", fields[2]).Msg("Failed to read pushed commits")
+		}
+		for _, c := range commits {
+			if !seen[c.Hash] {
+				seen[c.Hash] = true
+				req.Commits = append(req.Commits, verify.Commit{Hash: c.Hash, Message: c.Message, Diff: c.Diff})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to read the pushed refs")
+	}
+	if len(req.Commits) == 0 {
+		return
+	}
+
+	// The server takes at most verify.MaxRequestCommits commits per request.
+	resp := verify.Response{Pass: true}
+	for start := 0; start < len(req.Commits); start += verify.MaxRequestCommits {
+		batch := verify.Request{Commits: req.Commits[start:min(start+verify.MaxRequestCommits, len(req.Commits))]}
+		part, err := verify.Post(ctx, serverURL, token, batch)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Commit verification failed; rejecting the push")
+		}
+		resp.Pass = resp.Pass && part.Pass
+		resp.Commits = append(resp.Commits, part.Commits...)
+	}
+	fmt.Println(resp.Report())
+	if !resp.Pass {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,207 @@
+package testutil_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/internal/testutil"
+)
+
+// binary is the ai-commit command built for the pipeline tests.
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "ai-commit-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binary = filepath.Join(dir, "ai-commit")
+	// Build before any test isolates HOME, which holds the build and module caches.
+	if out, err := exec.Command("go", "build", "-o", binary, "../../cmd/ai-commit").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building ai-commit: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// provider is an OpenAI-compatible server that answers every chat completion
+// with reply and records the prompts it received.
+type provider struct {
+	*httptest.Server
+	mu      sync.Mutex
+	prompts []string
+}
+
+func newProvider(t *testing.T, reply string) *provider {
+	t.Helper()
+	p := &provider{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var prompt []string
+		for _, m := range req.Messages {
+			prompt = append(prompt, m.Content)
+		}
+		p.mu.Lock()
+		p.prompts = append(p.prompts, strings.Join(prompt, "\n"))
+		p.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"model":   "test",
+			"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+// Prompts returns the prompts received so far.
+func (p *provider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.prompts)
+}
+
+// commit runs ai-commit --force in repo against p with args added, failing the
+// test if it exits with an error, and returns what it printed to stderr.
+func commit(t *testing.T, repo *testutil.Repo, p *provider, args ...string) string {
+	t.Helper()
+	cmd := repo.Command(binary, append([]string{"--force", "--provider", "openai", "--baseURL", p.URL, "--apiKey", "test"}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("ai-commit: %v\n%s%s", err, stdout.String(), stderr.String())
+	}
+	return stderr.String()
+}
+
+func TestPipeline_Integration(t *testing.T) {
+	t.Parallel()
+	repo := testutil.NewRepoDir(t)
+	repo.Write("README.md", "# Test\n\nUsage notes.\n")
+	repo.Write("cache.go", "package cache\n")
+	repo.Write("go.sum", "example.com/x v1.0.0 h1:abc=\n")
+	repo.Stage()
+
+	p := newProvider(t, "feat(cache): add cache package")
+	commit(t, repo, p)
+
+	if got := repo.Subjects(2); !slices.Equal(got, []string{"feat(cache): add cache package", "initial commit"}) {
+		t.Errorf("log = %q", got)
+	}
+	if got := repo.Files("HEAD"); !slices.Equal(got, []string{"README.md", "cache.go", "go.sum"}) {
+		t.Errorf("committed files = %q", got)
+	}
+	prompts := p.Prompts()
+	if len(prompts) != 1 {
+		t.Fatalf("prompts = %d, want 1", len(prompts))
+	}
+	if got := prompts[0]; !strings.Contains(got, "Usage notes.") || !strings.Contains(got, "package cache") {
+		t.Errorf("prompt is missing the staged changes:\n%s", got)
+	}
+	if got := prompts[0]; strings.Contains(got, "h1:abc=") {
+		t.Errorf("prompt includes the lock file:\n%s", got)
+	}
+}
+
+func TestPipelineRenameOnly_Integration(t *testing.T) {
+	t.Parallel()
+	repo := testutil.NewRepoDir(t)
+	repo.Rename("README.md", "docs/README.md")
+
+	p := newProvider(t, "feat: should not be asked")
+	commit(t, repo, p)
+
+	if n := len(p.Prompts()); n != 0 {
+		t.Errorf("a rename-only commit asked the AI %d time(s)", n)
+	}
+	if msg := repo.Message("HEAD"); !strings.Contains(msg, "docs/README.md") {
+		t.Errorf("message = %q, want it to name the new path", msg)
+	}
+	if got := repo.Files("HEAD"); !slices.Equal(got, []string{"docs/README.md"}) {
+		t.Errorf("committed files = %q", got)
+	}
+}
+
+func TestPipelineBinaryFile_Integration(t *testing.T) {
+	t.Parallel()
+	repo := testutil.NewRepoDir(t)
+	repo.WriteBinary("logo.png")
+	repo.Write("index.html", "<img src=\"logo.png\">\n")
+	repo.Stage()
+
+	p := newProvider(t, "feat: add logo")
+	stderr := commit(t, repo, p, "--show-filtered")
+	if _, report, _ := strings.Cut(stderr, "Filtered from the prompt"); !strings.Contains(report, "logo.png") {
+		t.Errorf("filter report does not list the binary file:\n%s", stderr)
+	}
+	if got := p.Prompts()[0]; strings.Contains(got, "PNG") || !strings.Contains(got, "index.html") {
+		t.Errorf("prompt should describe index.html without the binary content:\n%s", got)
+	}
+	if got := repo.Files("HEAD"); !slices.Equal(got, []string{"README.md", "index.html", "logo.png"}) {
+		t.Errorf("committed files = %q", got)
+	}
+}
+
+func TestPipelinePartialStaging_Integration(t *testing.T) {
+	t.Parallel()
+	repo := testutil.NewRepoDir(t)
+	repo.StagePartial("README.md", "# Test\n\nStaged line.\n", "# Test\n\nStaged line.\nUnstaged line.\n")
+
+	p := newProvider(t, "docs: add staged line")
+	commit(t, repo, p)
+
+	if got := p.Prompts()[0]; !strings.Contains(got, "Staged line.") || strings.Contains(got, "Unstaged line.") {
+		t.Errorf("prompt should hold only the staged hunk:\n%s", got)
+	}
+	if got := repo.Git("show", "HEAD:README.md"); got != "# Test\n\nStaged line." {
+		t.Errorf("committed README.md = %q", got)
+	}
+	if got := repo.Git("status", "--porcelain"); got != "M README.md" {
+		t.Errorf("status after commit = %q, want the unstaged line left in the work tree", got)
+	}
+}
+
+func TestPipelineSubmodule_Integration(t *testing.T) {
+	t.Parallel()
+	repo := testutil.NewRepoDir(t)
+	repo.AddSubmodule("vendor/lib")
+
+	commit(t, repo, newProvider(t, "chore: add lib submodule"))
+
+	if got := repo.Subjects(1); got[0] != "chore: add lib submodule" {
+		t.Errorf("HEAD subject = %q", got[0])
+	}
+	if got := repo.Git("ls-tree", "HEAD", "vendor/lib"); !strings.HasPrefix(got, "160000 commit ") {
+		t.Errorf("vendor/lib in HEAD = %q, want a submodule entry", got)
+	}
+	if !slices.Contains(repo.Files("HEAD"), ".gitmodules") {
+		t.Error("HEAD is missing .gitmodules")
+	}
+	if staged := repo.Staged(); len(staged) != 0 {
+		t.Errorf("still staged after the commit: %q", staged)
+	}
+}
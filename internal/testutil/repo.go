@@ -0,0 +1,220 @@
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Repo is a temporary Git repository for integration tests, with one initial
+// commit holding README.md. Its helpers script the staged changes a test needs:
+// renames, binary files, partial staging, and submodules.
+type Repo struct {
+	t *testing.T
+	// Dir is the top directory of the work tree.
+	Dir string
+	// env holds the variables that isolate the repository from the user's
+	// configuration.
+	env []string
+}
+
+// scrubbed are the variables that would point git at another repository or
+// identity.
+var scrubbed = []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_AUTHOR_DATE", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL", "GIT_COMMITTER_DATE", "GIT_DIR", "GIT_WORK_TREE", "GIT_INDEX_FILE"}
+
+// NewRepo creates a repository in a temporary directory and changes into it for
+// the rest of the test, as ai-commit resolves the repository from the current
+// directory, so tests using it cannot run in parallel. Git runs with a test
+// identity and without the user's global and system config, so signing or
+// hooks set up there do not interfere; HOME points to an empty temporary
+// directory for the same reason.
+func NewRepo(t *testing.T) *Repo {
+	t.Helper()
+	r := NewRepoDir(t)
+	for _, kv := range r.env {
+		name, value, _ := strings.Cut(kv, "=")
+		t.Setenv(name, value)
+	}
+	for _, name := range scrubbed {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	t.Chdir(r.Dir)
+	return r
+}
+
+// NewRepoDir is NewRepo without changing into the repository or the process
+// environment, for tests that run ai-commit or git through Command. Such tests
+// may run in parallel.
+func NewRepoDir(t *testing.T) *Repo {
+	t.Helper()
+	return create(t, isolation(t))
+}
+
+// isolation returns the variables pointing git and HOME away from the user's
+// configuration.
+func isolation(t *testing.T) []string {
+	t.Helper()
+	home := t.TempDir()
+	global := filepath.Join(home, ".gitconfig")
+	// Local submodules are cloned over the file transport, which git blocks by default.
+	config := "[user]\n\tname = Test\n\temail = test@example.com\n" +
+		"[init]\n\tdefaultBranch = main\n" +
+		"[protocol \"file\"]\n\tallow = always\n"
+	if err := os.WriteFile(global, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return []string{
+		"HOME=" + home,
+		"XDG_CONFIG_HOME=" + filepath.Join(home, ".config"),
+		"GIT_CONFIG_GLOBAL=" + global,
+		"GIT_CONFIG_NOSYSTEM=1",
+	}
+}
+
+// create initializes a repository in a new temporary directory, with env, without
+// changing into it.
+func create(t *testing.T, env []string) *Repo {
+	t.Helper()
+	r := &Repo{t: t, Dir: t.TempDir(), env: env}
+	r.Git("init", "-q")
+	r.Write("README.md", "# Test\n")
+	r.Stage("README.md")
+	r.Commit("initial commit")
+	return r
+}
+
+// Command returns a command running name in the work tree with the isolated
+// environment.
+func (r *Repo) Command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = r.Dir
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); !slices.Contains(scrubbed, key) {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	cmd.Env = append(cmd.Env, r.env...)
+	return cmd
+}
+
+// Git runs git in the work tree and returns its trimmed output, failing the
+// test if it exits with an error.
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	out, err := r.Command("git", args...).CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Write writes content to the repository-relative path, creating directories
+// as needed. The change is not staged.
+func (r *Repo) Write(path, content string) {
+	r.t.Helper()
+	r.WriteBytes(path, []byte(content))
+}
+
+// WriteBytes is Write for raw contents.
+func (r *Repo) WriteBytes(path string, data []byte) {
+	r.t.Helper()
+	full := filepath.Join(r.Dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(full, data, 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// WriteBinary writes a small binary file (a PNG header followed by NUL bytes)
+// to path. The change is not staged.
+func (r *Repo) WriteBinary(path string) {
+	r.t.Helper()
+	r.WriteBytes(path, append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...))
+}
+
+// Stage adds paths to the index; with no paths it stages every change.
+func (r *Repo) Stage(paths ...string) {
+	r.t.Helper()
+	if len(paths) == 0 {
+		r.Git("add", "-A")
+		return
+	}
+	r.Git(append([]string{"add", "--"}, paths...)...)
+}
+
+// StagePartial stages staged as the content of path while leaving worktree in
+// the work tree, as `git add -p` does when only some hunks are staged.
+func (r *Repo) StagePartial(path, staged, worktree string) {
+	r.t.Helper()
+	r.Write(path, staged)
+	r.Stage(path)
+	r.Write(path, worktree)
+}
+
+// Rename moves a tracked file and stages the move.
+func (r *Repo) Rename(from, to string) {
+	r.t.Helper()
+	if dir := filepath.Dir(filepath.Join(r.Dir, filepath.FromSlash(to))); dir != r.Dir {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.Git("mv", from, to)
+}
+
+// AddSubmodule creates a separate repository and stages it as a submodule at
+// path, returning the submodule's directory in the work tree.
+func (r *Repo) AddSubmodule(path string) string {
+	r.t.Helper()
+	sub := create(r.t, r.env)
+	r.Git("submodule", "add", "-q", sub.Dir, path)
+	return filepath.Join(r.Dir, filepath.FromSlash(path))
+}
+
+// Commit commits what is staged with message and returns the new HEAD hash.
+func (r *Repo) Commit(message string) string {
+	r.t.Helper()
+	r.Git("commit", "-q", "--no-verify", "-m", message)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// Subjects returns the subjects of the last n commits, newest first.
+func (r *Repo) Subjects(n int) []string {
+	r.t.Helper()
+	return strings.Split(r.Git("log", "--format=%s", "-n", strconv.Itoa(n)), "\n")
+}
+
+// Message returns the full message of rev.
+func (r *Repo) Message(rev string) string {
+	r.t.Helper()
+	return r.Git("log", "-1", "--format=%B", rev)
+}
+
+// Files returns the paths in the tree of rev.
+func (r *Repo) Files(rev string) []string {
+	r.t.Helper()
+	return nonEmpty(strings.Split(r.Git("ls-tree", "-r", "--name-only", rev), "\n"))
+}
+
+// Staged returns the paths with staged changes.
+func (r *Repo) Staged() []string {
+	r.t.Helper()
+	return nonEmpty(strings.Split(r.Git("diff", "--cached", "--name-only"), "\n"))
+}
+
+func nonEmpty(lines []string) []string {
+	var out []string
+	for _, l := range lines {
+		if l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -24,16 +24,45 @@ type StreamingAIClient interface {
     StreamCommitMessage(ctx context.Context, prompt string, onDelta func(delta string)) (final string, err error)
 }
 
+// EmbeddingAIClient is an optional interface for providers that can embed text.
+// An empty model selects the provider's default embedding model. The returned
+// vectors are in the same order as texts.
+type EmbeddingAIClient interface {
+    Embed(ctx context.Context, model string, texts []string) ([][]float64, error)
+}
+
+// ModelAIClient is an optional interface for clients that know which model they use.
+type ModelAIClient interface {
+	ModelName() string
+}
+
+// ModelOf returns the model used by client, or "" when it does not report one.
+func ModelOf(client AIClient) string {
+	if m, ok := client.(ModelAIClient); ok {
+		return m.ModelName()
+	}
+	return ""
+}
+
 type BaseAIClient struct {
 	Provider string
+	Model    string
+	// Sampling is sent with every request; see SetSampling.
+	Sampling Sampling
 }
 
 func (b *BaseAIClient) ProviderName() string {
 	return b.Provider
 }
 
+func (b *BaseAIClient) ModelName() string {
+	return b.Model
+}
+
+// SanitizeResponse strips the commentary, code fences, and quotes models wrap
+// around a message and, when commitType is set, the model's own type prefix.
 func (b *BaseAIClient) SanitizeResponse(message, commitType string) string {
-	message = strings.ReplaceAll(message, "```", "")
+	message = strings.ReplaceAll(stripCommentary(message), "```", "")
 	message = strings.TrimSpace(message)
 	if commitType != "" {
 		lines := strings.SplitN(message, "\n", 2)
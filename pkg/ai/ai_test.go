@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"testing"
 
 	"github.com/renatogalera/ai-commit/pkg/committypes"
@@ -45,6 +46,34 @@ func TestProviderName(t *testing.T) {
 	}
 }
 
+type modelClient struct{ BaseAIClient }
+
+func (modelClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return "", nil
+}
+
+func TestModelOf(t *testing.T) {
+	t.Parallel()
+	if got := ModelOf(&modelClient{BaseAIClient{Provider: "openai", Model: "gpt-4o"}}); got != "gpt-4o" {
+		t.Errorf("ModelOf() = %q, want %q", got, "gpt-4o")
+	}
+}
+
+func TestApplySampling(t *testing.T) {
+	t.Parallel()
+	c := &modelClient{}
+	if !SamplingOf(c).IsZero() {
+		t.Errorf("SamplingOf() of a new client = %+v, want provider defaults", SamplingOf(c))
+	}
+	temperature, seed := 0.0, int64(42)
+	if !ApplySampling(c, Sampling{Temperature: &temperature, Seed: &seed}) {
+		t.Fatal("ApplySampling() = false, want BaseAIClient to support sampling")
+	}
+	if got := SamplingOf(c); got.Temperature == nil || *got.Temperature != 0 || got.Seed == nil || *got.Seed != 42 {
+		t.Errorf("SamplingOf() = %+v, want temperature 0 and seed 42", got)
+	}
+}
+
 func TestSanitizeResponse(t *testing.T) {
 	t.Parallel()
 	b := &BaseAIClient{Provider: "test"}
@@ -121,6 +150,62 @@ func TestSanitizeResponse(t *testing.T) {
 			commitType: "fix",
 			want:       "resolve race condition",
 		},
+		{
+			name:    "fence with language tag",
+			message: "```text\nfeat(auth): add login\n\nAdds the /login endpoint.\n```",
+			want:    "feat(auth): add login\n\nAdds the /login endpoint.",
+		},
+		{
+			name:    "preface and fence with trailing commentary",
+			message: "Here's your commit message:\n\n```git\nfix: handle nil config\n```\n\nThis follows the Conventional Commits format.",
+			want:    "fix: handle nil config",
+		},
+		{
+			name:    "chatty preface without fence",
+			message: "Sure! Here is a concise commit message based on the diff:\nrefactor: split parser into lexer and parser",
+			want:    "refactor: split parser into lexer and parser",
+		},
+		{
+			name:    "bold label line",
+			message: "**Commit message:**\n\ndocs: update README",
+			want:    "docs: update README",
+		},
+		{
+			name:    "inline label",
+			message: "Commit message: chore: bump deps",
+			want:    "chore: bump deps",
+		},
+		{
+			name:    "surrounding quotes",
+			message: "\"feat: add dark mode\"",
+			want:    "feat: add dark mode",
+		},
+		{
+			name:    "curly quotes",
+			message: "“fix: correct typo in help text”",
+			want:    "fix: correct typo in help text",
+		},
+		{
+			name:    "single backticks",
+			message: "`perf: cache compiled regexps`",
+			want:    "perf: cache compiled regexps",
+		},
+		{
+			name:    "inner quotes are kept",
+			message: "\"fix: quote \"name\" in SQL\"",
+			want:    "\"fix: quote \"name\" in SQL\"",
+		},
+		{
+			name:       "bold subject with type stripping",
+			message:    "**✨ feat(ui): add split view**\n\nShows the diff next to the message.",
+			commitType: "feat",
+			want:       "add split view\n\nShows the diff next to the message.",
+		},
+		{
+			name:    "subject ending with a colon is kept",
+			message: "feat: support the following formats:\n\n- json\n- csv",
+			want:    "feat: support the following formats:\n\n- json\n- csv",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrProviderAuth marks requests the provider rejected for their API key or credentials.
+	ErrProviderAuth = errors.New("provider authentication failed")
+	// ErrProviderTimeout marks requests that ran out of time before the provider answered.
+	ErrProviderTimeout = errors.New("provider timed out")
+	// ErrProviderRateLimited marks requests the provider refused for exceeding a rate limit or quota.
+	ErrProviderRateLimited = errors.New("provider rate limit exceeded")
+	// ErrContextTooLarge marks requests whose prompt does not fit the model's context window.
+	ErrContextTooLarge = errors.New("prompt exceeds the model's context window")
+)
+
+// contextLengthMarkers are fragments of the 400 responses providers send for a
+// prompt longer than the context window, which they do not mark otherwise.
+var contextLengthMarkers = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"context window",
+	"prompt is too long",
+	"exceeds the maximum number of tokens",
+	"input token count",
+	"too many tokens",
+}
+
+// ClassifyStatus wraps err, returned for an HTTP response with status, so that
+// errors.Is matches ErrProviderAuth for 401/403, ErrProviderRateLimited for 429,
+// ErrContextTooLarge for 413 or a 400 about the context length, and
+// ErrProviderTimeout for 408/504. Other statuses leave err unchanged.
+func ClassifyStatus(err error, status int) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %w", ErrProviderAuth, err)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", ErrProviderRateLimited, err)
+	case http.StatusRequestEntityTooLarge:
+		return fmt.Errorf("%w: %w", ErrContextTooLarge, err)
+	case http.StatusBadRequest:
+		if isContextLengthError(err) {
+			return fmt.Errorf("%w: %w", ErrContextTooLarge, err)
+		}
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %w", ErrProviderTimeout, err)
+	}
+	return err
+}
+
+func isContextLengthError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range contextLengthMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTimeout reports whether err is a provider timeout: a timeout status, an
+// expired request context, or a network timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, ErrProviderTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Hint returns advice for the user on a classified provider error, or "" when
+// there is none.
+func Hint(err error) string {
+	switch {
+	case errors.Is(err, ErrProviderAuth):
+		return "check the API key of the provider"
+	case errors.Is(err, ErrProviderRateLimited):
+		return "wait a moment and retry, or configure a fallback provider"
+	case errors.Is(err, ErrContextTooLarge):
+		return "stage fewer changes, or enable limits.diff to truncate the diff"
+	case IsTimeout(err):
+		return "retry, or configure a fallback provider"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type netTimeout struct{}
+
+func (netTimeout) Error() string   { return "i/o timeout" }
+func (netTimeout) Timeout() bool   { return true }
+func (netTimeout) Temporary() bool { return true }
+
+func TestClassifyStatus(t *testing.T) {
+	t.Parallel()
+	base := errors.New("request failed")
+	tests := []struct {
+		status                 int
+		err                    error
+		auth, tmout, rate, ctx bool
+	}{
+		{status: 401, auth: true},
+		{status: 403, auth: true},
+		{status: 408, tmout: true},
+		{status: 504, tmout: true},
+		{status: 429, rate: true},
+		{status: 413, ctx: true},
+		{status: 400, err: errors.New(`400 Bad Request: {"code": "context_length_exceeded"}`), ctx: true},
+		{status: 400, err: errors.New("prompt is too long: 210000 tokens > 200000 maximum"), ctx: true},
+		{status: 400},
+		{status: 500},
+	}
+	for _, tt := range tests {
+		in := base
+		if tt.err != nil {
+			in = tt.err
+		}
+		err := ClassifyStatus(in, tt.status)
+		if !errors.Is(err, in) {
+			t.Errorf("ClassifyStatus(%d) lost the original error: %v", tt.status, err)
+		}
+		if got := errors.Is(err, ErrProviderAuth); got != tt.auth {
+			t.Errorf("ClassifyStatus(%d) auth = %v, want %v", tt.status, got, tt.auth)
+		}
+		if got := IsTimeout(err); got != tt.tmout {
+			t.Errorf("ClassifyStatus(%d) timeout = %v, want %v", tt.status, got, tt.tmout)
+		}
+		if got := errors.Is(err, ErrProviderRateLimited); got != tt.rate {
+			t.Errorf("ClassifyStatus(%d) rate limited = %v, want %v", tt.status, got, tt.rate)
+		}
+		if got := errors.Is(err, ErrContextTooLarge); got != tt.ctx {
+			t.Errorf("ClassifyStatus(%d, %q) context too large = %v, want %v", tt.status, in, got, tt.ctx)
+		}
+		if tt.auth || tt.tmout || tt.rate || tt.ctx {
+			if Hint(err) == "" {
+				t.Errorf("ClassifyStatus(%d) has no hint", tt.status)
+			}
+		} else if hint := Hint(err); hint != "" {
+			t.Errorf("ClassifyStatus(%d) hint = %q, want none", tt.status, hint)
+		}
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	t.Parallel()
+	if !IsTimeout(fmt.Errorf("generate: %w", context.DeadlineExceeded)) {
+		t.Error("an expired context should be a timeout")
+	}
+	if !IsTimeout(fmt.Errorf("post: %w", netTimeout{})) {
+		t.Error("a network timeout should be a timeout")
+	}
+	if IsTimeout(context.Canceled) || IsTimeout(errors.New("boom")) {
+		t.Error("cancellation and other errors are not timeouts")
+	}
+}
@@ -0,0 +1,66 @@
+package ai
+
+// Sampling holds the generation parameters that make a provider's output
+// repeatable. A nil field keeps the provider's default.
+type Sampling struct {
+	Temperature *float64
+	// Seed is honored by OpenAI-compatible providers, Gemini, and Ollama;
+	// Anthropic has no seed and only uses the temperature.
+	Seed *int64
+}
+
+// IsZero reports whether s leaves every parameter at the provider default.
+func (s Sampling) IsZero() bool {
+	return s.Temperature == nil && s.Seed == nil
+}
+
+// SamplingAIClient is an optional interface for clients that know their
+// generation parameters. BaseAIClient implements it, so every provider does.
+type SamplingAIClient interface {
+	SamplingParams() Sampling
+}
+
+// SamplingChecker is an optional interface for clients whose API accepts a
+// narrower range of generation parameters than Sampling allows.
+type SamplingChecker interface {
+	CheckSampling(s Sampling) error
+}
+
+// CheckSampling returns an error when the API of client would reject s, so
+// the run fails before any request rather than on each one.
+func CheckSampling(client AIClient, s Sampling) error {
+	if c, ok := client.(SamplingChecker); ok {
+		return c.CheckSampling(s)
+	}
+	return nil
+}
+
+// SetSampling sets the generation parameters of the following requests.
+func (b *BaseAIClient) SetSampling(s Sampling) {
+	b.Sampling = s
+}
+
+// SamplingParams returns the generation parameters requests are sent with.
+func (b *BaseAIClient) SamplingParams() Sampling {
+	return b.Sampling
+}
+
+// SamplingOf returns the generation parameters of client, or the zero
+// Sampling when it does not report them.
+func SamplingOf(client AIClient) Sampling {
+	if s, ok := client.(SamplingAIClient); ok {
+		return s.SamplingParams()
+	}
+	return Sampling{}
+}
+
+// ApplySampling sets the generation parameters of client, reporting whether
+// it supports them. Apply it before wrapping the client, since wrappers only
+// forward SamplingParams.
+func ApplySampling(client AIClient, s Sampling) bool {
+	c, ok := client.(interface{ SetSampling(Sampling) })
+	if ok {
+		c.SetSampling(s)
+	}
+	return ok
+}
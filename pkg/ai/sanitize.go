@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// openingFence matches a code fence line with an optional language tag.
+	openingFence = regexp.MustCompile("^```[\\w+.#-]*[ \\t]*(?:\\r?\\n|$)")
+	// prefaceLine matches commentary models put before the message, such as
+	// "Here's your commit message:" or a bare "Commit message:" label.
+	prefaceLine = regexp.MustCompile(`(?i)^(?:(?:here|sure|certainly|okay|ok|of course|absolutely|below|the following|based on|i've|i have|this is)\b.*:|(?:sure|certainly|okay|ok|of course|absolutely)[!.,]?|(?:suggested |proposed |generated )?commit message:?)$`)
+	// messageLabel matches a "Commit message:" label in front of the message itself.
+	messageLabel = regexp.MustCompile(`(?i)^(?:suggested |proposed |generated )?commit message:\s+`)
+)
+
+// quotePairs maps opening quotes to their closing quote.
+var quotePairs = map[rune]rune{'"': '"', '\'': '\'', '“': '”', '‘': '’', '`': '`'}
+
+// stripCommentary removes the markdown and commentary models wrap around a
+// message: leading preface lines, a fenced block (keeping only its content and
+// dropping anything after it), bold markers around the first line, a
+// "Commit message:" label, and quotes around the whole message.
+func stripCommentary(message string) string {
+	message = strings.TrimSpace(message)
+	for {
+		first, rest, _ := strings.Cut(message, "\n")
+		if !prefaceLine.MatchString(unbold(strings.TrimSpace(first))) {
+			break
+		}
+		message = strings.TrimSpace(rest)
+	}
+	if loc := openingFence.FindStringIndex(message); loc != nil {
+		message = message[loc[1]:]
+		if end := strings.Index(message, "```"); end >= 0 {
+			message = message[:end]
+		}
+		message = strings.TrimSpace(message)
+	}
+	first, rest, hasBody := strings.Cut(message, "\n")
+	first = messageLabel.ReplaceAllString(unbold(strings.TrimSpace(first)), "")
+	if hasBody {
+		message = first + "\n" + rest
+	} else {
+		message = first
+	}
+	return unquote(strings.TrimSpace(message))
+}
+
+// unbold removes markdown bold markers around line.
+func unbold(line string) string {
+	if len(line) > 4 && strings.HasPrefix(line, "**") && strings.HasSuffix(line, "**") {
+		return strings.TrimSpace(line[2 : len(line)-2])
+	}
+	return line
+}
+
+// unquote removes a pair of quotes around message when they are its only
+// occurrences of that quote character.
+func unquote(message string) string {
+	runes := []rune(message)
+	if len(runes) < 2 {
+		return message
+	}
+	closing, ok := quotePairs[runes[0]]
+	if !ok || runes[len(runes)-1] != closing {
+		return message
+	}
+	inner := string(runes[1 : len(runes)-1])
+	if strings.ContainsRune(inner, runes[0]) || strings.ContainsRune(inner, closing) {
+		return message
+	}
+	return strings.TrimSpace(inner)
+}
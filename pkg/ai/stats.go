@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"sync"
+	"time"
+)
+
+// Usage is the token accounting a provider reported for one request.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+	// CachedTokens is the part of the input served from the provider's prompt cache.
+	CachedTokens int
+}
+
+// UsageAIClient is an optional interface for clients that report the usage of
+// their last request.
+type UsageAIClient interface {
+	LastUsage() (Usage, bool)
+}
+
+// UsageRecorder keeps the usage of a client's last request. Embed it in a
+// provider client, call ClearLastUsage when a request starts and SetLastUsage
+// once the provider reports usage, to implement UsageAIClient.
+type UsageRecorder struct {
+	mu   sync.Mutex
+	last Usage
+	set  bool
+}
+
+// SetLastUsage stores u as the usage of the last request.
+func (r *UsageRecorder) SetLastUsage(u Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last, r.set = u, true
+}
+
+// ClearLastUsage forgets the previous request's usage.
+func (r *UsageRecorder) ClearLastUsage() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last, r.set = Usage{}, false
+}
+
+// LastUsage returns the usage of the last request, if one was recorded.
+func (r *UsageRecorder) LastUsage() (Usage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.set
+}
+
+// CallStats describes the last request made through a client.
+type CallStats struct {
+	Provider string
+	Model    string
+	Latency  time.Duration
+	Usage
+	// Sampling holds the generation parameters the request was sent with.
+	Sampling Sampling
+	// Estimated is set when the provider did not report usage and the token
+	// counts were estimated from the text length.
+	Estimated bool
+}
+
+// StatsAIClient is an optional interface for clients that keep metadata about
+// their last request.
+type StatsAIClient interface {
+	LastCallStats() (CallStats, bool)
+}
+
+// StatsOf returns the metadata of client's last request, if it keeps any.
+func StatsOf(client AIClient) (CallStats, bool) {
+	if s, ok := client.(StatsAIClient); ok {
+		return s.LastCallStats()
+	}
+	return CallStats{}, false
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxSubjectLength = 150
+	maxMessageLines  = 60
+)
+
+var (
+	// chatterPattern matches replies that talk to the user instead of being a
+	// message; a subject such as "Okay button ..." or "I18n ..." is fine.
+	chatterPattern = regexp.MustCompile(`(?i)^((sure|certainly|of course|okay|ok)[!,.]|here('s| is| are) (the|your|a|an) ([a-z]+ )?commit message|as an ai\b|i('m| am) (sorry|unable|not able)\b|(sorry|unfortunately),)`)
+	// injectionPattern matches the phrases with which instructions hidden in the
+	// diff usually leak into the output.
+	injectionPattern = regexp.MustCompile(`(?i)((ignore|disregard|forget) (all )?(of )?(the |your )?(previous|prior|above|earlier) instructions|ignore (all )?(of )?your instructions)`)
+	// codePattern matches subjects that are diff or source lines rather than prose.
+	codePattern = regexp.MustCompile(`^(diff --git |@@ |[+-]{3} |package \w+$|func |import \(|#include )`)
+)
+
+// ValidateCommitMessage checks that msg has the shape of a commit message rather
+// than arbitrary text, such as a whole file or diff. It returns an error
+// describing the first problem found; see MessageWarning for softer checks.
+func ValidateCommitMessage(msg string) error {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return fmt.Errorf("message is empty")
+	}
+	lines := strings.Split(msg, "\n")
+	subject := strings.TrimSpace(lines[0])
+	switch {
+	case len(subject) > maxSubjectLength:
+		return fmt.Errorf("subject line is %d characters (max %d)", len(subject), maxSubjectLength)
+	case len(lines) > maxMessageLines:
+		return fmt.Errorf("message has %d lines (max %d)", len(lines), maxMessageLines)
+	case codePattern.MatchString(subject):
+		return fmt.Errorf("subject looks like code or diff output: %q", subject)
+	}
+	return nil
+}
+
+// MessageWarning returns why msg may not be a genuine commit message, such as
+// a chat reply or instructions from the diff echoed back, or "" when nothing
+// stands out. These are hints for the user, not grounds to reject msg, as a
+// real message may well mention such text.
+func MessageWarning(msg string) string {
+	msg = strings.TrimSpace(msg)
+	subject, _, _ := strings.Cut(msg, "\n")
+	switch {
+	case chatterPattern.MatchString(strings.TrimSpace(subject)):
+		return fmt.Sprintf("the subject reads like a chat reply: %q", subject)
+	case injectionPattern.MatchString(msg):
+		return fmt.Sprintf("the message contains prompt-injection text: %q", injectionPattern.FindString(msg))
+	}
+	return ""
+}
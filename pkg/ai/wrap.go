@@ -0,0 +1,119 @@
+package ai
+
+import "context"
+
+// Wrapper is the base of a client that decorates another one. Embedded, it
+// forwards the calls the decorator does not override, and the usage, model,
+// sampling, and stats the wrapped client reports.
+type Wrapper struct {
+	AIClient
+}
+
+// LastUsage forwards the token usage reported by the wrapped client.
+func (w Wrapper) LastUsage() (Usage, bool) {
+	if u, ok := w.AIClient.(UsageAIClient); ok {
+		return u.LastUsage()
+	}
+	return Usage{}, false
+}
+
+// ModelName forwards the model of the wrapped client.
+func (w Wrapper) ModelName() string {
+	return ModelOf(w.AIClient)
+}
+
+// SamplingParams forwards the generation parameters of the wrapped client.
+func (w Wrapper) SamplingParams() Sampling {
+	return SamplingOf(w.AIClient)
+}
+
+// LastCallStats forwards the metadata the wrapped client keeps, if any.
+func (w Wrapper) LastCallStats() (CallStats, bool) {
+	return StatsOf(w.AIClient)
+}
+
+// Decorator is a client wrapping another one that also sees its streaming and
+// embedding requests, made through next.
+type Decorator interface {
+	AIClient
+	StreamThrough(ctx context.Context, next StreamingAIClient, prompt string, onDelta func(string)) (string, error)
+	EmbedThrough(ctx context.Context, next EmbeddingAIClient, model string, texts []string) ([][]float64, error)
+}
+
+// Decorate returns d, which wraps inner, streaming and embedding through d
+// exactly when inner can: the result implements StreamingAIClient and
+// EmbeddingAIClient only if inner does.
+func Decorate(d Decorator, inner AIClient) AIClient {
+	base := decorated{d}
+	s, canStream := inner.(StreamingAIClient)
+	e, canEmbed := inner.(EmbeddingAIClient)
+	switch {
+	case canStream && canEmbed:
+		return streamingEmbedder{streaming{base, s}, e}
+	case canStream:
+		return streaming{base, s}
+	case canEmbed:
+		return embedding{base, e}
+	}
+	return base
+}
+
+// decorated exposes the optional metadata of its Decorator, which an interface
+// embedding would not promote.
+type decorated struct {
+	Decorator
+}
+
+func (d decorated) LastUsage() (Usage, bool) {
+	if u, ok := d.Decorator.(UsageAIClient); ok {
+		return u.LastUsage()
+	}
+	return Usage{}, false
+}
+
+func (d decorated) ModelName() string {
+	return ModelOf(d.Decorator)
+}
+
+func (d decorated) SamplingParams() Sampling {
+	return SamplingOf(d.Decorator)
+}
+
+func (d decorated) LastCallStats() (CallStats, bool) {
+	return StatsOf(d.Decorator)
+}
+
+type streaming struct {
+	decorated
+	next StreamingAIClient
+}
+
+func (s streaming) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	return s.StreamThrough(ctx, s.next, prompt, onDelta)
+}
+
+type embedding struct {
+	decorated
+	next EmbeddingAIClient
+}
+
+func (e embedding) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	return e.EmbedThrough(ctx, e.next, model, texts)
+}
+
+type streamingEmbedder struct {
+	streaming
+	embed EmbeddingAIClient
+}
+
+func (s streamingEmbedder) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	return s.EmbedThrough(ctx, s.embed, model, texts)
+}
+
+var _ StreamingAIClient = streaming{}
+var _ EmbeddingAIClient = embedding{}
+var _ StreamingAIClient = streamingEmbedder{}
+var _ EmbeddingAIClient = streamingEmbedder{}
+var _ StatsAIClient = decorated{}
+var _ UsageAIClient = Wrapper{}
+var _ SamplingAIClient = Wrapper{}
@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type plainClient struct{ BaseAIClient }
+
+func (c *plainClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return prompt, nil
+}
+
+type embeddingClient struct{ plainClient }
+
+func (c *embeddingClient) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	return [][]float64{{float64(len(texts))}}, nil
+}
+
+// upper decorates a client by upper-casing what is sent through it.
+type upper struct{ Wrapper }
+
+func (u *upper) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return u.AIClient.GetCommitMessage(ctx, strings.ToUpper(prompt))
+}
+
+func (u *upper) StreamThrough(ctx context.Context, next StreamingAIClient, prompt string, onDelta func(string)) (string, error) {
+	return next.StreamCommitMessage(ctx, strings.ToUpper(prompt), onDelta)
+}
+
+func (u *upper) EmbedThrough(ctx context.Context, next EmbeddingAIClient, model string, texts []string) ([][]float64, error) {
+	return next.Embed(ctx, model, append(texts, "extra"))
+}
+
+func TestDecorate(t *testing.T) {
+	t.Parallel()
+	inner := &plainClient{BaseAIClient{Provider: "ollama", Model: "llama3"}}
+	plain := Decorate(&upper{Wrapper{inner}}, inner)
+	if _, ok := plain.(EmbeddingAIClient); ok {
+		t.Error("expected a client without embeddings to stay without embeddings")
+	}
+	if _, ok := plain.(StreamingAIClient); ok {
+		t.Error("expected a non-streaming client to stay non-streaming")
+	}
+	if msg, _ := plain.GetCommitMessage(context.Background(), "fix"); msg != "FIX" {
+		t.Errorf("expected the decorator's GetCommitMessage, got %q", msg)
+	}
+	if ModelOf(plain) != "llama3" {
+		t.Errorf("expected the wrapped model, got %q", ModelOf(plain))
+	}
+
+	embedder := &embeddingClient{*inner}
+	e, ok := Decorate(&upper{Wrapper{embedder}}, embedder).(EmbeddingAIClient)
+	if !ok {
+		t.Fatal("expected embedding support to be preserved")
+	}
+	if vectors, _ := e.Embed(context.Background(), "", []string{"a"}); vectors[0][0] != 2 {
+		t.Errorf("expected the embedding to go through the decorator, got %v", vectors)
+	}
+}
@@ -0,0 +1,321 @@
+// Package anonymize replaces emails, usernames, host names, and IP addresses
+// in text sent to a provider with stable pseudonyms, and restores them in the
+// reply. The map between the two never leaves the process.
+package anonymize
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// InternalSuffixes are the domains whose host names are always pseudonymized,
+// since they only exist inside an organization's network. As names such as
+// "org.foo.internal" or ".env.local" end the same way, these only count in a
+// host context (see hostContext).
+var InternalSuffixes = []string{"internal", "local", "localdomain", "lan", "corp", "intranet", "home.arpa"}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	hostPattern  = regexp.MustCompile(`[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)+`)
+	ipv4Pattern  = regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}`)
+	ipv6Pattern  = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+	homePattern  = regexp.MustCompile(`(?:/home/|/Users/|\\Users\\)([A-Za-z0-9._-]+)`)
+
+	// hostKeyPattern ends with a key that names a host, as in "host: x",
+	// "HostName x", or `server = "x"`.
+	hostKeyPattern = regexp.MustCompile(`(?i)(?:host(?:name)?|server|addr(?:ess)?|domain)["']?\s*[:=]?\s*["']?$`)
+	// pseudonymPattern matches anything shaped like a pseudonym, so text that
+	// already contains one never gets it handed out.
+	pseudonymPattern = regexp.MustCompile(`(?i)person\d+@example\.invalid|host\d+\.example\.invalid|user\d+|198\.1[89]\.\d{1,3}\.\d{1,3}|2001:db8::[0-9a-f]{1,4}`)
+)
+
+// kind names a class of identifier; each has its own pseudonym format.
+type kind int
+
+const (
+	kindEmail kind = iota
+	kindHost
+	kindIPv4
+	kindIPv6
+	kindUser
+)
+
+// Anonymizer pseudonymizes identifiers. The same identifier always gets the
+// same pseudonym, so several prompts of one run stay consistent. It is safe
+// for concurrent use.
+type Anonymizer struct {
+	domains   []string
+	suffixes  []string
+	usernames *regexp.Regexp
+
+	mu       sync.Mutex
+	forward  map[string]string
+	backward map[string]string
+	counts   map[kind]int
+	restorer *regexp.Regexp
+	// taken holds the pseudonym-shaped words of every anonymized text, which
+	// Restore could not tell apart from the pseudonyms.
+	taken map[string]bool
+}
+
+// New returns an Anonymizer that, besides every email and IP address,
+// pseudonymizes host names under domains, those under InternalSuffixes in a
+// host context, and the given usernames wherever they appear as a whole word,
+// as well as the user in home directory paths. A domain covers itself and its
+// subdomains; a leading "*." is ignored.
+func New(domains, usernames []string) *Anonymizer {
+	a := &Anonymizer{
+		suffixes: InternalSuffixes,
+		forward:  make(map[string]string),
+		backward: make(map[string]string),
+		counts:   make(map[kind]int),
+		taken:    make(map[string]bool),
+	}
+	for _, d := range domains {
+		d = strings.ToLower(strings.Trim(strings.TrimPrefix(strings.TrimSpace(d), "*."), "."))
+		if d != "" {
+			a.domains = append(a.domains, d)
+		}
+	}
+	var names []string
+	for _, u := range usernames {
+		if u = strings.TrimSpace(u); u != "" {
+			names = append(names, regexp.QuoteMeta(u))
+		}
+	}
+	if len(names) > 0 {
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		a.usernames = regexp.MustCompile(`(?i)(?:` + strings.Join(names, "|") + `)`)
+	}
+	return a
+}
+
+// Anonymize returns text with every identifier replaced by its pseudonym.
+func (a *Anonymizer) Anonymize(text string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, m := range pseudonymPattern.FindAllString(text, -1) {
+		a.taken[strings.ToLower(m)] = true
+	}
+	text = replaceWords(text, emailPattern, nil, func(m string) string {
+		return a.pseudonym(kindEmail, strings.ToLower(m), m)
+	})
+	text = replaceWords(text, hostPattern, func(text string, start, end int) bool {
+		host := strings.ToLower(text[start:end])
+		return !under(host, a.domains) && !(under(host, a.suffixes) && hostContext(text, start, end))
+	}, func(m string) string {
+		return a.pseudonym(kindHost, strings.ToLower(m), m)
+	})
+	text = replaceWords(text, ipv4Pattern, dotted, func(m string) string {
+		ip := net.ParseIP(m)
+		if ip == nil || !identifying(ip) {
+			return m
+		}
+		return a.pseudonym(kindIPv4, ip.String(), m)
+	})
+	text = replaceWords(text, ipv6Pattern, nil, func(m string) string {
+		ip := net.ParseIP(m)
+		if ip == nil || ip.To4() != nil || !identifying(ip) || !plausibleIPv6(m) {
+			return m
+		}
+		return a.pseudonym(kindIPv6, ip.String(), m)
+	})
+	text = replaceSubmatch(text, homePattern, func(m string) string {
+		return a.pseudonym(kindUser, strings.ToLower(m), m)
+	})
+	if a.usernames != nil {
+		text = replaceWords(text, a.usernames, nil, func(m string) string {
+			return a.pseudonym(kindUser, strings.ToLower(m), m)
+		})
+	}
+	return text
+}
+
+// Restore returns text with every pseudonym handed out so far replaced by the
+// identifier it stands for.
+func (a *Anonymizer) Restore(text string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.restorer == nil {
+		return text
+	}
+	return replaceWords(text, a.restorer, nil, func(m string) string {
+		if original, ok := a.backward[strings.ToLower(m)]; ok {
+			return original
+		}
+		return m
+	})
+}
+
+// Len returns the number of identifiers pseudonymized so far.
+func (a *Anonymizer) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.forward)
+}
+
+// pseudonym returns the pseudonym of the identifier keyed by key, making one
+// for original if it is new. The caller holds a.mu.
+func (a *Anonymizer) pseudonym(k kind, key, original string) string {
+	key = fmt.Sprintf("%d:%s", k, key)
+	if p, ok := a.forward[key]; ok {
+		return p
+	}
+	p := a.next(k)
+	for a.taken[strings.ToLower(p)] {
+		p = a.next(k)
+	}
+	a.forward[key] = p
+	a.backward[strings.ToLower(p)] = original
+	a.rebuildRestorer()
+	return p
+}
+
+// next returns the next pseudonym of kind k. The caller holds a.mu.
+func (a *Anonymizer) next(k kind) string {
+	a.counts[k]++
+	n := a.counts[k]
+	var p string
+	switch k {
+	case kindEmail:
+		p = fmt.Sprintf("person%d@example.invalid", n)
+	case kindHost:
+		p = fmt.Sprintf("host%d.example.invalid", n)
+	case kindIPv4:
+		// 198.18.0.0/15 is reserved for benchmarking and never routed.
+		p = fmt.Sprintf("198.%d.%d.%d", 18+n/65536%2, n/256%256, n%256)
+	case kindIPv6:
+		p = fmt.Sprintf("2001:db8::%x", n)
+	case kindUser:
+		p = fmt.Sprintf("user%d", n)
+	}
+	return p
+}
+
+// rebuildRestorer compiles a pattern matching every pseudonym, longest first
+// so "user12" is not read as "user1". The caller holds a.mu.
+func (a *Anonymizer) rebuildRestorer() {
+	pseudonyms := make([]string, 0, len(a.backward))
+	for p := range a.backward {
+		pseudonyms = append(pseudonyms, regexp.QuoteMeta(p))
+	}
+	sort.Slice(pseudonyms, func(i, j int) bool {
+		if len(pseudonyms[i]) != len(pseudonyms[j]) {
+			return len(pseudonyms[i]) > len(pseudonyms[j])
+		}
+		return pseudonyms[i] < pseudonyms[j]
+	})
+	a.restorer = regexp.MustCompile(`(?i)(?:` + strings.Join(pseudonyms, "|") + `)`)
+}
+
+// under reports whether the lower-case host is one of domains or a subdomain.
+func under(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostContext reports whether text[start:end] is used as a host name: in a URL
+// or after a user ("ssh://x", "git@x"), with a port ("x:8080"), or as the value
+// of a host key ("host: x").
+func hostContext(text string, start, end int) bool {
+	before := text[max(start-32, 0):start]
+	if strings.HasSuffix(before, "://") || strings.HasSuffix(before, "@") {
+		return true
+	}
+	if end+1 < len(text) && text[end] == ':' && text[end+1] >= '0' && text[end+1] <= '9' {
+		return true
+	}
+	return hostKeyPattern.MatchString(before)
+}
+
+// identifying reports whether ip could point at a real machine; loopback,
+// unspecified, and netmask-like addresses say nothing about anyone.
+func identifying(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() {
+		return false
+	}
+	if v4 := ip.To4(); v4 != nil {
+		if _, bits := net.IPMask(v4).Size(); bits != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// plausibleIPv6 rejects text such as "8::" or "a::b" that parses as an IPv6
+// address but is far more likely a scope operator.
+func plausibleIPv6(s string) bool {
+	groups := 0
+	for _, g := range strings.Split(s, ":") {
+		if g != "" {
+			groups++
+		}
+	}
+	return groups >= 2 && strings.ContainsAny(s, "0123456789")
+}
+
+// isWord reports whether r continues an identifier.
+func isWord(r byte) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r)))
+}
+
+// dotted reports whether a match of text[start:end] continues as a longer
+// dotted number such as a version, which is then no IP address.
+func dotted(text string, start, end int) bool {
+	if start > 0 && text[start-1] == '.' {
+		return true
+	}
+	return end+1 < len(text) && text[end] == '.' && text[end+1] >= '0' && text[end+1] <= '9'
+}
+
+// replaceWords replaces each match of re in text with replace(match), except
+// matches that are part of a longer word or that reject(text, start, end)
+// refuses.
+func replaceWords(text string, re *regexp.Regexp, reject func(string, int, int) bool, replace func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if start == end || start > 0 && isWord(text[start-1]) || end < len(text) && isWord(text[end]) {
+			continue
+		}
+		if reject != nil && reject(text, start, end) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(replace(text[start:end]))
+		last = end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// replaceSubmatch replaces the first group of each match of re in text with
+// replace(group).
+func replaceSubmatch(text string, re *regexp.Regexp, replace func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[2], loc[3]
+		b.WriteString(text[last:start])
+		b.WriteString(replace(text[start:end]))
+		last = end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
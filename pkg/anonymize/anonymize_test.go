@@ -0,0 +1,107 @@
+package anonymize
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+func TestAnonymize(t *testing.T) {
+	t.Parallel()
+	a := New([]string{"*.acme.io"}, []string{"jdoe"})
+	in := strings.Join([]string{
+		"+author: Jane Doe <Jane.Doe@acme.io>",
+		"+dsn: postgres://db1.prod.acme.io:5432/app",
+		"+host: build.corp, 10.1.2.3 and fe80::1ff:fe23:4567:890a",
+		"+path: /home/jdoe/src, owner jdoe, cc jane.doe@acme.io",
+		"+keep: 127.0.0.1 255.255.255.0 v1.2.3.4.5 example.com std::vector u8::MAX 12:30:45 jdoes",
+		"+keep: import org.foo.internal.Util; load .env.local; fixture user1",
+	}, "\n")
+	want := strings.Join([]string{
+		"+author: Jane Doe <person1@example.invalid>",
+		"+dsn: postgres://host1.example.invalid:5432/app",
+		"+host: host2.example.invalid, 198.18.0.1 and 2001:db8::1",
+		"+path: /home/user2/src, owner user2, cc person1@example.invalid",
+		"+keep: 127.0.0.1 255.255.255.0 v1.2.3.4.5 example.com std::vector u8::MAX 12:30:45 jdoes",
+		"+keep: import org.foo.internal.Util; load .env.local; fixture user1",
+	}, "\n")
+	got := a.Anonymize(in)
+	if got != want {
+		t.Fatalf("Anonymize() =\n%s\nwant\n%s", got, want)
+	}
+	if a.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", a.Len())
+	}
+	if again := a.Anonymize("ping 10.1.2.3"); again != "ping 198.18.0.1" {
+		t.Errorf("second Anonymize() = %q, want the same pseudonym", again)
+	}
+
+	// user1 occurs in the text itself, so it stays as it is in the reply.
+	reply := "fix: point host1.example.invalid at 198.18.0.1 for person1@example.invalid (user2, user1, user12)"
+	wantReply := "fix: point db1.prod.acme.io at 10.1.2.3 for Jane.Doe@acme.io (jdoe, user1, user12)"
+	if got := a.Restore(reply); got != wantReply {
+		t.Errorf("Restore() = %q, want %q", got, wantReply)
+	}
+	if got := New(nil, nil).Restore("user1"); got != "user1" {
+		t.Errorf("Restore() without pseudonyms = %q", got)
+	}
+}
+
+type fakeClient struct {
+	ai.BaseAIClient
+	prompt string
+	reply  string
+}
+
+func (f *fakeClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	f.prompt = prompt
+	return f.reply, nil
+}
+
+type fakeStreamingClient struct{ fakeClient }
+
+func (f *fakeStreamingClient) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	f.prompt = prompt
+	for _, delta := range []string{"feat: ping ho", "st1.exa", "mple.invalid:22 from 198.", "18.0.1"} {
+		onDelta(delta)
+	}
+	return f.reply, nil
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	fake := &fakeClient{reply: "feat: email person1@example.invalid"}
+	c := Wrap(fake, New(nil, nil))
+	if _, ok := c.(ai.StreamingAIClient); ok {
+		t.Error("expected a non-streaming client to stay non-streaming")
+	}
+	msg, err := c.GetCommitMessage(ctx, "+to: ops@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.prompt != "+to: person1@example.invalid" {
+		t.Errorf("prompt sent = %q", fake.prompt)
+	}
+	if msg != "feat: email ops@example.com" {
+		t.Errorf("message = %q", msg)
+	}
+
+	stream := &fakeStreamingClient{fakeClient{reply: "feat: ping host1.example.invalid:22 from 198.18.0.1"}}
+	s, ok := Wrap(stream, New(nil, nil)).(ai.StreamingAIClient)
+	if !ok {
+		t.Fatal("expected streaming support to be preserved")
+	}
+	var streamed strings.Builder
+	msg, err = s.StreamCommitMessage(ctx, "+ping db.lan:22 from 10.0.0.7", func(d string) { streamed.WriteString(d) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "feat: ping db.lan:22 from 10.0.0.7"
+	if msg != want || streamed.String() != want {
+		t.Errorf("message = %q, streamed %q; want %q", msg, streamed.String(), want)
+	}
+}
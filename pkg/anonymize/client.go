@@ -0,0 +1,58 @@
+package anonymize
+
+import (
+	"context"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+// client pseudonymizes every prompt sent through the wrapped client and
+// restores the identifiers in its replies.
+type client struct {
+	ai.Wrapper
+	anonymizer *Anonymizer
+}
+
+// Wrap returns a client that sends prompts through a.Anonymize and returns
+// replies through a.Restore. Streaming support, embeddings, token usage, and
+// the model of the underlying client are preserved.
+func Wrap(c ai.AIClient, a *Anonymizer) ai.AIClient {
+	return ai.Decorate(&client{Wrapper: ai.Wrapper{AIClient: c}, anonymizer: a}, c)
+}
+
+func (c *client) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	msg, err := c.AIClient.GetCommitMessage(ctx, c.anonymizer.Anonymize(prompt))
+	return c.anonymizer.Restore(msg), err
+}
+
+// EmbedThrough sends the pseudonymized texts through next.
+func (c *client) EmbedThrough(ctx context.Context, next ai.EmbeddingAIClient, model string, texts []string) ([][]float64, error) {
+	anonymized := make([]string, len(texts))
+	for i, text := range texts {
+		anonymized[i] = c.anonymizer.Anonymize(text)
+	}
+	return next.Embed(ctx, model, anonymized)
+}
+
+// StreamThrough restores the identifiers in the text streamed by next too. A
+// pseudonym may be split across deltas, so text after the last whitespace is
+// held back until the next delta or the end of the stream.
+func (c *client) StreamThrough(ctx context.Context, next ai.StreamingAIClient, prompt string, onDelta func(string)) (string, error) {
+	var pending string
+	msg, err := next.StreamCommitMessage(ctx, c.anonymizer.Anonymize(prompt), func(delta string) {
+		pending += delta
+		cut := strings.LastIndexAny(pending, " \t\r\n")
+		if cut < 0 {
+			return
+		}
+		onDelta(c.anonymizer.Restore(pending[:cut+1]))
+		pending = pending[cut+1:]
+	})
+	if pending != "" {
+		onDelta(c.anonymizer.Restore(pending))
+	}
+	return c.anonymizer.Restore(msg), err
+}
+
+var _ ai.Decorator = (*client)(nil)
@@ -0,0 +1,154 @@
+// Package archguard checks staged Go changes against dependency rules such as
+// "pkg/ui must not import pkg/provider/*".
+package archguard
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// Violation is a forbidden import found in a changed file.
+type Violation struct {
+	File   string
+	Import string
+	Rule   config.ArchitectureRule
+}
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("%s imports %s (%s must not import %s)", v.File, v.Import, v.Rule.From, strings.Join(v.Rule.Deny, ", "))
+	if v.Rule.Reason != "" {
+		s += ": " + v.Rule.Reason
+	}
+	return s
+}
+
+// ModulePath returns the module path declared in the contents of a go.mod file.
+func ModulePath(goMod []byte) string {
+	sc := bufio.NewScanner(bytes.NewReader(goMod))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// ChangedGoFiles returns the .go files touched by diff, in diff order.
+func ChangedGoFiles(diff string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "diff --git ") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 4 {
+			continue
+		}
+		file := strings.TrimPrefix(parts[3], "b/")
+		if strings.HasSuffix(file, ".go") && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Match reports whether the repository-relative package path pkg matches pattern.
+// "*" matches a single path segment and a trailing "/..." matches the package and
+// all of its subpackages.
+func Match(pattern, pkg string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if Match(prefix, pkg) {
+			return true
+		}
+		for dir := path.Dir(pkg); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if Match(prefix, dir) {
+				return true
+			}
+		}
+		return false
+	}
+	ok, err := path.Match(pattern, pkg)
+	return err == nil && ok
+}
+
+// Check parses the imports of each file in files (path -> contents) and returns
+// the imports that break rules. Only imports inside modulePath are considered;
+// files that fail to parse are skipped.
+func Check(files map[string][]byte, modulePath string, rules []config.ArchitectureRule) []Violation {
+	if modulePath == "" || len(rules) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []Violation
+	fset := token.NewFileSet()
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, name, files[name], parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		pkg := path.Dir(name)
+		for _, imp := range f.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			rel, ok := strings.CutPrefix(importPath, modulePath+"/")
+			if !ok {
+				continue
+			}
+			for _, rule := range rules {
+				if !Match(rule.From, pkg) {
+					continue
+				}
+				for _, deny := range rule.Deny {
+					if Match(deny, rel) {
+						violations = append(violations, Violation{File: name, Import: importPath, Rule: rule})
+						break
+					}
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// FormatRules renders rules as a bullet list for the AI review prompt.
+func FormatRules(rules []config.ArchitectureRule) string {
+	var b strings.Builder
+	for _, r := range rules {
+		fmt.Fprintf(&b, "- %s must not depend on %s", r.From, strings.Join(r.Deny, ", "))
+		if r.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", r.Reason)
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// NoViolations is the token the AI review is asked to reply with, verbatim and
+// untranslated, when nothing is wrong, so the check works in every language.
+const NoViolations = "NO_VIOLATIONS"
+
+// HasAIFindings reports whether an AI review response flags anything.
+func HasAIFindings(response string) bool {
+	r := strings.TrimSpace(response)
+	return r != "" && !strings.Contains(r, NoViolations)
+}
@@ -0,0 +1,97 @@
+package archguard
+
+import (
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern, pkg string
+		want         bool
+	}{
+		{"pkg/provider/*", "pkg/provider/openai", true},
+		{"pkg/provider/*", "pkg/provider", false},
+		{"pkg/provider/*", "pkg/provider/openai/sub", false},
+		{"pkg/ui", "pkg/ui", true},
+		{"pkg/ui", "pkg/ui/splitter", false},
+		{"pkg/ui/...", "pkg/ui", true},
+		{"pkg/ui/...", "pkg/ui/splitter", true},
+		{"pkg/ui/...", "pkg/uix", false},
+	}
+	for _, tc := range tests {
+		if got := Match(tc.pattern, tc.pkg); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.pattern, tc.pkg, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	rules := []config.ArchitectureRule{
+		{From: "pkg/ui/...", Deny: []string{"pkg/provider/*"}, Reason: "use pkg/ai"},
+	}
+	files := map[string][]byte{
+		"pkg/ui/splitter/splitter.go": []byte(`package splitter
+
+import (
+	"fmt"
+
+	"example.com/app/pkg/ai"
+	openai "example.com/app/pkg/provider/openai"
+)
+`),
+		"pkg/git/git.go": []byte(`package git
+
+import "example.com/app/pkg/provider/openai"
+`),
+		"pkg/ui/broken.go": []byte("package ui\nimport ("),
+	}
+
+	violations := Check(files, "example.com/app", rules)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	v := violations[0]
+	if v.File != "pkg/ui/splitter/splitter.go" || v.Import != "example.com/app/pkg/provider/openai" {
+		t.Errorf("unexpected violation %+v", v)
+	}
+	want := "pkg/ui/splitter/splitter.go imports example.com/app/pkg/provider/openai (pkg/ui/... must not import pkg/provider/*): use pkg/ai"
+	if v.String() != want {
+		t.Errorf("String() = %q, want %q", v.String(), want)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	t.Parallel()
+	if got := ModulePath([]byte("// comment\nmodule github.com/acme/app\n\ngo 1.22\n")); got != "github.com/acme/app" {
+		t.Errorf("ModulePath() = %q", got)
+	}
+	if got := ModulePath([]byte("go 1.22\n")); got != "" {
+		t.Errorf("expected empty module path, got %q", got)
+	}
+}
+
+func TestChangedGoFiles(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/a.go b/a.go\n+x\ndiff --git a/README.md b/README.md\n+y\ndiff --git a/old.go b/new/b.go\n"
+	got := ChangedGoFiles(diff)
+	if len(got) != 2 || got[0] != "a.go" || got[1] != "new/b.go" {
+		t.Errorf("ChangedGoFiles() = %v", got)
+	}
+}
+
+func TestHasAIFindings(t *testing.T) {
+	t.Parallel()
+	if HasAIFindings("NO_VIOLATIONS") || HasAIFindings("`NO_VIOLATIONS`\n") || HasAIFindings("  ") {
+		t.Error("expected no findings")
+	}
+	if !HasAIFindings("- pkg/ui/view.go: keine Verstöße gegen die Regeln, aber ...") {
+		t.Error("a translated reply without the token should count as findings")
+	}
+	if !HasAIFindings("- pkg/ui/view.go builds provider requests directly") {
+		t.Error("expected findings")
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/renatogalera/ai-commit/pkg/ai"
 	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
 	"github.com/renatogalera/ai-commit/pkg/prompt"
 )
 
@@ -28,7 +29,7 @@ type Options struct {
 
 // Generate produces a markdown changelog for commits in the given range.
 func Generate(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, language string, opts Options) (string, error) {
-	repo, err := gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{DetectDotGit: true})
+	repo, err := git.DiscoverRepo()
 	if err != nil {
 		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -67,18 +68,26 @@ func Generate(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, lan
 		return "", fmt.Errorf("no commits found in range %s..%s", fromRef, toRef)
 	}
 
+	maxPrompt := 0
+	if cfg.Limits.Prompt.Enabled {
+		maxPrompt = cfg.Limits.Prompt.MaxChars
+	}
+	return Notes(ctx, aiClient, commits, fromRef, toRef, language, cfg.PromptTemplate, maxPrompt)
+}
+
+// Notes asks the AI for a markdown changelog of commits, grouped by type, for
+// the range fromRef..toRef. The prompt is cut to maxPromptChars when positive.
+func Notes(ctx context.Context, aiClient ai.AIClient, commits []*gogitobj.Commit, fromRef, toRef, language, template string, maxPromptChars int) (string, error) {
 	grouped := GroupCommitsByType(commits)
 	commitData := formatGroupedCommits(grouped)
 
-	changelogPrompt := prompt.BuildChangelogPrompt(commitData, fromRef, toRef, language, cfg.PromptTemplate)
-	if cfg.Limits.Prompt.Enabled && cfg.Limits.Prompt.MaxChars > 0 {
-		if len(changelogPrompt) > cfg.Limits.Prompt.MaxChars {
-			limit := cfg.Limits.Prompt.MaxChars
-			if limit > 3 {
-				limit -= 3
-			}
-			changelogPrompt = changelogPrompt[:limit] + "..."
+	changelogPrompt := prompt.BuildChangelogPrompt(commitData, fromRef, toRef, language, template)
+	if maxPromptChars > 0 && len(changelogPrompt) > maxPromptChars {
+		limit := maxPromptChars
+		if limit > 3 {
+			limit -= 3
 		}
+		changelogPrompt = changelogPrompt[:limit] + "..."
 	}
 
 	result, err := aiClient.GetCommitMessage(ctx, changelogPrompt)
@@ -89,12 +98,26 @@ func Generate(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, lan
 	return strings.TrimSpace(result), nil
 }
 
-// resolveRange determines the from/to refs based on options.
+// Range returns the from/to refs Generate uses for opts, e.g. the last two tags
+// when opts names no refs.
+func Range(opts Options) (string, string, error) {
+	repo, err := git.DiscoverRepo()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	return resolveRange(repo, opts)
+}
+
+// resolveRange determines the from/to refs based on options. A range without
+// an end ("v1.2.0..") ends at HEAD.
 func resolveRange(repo *gogit.Repository, opts Options) (string, string, error) {
 	if opts.Since != "" {
 		return "", "HEAD", nil
 	}
-	if opts.FromRef != "" && opts.ToRef != "" {
+	if opts.FromRef != "" {
+		if opts.ToRef == "" {
+			return opts.FromRef, "HEAD", nil
+		}
 		return opts.FromRef, opts.ToRef, nil
 	}
 	// Auto-detect from last two tags
@@ -0,0 +1,206 @@
+package changelog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	gogitobj "github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// Entry is one commit of an exported history, with its message normalized.
+type Entry struct {
+	Hash     string    `json:"hash"`
+	Date     time.Time `json:"date"`
+	Author   string    `json:"author"`
+	Type     string    `json:"type"`
+	Scope    string    `json:"scope,omitempty"`
+	Breaking bool      `json:"breaking"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body,omitempty"`
+}
+
+var (
+	// exportHeader splits a subject into type, scope, breaking marker, and description.
+	exportHeader = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s*(.*)$`)
+	// leadingEmoji matches one emoji or :shortcode: at the start of a subject.
+	leadingEmoji = regexp.MustCompile(`^(:[a-z][a-z0-9_+-]*:|\p{So}[\x{FE0F}\x{200D}\x{1F3FB}-\x{1F3FF}\p{So}]*)\s*`)
+	// emojiNoise and shortcodeNoise match emoji and :shortcodes: left inside a description.
+	emojiNoise     = regexp.MustCompile(`\p{So}[\x{FE0F}\x{200D}\x{1F3FB}-\x{1F3FF}\p{So}]*`)
+	shortcodeNoise = regexp.MustCompile(`(^|\s):[a-z][a-z0-9_+-]*:`)
+	// ciDirective matches CI skip markers such as "[skip ci]".
+	ciDirective = regexp.MustCompile(`(?i)\[(skip ci|ci skip|no ci|skip actions|actions skip)\]`)
+	// breakingFooter marks a breaking change in the body.
+	breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+)
+
+// typeAliases maps common non-standard types to their Conventional Commits name.
+var typeAliases = map[string]string{
+	"feature":     "feat",
+	"features":    "feat",
+	"bugfix":      "fix",
+	"hotfix":      "fix",
+	"doc":         "docs",
+	"tests":       "test",
+	"refactoring": "refactor",
+	"performance": "perf",
+	"chores":      "chore",
+}
+
+// gitmojiTypes maps gitmoji (as emoji or shortcode) to types, for messages that
+// use an emoji instead of a type prefix.
+var gitmojiTypes = map[string]string{
+	"✨": "feat", ":sparkles:": "feat",
+	"🐛": "fix", ":bug:": "fix",
+	"🚑": "fix", ":ambulance:": "fix",
+	"📝": "docs", ":memo:": "docs",
+	"📚": "docs", ":books:": "docs",
+	"♻": "refactor", ":recycle:": "refactor",
+	"⚡": "perf", ":zap:": "perf",
+	"✅": "test", ":white_check_mark:": "test",
+	"💄": "style", ":lipstick:": "style",
+	"🎨": "style", ":art:": "style",
+	"🔧": "chore", ":wrench:": "chore",
+	"👷": "ci", ":construction_worker:": "ci",
+	"💚": "ci", ":green_heart:": "ci",
+	"📦": "build", ":package:": "build",
+	"⬆": "build", ":arrow_up:": "build",
+	"⏪": "revert", ":rewind:": "revert",
+}
+
+// NormalizeMessage parses a commit message into an Entry without emoji, CI
+// directives, or type aliases. Messages without a recognizable type get the type
+// implied by their gitmoji, "revert" for git's revert subjects, or "other".
+func NormalizeMessage(message string) Entry {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	subject = strings.TrimSpace(ciDirective.ReplaceAllString(subject, ""))
+
+	var emojiType string
+	for {
+		m := leadingEmoji.FindStringSubmatch(subject)
+		if m == nil {
+			break
+		}
+		if emojiType == "" {
+			emojiType = emojiToType(m[1])
+		}
+		subject = subject[len(m[0]):]
+	}
+
+	e := Entry{Type: "other", Body: strings.TrimSpace(body)}
+	if h := exportHeader.FindStringSubmatch(subject); h != nil {
+		e.Type = normalizeType(h[1])
+		e.Scope = strings.TrimSpace(h[2])
+		e.Breaking = h[3] != ""
+		subject = h[4]
+	} else if strings.HasPrefix(subject, "Revert \"") {
+		e.Type = "revert"
+	} else if emojiType != "" {
+		e.Type = emojiType
+	}
+	subject = shortcodeNoise.ReplaceAllString(emojiNoise.ReplaceAllString(subject, ""), "$1")
+	e.Subject = strings.Join(strings.Fields(subject), " ")
+	e.Breaking = e.Breaking || breakingFooter.MatchString(e.Body)
+	return e
+}
+
+func normalizeType(typ string) string {
+	typ = strings.ToLower(typ)
+	if alias, ok := typeAliases[typ]; ok {
+		return alias
+	}
+	return typ
+}
+
+func emojiToType(emoji string) string {
+	if typ := committypes.TypeForEmoji(emoji); typ != "" {
+		return typ
+	}
+	return gitmojiTypes[strings.ReplaceAll(emoji, "\uFE0F", "")]
+}
+
+// Export returns the normalized non-merge commits of the range in opts, newest
+// first. Without FromRef or Since it exports the whole history of ToRef (HEAD by default).
+func Export(opts Options) ([]Entry, error) {
+	repo, err := git.DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	var commits []*gogitobj.Commit
+	switch {
+	case opts.Since != "":
+		since, err := ParseSince(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		commits, err = collectCommitsSince(repo, since)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		toRef := opts.ToRef
+		if toRef == "" {
+			toRef = "HEAD"
+		}
+		toHash, err := resolveRef(repo, toRef)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %q: %w", toRef, err)
+		}
+		// No commit has the zero hash, so without FromRef the walk reaches the root.
+		fromHash := plumbing.ZeroHash
+		if opts.FromRef != "" {
+			if fromHash, err = resolveRef(repo, opts.FromRef); err != nil {
+				return nil, fmt.Errorf("cannot resolve %q: %w", opts.FromRef, err)
+			}
+		}
+		if commits, err = collectCommitsBetween(repo, fromHash, toHash); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]Entry, 0, len(commits))
+	for _, c := range commits {
+		if c.NumParents() > 1 {
+			continue
+		}
+		e := NormalizeMessage(c.Message)
+		e.Hash = c.Hash.String()
+		e.Date = c.Author.When
+		e.Author = fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// WriteJSON writes entries as an indented JSON array.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteCSV writes entries as CSV with a header row; dates use RFC 3339.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"hash", "date", "author", "type", "scope", "breaking", "subject", "body"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{e.Hash, e.Date.Format(time.RFC3339), e.Author, e.Type, e.Scope, strconv.FormatBool(e.Breaking), e.Subject, e.Body}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,69 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		message string
+		want    Entry
+	}{
+		{"✨ feat(ui): add dashboard", Entry{Type: "feat", Scope: "ui", Subject: "add dashboard"}},
+		{":bug: Fix: handle nil config :tada:", Entry{Type: "fix", Subject: "handle nil config"}},
+		{"Feature!: drop v1 API [skip ci]", Entry{Type: "feat", Breaking: true, Subject: "drop v1 API"}},
+		{"♻️ extract parser", Entry{Type: "refactor", Subject: "extract parser"}},
+		{"📝 update docs at 10:30:00", Entry{Type: "docs", Subject: "update docs at 10:30:00"}},
+		{"Revert \"feat: add login\"", Entry{Type: "revert", Subject: "Revert \"feat: add login\""}},
+		{"update `go.mod` deps", Entry{Type: "other", Subject: "update `go.mod` deps"}},
+		{"fix: typo\n\nBREAKING CHANGE: renamed flag", Entry{Type: "fix", Breaking: true, Subject: "typo", Body: "BREAKING CHANGE: renamed flag"}},
+	}
+	for _, tc := range tests {
+		if got := NormalizeMessage(tc.message); got != tc.want {
+			t.Errorf("NormalizeMessage(%q) =\n%+v\nwant\n%+v", tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	t.Parallel()
+	entries := []Entry{{
+		Hash:    "abc123",
+		Date:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Author:  "Jane Doe <jane@example.com>",
+		Type:    "feat",
+		Scope:   "api",
+		Subject: "add users, roles",
+		Body:    "Line one.\nLine two.",
+	}}
+
+	var csvOut bytes.Buffer
+	if err := WriteCSV(&csvOut, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "hash,date,author,type,scope,breaking,subject,body\n" +
+		"abc123,2024-05-01T12:00:00Z,Jane Doe <jane@example.com>,feat,api,false,\"add users, roles\",\"Line one.\nLine two.\"\n"
+	if csvOut.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", csvOut.String(), want)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteJSON(&jsonOut, entries); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Entry
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Subject != "add users, roles" || !decoded[0].Date.Equal(entries[0].Date) {
+		t.Errorf("WriteJSON() round trip = %+v", decoded)
+	}
+	if !strings.Contains(jsonOut.String(), `"breaking": false`) {
+		t.Errorf("WriteJSON() output lacks breaking: %s", jsonOut.String())
+	}
+}
@@ -0,0 +1,66 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Section turns a generated changelog into a release section of a CHANGELOG.md
+// file: a "## <title> - <date>" heading with the generated headings nested one
+// level below it.
+func Section(title string, date time.Time, body string) string {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return fmt.Sprintf("## %s - %s\n\n%s\n", title, date.Format("2006-01-02"), strings.Join(lines, "\n"))
+}
+
+// SectionTitle is the heading of the section for commits up to toRef:
+// "Unreleased" for HEAD, the ref (usually a tag) otherwise.
+func SectionTitle(toRef string) string {
+	if toRef == "" || toRef == "HEAD" {
+		return "Unreleased"
+	}
+	return toRef
+}
+
+// Prepend adds section to the changelog file at path above its latest release,
+// keeping the title and introduction at the top of the file. A missing file is
+// created with a "# Changelog" title.
+func Prepend(path, section string) error {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.WriteFile(path, []byte("# Changelog\n\n"+section), 0o644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(insertSection(string(existing), section)), 0o644)
+}
+
+// insertSection puts section before the first "## " heading of content, or at
+// its end when there is none.
+func insertSection(content, section string) string {
+	section = strings.TrimRight(section, "\n") + "\n\n"
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			return content[:offset] + section + content[offset:]
+		}
+		offset += len(line)
+	}
+	if content = strings.TrimRight(content, "\n"); content == "" {
+		return section
+	}
+	return content + "\n\n" + section
+}
@@ -0,0 +1,51 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSection(t *testing.T) {
+	t.Parallel()
+	body := "## Summary\nFaster login.\n\n## Features\n- Add SSO\n```\n# not a heading\n```\n"
+	got := Section("v1.3.0", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), body)
+	want := "## v1.3.0 - 2026-10-16\n\n### Summary\nFaster login.\n\n### Features\n- Add SSO\n```\n# not a heading\n```\n"
+	if got != want {
+		t.Errorf("Section =\n%s\nwant\n%s", got, want)
+	}
+	if SectionTitle("HEAD") != "Unreleased" || SectionTitle("v2.0.0") != "v2.0.0" {
+		t.Error("SectionTitle does not name HEAD Unreleased")
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := Prepend(path, "## v1.0.0 - 2026-01-01\n\n- First\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Prepend(path, "## v1.1.0 - 2026-02-01\n\n- Second\n"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n## v1.1.0 - 2026-02-01\n\n- Second\n\n## v1.0.0 - 2026-01-01\n\n- First\n"
+	if string(got) != want {
+		t.Errorf("CHANGELOG.md =\n%s\nwant\n%s", got, want)
+	}
+
+	tests := []struct{ content, want string }{
+		{"", "## new\n\n"},
+		{"# Changelog\n\nAll notable changes.\n", "# Changelog\n\nAll notable changes.\n\n## new\n\n"},
+		{"# Changelog\n\nIntro.\n\n## old\n", "# Changelog\n\nIntro.\n\n## new\n\n## old\n"},
+	}
+	for _, tt := range tests {
+		if got := insertSection(tt.content, "## new\n"); got != tt.want {
+			t.Errorf("insertSection(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -45,6 +45,21 @@ func GetEmojiForType(t string) string {
 	return ""
 }
 
+// TypeForEmoji returns the configured type whose emoji is emoji, ignoring
+// variation selectors, or "" when no type uses it.
+func TypeForEmoji(emoji string) string {
+	emoji = strings.ReplaceAll(strings.TrimSpace(emoji), "\uFE0F", "")
+	if emoji == "" {
+		return ""
+	}
+	for _, info := range commitTypeList {
+		if strings.ReplaceAll(info.Emoji, "\uFE0F", "") == emoji {
+			return info.Type
+		}
+	}
+	return ""
+}
+
 // GuessCommitType tries to pick the most likely type from the message's first line.
 // It uses word-boundary matching to avoid "fix" in "prefix" false-positives.
 func GuessCommitType(message string) string {
@@ -80,9 +95,10 @@ func TypesRegexPattern() string {
 	return strings.Join(t, "|")
 }
 
-// BuildRegexPatternWithEmoji matches optional emoji, a valid type, optional scope, and colon.
+// BuildRegexPatternWithEmoji matches optional emoji, a valid type, optional scope,
+// optional "!" breaking marker, and colon. The scope is group 4 and the marker group 5.
 func BuildRegexPatternWithEmoji() *regexp.Regexp {
-	pattern := `^((\p{So}|\p{Sk}|:\w+:)\s*)?(` + TypesRegexPattern() + `)(\([^)]+\))?:\s*`
+	pattern := `^((\p{So}|\p{Sk}|:\w+:)\s*)?(` + TypesRegexPattern() + `)(\([^)]+\))?(!)?:\s*`
 	return regexp.MustCompile(pattern)
 }
 
@@ -109,6 +109,25 @@ func TestGetEmojiForType(t *testing.T) {
 	}
 }
 
+func TestTypeForEmoji(t *testing.T) {
+	setupTypes(t)
+	tests := []struct {
+		emoji string
+		want  string
+	}{
+		{"✨", "feat"},
+		{"🐛", "fix"},
+		{"📚\uFE0F", "docs"},
+		{"🎉", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := TypeForEmoji(tt.emoji); got != tt.want {
+			t.Errorf("TypeForEmoji(%q) = %q, want %q", tt.emoji, got, tt.want)
+		}
+	}
+}
+
 func TestGuessCommitType(t *testing.T) {
 	setupTypes(t)
 	tests := []struct {
@@ -186,6 +205,7 @@ func TestBuildRegexPatternWithEmoji(t *testing.T) {
 	}{
 		{"simple type prefix", "feat: add login", true},
 		{"type with scope", "fix(auth): resolve bug", true},
+		{"breaking marker", "feat(api)!: drop v1", true},
 		{"emoji prefix", "✨ feat: add feature", true},
 		{"no type prefix", "add something", false},
 		{"invalid type", "invalid: something", false},
@@ -29,6 +29,10 @@ type ProviderSettings struct {
     APIKey  string `yaml:"apiKey,omitempty"`
     Model   string `yaml:"model,omitempty"`
     BaseURL string `yaml:"baseURL,omitempty"`
+    // Temperature and Seed are sent with every request, overridden by
+    // --temperature and --seed; unset keeps the provider default.
+    Temperature *float64 `yaml:"temperature,omitempty" validate:"omitempty,gte=0,lte=2"`
+    Seed        *int64   `yaml:"seed,omitempty"`
 }
 
 type LimitSettings struct {
@@ -41,41 +45,334 @@ type Limits struct {
     Prompt LimitSettings `yaml:"prompt,omitempty"`
 }
 
+// CommentFilterSettings controls how comment-only diff lines are removed from prompts.
+type CommentFilterSettings struct {
+    // Disabled turns comment filtering off entirely.
+    Disabled bool `yaml:"disabled,omitempty"`
+    // Languages maps a file extension (e.g. ".go") to its comment markers,
+    // overriding the built-in table. An empty list disables filtering for that extension.
+    Languages map[string][]string `yaml:"languages,omitempty"`
+}
+
+// PrivacySettings keeps files out of every prompt.
+type PrivacySettings struct {
+    // NeverSendPaths are globs of files whose content is never sent to a
+    // provider; their diffs are replaced with a note. "**" spans directories,
+    // and a pattern without "/" matches the file name at any depth.
+    NeverSendPaths []string `yaml:"neverSendPaths,omitempty"`
+    Anonymize      AnonymizeSettings `yaml:"anonymize,omitempty"`
+}
+
+// AnonymizeSettings replaces identifiers in every prompt with pseudonyms that
+// are mapped back in the reply; the map is only kept in memory.
+type AnonymizeSettings struct {
+    // Enabled pseudonymizes every email and IP address, host names under
+    // .internal, .local, .lan, .corp and similar, and the user in home paths.
+    Enabled bool `yaml:"enabled,omitempty"`
+    // Domains adds domains whose host names are pseudonymized, e.g.
+    // "acme.io" covers "db1.prod.acme.io".
+    Domains []string `yaml:"domains,omitempty"`
+    // Usernames are pseudonymized wherever they appear as a whole word.
+    Usernames []string `yaml:"usernames,omitempty"`
+}
+
+// DuplicateCheckSettings controls the embeddings-based warning about staged changes
+// that closely resemble a recent commit.
+type DuplicateCheckSettings struct {
+    Enabled bool `yaml:"enabled,omitempty"`
+    // Provider and Model select the embedding backend; empty uses the active provider
+    // and its default embedding model.
+    Provider string `yaml:"provider,omitempty"`
+    Model    string `yaml:"model,omitempty"`
+    // Commits is how many recent commits to compare against (default 50).
+    Commits int `yaml:"commits,omitempty"`
+    // Threshold is the cosine similarity above which a commit is reported (default 0.9).
+    Threshold float64 `yaml:"threshold,omitempty"`
+}
+
+// ArchitectureRule forbids packages matching From from importing packages matching
+// any Deny pattern. Patterns are repository-relative package paths where "*" matches
+// one path segment and a trailing "/..." matches any subpackage.
+type ArchitectureRule struct {
+    From   string   `yaml:"from"`
+    Deny   []string `yaml:"deny"`
+    Reason string   `yaml:"reason,omitempty"`
+}
+
+// ArchitectureSettings configures the pre-commit dependency rule check.
+type ArchitectureSettings struct {
+    Rules []ArchitectureRule `yaml:"rules,omitempty"`
+    // Mode is "warn" (default) or "block"; block aborts the commit on violations.
+    Mode string `yaml:"mode,omitempty" validate:"omitempty,oneof=warn block"`
+    // AIReview also asks the AI to flag conceptual violations the import check cannot see.
+    AIReview bool `yaml:"aiReview,omitempty"`
+}
+
+// TypeRule assigns a commit type when every changed file matches one of Paths.
+// Paths use the same globs as RouteRule.
+type TypeRule struct {
+    Type  string   `yaml:"type"`
+    Paths []string `yaml:"paths"`
+}
+
+// TypeRuleSettings configures the deterministic file-category to commit-type mapping
+// applied before the AI call. Rules are checked before the built-in test, ci, and
+// docs categories.
+type TypeRuleSettings struct {
+    Disabled bool       `yaml:"disabled,omitempty"`
+    Rules    []TypeRule `yaml:"rules,omitempty"`
+    // Mode is "hint" (default), which tells the AI the expected type, or "override",
+    // which forces the type as if --commit-type had been given.
+    Mode string `yaml:"mode,omitempty" validate:"omitempty,oneof=hint override"`
+}
+
+// RouteRule sends generation to a different provider/model when the files matching
+// Paths make up most of the staged diff. Paths are globs where "**" matches any
+// number of directories (e.g. "docs/**", "pkg/crypto/**/*.go").
+type RouteRule struct {
+    Paths    []string `yaml:"paths"`
+    Provider string   `yaml:"provider,omitempty"`
+    Model    string   `yaml:"model,omitempty"`
+    // CodeReview runs an AI code review of the diff with the routed model before
+    // the message is generated.
+    CodeReview bool `yaml:"codeReview,omitempty"`
+}
+
+// Plugin is an executable run at the lifecycle points in Points with a JSON
+// description of the run on stdin, or a sandboxed Starlark script; see package
+// plugin for the protocol.
+type Plugin struct {
+    Name    string   `yaml:"name,omitempty"`
+    Command string   `yaml:"command,omitempty" validate:"required_without=Script,excluded_with=Script"`
+    // Script is a Starlark file run in place of Command; a relative path is
+    // resolved from the repository root, so each repository can ship its own.
+    Script  string   `yaml:"script,omitempty"`
+    Args    []string `yaml:"args,omitempty"`
+    Points  []string `yaml:"points" validate:"required,dive,oneof=pre-prompt post-generate pre-commit post-commit"`
+    // Timeout (e.g. "10s") bounds each run; it defaults to 30s.
+    Timeout string `yaml:"timeout,omitempty"`
+}
+
+// BudgetLimit caps usage over one period; zero values mean unlimited.
+type BudgetLimit struct {
+    MaxRequests int     `yaml:"maxRequests,omitempty" validate:"gte=0"`
+    MaxCost     float64 `yaml:"maxCost,omitempty" validate:"gte=0"`
+}
+
+// ModelPrice is the cost in USD per million tokens, used to estimate spend.
+type ModelPrice struct {
+    Input  float64 `yaml:"input,omitempty"`
+    Output float64 `yaml:"output,omitempty"`
+}
+
+// BudgetSettings limits AI usage per day and month, as recorded in the usage ledger.
+type BudgetSettings struct {
+    Daily   BudgetLimit `yaml:"daily,omitempty"`
+    Monthly BudgetLimit `yaml:"monthly,omitempty"`
+    // Prices maps "provider" or "provider:model" to its token prices.
+    Prices map[string]ModelPrice `yaml:"prices,omitempty"`
+    // FallbackProvider/FallbackModel are used once a limit is exceeded; without a
+    // fallback, ai-commit refuses to call the AI unless --override-budget is given.
+    FallbackProvider string `yaml:"fallbackProvider,omitempty"`
+    FallbackModel    string `yaml:"fallbackModel,omitempty"`
+}
+
+// CircuitBreakerSettings skips a provider that failed several requests in a
+// row for a while, using the fallback provider instead.
+type CircuitBreakerSettings struct {
+    Disabled bool `yaml:"disabled,omitempty"`
+    // Threshold is the number of consecutive failures that trips the breaker
+    // (default 3).
+    Threshold int `yaml:"threshold,omitempty" validate:"gte=0"`
+    // Cooldown is how long a tripped provider is skipped, e.g. "5m" (the default).
+    Cooldown string `yaml:"cooldown,omitempty"`
+}
+
+// GuardrailSettings configures the scan that blocks committing messages containing
+// likely secrets, internal hostnames, or profanity.
+type GuardrailSettings struct {
+    Disabled bool `yaml:"disabled,omitempty"`
+    // DeniedHosts lists hostnames that must not appear in messages. Entries may use
+    // "*" wildcards ("*.corp.example.com"); plain entries also match subdomains.
+    DeniedHosts []string `yaml:"deniedHosts,omitempty"`
+    // Profanity adds words to the built-in profanity list.
+    Profanity []string `yaml:"profanity,omitempty"`
+}
+
+// NotifySettings configures notifications when a message is ready or a forced
+// commit completes, so users can switch away during slow generations.
+type NotifySettings struct {
+    Desktop bool `yaml:"desktop,omitempty"`
+    // Bell rings the terminal bell.
+    Bell bool `yaml:"bell,omitempty"`
+    // MinSeconds skips notifications for generations faster than this.
+    MinSeconds int `yaml:"minSeconds,omitempty" validate:"gte=0"`
+    // URL receives a JSON POST after every commit and semantic-release tag, e.g.
+    // a Slack incoming webhook. MinSeconds does not apply to it.
+    URL string `yaml:"url,omitempty" validate:"omitempty,url"`
+    // Payload is the JSON body posted to URL, with JSON-escaped placeholders
+    // such as {subject} and {tag} (default {"text": "{text}"}).
+    Payload string `yaml:"payload,omitempty"`
+    // Headers are added to the webhook request, e.g. Authorization.
+    Headers map[string]string `yaml:"headers,omitempty"`
+    // Events limits the webhook to "commit" or "release" (default both).
+    Events []string `yaml:"events,omitempty" validate:"dive,oneof=commit release"`
+}
+
+// ReleaseSettings configures the tags created by --semantic-release.
+type ReleaseSettings struct {
+    // Channels maps branch names (or path.Match patterns such as "release/*") to a
+    // pre-release identifier, e.g. {develop: beta, next: rc}, like --prerelease.
+    Channels map[string]string `yaml:"channels,omitempty"`
+    // BuildMetadata is appended to every tag as "+metadata"; "{COMMIT}" is replaced
+    // with the short HEAD hash. --build-metadata overrides it.
+    BuildMetadata string `yaml:"buildMetadata,omitempty"`
+    // TagPrefix is the part of tag names before X.Y.Z (default "v").
+    TagPrefix string `yaml:"tagPrefix,omitempty"`
+    // Notes annotates every tag with AI release notes of the commits since the
+    // previous tag, like --release-notes.
+    Notes bool `yaml:"notes,omitempty"`
+    // GitHubRelease also publishes the notes as a GitHub release, like
+    // --github-release; it implies Notes and Push.
+    GitHubRelease bool `yaml:"githubRelease,omitempty"`
+    // Push pushes every new tag to Remote (default "origin"), like --push-tag.
+    Push   bool   `yaml:"push,omitempty"`
+    Remote string `yaml:"remote,omitempty"`
+    // Strategy decides the bump: "ai" (default), "conventional" (from commit
+    // types and BREAKING CHANGE footers, offline), or "hybrid" (conventional,
+    // with the AI judging untyped commits). --bump-strategy overrides it.
+    Strategy string `yaml:"strategy,omitempty" validate:"omitempty,oneof=ai conventional hybrid"`
+}
+
+// TodoSettings configures the TODO, FIXME, HACK, and XXX markers on the lines a
+// commit adds.
+type TodoSettings struct {
+    // Enabled lists them when the commit is created, like --todos.
+    Enabled bool `yaml:"enabled,omitempty"`
+    // Body appends a "TODO:" section listing them to the message body.
+    Body bool `yaml:"body,omitempty"`
+    // File appends them as checklist items, with the commit hash, to a
+    // tracking file relative to the repository root (e.g. TODO.md).
+    File string `yaml:"file,omitempty"`
+}
+
+// PackageSettings describes a separately versioned package of a monorepo.
+type PackageSettings struct {
+    // Path is the package directory relative to the repository root.
+    Path string `yaml:"path" validate:"required"`
+    // TagPrefix is the part of the package's tag names before X.Y.Z
+    // (default "<name>/v", e.g. "pkg-ui/v1.2.3").
+    TagPrefix string `yaml:"tagPrefix,omitempty"`
+}
+
 type Config struct {
 	Prompt           string             `yaml:"prompt,omitempty"`
 	CommitType       string             `yaml:"commitType,omitempty"`
 	Template         string             `yaml:"template,omitempty"`
 	SemanticRelease  bool               `yaml:"semanticRelease,omitempty"`
+	Release          ReleaseSettings    `yaml:"release,omitempty"`
 	InteractiveSplit bool               `yaml:"interactiveSplit,omitempty"`
 	EnableEmoji      bool               `yaml:"enableEmoji,omitempty"`
+	// Packages makes --semantic-release tag each package whose path changed
+	// since its last release, instead of the whole repository.
+	Packages map[string]PackageSettings `yaml:"packages,omitempty" validate:"dive"`
 
     Provider    string             `yaml:"provider,omitempty"`
     CommitTypes []CommitTypeConfig `yaml:"commitTypes,omitempty"`
     LockFiles   []string           `yaml:"lockFiles,omitempty"`
+    CommentFilter CommentFilterSettings `yaml:"commentFilter,omitempty"`
+    Privacy       PrivacySettings       `yaml:"privacy,omitempty"`
+    // LanguageHints adds prompt guidance per changed language, keyed by language
+    // name (e.g. "Go", "React", "Python"; case-insensitive).
+    LanguageHints map[string]string `yaml:"languageHints,omitempty"`
+    TypeRules     TypeRuleSettings  `yaml:"typeRules,omitempty"`
     Limits Limits `yaml:"limits,omitempty"`
+    DuplicateCheck DuplicateCheckSettings `yaml:"duplicateCheck,omitempty"`
+    Architecture   ArchitectureSettings   `yaml:"architecture,omitempty"`
+    Routing        []RouteRule            `yaml:"routing,omitempty"`
+    // Plugins run in the order listed at each lifecycle point.
+    Plugins        []Plugin               `yaml:"plugins,omitempty" validate:"dive"`
+    Budget         BudgetSettings         `yaml:"budget,omitempty"`
+    Guardrails     GuardrailSettings      `yaml:"guardrails,omitempty"`
+    // InstantQuit quits the TUIs without asking, even with manual edits to the
+    // message or commits queued in --interactive-split.
+    InstantQuit bool `yaml:"instantQuit,omitempty"`
+    // NoTTY decides what a run that would open the TUI does without a terminal
+    // (git hooks, CI): "force" (the default) commits as with --force after a
+    // warning, "fail" exits with an error.
+    NoTTY string `yaml:"noTTY,omitempty" validate:"omitempty,oneof=force fail"`
+    // Keys rebinds TUI actions, e.g. {commit: c, regenerate: "ctrl+r"}; values may
+    // list several keys separated by commas. Conflicts are rejected at startup.
+    Keys map[string]string `yaml:"keys,omitempty"`
+    Notify         NotifySettings         `yaml:"notify,omitempty"`
+    // MaxWait (e.g. "10s") is how long generation may run before the partial
+    // message is offered or Fallback takes over, like --max-wait.
+    MaxWait string `yaml:"maxWait,omitempty"`
+    // Fallback is the provider[:model] used when MaxWait passes without output;
+    // budget.fallbackProvider is used when it is empty.
+    Fallback string `yaml:"fallback,omitempty"`
+    CircuitBreaker CircuitBreakerSettings `yaml:"circuitBreaker,omitempty"`
+    // Candidates is how many alternative messages one request asks for, like
+    // --candidates; the TUI switches between them.
+    Candidates int `yaml:"candidates,omitempty" validate:"gte=0,lte=10"`
+    // RelatedCommits is how many subjects of recent commits touching the same
+    // files are added to the prompt, like --related-commits.
+    RelatedCommits int `yaml:"relatedCommits,omitempty" validate:"gte=0,lte=50"`
+    Todos          TodoSettings `yaml:"todos,omitempty"`
+    // Share prints a chat-ready snippet of every commit and copies it to the
+    // clipboard, like --share. Setting ShareTemplate implies it.
+    Share bool `yaml:"share,omitempty"`
+    // ShareTemplate lays out the snippet with {hash}, {short}, {subject},
+    // {bullets}, {body}, and {url} placeholders.
+    ShareTemplate string `yaml:"shareTemplate,omitempty"`
 
     // Enterprise-style provider configuration. Preferred over legacy flat fields below.
-    Providers map[string]ProviderSettings `yaml:"providers,omitempty"`
+    Providers map[string]ProviderSettings `yaml:"providers,omitempty" validate:"dive"`
 
     PromptTemplate string `yaml:"promptTemplate,omitempty"`
     TicketPattern  string `yaml:"ticketPattern,omitempty"`
 
 	AuthorName  string `yaml:"authorName,omitempty"`
 	AuthorEmail string `yaml:"authorEmail,omitempty"`
+	// Signoff adds a "Signed-off-by:" trailer to every commit (DCO), like --signoff.
+	Signoff bool `yaml:"signoff,omitempty"`
+	// Sign signs every commit with the GPG or SSH key of the git config, like --sign.
+	Sign bool `yaml:"sign,omitempty"`
+	// Gerrit adds a Gerrit "Change-Id:" trailer to every commit, replacing Gerrit's
+	// commit-msg hook.
+	Gerrit bool `yaml:"gerrit,omitempty"`
+	// Provenance adds an "X-AI-Commit:" trailer naming the provider, model, prompt
+	// template hash, and ai-commit version, like --provenance.
+	Provenance bool `yaml:"provenance,omitempty"`
+	// Deterministic sends every request with temperature 0 and a fixed seed and
+	// attaches a reproducibility report to each commit as a git note, like
+	// --deterministic.
+	Deterministic bool `yaml:"deterministic,omitempty"`
+	// IgnoreCommitTemplate stops the repository's commit.template from shaping
+	// generated messages and adding its footers.
+	IgnoreCommitTemplate bool `yaml:"ignoreCommitTemplate,omitempty"`
 }
 
-func LoadOrCreateConfig() (*Config, error) {
+// Dir returns the per-user directory holding config.yaml (~/.config/<binary name>).
+func Dir() (string, error) {
 	exePath, err := os.Executable()
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine executable path: %w", err)
+		return "", fmt.Errorf("failed to determine executable path: %w", err)
 	}
 	binaryName := filepath.Base(exePath)
 
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine user home directory: %w", err)
+		return "", fmt.Errorf("failed to determine user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", binaryName), nil
+}
+
+func LoadOrCreateConfig() (*Config, error) {
+	configDir, err := Dir()
+	if err != nil {
+		return nil, err
 	}
-	configDir := filepath.Join(homeDir, ".config", binaryName)
 	configPath := filepath.Join(configDir, "config.yaml")
 
 	if _, err := os.Stat(configDir); os.IsNotExist(err) {
@@ -153,6 +450,17 @@ func (cfg *Config) Validate() error {
     if err := v.Struct(cfg); err != nil {
         return fmt.Errorf("config validation failed: %w", err)
     }
+    for _, p := range cfg.Plugins {
+        if p.Script == "" {
+            continue
+        }
+        // Scripts transform a message, so only points that have one can run them.
+        for _, point := range p.Points {
+            if point != "post-generate" && point != "pre-commit" {
+                return fmt.Errorf("config validation failed: plugin script %s runs only at post-generate and pre-commit, not %s", p.Script, point)
+            }
+        }
+    }
     return nil
 }
 
@@ -64,6 +64,45 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidate_ArchitectureMode(t *testing.T) {
+	t.Parallel()
+	for mode, wantErr := range map[string]bool{"": false, "warn": false, "block": false, "strict": true} {
+		cfg := &Config{Architecture: ArchitectureSettings{Mode: mode}}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("mode %q: Validate() error = %v, wantErr %v", mode, err, wantErr)
+		}
+	}
+}
+
+func TestValidate_TypeRulesMode(t *testing.T) {
+	t.Parallel()
+	for mode, wantErr := range map[string]bool{"": false, "hint": false, "override": false, "force": true} {
+		cfg := &Config{TypeRules: TypeRuleSettings{Mode: mode}}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("mode %q: Validate() error = %v, wantErr %v", mode, err, wantErr)
+		}
+	}
+}
+
+func TestValidate_PluginScriptPoints(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		plugin  Plugin
+		wantErr bool
+	}{
+		"script at post-generate": {Plugin{Script: "format.star", Points: []string{"post-generate", "pre-commit"}}, false},
+		"script at pre-prompt":    {Plugin{Script: "format.star", Points: []string{"pre-prompt"}}, true},
+		"script at post-commit":   {Plugin{Script: "format.star", Points: []string{"post-generate", "post-commit"}}, true},
+		"command at post-commit":  {Plugin{Command: "notify", Points: []string{"post-commit"}}, false},
+	}
+	for name, tt := range tests {
+		cfg := &Config{Plugins: []Plugin{tt.plugin}}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", name, err, tt.wantErr)
+		}
+	}
+}
+
 func TestResolveAPIKey(t *testing.T) {
 	// Cannot use t.Parallel() because subtests use t.Setenv
 	tests := []struct {
@@ -0,0 +1,173 @@
+// Package duplicate detects staged changes that closely resemble recent commits
+// by comparing embeddings of their diffs.
+package duplicate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTextChars caps the text sent for embedding; the head of a diff carries most of its signal.
+const maxTextChars = 8000
+
+// Entry is the cached embedding of one commit.
+type Entry struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+	Vector  []float64 `json:"vector"`
+}
+
+// Cache holds commit embeddings for one embedding model, keyed by commit hash.
+type Cache struct {
+	Model   string           `json:"model"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Match is a recent commit whose diff resembles the staged one.
+type Match struct {
+	Entry
+	Similarity float64
+}
+
+// CachePath returns where commit embeddings are cached for the current repository.
+func CachePath() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), "ai-commit", "embeddings.json"), nil
+}
+
+// LoadCache reads the cache at path. A missing file, or one built with a different
+// model, yields an empty cache for model.
+func LoadCache(path, model string) (*Cache, error) {
+	empty := &Cache{Model: model, Entries: map[string]Entry{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return empty, nil
+		}
+		return nil, fmt.Errorf("failed to read embedding cache: %w", err)
+	}
+	var c Cache
+	if err := json.Unmarshal(data, &c); err != nil || c.Model != model {
+		return empty, nil
+	}
+	if c.Entries == nil {
+		c.Entries = map[string]Entry{}
+	}
+	return &c, nil
+}
+
+// Save writes the cache to path, keeping only the entries whose hash is in keep
+// so the file does not grow without bound.
+func (c *Cache) Save(path string, keep []string) error {
+	pruned := &Cache{Model: c.Model, Entries: make(map[string]Entry, len(keep))}
+	for _, h := range keep {
+		if e, ok := c.Entries[h]; ok {
+			pruned.Entries[h] = e
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(pruned)
+	if err != nil {
+		return fmt.Errorf("failed to encode embedding cache: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	return nil
+}
+
+// Text reduces a diff to the file headers and changed lines that are worth embedding.
+func Text(diff string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "diff --git"):
+			b.WriteString(line)
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			if strings.TrimSpace(line[1:]) == "" {
+				continue
+			}
+			b.WriteString(line)
+		default:
+			continue
+		}
+		b.WriteByte('\n')
+		if b.Len() >= maxTextChars {
+			break
+		}
+	}
+	text := b.String()
+	if len(text) > maxTextChars {
+		text = text[:maxTextChars]
+	}
+	return strings.TrimSpace(text)
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if they are not comparable.
+func Cosine(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// FindSimilar returns the entries at least threshold-similar to vector, most similar first.
+func FindSimilar(vector []float64, entries []Entry, threshold float64) []Match {
+	var matches []Match
+	for _, e := range entries {
+		if sim := Cosine(vector, e.Vector); sim >= threshold {
+			matches = append(matches, Match{Entry: e, Similarity: sim})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Similarity > matches[j].Similarity })
+	return matches
+}
+
+// Describe renders a match as a one-line warning, e.g.
+// `looks 94% similar to abc1234 "fix: retry on timeout" from 6 days ago`.
+func Describe(m Match, now time.Time) string {
+	short := m.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	return fmt.Sprintf("looks %.0f%% similar to %s %q from %s", m.Similarity*100, short, m.Subject, age(now.Sub(m.Date)))
+}
+
+func age(d time.Duration) string {
+	switch days := int(d.Hours() / 24); {
+	case d < 2*time.Hour:
+		return "less than two hours ago"
+	case days == 0:
+		return fmt.Sprintf("%d hours ago", int(d.Hours()))
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	default:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	}
+}
@@ -0,0 +1,93 @@
+package duplicate
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCosine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{name: "identical", a: []float64{1, 2, 3}, b: []float64{1, 2, 3}, want: 1},
+		{name: "orthogonal", a: []float64{1, 0}, b: []float64{0, 1}, want: 0},
+		{name: "length mismatch", a: []float64{1, 0}, b: []float64{1}, want: 0},
+		{name: "zero vector", a: []float64{0, 0}, b: []float64{1, 1}, want: 0},
+	}
+	for _, tc := range tests {
+		if got := Cosine(tc.a, tc.b); got-tc.want > 1e-9 || tc.want-got > 1e-9 {
+			t.Errorf("%s: Cosine() = %f, want %f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFindSimilar(t *testing.T) {
+	t.Parallel()
+	entries := []Entry{
+		{Hash: "aaa", Vector: []float64{1, 0}},
+		{Hash: "bbb", Vector: []float64{1, 0.1}},
+		{Hash: "ccc", Vector: []float64{0, 1}},
+	}
+	matches := FindSimilar([]float64{1, 0.05}, entries, 0.9)
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Similarity < matches[1].Similarity {
+		t.Errorf("expected matches sorted by similarity, got %+v", matches)
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/a.go b/a.go\nindex 1..2 100644\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,2 @@\n context\n-old\n+new\n+\n"
+	got := Text(diff)
+	want := "diff --git a/a.go b/a.go\n-old\n+new"
+	if got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	if long := Text("+" + strings.Repeat("x", 2*maxTextChars)); len(long) > maxTextChars {
+		t.Errorf("expected text capped at %d chars, got %d", maxTextChars, len(long))
+	}
+}
+
+func TestCacheRoundTripAndPrune(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "ai-commit", "embeddings.json")
+	c, err := LoadCache(path, "openai:")
+	if err != nil || len(c.Entries) != 0 {
+		t.Fatalf("expected empty cache, got %+v, %v", c, err)
+	}
+	c.Entries["keep"] = Entry{Hash: "keep", Vector: []float64{1}}
+	c.Entries["drop"] = Entry{Hash: "drop", Vector: []float64{2}}
+	if err := c.Save(path, []string{"keep"}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCache(path, "openai:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loaded.Entries["keep"]; !ok || len(loaded.Entries) != 1 {
+		t.Errorf("expected only the kept entry, got %+v", loaded.Entries)
+	}
+	other, err := LoadCache(path, "ollama:nomic-embed-text")
+	if err != nil || len(other.Entries) != 0 {
+		t.Errorf("expected a cache for another model to start empty, got %+v", other.Entries)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	m := Match{Entry: Entry{Hash: "abc1234def", Subject: "fix: retry on timeout", Date: now.AddDate(0, 0, -6)}, Similarity: 0.936}
+	got := Describe(m, now)
+	want := `looks 94% similar to abc1234 "fix: retry on timeout" from 6 days ago`
+	if got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// conventionalSubject captures type and scope of a Conventional Commits subject,
+// tolerating a leading emoji or other non-letter prefix.
+var conventionalSubject = regexp.MustCompile(`^[^a-zA-Z]*([a-zA-Z]+)(?:\(([^)]*)\))?!?:\s*(.*)$`)
+
+var wordPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+var gradePattern = regexp.MustCompile(`\b(10|[0-9])\b`)
+
+// Score compares one generated message with the message that was actually committed.
+type Score struct {
+	TypeMatch  bool
+	ScopeMatch bool
+	// Similarity is the word-level Jaccard similarity of the two subject descriptions (0..1).
+	Similarity float64
+	// Grade is an optional 1-10 rating from a grading model; 0 means not graded.
+	Grade int
+}
+
+type subject struct {
+	commitType  string
+	scope       string
+	description string
+}
+
+func parseSubject(message string) subject {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(message), "\n", 2)[0])
+	if m := conventionalSubject.FindStringSubmatch(line); m != nil {
+		return subject{commitType: strings.ToLower(m[1]), scope: strings.ToLower(m[2]), description: m[3]}
+	}
+	return subject{description: line}
+}
+
+// Compare scores generated against actual using the subject line of each.
+func Compare(generated, actual string) Score {
+	g, a := parseSubject(generated), parseSubject(actual)
+	return Score{
+		TypeMatch:  g.commitType != "" && g.commitType == a.commitType,
+		ScopeMatch: g.scope == a.scope,
+		Similarity: jaccard(g.description, a.description),
+	}
+}
+
+func jaccard(a, b string) float64 {
+	setA := wordSet(a)
+	setB := wordSet(b)
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+	inter := 0
+	for w := range setA {
+		if setB[w] {
+			inter++
+		}
+	}
+	union := len(setA) + len(setB) - inter
+	return float64(inter) / float64(union)
+}
+
+func wordSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(strings.ToLower(s), -1) {
+		set[w] = true
+	}
+	return set
+}
+
+// ParseGrade extracts the first 0-10 number from a grading model's response.
+func ParseGrade(response string) int {
+	m := gradePattern.FindString(response)
+	if m == "" {
+		return 0
+	}
+	n, _ := strconv.Atoi(m)
+	return n
+}
+
+// Result is the outcome of replaying one commit through one profile.
+type Result struct {
+	Profile string
+	Commit  string
+	Score   Score
+	Err     error
+}
+
+// Summary aggregates the results of one profile.
+type Summary struct {
+	Profile       string
+	Runs          int
+	Failures      int
+	TypeAccuracy  float64
+	ScopeAccuracy float64
+	AvgSimilarity float64
+	// AvgGrade is 0 when no result was graded.
+	AvgGrade float64
+}
+
+// Summarize aggregates results per profile, best average similarity first.
+func Summarize(results []Result) []Summary {
+	type acc struct {
+		Summary
+		ok, graded, types, scopes int
+		sim, grade                float64
+	}
+	byProfile := make(map[string]*acc)
+	var order []string
+	for _, r := range results {
+		a, ok := byProfile[r.Profile]
+		if !ok {
+			a = &acc{Summary: Summary{Profile: r.Profile}}
+			byProfile[r.Profile] = a
+			order = append(order, r.Profile)
+		}
+		a.Runs++
+		if r.Err != nil {
+			a.Failures++
+			continue
+		}
+		a.ok++
+		if r.Score.TypeMatch {
+			a.types++
+		}
+		if r.Score.ScopeMatch {
+			a.scopes++
+		}
+		a.sim += r.Score.Similarity
+		if r.Score.Grade > 0 {
+			a.graded++
+			a.grade += float64(r.Score.Grade)
+		}
+	}
+
+	summaries := make([]Summary, 0, len(order))
+	for _, name := range order {
+		a := byProfile[name]
+		s := a.Summary
+		if a.ok > 0 {
+			s.TypeAccuracy = float64(a.types) / float64(a.ok)
+			s.ScopeAccuracy = float64(a.scopes) / float64(a.ok)
+			s.AvgSimilarity = a.sim / float64(a.ok)
+		}
+		if a.graded > 0 {
+			s.AvgGrade = a.grade / float64(a.graded)
+		}
+		summaries = append(summaries, s)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool { return summaries[i].AvgSimilarity > summaries[j].AvgSimilarity })
+	return summaries
+}
+
+// FormatScoreboard renders summaries as a plain-text table.
+func FormatScoreboard(summaries []Summary) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-32s %5s %5s %7s %7s %7s %6s\n", "PROFILE", "RUNS", "FAIL", "TYPE", "SCOPE", "SIMIL", "GRADE"))
+	for _, s := range summaries {
+		grade := "-"
+		if s.AvgGrade > 0 {
+			grade = fmt.Sprintf("%.1f", s.AvgGrade)
+		}
+		b.WriteString(fmt.Sprintf("%-32s %5d %5d %6.0f%% %6.0f%% %7.2f %6s\n",
+			s.Profile, s.Runs, s.Failures, s.TypeAccuracy*100, s.ScopeAccuracy*100, s.AvgSimilarity, grade))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
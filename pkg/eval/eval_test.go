@@ -0,0 +1,91 @@
+package eval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		generated  string
+		actual     string
+		typeMatch  bool
+		scopeMatch bool
+		similarity float64
+	}{
+		{
+			name:       "identical",
+			generated:  "feat(auth): add login endpoint",
+			actual:     "feat(auth): add login endpoint\n\nbody",
+			typeMatch:  true,
+			scopeMatch: true,
+			similarity: 1,
+		},
+		{
+			name:       "emoji prefix and different scope",
+			generated:  "✨ feat(api): add login",
+			actual:     "feat(auth): add login endpoint",
+			typeMatch:  true,
+			similarity: 2.0 / 3.0,
+		},
+		{
+			name:       "non conventional actual",
+			generated:  "fix: handle nil config",
+			actual:     "Handle nil config",
+			scopeMatch: true,
+			similarity: 1,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := Compare(tc.generated, tc.actual)
+			if got.TypeMatch != tc.typeMatch || got.ScopeMatch != tc.scopeMatch {
+				t.Errorf("Compare() = %+v", got)
+			}
+			if diff := got.Similarity - tc.similarity; diff > 0.001 || diff < -0.001 {
+				t.Errorf("Similarity = %f, want %f", got.Similarity, tc.similarity)
+			}
+		})
+	}
+}
+
+func TestParseGrade(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int{"8": 8, "Score: 10/10": 10, "I'd say 7.": 7, "no idea": 0}
+	for in, want := range tests {
+		if got := ParseGrade(in); got != want {
+			t.Errorf("ParseGrade(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	results := []Result{
+		{Profile: "a", Score: Score{TypeMatch: true, Similarity: 0.5, Grade: 6}},
+		{Profile: "a", Score: Score{Similarity: 0.3}},
+		{Profile: "b", Score: Score{TypeMatch: true, ScopeMatch: true, Similarity: 0.9}},
+		{Profile: "b", Err: errors.New("timeout")},
+	}
+	summaries := Summarize(results)
+	if len(summaries) != 2 || summaries[0].Profile != "b" {
+		t.Fatalf("expected b first, got %+v", summaries)
+	}
+	a := summaries[1]
+	if a.Runs != 2 || a.TypeAccuracy != 0.5 || a.AvgGrade != 6 || a.AvgSimilarity != 0.4 {
+		t.Errorf("unexpected summary for a: %+v", a)
+	}
+	if summaries[0].Failures != 1 || summaries[0].AvgSimilarity != 0.9 {
+		t.Errorf("unexpected summary for b: %+v", summaries[0])
+	}
+
+	table := FormatScoreboard(summaries)
+	if !strings.Contains(table, "PROFILE") || !strings.Contains(table, "6.0") {
+		t.Errorf("unexpected scoreboard:\n%s", table)
+	}
+}
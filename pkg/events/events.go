@@ -0,0 +1,114 @@
+// Package events is an in-process event bus. Commands and the TUI publish what
+// happened (an AI request, a ready message, a commit, a release tag), and
+// cross-cutting features such as the usage ledger and notifications subscribe,
+// so the code that does the work need not know about them.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+// Event is one of the event types below.
+type Event interface {
+	// Name identifies the event type, e.g. in logs.
+	Name() string
+}
+
+// GenerationStarted is published before a request is sent to a provider.
+type GenerationStarted struct {
+	Provider string
+	Model    string
+	Prompt   string
+}
+
+// GenerationFinished is published when a provider request returns, failed or
+// not. Stats holds its latency and token usage, estimated from Prompt and
+// Output when the provider reports none.
+type GenerationFinished struct {
+	Stats  ai.CallStats
+	Prompt string
+	Output string
+	Err    error
+}
+
+// MessageReady is published when a commit message is ready for the user;
+// Elapsed is how long its generation took.
+type MessageReady struct {
+	Message string
+	Elapsed time.Duration
+}
+
+// CommitCreated is published after a commit is created. Interactive is set
+// when the user confirmed it in the TUI; Elapsed is the time since generation
+// started otherwise.
+type CommitCreated struct {
+	Hash        string
+	Message     string
+	Elapsed     time.Duration
+	Interactive bool
+}
+
+// ReleaseTagged is published after a release tag is created on Commit. Notes
+// holds the AI release notes when they were generated; Prerelease is set when
+// the tagged version has a pre-release part (v1.2.0-rc.1).
+type ReleaseTagged struct {
+	Tag        string
+	Previous   string
+	Commit     string
+	Notes      string
+	Prerelease bool
+}
+
+func (GenerationStarted) Name() string  { return "generation.started" }
+func (GenerationFinished) Name() string { return "generation.finished" }
+func (MessageReady) Name() string       { return "message.ready" }
+func (CommitCreated) Name() string      { return "commit.created" }
+func (ReleaseTagged) Name() string      { return "release.tagged" }
+
+// Bus delivers published events to its subscribers, synchronously and in
+// subscription order. A nil Bus drops every event.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(Event)
+}
+
+// New returns an empty bus.
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls fn with every event published on b. Handlers run on the
+// publishing goroutine, so they should return quickly.
+func (b *Bus) Subscribe(fn func(Event)) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, fn)
+}
+
+// Publish delivers e to the subscribers of b.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// On subscribes fn to the events of type T published on b.
+func On[T Event](b *Bus, fn func(T)) {
+	b.Subscribe(func(e Event) {
+		if t, ok := e.(T); ok {
+			fn(t)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	t.Parallel()
+	b := New()
+	var names []string
+	b.Subscribe(func(e Event) { names = append(names, e.Name()) })
+	var commits []CommitCreated
+	On(b, func(e CommitCreated) { commits = append(commits, e) })
+
+	b.Publish(MessageReady{Message: "feat: x"})
+	b.Publish(CommitCreated{Hash: "abc1234", Message: "feat: x"})
+
+	if len(names) != 2 || names[0] != "message.ready" || names[1] != "commit.created" {
+		t.Errorf("events = %q, want message.ready and commit.created in order", names)
+	}
+	if len(commits) != 1 || commits[0].Hash != "abc1234" {
+		t.Errorf("typed subscriber got %+v, want only the commit", commits)
+	}
+
+	var nilBus *Bus
+	nilBus.Subscribe(func(Event) { t.Error("nil bus delivered an event") })
+	nilBus.Publish(MessageReady{})
+}
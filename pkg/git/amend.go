@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// emptyTreeHash is git's empty tree, the base of a root commit's diff.
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// AmendDiff returns the diff the HEAD commit would have after amending it: from
+// HEAD's parent to the index, covering the changes of HEAD and any staged since.
+func AmendDiff(ctx context.Context) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := amendableHead(repo)
+	if err != nil {
+		return "", err
+	}
+	base := emptyTreeHash
+	if head.NumParents() > 0 {
+		base = head.ParentHashes[0].String()
+	}
+	out, err := gitCommand(ctx, "diff", "--cached", "--no-color", "--no-ext-diff", base, "--").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git diff --cached %s: %s", base, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git diff --cached %s: %w", base, err)
+	}
+	return redactPrivateFiles(string(out), nil), nil
+}
+
+// GetAmendPromptDiff is GetPromptDiff for the amended HEAD commit (see
+// AmendDiff) instead of the staged changes alone.
+func GetAmendPromptDiff(ctx context.Context, lockFiles []string) (string, *FilterReport, error) {
+	raw, err := AmendDiff(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	diff, report := promptDiffOf(ctx, raw, lockFiles)
+	return diff, report, nil
+}
+
+// amendableHead returns the HEAD commit. Merge commits are refused, since the
+// amended commit would keep only the first parent.
+func amendableHead(repo *gogit.Repository) (*object.Commit, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("nothing to amend: %w", err)
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	if head.NumParents() > 1 {
+		return nil, fmt.Errorf("HEAD %s is a merge commit; amending it is not supported", head.Hash.String()[:7])
+	}
+	return head, nil
+}
+
+// keepAuthor returns opts with the author identity and date of the amended
+// commit, as `git commit --amend` keeps them, unless --author/--date or
+// GIT_AUTHOR_* override them.
+func keepAuthor(opts CommitOptions, author object.Signature) CommitOptions {
+	if opts.AuthorName == "" && os.Getenv("GIT_AUTHOR_NAME") == "" {
+		opts.AuthorName = author.Name
+	}
+	if opts.AuthorEmail == "" && os.Getenv("GIT_AUTHOR_EMAIL") == "" {
+		opts.AuthorEmail = author.Email
+	}
+	if opts.AuthorDate.IsZero() && os.Getenv("GIT_AUTHOR_DATE") == "" {
+		opts.AuthorDate = author.When
+	}
+	return opts
+}
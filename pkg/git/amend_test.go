@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+func TestAmend_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := repo.Head()
+	wt, _ := repo.Worktree()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt.Add("a.txt")
+	if err := CommitChanges(context.Background(), "feat: add a"); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := repo.Head()
+	original, _ := repo.CommitObject(before.Hash())
+
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt.Add("b.txt")
+
+	diff, err := AmendDiff(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"a.txt", "b.txt"} {
+		if !strings.Contains(diff, "+++ b/"+path) {
+			t.Errorf("amend diff is missing %s:\n%s", path, diff)
+		}
+	}
+	if strings.Contains(diff, "README.md") {
+		t.Errorf("amend diff includes the parent's changes:\n%s", diff)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "")
+	t.Setenv("GIT_COMMITTER_NAME", "Someone Else")
+	t.Setenv("GIT_COMMITTER_EMAIL", "else@example.com")
+	if err := CommitChangesWithOptions(context.Background(), "feat: add a and b", CommitOptions{Amend: true}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := repo.Head()
+	amended, err := repo.CommitObject(after.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amended.Hash == original.Hash || amended.Message != "feat: add a and b" {
+		t.Errorf("HEAD = %s %q, want the amended commit", amended.Hash, amended.Message)
+	}
+	if len(amended.ParentHashes) != 1 || amended.ParentHashes[0] != root.Hash() {
+		t.Errorf("parents = %v, want the original parent %s", amended.ParentHashes, root.Hash())
+	}
+	if amended.Author.Name != original.Author.Name || amended.Author.Email != original.Author.Email {
+		t.Errorf("author = %s <%s>, want the original %s <%s>", amended.Author.Name, amended.Author.Email, original.Author.Name, original.Author.Email)
+	}
+	if _, err := amended.File("b.txt"); err != nil {
+		t.Errorf("amended commit is missing the newly staged file: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// generatedAttrs are the .gitattributes attributes that mark a file as not worth
+// showing to the AI, mirroring how GitHub collapses such files in pull requests.
+var generatedAttrs = []string{"linguist-generated", "diff"}
+
+// parseCheckAttr parses `git check-attr -z` output ("path\0attr\0value\0" records)
+// and returns the paths that are linguist-generated or have diff unset (-diff).
+func parseCheckAttr(out []byte) map[string]FilterReason {
+	fields := bytes.Split(out, []byte{0})
+	generated := make(map[string]FilterReason)
+	for i := 0; i+2 < len(fields); i += 3 {
+		path, attr, value := string(fields[i]), string(fields[i+1]), string(fields[i+2])
+		switch {
+		case attr == "linguist-generated" && (value == "set" || value == "true"):
+			generated[path] = FilterGenerated
+		case attr == "diff" && value == "unset":
+			if _, ok := generated[path]; !ok {
+				generated[path] = FilterNoDiff
+			}
+		}
+	}
+	return generated
+}
+
+// generatedFiles asks git which of paths are marked generated or -diff.
+func generatedFiles(ctx context.Context, paths []string) (map[string]FilterReason, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	args := append([]string{"check-attr", "-z"}, generatedAttrs...)
+	args = append(args, "--")
+	args = append(args, paths...)
+	out, err := gitCommand(ctx, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git check-attr failed: %w", err)
+	}
+	return parseCheckAttr(out), nil
+}
+
+// DiffPaths lists the file paths in diff in order of appearance.
+func DiffPaths(diff string) []string {
+	var paths []string
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if p := parseFilePath(line); p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// summarizeGeneratedFiles replaces the body of every file in generated with a
+// one-line note, so the AI still sees that the file changed but not its content.
+func summarizeGeneratedFiles(diff string, generated map[string]FilterReason, report *FilterReport) string {
+	if len(generated) == 0 {
+		return diff
+	}
+	var out []string
+	var reason FilterReason
+	path := ""
+	changed := 0
+	flush := func() {
+		if reason == "" {
+			return
+		}
+		out = append(out, fmt.Sprintf("[%s: %d changed lines omitted]", reason, changed))
+		report.add(path, reason, changed)
+		reason, changed = "", 0
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			path = parseFilePath(line)
+			reason = generated[path]
+			out = append(out, line)
+			continue
+		}
+		if reason == "" {
+			out = append(out, line)
+		} else if isChangeLine(line) {
+			changed++
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// filterGeneratedFiles summarizes files marked linguist-generated or -diff in .gitattributes.
+// If the attributes cannot be read the diff is returned unchanged.
+func filterGeneratedFiles(ctx context.Context, diff string, report *FilterReport) string {
+	generated, err := generatedFiles(ctx, DiffPaths(diff))
+	if err != nil {
+		return diff
+	}
+	return summarizeGeneratedFiles(diff, generated, report)
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCheckAttr(t *testing.T) {
+	t.Parallel()
+	out := strings.Join([]string{
+		"api.pb.go", "linguist-generated", "true",
+		"api.pb.go", "diff", "unspecified",
+		"dist/app.min.js", "linguist-generated", "unspecified",
+		"dist/app.min.js", "diff", "unset",
+		"main.go", "linguist-generated", "unspecified",
+		"main.go", "diff", "unspecified",
+		"schema.gen.go", "linguist-generated", "set",
+		"schema.gen.go", "diff", "unset",
+	}, "\x00") + "\x00"
+
+	got := parseCheckAttr([]byte(out))
+	want := map[string]FilterReason{
+		"api.pb.go":       FilterGenerated,
+		"dist/app.min.js": FilterNoDiff,
+		"schema.gen.go":   FilterGenerated,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseCheckAttr() = %v, want %v", got, want)
+	}
+	for path, reason := range want {
+		if got[path] != reason {
+			t.Errorf("%s: reason = %q, want %q", path, got[path], reason)
+		}
+	}
+}
+
+func TestSummarizeGeneratedFiles(t *testing.T) {
+	t.Parallel()
+	diff := strings.Join([]string{
+		"diff --git a/api.pb.go b/api.pb.go",
+		"--- a/api.pb.go",
+		"+++ b/api.pb.go",
+		"@@ -1,2 +1,2 @@",
+		"-old",
+		"+new",
+		"+more",
+		"diff --git a/main.go b/main.go",
+		"@@ -1 +1 @@",
+		"+func main() {}",
+	}, "\n")
+
+	report := &FilterReport{}
+	got := summarizeGeneratedFiles(diff, map[string]FilterReason{"api.pb.go": FilterGenerated}, report)
+	want := strings.Join([]string{
+		"diff --git a/api.pb.go b/api.pb.go",
+		"[generated file: 3 changed lines omitted]",
+		"diff --git a/main.go b/main.go",
+		"@@ -1 +1 @@",
+		"+func main() {}",
+	}, "\n")
+	if got != want {
+		t.Errorf("summarizeGeneratedFiles() =\n%s\nwant\n%s", got, want)
+	}
+	if len(report.Items) != 1 || report.Items[0].Path != "api.pb.go" || report.Items[0].Lines != 3 {
+		t.Errorf("unexpected report %+v", report.Items)
+	}
+}
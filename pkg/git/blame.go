@@ -0,0 +1,51 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BlameAuthors counts the lines of path at rev that git blame credits to each
+// author, keyed by their "Name <email>" identity.
+func BlameAuthors(ctx context.Context, rev, path string) (map[string]int, error) {
+	out, err := gitCommand(ctx, "blame", "--line-porcelain", rev, "--", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s failed: %w", path, err)
+	}
+	return parseBlameAuthors(out), nil
+}
+
+// parseBlameAuthors reads the author and author-mail headers git blame
+// --line-porcelain repeats for every line.
+func parseBlameAuthors(out []byte) map[string]int {
+	authors := make(map[string]int)
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			email := strings.TrimPrefix(line, "author-mail ")
+			// Uncommitted lines are credited to "Not Committed Yet".
+			if email != "<not.committed.yet>" {
+				authors[name+" "+email]++
+			}
+		}
+	}
+	return authors
+}
+
+// UserEmail returns the user.email git commits with, or "" when it is unset.
+func UserEmail(ctx context.Context) string {
+	out, err := gitCommand(ctx, "config", "--get", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,22 @@
+package git
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseBlameAuthors(t *testing.T) {
+	t.Parallel()
+	out := "0123456789abcdef0123456789abcdef01234567 1 1 2\n" +
+		"author Pat Doe\nauthor-mail <pat@example.com>\nauthor-time 1700000000\nfilename main.go\n\tpackage main\n" +
+		"0123456789abcdef0123456789abcdef01234567 2 2\n" +
+		"author Pat Doe\nauthor-mail <pat@example.com>\nfilename main.go\n\n" +
+		"89abcdef0123456789abcdef0123456789abcdef 3 3 1\n" +
+		"author Sam Roe\nauthor-mail <sam@example.com>\nfilename main.go\n\tfunc main() {}\n" +
+		"0000000000000000000000000000000000000000 4 4 1\n" +
+		"author Not Committed Yet\nauthor-mail <not.committed.yet>\nfilename main.go\n\t// new\n"
+	want := map[string]int{"Pat Doe <pat@example.com>": 2, "Sam Roe <sam@example.com>": 1}
+	if got := parseBlameAuthors([]byte(out)); !maps.Equal(got, want) {
+		t.Errorf("parseBlameAuthors = %v, want %v", got, want)
+	}
+}
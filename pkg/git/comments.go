@@ -0,0 +1,223 @@
+package git
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// genericCommentPattern matches common comment starters across languages. It is
+// used for files whose extension has no language-specific entry.
+var genericCommentPattern = regexp.MustCompile(`^(//|/\*|\*|#|--|<!--|;|"""|'|\(\*).*`)
+
+// defaultCommentPrefixes maps file extensions to the line-comment markers of their language.
+// A leading "*" is not one: it is a dereference or a generator in C-like code, and
+// only a comment inside an open /* ... */ block, which blockComments tracks.
+var defaultCommentPrefixes = map[string][]string{
+	".go":    {"//", "/*"},
+	".c":     {"//", "/*"},
+	".h":     {"//", "/*"},
+	".cpp":   {"//", "/*"},
+	".cs":    {"//", "/*"},
+	".java":  {"//", "/*"},
+	".kt":    {"//", "/*"},
+	".js":    {"//", "/*"},
+	".ts":    {"//", "/*"},
+	".tsx":   {"//", "/*"},
+	".jsx":   {"//", "/*"},
+	".rs":    {"//", "/*"},
+	".swift": {"//", "/*"},
+	".php":   {"//", "/*", "#"},
+	".py":    {"#", `"""`},
+	".rb":    {"#"},
+	".sh":    {"#"},
+	".bash":  {"#"},
+	".yaml":  {"#"},
+	".yml":   {"#"},
+	".toml":  {"#"},
+	".sql":   {"--"},
+	".lua":   {"--"},
+	".hs":    {"--"},
+	".html":  {"<!--"},
+	".xml":   {"<!--"},
+	".clj":   {";"},
+	".el":    {";"},
+	".lisp":  {";"},
+	".ml":    {"(*"},
+}
+
+// docsExtensions are prose formats where '#' and similar markers are content, not comments.
+var docsExtensions = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".rst":      true,
+	".adoc":     true,
+	".txt":      true,
+}
+
+// goExportedDecl matches a Go declaration of an exported identifier.
+var goExportedDecl = regexp.MustCompile(`^(func\s+(\([^)]*\)\s*)?|type\s+|var\s+|const\s+)[A-Z]`)
+
+var commentFilter config.CommentFilterSettings
+
+// ConfigureCommentFilter sets how comment-only changes are removed from diffs.
+func ConfigureCommentFilter(settings config.CommentFilterSettings) {
+	commentFilter = settings
+}
+
+// isDocsPath reports whether a file is documentation (by directory or extension).
+func isDocsPath(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	if docsExtensions[path.Ext(lower)] {
+		return true
+	}
+	for _, dir := range []string{"docs/", "doc/", "documentation/"} {
+		if strings.HasPrefix(lower, dir) || strings.Contains(lower, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// commentPrefixesFor returns the comment markers for a file and whether comment
+// filtering applies to it at all. A nil slice with true means "use the generic pattern".
+func commentPrefixesFor(filePath string) ([]string, bool) {
+	if isDocsPath(filePath) {
+		return nil, false
+	}
+	ext := strings.ToLower(path.Ext(filePath))
+	if prefixes, ok := commentFilter.Languages[ext]; ok {
+		return prefixes, len(prefixes) > 0
+	}
+	if prefixes, ok := defaultCommentPrefixes[ext]; ok {
+		return prefixes, true
+	}
+	return nil, true
+}
+
+// isCommentLine reports whether a +/- diff line only touches a comment using the
+// given markers. inBlock tells that the line starts inside an open /* ... */
+// comment, where it is comment text unless code follows the closing "*/".
+func isCommentLine(line string, prefixes []string, inBlock bool) bool {
+	if prefixes == nil {
+		return isCommentOnlyChange(line)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 || (line[0] != '+' && line[0] != '-') {
+		return false
+	}
+	payload := strings.TrimSpace(line[1:])
+	if inBlock {
+		_, after, closed := strings.Cut(payload, "*/")
+		return !closed || strings.TrimSpace(after) == ""
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(payload, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockComments follows /* ... */ comments across the lines of a hunk, for files
+// whose comment markers include "/*".
+type blockComments struct {
+	enabled bool
+	open    bool
+}
+
+// reset starts a file with the given comment markers, or a hunk of it.
+func (b *blockComments) reset(prefixes []string) {
+	b.enabled = slices.Contains(prefixes, "/*")
+	b.open = false
+}
+
+// next reports whether line starts inside an open block comment and moves past
+// it. Both sides of the diff share the state, which suits the usual hunk where
+// a comment is edited in place.
+func (b *blockComments) next(line string) bool {
+	if !b.enabled || line == "" || !strings.ContainsRune("+- ", rune(line[0])) {
+		return false
+	}
+	inBlock := b.open
+	rest := line[1:]
+	for {
+		marker := "/*"
+		if b.open {
+			marker = "*/"
+		}
+		i := strings.Index(rest, marker)
+		if i < 0 {
+			return inBlock
+		}
+		rest = rest[i+2:]
+		b.open = !b.open
+	}
+}
+
+// isGoDocComment reports whether the comment at lines[i] is part of a doc comment
+// block attached to an exported Go declaration. Those comments describe public API
+// and are kept even when comment filtering is on.
+func isGoDocComment(lines []string, i int) bool {
+	for j := i + 1; j < len(lines); j++ {
+		l := lines[j]
+		if l == "" || strings.HasPrefix(l, "@@") || strings.HasPrefix(l, "diff --git ") {
+			return false
+		}
+		if l[0] == '-' {
+			continue
+		}
+		payload := strings.TrimSpace(l[1:])
+		if strings.HasPrefix(payload, "//") {
+			continue
+		}
+		return goExportedDecl.MatchString(payload)
+	}
+	return false
+}
+
+// FallbackCommitType suggests a commit type for a staged diff whose cleaned
+// version came out empty: "build" for lock-file-only changes, "docs" for
+// documentation or comment-only changes, and "style" for formatting changes.
+func FallbackCommitType(rawDiff string, lockFiles []string) string {
+	lines := strings.Split(rawDiff, "\n")
+	if strings.TrimSpace(FilterLockFiles(rawDiff, lockFiles)) == "" {
+		return "build"
+	}
+	if onlyDocsChanged(lines) {
+		return "docs"
+	}
+
+	var prefixes []string
+	var blocks blockComments
+	sawChange := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			prefixes, _ = commentPrefixesFor(parseFilePath(line))
+			blocks.reset(prefixes)
+			continue
+		}
+		if strings.HasPrefix(line, "@@") {
+			blocks.reset(prefixes)
+			continue
+		}
+		inBlock := blocks.next(line)
+		if line == "" || (line[0] != '+' && line[0] != '-') {
+			continue
+		}
+		if strings.TrimSpace(line[1:]) == "" {
+			continue
+		}
+		sawChange = true
+		if !isCommentLine(line, prefixes, inBlock) {
+			return "style"
+		}
+	}
+	if sawChange {
+		return "docs"
+	}
+	return "style"
+}
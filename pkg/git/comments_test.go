@@ -0,0 +1,169 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func configCommentFilter(disabled bool, languages map[string][]string) config.CommentFilterSettings {
+	return config.CommentFilterSettings{Disabled: disabled, Languages: languages}
+}
+
+func TestIsDocsPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"README.md", true},
+		{"docs/guide.txt", true},
+		{"site/docs/index.html", true},
+		{"doc/api.rst", true},
+		{"pkg/git/git.go", false},
+		{"scripts/install.sh", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			if got := isDocsPath(tt.path); got != tt.want {
+				t.Errorf("isDocsPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCommentLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		line     string
+		prefixes []string
+		inBlock  bool
+		want     bool
+	}{
+		{"go line comment", "+// note", []string{"//"}, false, true},
+		{"python hash with go prefixes", "+# not a go comment", []string{"//"}, false, false},
+		{"generic fallback", "+# shell comment", nil, false, true},
+		{"context line", " // unchanged", []string{"//"}, false, false},
+		{"go dereference", "+\t*p = 1", []string{"//", "/*"}, false, false},
+		{"inside a block comment", "+ * more detail", []string{"//", "/*"}, true, true},
+		{"closing a block comment", "+ */", []string{"//", "/*"}, true, true},
+		{"code after a block comment", "+ */ x := 1", []string{"//", "/*"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isCommentLine(tt.line, tt.prefixes, tt.inBlock); got != tt.want {
+				t.Errorf("isCommentLine(%q, %v, %v) = %v, want %v", tt.line, tt.prefixes, tt.inBlock, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGoDocComment(t *testing.T) {
+	t.Parallel()
+	exported := []string{"+// Foo does things.", "+// More detail.", "+func Foo() {}"}
+	if !isGoDocComment(exported, 0) {
+		t.Error("expected doc comment on exported func to be detected")
+	}
+	unexported := []string{"+// helper", "+func helper() {}"}
+	if isGoDocComment(unexported, 0) {
+		t.Error("expected comment on unexported func not to count as doc comment")
+	}
+	inline := []string{"+// just a note", "+x := 1"}
+	if isGoDocComment(inline, 0) {
+		t.Error("expected inline comment not to count as doc comment")
+	}
+}
+
+// Tests below mutate the package-level comment filter and must not run in parallel.
+
+func TestCleanupDiff_KeepsGoDocComments(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/api.go b/api.go\n@@ -1,2 +1,4 @@\n+// Open opens a session.\n+func Open() {}\n+// internal note\n+x := 1"
+	got := cleanupDiff(diff)
+	if !strings.Contains(got, "Open opens a session") {
+		t.Errorf("expected exported doc comment to be kept, got:\n%s", got)
+	}
+	if strings.Contains(got, "internal note") {
+		t.Errorf("expected plain comment to be dropped, got:\n%s", got)
+	}
+}
+
+func TestCleanupDiff_BlockComments(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/ptr.go b/ptr.go\n@@ -1,2 +1,6 @@\n+/*\n+ * internal note\n+ */\n+\t*p = 1\n+\t*q++"
+	got := cleanupDiff(diff)
+	if !strings.Contains(got, "+\t*p = 1") || !strings.Contains(got, "+\t*q++") {
+		t.Errorf("expected pointer dereferences to be kept, got:\n%s", got)
+	}
+	if strings.Contains(got, "internal note") {
+		t.Errorf("expected the block comment to be dropped, got:\n%s", got)
+	}
+}
+
+func TestCleanupDiff_DocsOnlyChangeUntouched(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/docs/setup.sh b/docs/setup.sh\n@@ -1 +1,2 @@\n+# install deps first"
+	if got := cleanupDiff(diff); got != diff {
+		t.Errorf("expected docs-only diff to be unchanged, got:\n%s", got)
+	}
+}
+
+func TestCleanupDiff_Disabled(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(true, nil))
+	defer ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/main.go b/main.go\n@@ -1 +1,2 @@\n+// a comment"
+	if got := cleanupDiff(diff); !strings.Contains(got, "a comment") {
+		t.Errorf("expected comments to be kept when filtering is disabled, got:\n%s", got)
+	}
+}
+
+func TestCleanupDiff_LanguageOverride(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(false, map[string][]string{".go": {}}))
+	defer ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/main.go b/main.go\n@@ -1 +1,2 @@\n+// a comment"
+	if got := cleanupDiff(diff); !strings.Contains(got, "a comment") {
+		t.Errorf("expected empty override to disable filtering for .go, got:\n%s", got)
+	}
+}
+
+func TestFallbackCommitType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{
+			name: "lock files only",
+			diff: "diff --git a/go.sum b/go.sum\n@@ -1 +1 @@\n-a\n+b",
+			want: "build",
+		},
+		{
+			name: "docs only",
+			diff: "diff --git a/README.md b/README.md\n@@ -1 +1 @@\n-a\n+b",
+			want: "docs",
+		},
+		{
+			name: "comment only in code",
+			diff: "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n-// old\n+// new",
+			want: "docs",
+		},
+		{
+			name: "formatting change",
+			diff: "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n-x:=1\n+x := 1",
+			want: "style",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FallbackCommitType(tt.diff, []string{"go.sum"}); got != tt.want {
+				t.Errorf("FallbackCommitType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
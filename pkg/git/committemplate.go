@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// templateTrailer matches a trailer line of a commit template, whose value may
+// still be empty ("Reviewed-by:").
+var templateTrailer = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*:(\s|$)`)
+
+// CommitTemplate is the content of the file git's commit.template points to.
+type CommitTemplate struct {
+	// Skeleton is the template without comments and trailers: the structure
+	// the message body should follow.
+	Skeleton string
+	// Footers are the trailers of the template that have a value, such as
+	// "Refs: PROJ-1"; placeholders like "Reviewed-by:" are dropped.
+	Footers []string
+}
+
+// LoadCommitTemplate reads the commit.template configured for the repository
+// (or globally). It returns the zero CommitTemplate when none is set.
+func LoadCommitTemplate(ctx context.Context) (CommitTemplate, error) {
+	out, err := exec.CommandContext(ctx, "git", "config", "--path", "commit.template").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return CommitTemplate{}, nil // not set
+		}
+		return CommitTemplate{}, fmt.Errorf("failed to read commit.template: %w", err)
+	}
+	path := strings.TrimSpace(string(out))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CommitTemplate{}, fmt.Errorf("failed to read commit template %s: %w", path, err)
+	}
+	return ParseCommitTemplate(string(data)), nil
+}
+
+// ParseCommitTemplate splits a commit template into its skeleton and footers.
+// Lines starting with "#" are comments, as in git; the last paragraph is the
+// footer block when every line of it is a trailer.
+func ParseCommitTemplate(text string) CommitTemplate {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	start := len(lines)
+	for start > 0 && lines[start-1] != "" {
+		start--
+	}
+
+	var t CommitTemplate
+	footer := start < len(lines)
+	for _, line := range lines[start:] {
+		footer = footer && templateTrailer.MatchString(line)
+	}
+	if footer {
+		for _, line := range lines[start:] {
+			if trailerLine.MatchString(line) {
+				t.Footers = append(t.Footers, line)
+			}
+		}
+		lines = lines[:start]
+	}
+	t.Skeleton = strings.TrimSpace(strings.Join(lines, "\n"))
+	return t
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommitTemplate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		text string
+		want CommitTemplate
+	}{
+		{
+			name: "skeleton and footers",
+			text: "# Subject: imperative, max 50 chars\n\nWhy:\n\nWhat:\n\n# Trailers\nRefs: PROJ-1\nReviewed-by:\n",
+			want: CommitTemplate{Skeleton: "Why:\n\nWhat:", Footers: []string{"Refs: PROJ-1"}},
+		},
+		{
+			name: "footers only",
+			text: "Team: payments\r\nCo-authored-by: Pat <pat@example.com>\r\n",
+			want: CommitTemplate{Footers: []string{"Team: payments", "Co-authored-by: Pat <pat@example.com>"}},
+		},
+		{
+			name: "last paragraph with prose is skeleton",
+			text: "Summary of the change.\n\nTesting: describe how you tested it\nand what you saw.",
+			want: CommitTemplate{Skeleton: "Summary of the change.\n\nTesting: describe how you tested it\nand what you saw."},
+		},
+		{
+			name: "comments only",
+			text: "# Write a good message\n#\n",
+			want: CommitTemplate{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseCommitTemplate(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommitTemplate() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// draftFile is where SaveDraft keeps a message, relative to the git directory.
+const draftFile = "ai-commit/DRAFT_MSG"
+
+// draftHeader starts a saved draft and records the HEAD it was written on.
+const draftHeader = "# ai-commit draft on "
+
+// autosaveFile is where Autosave keeps the message being worked on, relative to
+// the git directory.
+const autosaveFile = "ai-commit/AUTOSAVE_MSG"
+
+// autosaveHeader starts an autosaved message and records the DiffHash of the
+// staged diff it was written for.
+const autosaveHeader = "# ai-commit autosave for diff "
+
+// Draft is a commit message left over from an earlier attempt.
+type Draft struct {
+	// Source is where the draft came from: "MERGE_MSG", "autosaved", or "saved".
+	Source  string
+	Message string
+}
+
+// DiffHash identifies a staged diff, so an autosaved message is only restored
+// for the changes it describes.
+func DiffHash(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadDraft returns, in this order, the message of a merge, cherry-pick, or
+// revert in progress (MERGE_MSG), the message Autosave kept for the staged diff
+// with hash diffHash, or the draft SaveDraft kept from a commit that did not
+// happen. A saved draft is ignored once HEAD has moved, since its commit was then
+// most likely made after all. ok is false when there is no draft.
+func LoadDraft(ctx context.Context, diffHash string) (Draft, bool, error) {
+	mergeMsg, err := gitPath(ctx, "MERGE_MSG")
+	if err != nil {
+		return Draft{}, false, err
+	}
+	if data, err := os.ReadFile(mergeMsg); err == nil {
+		if msg := stripCommentLines(string(data)); msg != "" {
+			return Draft{Source: "MERGE_MSG", Message: msg}, true, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return Draft{}, false, fmt.Errorf("failed to read MERGE_MSG: %w", err)
+	}
+
+	if diffHash != "" {
+		msg, err := readStateMessage(ctx, autosaveFile, autosaveHeader+diffHash)
+		if err != nil {
+			return Draft{}, false, err
+		} else if msg != "" {
+			return Draft{Source: "autosaved", Message: msg}, true, nil
+		}
+	}
+
+	head, _ := GetHeadCommitHash(ctx)
+	msg, err := readStateMessage(ctx, draftFile, draftHeader+head)
+	if err != nil || msg == "" {
+		return Draft{}, false, err
+	}
+	return Draft{Source: "saved", Message: msg}, true, nil
+}
+
+// readStateMessage returns the message in the state file name, or "" when the
+// file is missing or its first line is not header.
+func readStateMessage(ctx context.Context, name, header string) (string, error) {
+	path, err := gitPath(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	text := string(data)
+	if firstLine, _, _ := strings.Cut(text, "\n"); strings.TrimSpace(firstLine) != strings.TrimSpace(header) {
+		return "", nil
+	}
+	return stripCommentLines(text), nil
+}
+
+// writeStateMessage replaces the state file name with header and message. It
+// writes a temporary file and renames it, so a crash never leaves half a message.
+func writeStateMessage(ctx context.Context, name, header, message string) error {
+	path, err := gitPath(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create the draft directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(header+"\n"+strings.TrimSpace(message)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to save the draft: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save the draft: %w", err)
+	}
+	return nil
+}
+
+// SaveDraft keeps message so the next run can offer it again, e.g. after the
+// commit failed or a commit-msg hook rejected it.
+func SaveDraft(ctx context.Context, message string) error {
+	head, _ := GetHeadCommitHash(ctx) // empty before the first commit
+	return writeStateMessage(ctx, draftFile, draftHeader+head, message)
+}
+
+// Autosave keeps message, being written for the staged diff with hash diffHash,
+// so a run after a crash or an accidental quit can restore it.
+func Autosave(ctx context.Context, diffHash, message string) error {
+	return writeStateMessage(ctx, autosaveFile, autosaveHeader+diffHash, message)
+}
+
+// ClearDraft removes the drafts kept by SaveDraft and Autosave, if any.
+func ClearDraft(ctx context.Context) error {
+	for _, name := range []string{draftFile, autosaveFile} {
+		path, err := gitPath(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove the saved draft: %w", err)
+		}
+	}
+	return nil
+}
+
+// StatePath resolves name inside ai-commit's state directory, <git-dir>/ai-commit,
+// where per-repository data such as drafts and caches is kept.
+func StatePath(ctx context.Context, name string) (string, error) {
+	return gitPath(ctx, filepath.Join("ai-commit", name))
+}
+
+// gitPath resolves name inside the git directory, as `git rev-parse --git-path`
+// does (so linked worktrees get their own MERGE_MSG).
+func gitPath(ctx context.Context, name string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNotARepo, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// stripCommentLines drops the "#" comment lines git adds to message files and
+// trims the rest.
+func stripCommentLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestDraft_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	ctx := context.Background()
+
+	if _, ok, err := LoadDraft(ctx, ""); err != nil || ok {
+		t.Fatalf("LoadDraft() with no draft = %v, %v", ok, err)
+	}
+
+	if err := SaveDraft(ctx, "feat: add login\n\nRefs: PROJ-1\n"); err != nil {
+		t.Fatal(err)
+	}
+	draft, ok, err := LoadDraft(ctx, "")
+	if err != nil || !ok {
+		t.Fatalf("LoadDraft() = %v, %v", ok, err)
+	}
+	if want := (Draft{Source: "saved", Message: "feat: add login\n\nRefs: PROJ-1"}); draft != want {
+		t.Errorf("LoadDraft() = %#v, want %#v", draft, want)
+	}
+
+	// MERGE_MSG takes precedence, without git's comment lines.
+	mergeMsg := filepath.Join(dir, ".git", "MERGE_MSG")
+	if err := os.WriteFile(mergeMsg, []byte("Merge branch 'topic'\n\n# Conflicts:\n#\tmain.go\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if draft, _, _ := LoadDraft(ctx, ""); draft != (Draft{Source: "MERGE_MSG", Message: "Merge branch 'topic'"}) {
+		t.Errorf("LoadDraft() with MERGE_MSG = %#v", draft)
+	}
+	if err := os.Remove(mergeMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once HEAD moves, the saved draft is stale.
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit("feat: add login", &gogit.CommitOptions{Author: sig, AllowEmptyCommits: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := LoadDraft(ctx, ""); err != nil || ok {
+		t.Errorf("LoadDraft() after a new commit = %v, %v; want no draft", ok, err)
+	}
+
+	if err := SaveDraft(ctx, "fix: typo"); err != nil {
+		t.Fatal(err)
+	}
+	// An autosave is restored for the staged diff it was written for only.
+	hash := DiffHash("diff --git a/main.go b/main.go\n")
+	if err := Autosave(ctx, hash, "feat: add main\n\nEdited by hand."); err != nil {
+		t.Fatal(err)
+	}
+	if draft, _, _ := LoadDraft(ctx, hash); draft != (Draft{Source: "autosaved", Message: "feat: add main\n\nEdited by hand."}) {
+		t.Errorf("LoadDraft(same diff) = %#v", draft)
+	}
+	if draft, _, _ := LoadDraft(ctx, DiffHash("other")); draft != (Draft{Source: "saved", Message: "fix: typo"}) {
+		t.Errorf("LoadDraft(other diff) = %#v, want the saved draft", draft)
+	}
+
+	if err := ClearDraft(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := LoadDraft(ctx, hash); ok {
+		t.Error("LoadDraft() after ClearDraft() found a draft")
+	}
+	if err := ClearDraft(ctx); err != nil {
+		t.Errorf("ClearDraft() without a draft = %v", err)
+	}
+}
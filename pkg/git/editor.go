@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// editFile is the message file EditMessage opens, in the state directory.
+const editFile = "EDITMSG"
+
+// Editor returns the editor git uses for commit messages, resolved from
+// GIT_EDITOR, core.editor, VISUAL, and EDITOR as `git var GIT_EDITOR` does.
+func Editor(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "var", "GIT_EDITOR").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine the editor: %w", err)
+	}
+	editor := strings.TrimSpace(string(out))
+	if editor == "" {
+		return "", fmt.Errorf("no editor configured; set GIT_EDITOR, core.editor, VISUAL, or EDITOR")
+	}
+	return editor, nil
+}
+
+// EditMessage opens message in the commit message editor, below it the usual
+// instructions as comment lines, and returns the edited message cleaned the
+// way "git commit" cleans it. An empty result means the user aborted.
+func EditMessage(ctx context.Context, message string) (string, error) {
+	editor, err := Editor(ctx)
+	if err != nil {
+		return "", err
+	}
+	path, err := StatePath(ctx, editFile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write the message file: %w", err)
+	}
+	defer os.Remove(path)
+
+	c := CommentChar(ctx)
+	text := strings.TrimSpace(message) + "\n\n" +
+		c + " Please edit the commit message for your changes. Lines starting\n" +
+		c + " with '" + c + "' will be ignored, and an empty message aborts the commit.\n"
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write the message file: %w", err)
+	}
+
+	// Like git, run the editor through the shell so it may carry arguments,
+	// e.g. "code --wait".
+	cmd := exec.CommandContext(ctx, "sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("the editor %q failed: %w", editor, err)
+	}
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the edited message: %w", err)
+	}
+	return CleanMessage(string(edited), c), nil
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestEditMessage_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	ctx := context.Background()
+
+	t.Setenv("GIT_EDITOR", `sed -i.bak 's/add login/add the login page/'`)
+	got, err := EditMessage(ctx, "feat: add login\n\nAdds the endpoint.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "feat: add the login page\n\nAdds the endpoint."; got != want {
+		t.Errorf("EditMessage = %q, want %q", got, want)
+	}
+
+	// Deleting every line aborts.
+	t.Setenv("GIT_EDITOR", "sed -i.bak d")
+	if got, err := EditMessage(ctx, "feat: add login"); err != nil || got != "" {
+		t.Errorf("EditMessage with an emptied file = %q, %v, want empty", got, err)
+	}
+
+	t.Setenv("GIT_EDITOR", "false")
+	if _, err := EditMessage(ctx, "feat: add login"); err == nil {
+		t.Error("EditMessage with a failing editor succeeded")
+	}
+}
@@ -0,0 +1,10 @@
+package git
+
+import "errors"
+
+var (
+	// ErrNotARepo marks operations run outside a Git repository.
+	ErrNotARepo = errors.New("not a git repository")
+	// ErrNoStagedChanges marks commits refused because nothing is staged.
+	ErrNoStagedChanges = errors.New("no staged changes")
+)
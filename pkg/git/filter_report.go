@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FilterReason explains why part of the staged diff was left out of the prompt.
+type FilterReason string
+
+const (
+	FilterLockFile  FilterReason = "lock file"
+	FilterComment   FilterReason = "comment-only lines"
+	FilterMoved     FilterReason = "moved lines"
+	FilterBinary    FilterReason = "binary file"
+	FilterTruncated FilterReason = "truncated"
+	FilterGenerated FilterReason = "generated file"
+	FilterNoDiff    FilterReason = "-diff file"
+	FilterPrivate   FilterReason = "never-send path"
+)
+
+// FilteredItem is one file (and reason) whose content was excluded from the prompt.
+// Lines counts the dropped +/- lines; it is zero when the whole file was skipped.
+type FilteredItem struct {
+	Path   string
+	Reason FilterReason
+	Lines  int
+}
+
+// FilterReport collects everything removed between the staged diff and the prompt.
+// A nil report is valid and ignores all additions.
+type FilterReport struct {
+	Items []FilteredItem
+}
+
+func (r *FilterReport) add(path string, reason FilterReason, lines int) {
+	if r == nil {
+		return
+	}
+	for i := range r.Items {
+		if r.Items[i].Path == path && r.Items[i].Reason == reason {
+			r.Items[i].Lines += lines
+			return
+		}
+	}
+	r.Items = append(r.Items, FilteredItem{Path: path, Reason: reason, Lines: lines})
+}
+
+// AddTruncation records that the diff was summarized or cut to fit the size limits.
+func (r *FilterReport) AddTruncation(originalChars, keptChars int) {
+	if r == nil {
+		return
+	}
+	r.Items = append(r.Items, FilteredItem{
+		Path:   fmt.Sprintf("(whole diff: %d of %d chars kept)", keptChars, originalChars),
+		Reason: FilterTruncated,
+	})
+}
+
+// Empty reports whether nothing was filtered.
+func (r *FilterReport) Empty() bool {
+	return r == nil || len(r.Items) == 0
+}
+
+// String renders the report grouped by reason, one file per line.
+func (r *FilterReport) String() string {
+	if r.Empty() {
+		return "Nothing was filtered from the prompt."
+	}
+	byReason := make(map[FilterReason][]FilteredItem)
+	var reasons []FilterReason
+	for _, it := range r.Items {
+		if _, ok := byReason[it.Reason]; !ok {
+			reasons = append(reasons, it.Reason)
+		}
+		byReason[it.Reason] = append(byReason[it.Reason], it)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return reasons[i] < reasons[j] })
+
+	var b strings.Builder
+	for _, reason := range reasons {
+		b.WriteString(fmt.Sprintf("%s:\n", reason))
+		for _, it := range byReason[reason] {
+			if it.Lines > 0 {
+				b.WriteString(fmt.Sprintf("  - %s (%d lines)\n", it.Path, it.Lines))
+			} else {
+				b.WriteString(fmt.Sprintf("  - %s\n", it.Path))
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// isChangeLine reports whether a diff line is an added or removed line (not a file header).
+func isChangeLine(line string) bool {
+	if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+		return false
+	}
+	return strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")
+}
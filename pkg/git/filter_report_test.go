@@ -0,0 +1,59 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterReport_NilIsEmpty(t *testing.T) {
+	t.Parallel()
+	var r *FilterReport
+	r.add("main.go", FilterComment, 1)
+	r.AddTruncation(100, 10)
+	if !r.Empty() {
+		t.Fatal("expected nil report to stay empty")
+	}
+	if got := r.String(); !strings.Contains(got, "Nothing was filtered") {
+		t.Errorf("unexpected nil report string: %q", got)
+	}
+}
+
+func TestFilterReport_MergesLinesPerFileAndReason(t *testing.T) {
+	t.Parallel()
+	r := &FilterReport{}
+	r.add("main.go", FilterComment, 1)
+	r.add("main.go", FilterComment, 2)
+	r.add("logo.png", FilterBinary, 0)
+	if len(r.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d: %+v", len(r.Items), r.Items)
+	}
+	got := r.String()
+	for _, want := range []string{"comment-only lines:", "main.go (3 lines)", "binary file:", "  - logo.png\n"} {
+		if !strings.Contains(got+"\n", want) {
+			t.Errorf("expected %q in report:\n%s", want, got)
+		}
+	}
+}
+
+func TestFilterLockFiles_Report(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/go.sum b/go.sum\n@@ -1 +1 @@\n-a v1\n+a v2\ndiff --git a/main.go b/main.go\n@@ -1 +1 @@\n+x := 1"
+	r := &FilterReport{}
+	got := filterLockFiles(diff, []string{"go.sum"}, r)
+	if strings.Contains(got, "go.sum") {
+		t.Errorf("expected go.sum section to be dropped, got:\n%s", got)
+	}
+	if len(r.Items) != 1 || r.Items[0].Path != "go.sum" || r.Items[0].Reason != FilterLockFile || r.Items[0].Lines != 2 {
+		t.Errorf("unexpected report items: %+v", r.Items)
+	}
+}
+
+func TestCleanupDiff_ReportsComments(t *testing.T) {
+	ConfigureCommentFilter(configCommentFilter(false, nil))
+	diff := "diff --git a/main.go b/main.go\n@@ -1 +1,3 @@\n+// note one\n+// note two\n+x := 1"
+	r := &FilterReport{}
+	cleanupDiffWithReport(diff, r)
+	if len(r.Items) != 1 || r.Items[0].Path != "main.go" || r.Items[0].Reason != FilterComment || r.Items[0].Lines != 2 {
+		t.Errorf("unexpected report items: %+v", r.Items)
+	}
+}
@@ -0,0 +1,54 @@
+package git
+
+import "strings"
+
+// Fixup kinds, named after the git commit options that create them.
+const (
+	// FixupKind marks a commit whose changes are folded into its target by
+	// `git rebase --autosquash`, dropping its message.
+	FixupKind = "fixup"
+	// SquashKind marks a commit that is folded in the same way, adding its
+	// message body to the target's.
+	SquashKind = "squash"
+)
+
+// Files lists the paths the commit's patch touches.
+func (c CommitInfo) Files() []string {
+	return DiffPaths(c.Diff)
+}
+
+// FixupMessage returns the message of a fixup or squash commit for target, as
+// `git commit --fixup`/`--squash` write it. body is kept for squash commits
+// only, since autosquash discards the message of a fixup.
+func FixupMessage(kind string, target CommitInfo, body string) string {
+	msg := kind + "! " + target.Subject
+	if body = strings.TrimSpace(body); kind == SquashKind && body != "" {
+		msg += "\n\n" + body
+	}
+	return msg
+}
+
+// MatchFixupTarget finds the candidate whose hash the first line of reply
+// names, by a prefix of at least 7 hex digits, and returns its index.
+func MatchFixupTarget(reply string, candidates []CommitInfo) (int, bool) {
+	first, _, _ := strings.Cut(strings.TrimSpace(reply), "\n")
+	for _, word := range strings.Fields(first) {
+		word = strings.ToLower(strings.Trim(word, "`*.,:;()[]\"'"))
+		if len(word) < 7 || strings.Trim(word, "0123456789abcdef") != "" {
+			continue
+		}
+		for i, c := range candidates {
+			if strings.HasPrefix(c.Hash, word) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FixupDescription returns what follows the first line of reply: the model's
+// description of the staged changes.
+func FixupDescription(reply string) string {
+	_, rest, _ := strings.Cut(strings.TrimSpace(reply), "\n")
+	return strings.TrimSpace(rest)
+}
@@ -0,0 +1,46 @@
+package git
+
+import "testing"
+
+func TestFixupMessage(t *testing.T) {
+	t.Parallel()
+	target := CommitInfo{Subject: "feat: add login"}
+	if got := FixupMessage(FixupKind, target, "ignored"); got != "fixup! feat: add login" {
+		t.Errorf("fixup = %q", got)
+	}
+	if got := FixupMessage(SquashKind, target, "Handle expired tokens.\n"); got != "squash! feat: add login\n\nHandle expired tokens." {
+		t.Errorf("squash = %q", got)
+	}
+	if got := FixupMessage(SquashKind, target, ""); got != "squash! feat: add login" {
+		t.Errorf("squash without body = %q", got)
+	}
+}
+
+func TestMatchFixupTarget(t *testing.T) {
+	t.Parallel()
+	candidates := []CommitInfo{
+		{Hash: "abc1234def5678abc1234def5678abc1234def56"},
+		{Hash: "0123456789abcdef0123456789abcdef01234567"},
+	}
+	tests := []struct {
+		reply string
+		want  int
+		ok    bool
+	}{
+		{"0123456\nAdds the missing test.", 1, true},
+		{"`ABC1234DEF`", 0, true},
+		{"The target is 0123456789ab.", 1, true},
+		{"NONE", 0, false},
+		{"abc12\n0123456", 0, false},
+		{"fffffff", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := MatchFixupTarget(tt.reply, candidates)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MatchFixupTarget(%q) = %d, %v; want %d, %v", tt.reply, got, ok, tt.want, tt.ok)
+		}
+	}
+	if got := FixupDescription("0123456\n\nAdds the missing test.\n"); got != "Adds the missing test." {
+		t.Errorf("FixupDescription = %q", got)
+	}
+}
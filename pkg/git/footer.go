@@ -0,0 +1,170 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// footerKey matches a trailer key that git interpret-trailers accepts.
+var footerKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// breakingChange is the Conventional Commits footer key; unlike git trailer
+// keys it contains a space.
+const breakingChange = "BREAKING CHANGE"
+
+// wellKnownFooters maps lowercase footer keys to their usual spelling. single
+// marks footers a message carries at most once, which SetFooter replaces.
+var wellKnownFooters = map[string]struct {
+	key    string
+	single bool
+}{
+	"refs":            {"Refs", true},
+	"reviewed-by":     {"Reviewed-by", false},
+	"co-authored-by":  {"Co-authored-by", false},
+	"breaking change": {breakingChange, true},
+	"breaking-change": {breakingChange, true},
+}
+
+// Footer is a trailer at the end of a commit message, such as "Refs: PROJ-1".
+type Footer struct {
+	Key   string
+	Value string
+}
+
+// ParseFooter parses a footer written as "key=value" (the --footer flag) or
+// "Key: value", normalizing it like NewFooter.
+func ParseFooter(s string) (Footer, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || strings.Contains(key, ":") {
+		key, value, ok = strings.Cut(s, ":")
+	}
+	if !ok {
+		return Footer{}, fmt.Errorf("invalid footer %q: want key=value", s)
+	}
+	return NewFooter(key, value)
+}
+
+// NewFooter validates a footer. Well-known keys get their usual spelling
+// ("reviewed-by" becomes "Reviewed-by", "breaking-change" becomes "BREAKING
+// CHANGE"), and the lines of a multi-line value are trimmed.
+func NewFooter(key, value string) (Footer, error) {
+	key = canonicalFooterKey(strings.TrimSpace(key))
+	if key != breakingChange && !footerKey.MatchString(key) {
+		return Footer{}, fmt.Errorf("invalid footer key %q: use letters, digits, and dashes", key)
+	}
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return Footer{}, fmt.Errorf("footer %s has no value", key)
+	}
+	return Footer{Key: key, Value: strings.Join(lines, "\n")}, nil
+}
+
+func canonicalFooterKey(key string) string {
+	if known, ok := wellKnownFooters[strings.ToLower(key)]; ok {
+		return known.key
+	}
+	return key
+}
+
+// String formats f as a trailer line. Continuation lines of a multi-line value
+// are indented, as git interpret-trailers expects.
+func (f Footer) String() string {
+	return f.Key + ": " + strings.ReplaceAll(f.Value, "\n", "\n ")
+}
+
+// MessageFooters returns the footers in the trailer block of message.
+func MessageFooters(message string) []Footer {
+	_, footers := splitFooters(message)
+	return footers
+}
+
+// AddFooter adds f to the trailer block of message unless a footer with the
+// same key (compared case-insensitively, as git does) and value is there.
+func AddFooter(message string, f Footer) string {
+	body, footers := splitFooters(message)
+	for _, existing := range footers {
+		if sameFooterKey(existing.Key, f.Key) && existing.Value == f.Value {
+			return joinFooters(body, footers)
+		}
+	}
+	return joinFooters(body, append(footers, f))
+}
+
+// ReplaceFooter removes every footer with the key of f from message and adds f
+// at the end of the trailer block.
+func ReplaceFooter(message string, f Footer) string {
+	body, footers := splitFooters(message)
+	kept := footers[:0]
+	for _, existing := range footers {
+		if !sameFooterKey(existing.Key, f.Key) {
+			kept = append(kept, existing)
+		}
+	}
+	return joinFooters(body, append(kept, f))
+}
+
+// SetFooter replaces footers a message carries once (Refs, BREAKING CHANGE) and
+// adds the others (Reviewed-by, Co-authored-by, ...) with AddFooter, so applying
+// the same footer twice leaves the message unchanged.
+func SetFooter(message string, f Footer) string {
+	if known, ok := wellKnownFooters[strings.ToLower(f.Key)]; ok && known.single {
+		return ReplaceFooter(message, f)
+	}
+	return AddFooter(message, f)
+}
+
+// ApplyFooters sets each footer on message with SetFooter.
+func ApplyFooters(message string, footers []Footer) string {
+	for _, f := range footers {
+		message = SetFooter(message, f)
+	}
+	return message
+}
+
+func sameFooterKey(a, b string) bool {
+	return strings.EqualFold(canonicalFooterKey(a), canonicalFooterKey(b))
+}
+
+// splitFooters splits message into the text before its trailer block and the
+// footers in that block. A single-paragraph message has no trailer block.
+func splitFooters(message string) (string, []Footer) {
+	message = strings.TrimSpace(message)
+	lines := strings.Split(message, "\n")
+	if !endsWithTrailerBlock(lines) {
+		return message, nil
+	}
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	var footers []Footer
+	for _, line := range lines[start:] {
+		if line[0] == ' ' || line[0] == '\t' {
+			footers[len(footers)-1].Value += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		key, value, _ := strings.Cut(line, ":")
+		footers = append(footers, Footer{Key: key, Value: strings.TrimSpace(value)})
+	}
+	return strings.TrimSpace(strings.Join(lines[:start], "\n")), footers
+}
+
+func joinFooters(body string, footers []Footer) string {
+	if len(footers) == 0 {
+		return body
+	}
+	lines := make([]string, len(footers))
+	for i, f := range footers {
+		lines[i] = f.String()
+	}
+	if body == "" {
+		return strings.Join(lines, "\n")
+	}
+	return body + "\n\n" + strings.Join(lines, "\n")
+}
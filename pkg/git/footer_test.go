@@ -0,0 +1,104 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFooter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    Footer
+		wantErr bool
+	}{
+		{in: "refs=PROJ-1", want: Footer{Key: "Refs", Value: "PROJ-1"}},
+		{in: "Co-Authored-By=Pat <pat@example.com>", want: Footer{Key: "Co-authored-by", Value: "Pat <pat@example.com>"}},
+		{in: "breaking-change=drops the v1 API", want: Footer{Key: "BREAKING CHANGE", Value: "drops the v1 API"}},
+		{in: "Reviewed-by: Sam <sam@example.com>", want: Footer{Key: "Reviewed-by", Value: "Sam <sam@example.com>"}},
+		{in: "See-also: a=b", want: Footer{Key: "See-also", Value: "a=b"}},
+		{in: "Note=first\n\n  second  ", want: Footer{Key: "Note", Value: "first\nsecond"}},
+		{in: "Refs", wantErr: true},
+		{in: "Refs=  ", wantErr: true},
+		{in: "Fixed in=PROJ-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFooter(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFooter(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFooter(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetFooter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		msg    string
+		footer Footer
+		want   string
+	}{
+		{
+			name:   "starts a trailer block",
+			msg:    "feat: add login",
+			footer: Footer{Key: "Refs", Value: "PROJ-1"},
+			want:   "feat: add login\n\nRefs: PROJ-1",
+		},
+		{
+			name:   "replaces a single-valued footer",
+			msg:    "feat: add login\n\nBody.\n\nrefs: PROJ-1\nSigned-off-by: Jane <jane@example.com>",
+			footer: Footer{Key: "Refs", Value: "PROJ-2"},
+			want:   "feat: add login\n\nBody.\n\nSigned-off-by: Jane <jane@example.com>\nRefs: PROJ-2",
+		},
+		{
+			name:   "adds another reviewer",
+			msg:    "fix: typo\n\nReviewed-by: Sam <sam@example.com>",
+			footer: Footer{Key: "Reviewed-by", Value: "Lee <lee@example.com>"},
+			want:   "fix: typo\n\nReviewed-by: Sam <sam@example.com>\nReviewed-by: Lee <lee@example.com>",
+		},
+		{
+			name:   "same reviewer is idempotent",
+			msg:    "fix: typo\n\nreviewed-by: Sam <sam@example.com>\n",
+			footer: Footer{Key: "Reviewed-by", Value: "Sam <sam@example.com>"},
+			want:   "fix: typo\n\nreviewed-by: Sam <sam@example.com>",
+		},
+		{
+			name:   "multi-line breaking change is indented",
+			msg:    "feat!: new config\n\nBREAKING-CHANGE: old\n  text\nRefs: PROJ-1",
+			footer: Footer{Key: "BREAKING CHANGE", Value: "config moved\nto config.yaml"},
+			want:   "feat!: new config\n\nRefs: PROJ-1\nBREAKING CHANGE: config moved\n to config.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SetFooter(tt.msg, tt.footer)
+			if got != tt.want {
+				t.Errorf("SetFooter() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if again := SetFooter(got, tt.footer); again != got {
+				t.Errorf("SetFooter() is not idempotent:\n%q\nthen\n%q", got, again)
+			}
+		})
+	}
+}
+
+func TestMessageFooters(t *testing.T) {
+	t.Parallel()
+	msg := "feat: add login\n\nRefs: PROJ-1\nBREAKING CHANGE: sessions\n expire sooner"
+	want := []Footer{{Key: "Refs", Value: "PROJ-1"}, {Key: "BREAKING CHANGE", Value: "sessions\nexpire sooner"}}
+	if got := MessageFooters(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("MessageFooters() = %#v, want %#v", got, want)
+	}
+	if got := MessageFooters("fix: handle note: empty input"); got != nil {
+		t.Errorf("MessageFooters(subject only) = %#v, want nil", got)
+	}
+	// Signoff joins a block that ends in a breaking change footer.
+	if got, want := AppendSignoff(msg, "Jane", "jane@example.com"), msg+"\nSigned-off-by: Jane <jane@example.com>"; got != want {
+		t.Errorf("AppendSignoff() =\n%q\nwant\n%q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// changeIDLine matches a Gerrit "Change-Id: I<sha1>" trailer.
+var changeIDLine = regexp.MustCompile(`(?m)^Change-Id: (I[0-9a-f]{40})\s*$`)
+
+// ChangeID derives a Gerrit Change-Id the way Gerrit's commit-msg hook does: "I"
+// followed by the blob hash of the committer ident ("Name <email> <unix> <+hhmm>"),
+// the parent commit hash (empty for a root commit), and the message.
+func ChangeID(committerIdent, parent, message string) string {
+	content := committerIdent + "\n" + parent + "\n" + strings.TrimSpace(message) + "\n"
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write([]byte(content))
+	return "I" + hex.EncodeToString(h.Sum(nil))
+}
+
+// FindChangeID returns the Change-Id of message, or "" when it has none.
+func FindChangeID(message string) string {
+	if m := changeIDLine.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// AppendChangeID adds a Change-Id trailer for a commit on top of HEAD (or
+// replacing it, with opts.Amend), keeping an existing one so amended and
+// reworded commits stay attached to their change.
+func AppendChangeID(message string, opts CommitOptions) (string, error) {
+	if FindChangeID(message) != "" {
+		return message, nil
+	}
+	_, committer, err := commitSignatures(opts, time.Now())
+	if err != nil {
+		return "", err
+	}
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	var amended *object.Commit
+	if opts.Amend {
+		if amended, err = amendableHead(repo); err != nil {
+			return "", err
+		}
+	}
+	return addChangeID(repo, message, committer, amended), nil
+}
+
+// addChangeID appends the Change-Id of a commit on top of HEAD or, when
+// amending, keeps the Change-Id of the amended commit so it stays attached to
+// its change.
+func addChangeID(repo *gogit.Repository, message string, committer *object.Signature, amended *object.Commit) string {
+	if FindChangeID(message) != "" {
+		return message
+	}
+	var parent string
+	switch {
+	case amended != nil && FindChangeID(amended.Message) != "":
+		return AppendTrailer(message, "Change-Id: "+FindChangeID(amended.Message))
+	case amended != nil:
+		if amended.NumParents() > 0 {
+			parent = amended.ParentHashes[0].String()
+		}
+	default:
+		if head, err := repo.Head(); err == nil {
+			parent = head.Hash().String()
+		}
+	}
+	return appendChangeID(message, committer, parent)
+}
+
+func appendChangeID(message string, committer *object.Signature, parent string) string {
+	if FindChangeID(message) != "" {
+		return message
+	}
+	ident := fmt.Sprintf("%s <%s> %d %s", committer.Name, committer.Email, committer.When.Unix(), committer.When.Format("-0700"))
+	return AppendTrailer(message, "Change-Id: "+ChangeID(ident, parent, message))
+}
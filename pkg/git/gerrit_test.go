@@ -0,0 +1,42 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestChangeID(t *testing.T) {
+	t.Parallel()
+	ident := "Jane Doe <jane@example.com> 1700000000 +0100"
+	// Expected values from `{ echo "$ident"; echo "$parent"; echo "$msg"; } | git hash-object --stdin`.
+	if got, want := ChangeID(ident, "0123456789abcdef0123456789abcdef01234567", "feat: add login"), "I88a26e650e82ccb5f36aebcc5124ea2524e41032"; got != want {
+		t.Errorf("ChangeID() = %q, want %q", got, want)
+	}
+	if got, want := ChangeID(ident, "", "feat: add login\n"), "Ib64d4b7869d8317790e89d23c72e0cb70270a65f"; got != want {
+		t.Errorf("ChangeID() for a root commit = %q, want %q", got, want)
+	}
+}
+
+func TestAppendChangeID(t *testing.T) {
+	t.Parallel()
+	committer := &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Unix(1700000000, 0).In(time.FixedZone("", 3600))}
+
+	got := appendChangeID("feat: add login\n\nRefs: PROJ-1", committer, "")
+	want := "feat: add login\n\nRefs: PROJ-1\nChange-Id: " + ChangeID("Jane Doe <jane@example.com> 1700000000 +0100", "", "feat: add login\n\nRefs: PROJ-1")
+	if got != want {
+		t.Errorf("appendChangeID() =\n%q\nwant\n%q", got, want)
+	}
+	if FindChangeID(got) == "" {
+		t.Errorf("FindChangeID(%q) is empty", got)
+	}
+
+	existing := "fix: typo\n\nChange-Id: I0123456789abcdef0123456789abcdef01234567"
+	if got := appendChangeID(existing, committer, "abc"); got != existing {
+		t.Errorf("appendChangeID() replaced the existing Change-Id: %q", got)
+	}
+	if got, want := FindChangeID(existing), "I0123456789abcdef0123456789abcdef01234567"; got != want {
+		t.Errorf("FindChangeID() = %q, want %q", got, want)
+	}
+}
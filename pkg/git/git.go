@@ -3,19 +3,22 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/renatogalera/ai-commit/pkg/committypes"
-	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/hook"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -25,29 +28,123 @@ type lineDiff struct {
 	Text string
 }
 
-// openRepo opens the git repository from the current directory,
-// walking up parent directories to find the .git folder if needed.
-func openRepo() (*gogit.Repository, error) {
-	return gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{
+// DiscoverRepo opens the repository containing the current directory, walking
+// up parent directories to the one holding .git, as git itself does. Every
+// command opens the repository through it, so ai-commit works from any
+// subdirectory.
+func DiscoverRepo() (*gogit.Repository, error) {
+	repo, err := gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{
 		DetectDotGit: true,
 	})
+	if errors.Is(err, gogit.ErrRepositoryNotExists) {
+		return nil, fmt.Errorf("%w: %w", ErrNotARepo, err)
+	}
+	return repo, err
+}
+
+// RepoRoot returns the top directory of the work tree DiscoverRepo finds.
+func RepoRoot() (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+// ReadWorktreeFile reads the work tree copy of a repository-relative path, as
+// diffs name files, from wherever in the repository ai-commit runs.
+func ReadWorktreeFile(path string) ([]byte, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(root, path))
+}
+
+// gitCommand prepares a git command that runs at the top of the work tree, where
+// the repository-relative paths of diffs and patches resolve.
+func gitCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if root, err := RepoRoot(); err == nil {
+		cmd.Dir = root
+	}
+	return cmd
 }
 
 // IsGitRepository returns true if "." (or an ancestor) is a Git repo.
 func IsGitRepository(ctx context.Context) bool {
-	_, err := openRepo()
+	_, err := DiscoverRepo()
 	return err == nil
 }
 
-// GetGitDiffIgnoringMoves builds a textual diff based on HEAD vs current working tree,
-// focused on staged changes (status.Staging != Unmodified). It removes moves and
-// attempts to drop pure comment-only changes to produce a cleaner prompt for LLMs.
-//
-// NOTE: New content is read from the working tree, not the index. This is a known limitation
-// if the user stages partial changes and then edits further. To make it *exactly* reflect the
-// index, you’d need to read blobs from the index (or shell-out to `git show :path`).
+// GetGitDiffIgnoringMoves builds a textual diff of HEAD against the index, so it
+// describes exactly what will be committed, including partially staged files
+// (git add -p). It removes moves and attempts to drop pure comment-only changes
+// to produce a cleaner prompt for LLMs.
 func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
-	repo, err := openRepo()
+	return getCleanedDiff(ctx, nil)
+}
+
+// GetPromptDiff returns the diff that is sent to the AI (moves, comments, and lock
+// files removed; generated and -diff files summarized per .gitattributes) together
+// with a report of everything that was left out.
+func GetPromptDiff(ctx context.Context, lockFiles []string) (string, *FilterReport, error) {
+	report := &FilterReport{}
+	diff, err := getCleanedDiff(ctx, report)
+	if err != nil {
+		return "", nil, err
+	}
+	diff = filterLockFiles(diff, lockFiles, report)
+	diff = filterGeneratedFiles(ctx, diff, report)
+	if strings.TrimSpace(diff) == "" {
+		return "", report, nil
+	}
+	return diff, report, nil
+}
+
+func getCleanedDiff(ctx context.Context, report *FilterReport) (string, error) {
+	diff, err := stagedDiff(ctx, report)
+	if err != nil {
+		return "", err
+	}
+	cleanedDiff := cleanupDiffWithReport(diff, report)
+	if strings.TrimSpace(cleanedDiff) == "" {
+		return "", nil
+	}
+	return cleanedDiff, nil
+}
+
+// GetStagedDiff is GetGitDiffIgnoringMoves without the comment/formatting cleanup.
+// It is used as a fallback when cleanup leaves nothing to describe.
+func GetStagedDiff(ctx context.Context) (string, error) {
+	return stagedDiff(ctx, nil)
+}
+
+// GetStagedFile returns the staged (index) contents of a repository-relative path.
+func GetStagedFile(ctx context.Context, filePath string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, "git", "show", ":"+filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged %s: %w", filePath, err)
+	}
+	return out, nil
+}
+
+// stagedDiff returns the staged diff with the files of privacy.neverSendPaths
+// redacted.
+func stagedDiff(ctx context.Context, report *FilterReport) (string, error) {
+	diff, err := readStagedDiff(report)
+	if err != nil {
+		return "", err
+	}
+	return redactPrivateFiles(diff, report), nil
+}
+
+func readStagedDiff(report *FilterReport) (string, error) {
+	repo, err := DiscoverRepo()
 	if err != nil {
 		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -62,6 +159,10 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 	if status.IsClean() {
 		return "", nil
 	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the index: %w", err)
+	}
 
 	dmp := diffmatchpatch.New()
 	var diffResult strings.Builder
@@ -69,7 +170,7 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 	headRef, err := repo.Head()
 	if err != nil {
 		// No HEAD (e.g., first commit) – treat as diff against empty tree.
-		return getDiffAgainstEmptyIgnoringMoves(repo)
+		return getDiffAgainstEmptyIgnoringMoves(repo, idx, report)
 	}
 	headCommit, err := repo.CommitObject(headRef.Hash())
 	if err != nil {
@@ -80,8 +181,16 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to get HEAD tree: %w", err)
 	}
 
+	// Content-identical moves are reported as headers only.
+	renamed := make(map[string]bool)
+	for _, r := range detectStagedRenames(repo, headTree, status) {
+		renamed[r.From] = true
+		renamed[r.To] = true
+		writeRenameHeader(&diffResult, r)
+	}
+
 	for filePath, fileStatus := range status {
-		if fileStatus.Staging == gogit.Unmodified {
+		if !isStaged(fileStatus) || renamed[filePath] {
 			continue
 		}
 
@@ -101,9 +210,12 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 
 		var newContent string
 		if fileStatus.Staging != gogit.Deleted {
-			// NOTE: reads working tree; for exact staged content, use index blob or `git show :path`.
-			if data, err := os.ReadFile(newPath); err == nil && !isBinary(data) {
-				newContent = string(data)
+			if data, err := indexFile(repo, idx, newPath); err == nil {
+				if isBinary(data) {
+					report.add(newPath, FilterBinary, 0)
+				} else {
+					newContent = string(data)
+				}
 			}
 		}
 
@@ -118,6 +230,7 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 		diffs = removeMovedBlocks(diffs)
 
 		if len(diffs) == 0 {
+			report.add(newPath, FilterMoved, 0)
 			continue
 		}
 
@@ -134,16 +247,11 @@ func GetGitDiffIgnoringMoves(ctx context.Context) (string, error) {
 		diffResult.WriteString("\n")
 	}
 
-	diff := diffResult.String()
-	cleanedDiff := cleanupDiff(diff)
-	if strings.TrimSpace(cleanedDiff) == "" {
-		return "", nil
-	}
-	return cleanedDiff, nil
+	return diffResult.String(), nil
 }
 
-// getDiffAgainstEmptyIgnoringMoves computes a diff vs empty repo.
-func getDiffAgainstEmptyIgnoringMoves(repo *gogit.Repository) (string, error) {
+// getDiffAgainstEmptyIgnoringMoves computes a diff of the index vs empty repo.
+func getDiffAgainstEmptyIgnoringMoves(repo *gogit.Repository, idx *index.Index, report *FilterReport) (string, error) {
 	worktree, err := repo.Worktree()
 	if err != nil {
 		return "", fmt.Errorf("failed to get worktree: %w", err)
@@ -157,14 +265,18 @@ func getDiffAgainstEmptyIgnoringMoves(repo *gogit.Repository) (string, error) {
 	var diffResult strings.Builder
 
 	for filePath, fileStatus := range status {
-		if fileStatus.Staging == gogit.Unmodified {
+		if !isStaged(fileStatus) {
 			continue
 		}
 		var newContent string
 		if fileStatus.Staging != gogit.Deleted {
-			data, err := os.ReadFile(filePath)
-			if err == nil && !isBinary(data) {
-				newContent = string(data)
+			data, err := indexFile(repo, idx, filePath)
+			if err == nil {
+				if isBinary(data) {
+					report.add(filePath, FilterBinary, 0)
+				} else {
+					newContent = string(data)
+				}
 			}
 		}
 		diffs := dmp.DiffMain("", newContent, true)
@@ -183,6 +295,30 @@ func getDiffAgainstEmptyIgnoringMoves(repo *gogit.Repository) (string, error) {
 	return diffResult.String(), nil
 }
 
+// isStaged reports whether the index changes a file; untracked files are not staged.
+func isStaged(fs *gogit.FileStatus) bool {
+	return fs.Staging != gogit.Unmodified && fs.Staging != gogit.Untracked
+}
+
+// indexFile returns the staged contents of a repository-relative path, as
+// `git show :path` does.
+func indexFile(repo *gogit.Repository, idx *index.Index, path string) ([]byte, error) {
+	entry, err := idx.Entry(path)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
 // removeMovedBlocks naively removes added lines that exactly match previously deleted lines.
 // It’s line-based; duplicates are decremented from a multiset to avoid over-deleting.
 func removeMovedBlocks(diffs []diffmatchpatch.Diff) []diffmatchpatch.Diff {
@@ -278,12 +414,17 @@ func isBinary(data []byte) bool {
 
 // FilterLockFiles drops entire file sections that match any of the provided lock file names.
 func FilterLockFiles(diff string, lockFiles []string) string {
+	return filterLockFiles(diff, lockFiles, nil)
+}
+
+func filterLockFiles(diff string, lockFiles []string, report *FilterReport) string {
 	if len(lockFiles) == 0 {
 		return diff
 	}
 	lines := strings.Split(diff, "\n")
 	var filtered []string
 	isLockFile := false
+	lockPath := ""
 
 	for _, line := range lines {
 		if strings.HasPrefix(line, "diff --git ") {
@@ -298,19 +439,62 @@ func FilterLockFiles(diff string, lockFiles []string) string {
 			}
 			isLockFile = matchFound
 			if isLockFile {
+				lockPath = parseFilePath(line)
+				report.add(lockPath, FilterLockFile, 0)
 				continue
 			}
 		}
 		if !isLockFile {
 			filtered = append(filtered, line)
+		} else if isChangeLine(line) {
+			report.add(lockPath, FilterLockFile, 1)
 		}
 	}
 	return strings.Join(filtered, "\n")
 }
 
-// CommitChanges creates a commit with a supplied message and the configured author identity.
+// CommitOptions tweaks how CommitChanges records a commit.
+type CommitOptions struct {
+	// AllowEmpty permits commits that do not change the tree (e.g. to trigger CI).
+	AllowEmpty bool
+	// Signoff appends a "Signed-off-by:" trailer for the author identity.
+	Signoff bool
+	// AuthorName, AuthorEmail, and AuthorDate override the author identity and date
+	// (like `git commit --author/--date`); zero values fall back to GIT_AUTHOR_* and the config.
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	// Footers are set on the message with SetFooter, replacing single-valued
+	// footers such as Refs, before Trailers are appended.
+	Footers []Footer
+	// Trailers are appended to the message before the Signed-off-by trailer.
+	Trailers []string
+	// ChangeID appends a Gerrit "Change-Id:" trailer unless the message has one.
+	ChangeID bool
+	// Amend replaces the HEAD commit instead of adding one, keeping its author
+	// and, with ChangeID, its Change-Id.
+	Amend bool
+	// Sign signs the commit like `git commit -S`, with the GPG, X.509, or SSH
+	// setup of the git config (gpg.format, user.signingKey).
+	Sign bool
+	// Verify, when set, is called with the final message just before the commit
+	// is created, like a commit-msg hook: it may return a replacement, and an
+	// error aborts the commit.
+	Verify func(ctx context.Context, message string) (string, error)
+	// NoVerify skips the repository's pre-commit and commit-msg hooks, like
+	// `git commit --no-verify`.
+	NoVerify bool
+}
+
+// CommitChanges creates a commit with a supplied message and the configured author
+// identity, honoring the GIT_AUTHOR_* and GIT_COMMITTER_* environment variables.
 func CommitChanges(ctx context.Context, commitMessage string) error {
-	repo, err := openRepo()
+	return CommitChangesWithOptions(ctx, commitMessage, CommitOptions{})
+}
+
+// CommitChangesWithOptions is CommitChanges with additional commit options.
+func CommitChangesWithOptions(ctx context.Context, commitMessage string, opts CommitOptions) error {
+	repo, err := DiscoverRepo()
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -318,13 +502,65 @@ func CommitChanges(ctx context.Context, commitMessage string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
-	_, err = worktree.Commit(commitMessage, &gogit.CommitOptions{
-		Author: &object.Signature{
-			Name:  config.DefaultAuthorName,
-			Email: config.DefaultAuthorEmail,
-			When:  time.Now(),
-		},
-	})
+	var amended *object.Commit
+	if opts.Amend {
+		if amended, err = amendableHead(repo); err != nil {
+			return err
+		}
+		opts = keepAuthor(opts, amended.Author)
+	}
+	author, committer, err := commitSignatures(opts, time.Now())
+	if err != nil {
+		return err
+	}
+	if !opts.NoVerify {
+		if err := hook.Run(ctx, "pre-commit"); err != nil {
+			return err
+		}
+	}
+	commitMessage = ApplyFooters(commitMessage, opts.Footers)
+	for _, trailer := range opts.Trailers {
+		commitMessage = AppendTrailer(commitMessage, trailer)
+	}
+	if opts.ChangeID {
+		commitMessage = addChangeID(repo, commitMessage, committer, amended)
+	}
+	if opts.Signoff {
+		commitMessage = AppendSignoff(commitMessage, author.Name, author.Email)
+	}
+	if opts.Verify != nil {
+		if commitMessage, err = opts.Verify(ctx, commitMessage); err != nil {
+			return err
+		}
+	}
+	if !opts.NoVerify {
+		if commitMessage, err = hook.RunCommitMsg(ctx, commitMessage); err != nil {
+			return err
+		}
+	}
+	commitOpts := &gogit.CommitOptions{
+		Author:            author,
+		Committer:         committer,
+		AllowEmptyCommits: opts.AllowEmpty,
+		Amend:             opts.Amend,
+	}
+	sign := opts.Sign
+	if !sign {
+		if sign, err = signByDefault(ctx); err != nil {
+			return err
+		}
+	}
+	if sign {
+		signer, err := newCommitSigner(ctx, committer)
+		if err != nil {
+			return fmt.Errorf("cannot sign the commit: %w", err)
+		}
+		commitOpts.Signer = signer
+	}
+	_, err = worktree.Commit(commitMessage, commitOpts)
+	if errors.Is(err, gogit.ErrEmptyCommit) {
+		return fmt.Errorf("commit failed: %w", ErrNoStagedChanges)
+	}
 	if err != nil {
 		return fmt.Errorf("commit failed: %w", err)
 	}
@@ -333,7 +569,7 @@ func CommitChanges(ctx context.Context, commitMessage string) error {
 
 // GetHeadCommitMessage returns the HEAD commit message.
 func GetHeadCommitMessage(ctx context.Context) (string, error) {
-	repo, err := openRepo()
+	repo, err := DiscoverRepo()
 	if err != nil {
 		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -348,9 +584,22 @@ func GetHeadCommitMessage(ctx context.Context) (string, error) {
 	return strings.TrimSpace(commit.Message), nil
 }
 
+// GetHeadCommitHash returns the full hash of the HEAD commit.
+func GetHeadCommitHash(ctx context.Context) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	headRef, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	return headRef.Hash().String(), nil
+}
+
 // GetCurrentBranch returns the short name of the current branch.
 func GetCurrentBranch(ctx context.Context) (string, error) {
-	repo, err := openRepo()
+	repo, err := DiscoverRepo()
 	if err != nil {
 		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -361,18 +610,33 @@ func GetCurrentBranch(ctx context.Context) (string, error) {
 	return headRef.Name().Short(), nil
 }
 
-// PrependCommitType ensures there's a single prefix (optionally with gitmoji) and prepends it.
+// RemoteURL returns the first URL of the named remote (e.g. "origin").
+func RemoteURL(name string) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	remote, err := repo.Remote(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read remote %q: %w", name, err)
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", fmt.Errorf("remote %q has no URL", name)
+}
+
+// PrependCommitType ensures there's a single prefix (optionally with gitmoji) and
+// prepends it, keeping the scope and "!" breaking marker of the message's own
+// prefix: "feat(auth)!: x" with type fix becomes "fix(auth)!: x".
 func PrependCommitType(message, commitType string, withEmoji bool) string {
 	if commitType == "" {
 		return message
 	}
-	regex := committypes.BuildRegexPatternWithEmoji()
-	message = regex.ReplaceAllString(message, "")
-	message = strings.TrimSpace(message)
 	if withEmoji {
 		return AddGitmoji(message, commitType)
 	}
-	return fmt.Sprintf("%s: %s", commitType, message)
+	return replaceTypePrefix(message, commitType)
 }
 
 // AddGitmoji adds emoji if configured, or just ensures a clean type prefix.
@@ -380,16 +644,50 @@ func AddGitmoji(message, commitType string) string {
 	if commitType == "" {
 		return message
 	}
-	emoji := committypes.GetEmojiForType(commitType)
 	prefix := commitType
-	if emoji != "" {
+	if emoji := committypes.GetEmojiForType(commitType); emoji != "" {
 		prefix = fmt.Sprintf("%s %s", emoji, commitType)
 	}
-	emojiPattern := committypes.BuildRegexPatternWithEmoji()
-	if emojiPattern.MatchString(message) {
-		message = emojiPattern.ReplaceAllString(message, "")
+	return replaceTypePrefix(message, prefix)
+}
+
+// replaceTypePrefix swaps the message's "[emoji] type(scope)!:" prefix, if any,
+// for prefix followed by the same scope and breaking marker.
+func replaceTypePrefix(message, prefix string) string {
+	message = strings.TrimSpace(message)
+	if m := committypes.BuildRegexPatternWithEmoji().FindStringSubmatch(message); m != nil {
+		prefix += m[4] + m[5]
+		message = strings.TrimSpace(message[len(m[0]):])
+	}
+	return fmt.Sprintf("%s: %s", prefix, message)
+}
+
+// MessageScope returns the scope of the message's "type(scope):" header, or "".
+func MessageScope(message string) string {
+	m := committypes.BuildRegexPatternWithEmoji().FindStringSubmatch(strings.TrimSpace(message))
+	if m == nil || m[4] == "" {
+		return ""
+	}
+	return strings.TrimSpace(m[4][1 : len(m[4])-1])
+}
+
+// SetScope rewrites the scope of the message's "[emoji] type(scope)!:" header,
+// keeping the type, emoji, and breaking marker; an empty scope removes it.
+func SetScope(message, scope string) (string, error) {
+	scope = strings.TrimSpace(scope)
+	if strings.ContainsAny(scope, "()\n") {
+		return "", fmt.Errorf("invalid scope %q", scope)
+	}
+	message = strings.TrimSpace(message)
+	m := committypes.BuildRegexPatternWithEmoji().FindStringSubmatchIndex(message)
+	if m == nil {
+		return "", fmt.Errorf("the message has no Conventional Commits type to scope")
+	}
+	if scope != "" {
+		scope = "(" + scope + ")"
 	}
-	return fmt.Sprintf("%s: %s", prefix, strings.TrimSpace(message))
+	// m[6]:m[7] spans the type; the scope group, when present, follows it.
+	return message[:m[7]] + scope + message[max(m[7], m[9]):], nil
 }
 
 // DiffChunk represents a parsed @@ hunk from a diff.
@@ -454,28 +752,91 @@ func parseFilePath(diffLine string) string {
 	return bPath
 }
 
+// FileStat is how many lines a diff adds to and removes from one file.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+}
+
+// DiffFileStats lists the files of diff in the order they appear, with their
+// added and removed lines. The "---"/"+++" file headers are only skipped before
+// a file's first hunk, so removed "-- " lines and added "++" lines still count.
+func DiffFileStats(diff string) []FileStat {
+	var stats []FileStat
+	inHunks := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			stats = append(stats, FileStat{Path: parseFilePath(line)})
+			inHunks = false
+		case len(stats) == 0:
+		case strings.HasPrefix(line, "@@"):
+			inHunks = true
+		case !inHunks && (strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---")):
+		case strings.HasPrefix(line, "+"):
+			stats[len(stats)-1].Added++
+		case strings.HasPrefix(line, "-"):
+			stats[len(stats)-1].Deleted++
+		}
+	}
+	return stats
+}
+
 // cleanupDiff removes comment-only changes and simple "move" no-ops from DMP patches.
+// Comment detection is language-aware (see ConfigureCommentFilter); documentation files
+// are never comment-filtered, and a diff that touches only documentation is kept as-is.
 func cleanupDiff(diff string) string {
+	return cleanupDiffWithReport(diff, nil)
+}
+
+func cleanupDiffWithReport(diff string, report *FilterReport) string {
 	lines := strings.Split(diff, "\n")
+	filterComments := !commentFilter.Disabled && !onlyDocsChanged(lines)
 	var cleaned []string
 	skipContext := false
+	var prefixes []string
+	var blocks blockComments
+	fileFiltered := filterComments
+	isGo := false
+	filePath := ""
 
 	for i := 0; i < len(lines); i++ {
 		line := lines[i]
 
+		if strings.HasPrefix(line, "diff --git ") {
+			filePath = parseFilePath(line)
+			prefixes, fileFiltered = commentPrefixesFor(filePath)
+			fileFiltered = fileFiltered && filterComments
+			blocks.reset(prefixes)
+			isGo = strings.HasSuffix(filePath, ".go")
+			skipContext = false
+			cleaned = append(cleaned, line)
+			continue
+		}
+
 		// Keep hunk headers intact.
 		if strings.HasPrefix(line, "@@") {
 			skipContext = false
+			blocks.reset(prefixes)
 			cleaned = append(cleaned, line)
 			continue
 		}
+		inBlock := blocks.next(line)
 
 		// Drop pure-context lines if we're skipping a comment-only section.
 		if skipContext && strings.HasPrefix(line, " ") {
 			continue
 		}
 
-		if isCommentOnlyChange(line) || isPureMovement(lines, i) {
+		dropComment := fileFiltered && isCommentLine(line, prefixes, inBlock) && !(isGo && isGoDocComment(lines, i))
+		if dropComment {
+			report.add(filePath, FilterComment, 1)
+			skipContext = true
+			continue
+		}
+		if isPureMovement(lines, i) {
+			report.add(filePath, FilterMoved, 1)
 			skipContext = true
 			continue
 		}
@@ -486,6 +847,21 @@ func cleanupDiff(diff string) string {
 	return strings.Join(cleaned, "\n")
 }
 
+// onlyDocsChanged reports whether every file header in the diff is a documentation path.
+func onlyDocsChanged(lines []string) bool {
+	seen := false
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "diff --git ") {
+			continue
+		}
+		seen = true
+		if !isDocsPath(parseFilePath(line)) {
+			return false
+		}
+	}
+	return seen
+}
+
 // isCommentOnlyChange detects when a diff line (+/-) only changes comments.
 func isCommentOnlyChange(line string) bool {
 	line = strings.TrimRight(line, "\r\n")
@@ -502,10 +878,7 @@ func isCommentOnlyChange(line string) bool {
 
 	// Strip the +/- and any leading whitespace before checking comment markers.
 	payload := strings.TrimSpace(line[1:])
-
-	// Common comment starters across languages.
-	commentPattern := regexp.MustCompile(`^(//|/\*|\*|#|--|<!--|;|"""|'|\(\*).*`)
-	return commentPattern.MatchString(payload)
+	return genericCommentPattern.MatchString(payload)
 }
 
 // isPureMovement returns true if a '-' line is immediately followed by an identical '+' line.
@@ -555,7 +928,7 @@ func partialCommit(chunks []DiffChunk, selected map[int]bool, client any) error
 		return fmt.Errorf("no chunks selected")
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "apply", "--cached", "-")
+	cmd := gitCommand(ctx, "apply", "--cached", "-")
 	cmd.Stdin = strings.NewReader(patch)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -73,10 +74,23 @@ func TestPrependCommitType(t *testing.T) {
 			want:      "✨ feat: resolve bug",
 		},
 		{
-			name:    "strips type with scope",
+			name:    "keeps the scope of the replaced type",
 			message: "feat(auth): add oauth",
 			typ:     "fix",
-			want:    "fix: add oauth",
+			want:    "fix(auth): add oauth",
+		},
+		{
+			name:      "keeps scope and breaking marker with emoji",
+			message:   "🐛 fix(api)!: drop the v1 endpoints",
+			typ:       "feat",
+			withEmoji: true,
+			want:      "✨ feat(api)!: drop the v1 endpoints",
+		},
+		{
+			name:    "keeps a breaking marker without scope",
+			message: "refactor!: rename config keys",
+			typ:     "feat",
+			want:    "feat!: rename config keys",
 		},
 	}
 	for _, tt := range tests {
@@ -142,6 +156,39 @@ func TestAddGitmoji(t *testing.T) {
 	}
 }
 
+func TestSetScope(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		message string
+		scope   string
+		want    string
+		wantErr bool
+	}{
+		{name: "adds a scope", message: "feat: add login", scope: "auth", want: "feat(auth): add login"},
+		{name: "replaces the scope", message: "fix(ui): resize\n\nBody.", scope: " tui ", want: "fix(tui): resize\n\nBody."},
+		{name: "keeps emoji and breaking marker", message: "✨ feat(api)!: drop v1", scope: "rest", want: "✨ feat(rest)!: drop v1"},
+		{name: "empty scope removes it", message: "feat(api)!: drop v1", scope: "", want: "feat!: drop v1"},
+		{name: "no type prefix", message: "add login", scope: "auth", wantErr: true},
+		{name: "parentheses", message: "feat: add login", scope: "a)b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := SetScope(tt.message, tt.scope)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetScope(%q, %q) error = %v, wantErr %v", tt.message, tt.scope, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SetScope(%q, %q) = %q, want %q", tt.message, tt.scope, got, tt.want)
+			}
+			if !tt.wantErr && MessageScope(got) != strings.TrimSpace(tt.scope) {
+				t.Errorf("MessageScope(%q) = %q, want %q", got, MessageScope(got), strings.TrimSpace(tt.scope))
+			}
+		})
+	}
+}
+
 func TestParseFilePath(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -244,6 +291,29 @@ diff --git a/b.go b/b.go
 	})
 }
 
+func TestDiffFileStats(t *testing.T) {
+	t.Parallel()
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1,3 +1,4 @@
++line1
+-line2
++line3
+--- removed SQL comment
++++i
+diff --git a/b.go b/b.go
+--- a/b.go
++++ b/b.go
+@@ -1,1 +1,0 @@
+-gone`
+	got := DiffFileStats(diff)
+	want := []FileStat{{Path: "a.go", Added: 3, Deleted: 2}, {Path: "b.go", Deleted: 1}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("DiffFileStats = %+v, want %+v", got, want)
+	}
+}
+
 func TestFilterLockFiles(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -501,6 +571,87 @@ func TestIsGitRepository_Integration(t *testing.T) {
 	})
 }
 
+func TestStagedDiffFromSubdirectory_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	if err := os.MkdirAll(filepath.Join(dir, "pkg", "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n\nUsage notes.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(filepath.Join(dir, "pkg", "api")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	diff, err := GetStagedDiff(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "diff --git a/README.md b/README.md") || !strings.Contains(diff, "Usage notes.") {
+		t.Errorf("staged diff from a subdirectory = %q, want the README change", diff)
+	}
+	if data, err := ReadWorktreeFile("README.md"); err != nil || !strings.Contains(string(data), "Usage notes.") {
+		t.Errorf("ReadWorktreeFile(README.md) = %q, %v", data, err)
+	}
+}
+
+func TestStagedDiffReadsIndex_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Test\n\nStaged line.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	// Edits after staging and untracked files are not part of the commit.
+	if err := os.WriteFile(readme, []byte("# Test\n\nStaged line.\nUnstaged line.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("scratch\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	diff, err := GetStagedDiff(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "Staged line.") {
+		t.Errorf("staged diff lacks the staged change:\n%s", diff)
+	}
+	if strings.Contains(diff, "Unstaged line.") || strings.Contains(diff, "notes.txt") {
+		t.Errorf("staged diff includes changes that are not staged:\n%s", diff)
+	}
+}
+
 func TestGetHeadCommitMessage_Integration(t *testing.T) {
 	dir := initTestRepo(t)
 	origDir, _ := os.Getwd()
@@ -516,6 +667,14 @@ func TestGetHeadCommitMessage_Integration(t *testing.T) {
 	if msg != "initial commit" {
 		t.Errorf("got %q, want 'initial commit'", msg)
 	}
+
+	hash, err := GetHeadCommitHash(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hash) != 40 {
+		t.Errorf("GetHeadCommitHash() = %q, want a full hash", hash)
+	}
 }
 
 func TestGetCurrentBranch_Integration(t *testing.T) {
@@ -575,3 +734,103 @@ func TestCommitChanges_Integration(t *testing.T) {
 		t.Errorf("got %q, want 'feat: add new file'", msg)
 	}
 }
+
+func TestCommitChangesWithOptions_AllowEmpty_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := CommitChanges(context.Background(), "chore: nothing"); err == nil {
+		t.Fatal("expected empty commit to fail without AllowEmpty")
+	}
+	if err := CommitChangesWithOptions(context.Background(), "ci: trigger rebuild", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := GetHeadCommitMessage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "ci: trigger rebuild" {
+		t.Errorf("got %q, want 'ci: trigger rebuild'", msg)
+	}
+
+	vetoed := errors.New("vetoed")
+	veto := func(ctx context.Context, msg string) (string, error) { return "", vetoed }
+	if err := CommitChangesWithOptions(context.Background(), "ci: again", CommitOptions{AllowEmpty: true, Verify: veto}); !errors.Is(err, vetoed) {
+		t.Errorf("commit with a failing Verify = %v, want its error", err)
+	}
+	replace := func(ctx context.Context, msg string) (string, error) { return msg + "\n\nRefs: PROJ-1", nil }
+	opts := CommitOptions{AllowEmpty: true, Trailers: []string{"Reviewed-by: Pat"}, Verify: replace}
+	if err := CommitChangesWithOptions(context.Background(), "ci: again", opts); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := GetHeadCommitMessage(context.Background()); msg != "ci: again\n\nReviewed-by: Pat\n\nRefs: PROJ-1" {
+		t.Errorf("message after Verify = %q, want the trailers followed by its addition", msg)
+	}
+}
+
+func TestCommitChangesWithOptions_Hooks_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	hooks := filepath.Join(dir, ".git", "hooks")
+	if err := os.MkdirAll(hooks, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooks, "pre-commit"), []byte("#!/bin/sh\necho 'tests failed'\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooks, "commit-msg"), []byte("#!/bin/sh\nprintf '\\nTested-by: hook\\n' >> \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	err := CommitChangesWithOptions(ctx, "ci: blocked", CommitOptions{AllowEmpty: true})
+	if err == nil || !strings.Contains(err.Error(), "tests failed") {
+		t.Fatalf("commit with a failing pre-commit hook = %v", err)
+	}
+	if msg, _ := GetHeadCommitMessage(ctx); msg != "initial commit" {
+		t.Errorf("a rejected commit was created: %q", msg)
+	}
+	if err := CommitChangesWithOptions(ctx, "ci: skip hooks", CommitOptions{AllowEmpty: true, NoVerify: true}); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := GetHeadCommitMessage(ctx); msg != "ci: skip hooks" {
+		t.Errorf("message with NoVerify = %q", msg)
+	}
+
+	if err := os.Remove(filepath.Join(hooks, "pre-commit")); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitChangesWithOptions(ctx, "ci: hooked", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := GetHeadCommitMessage(ctx); msg != "ci: hooked\n\nTested-by: hook" {
+		t.Errorf("message after the commit-msg hook = %q", msg)
+	}
+}
+
+func TestTypedErrors_Integration(t *testing.T) {
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DiscoverRepo(); !errors.Is(err, ErrNotARepo) {
+		t.Errorf("DiscoverRepo outside a repository = %v, want ErrNotARepo", err)
+	}
+
+	if err := os.Chdir(initTestRepo(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitChanges(context.Background(), "feat: nothing"); !errors.Is(err, ErrNoStagedChanges) {
+		t.Errorf("commit with nothing staged = %v, want ErrNoStagedChanges", err)
+	}
+}
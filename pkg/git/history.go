@@ -0,0 +1,83 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// RecentCommits returns up to n non-merge commits reachable from HEAD, newest first,
+// each with the patch it introduced.
+func RecentCommits(ctx context.Context, n int) ([]CommitInfo, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	iter, err := repo.Log(&gogit.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var commits []CommitInfo
+	err = iter.ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(commits) >= n {
+			return storer.ErrStop
+		}
+		if c.NumParents() > 1 {
+			return nil
+		}
+		info, err := newCommitInfo(c)
+		if err != nil {
+			return err
+		}
+		commits = append(commits, *info)
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// maxRelatedPaths bounds the pathspec of RelatedCommits for diffs touching many
+// files.
+const maxRelatedPaths = 100
+
+// RelatedCommits returns up to n non-merge commits reachable from rev that touch
+// any of paths, newest first, with only ShortHash and Subject set.
+func RelatedCommits(ctx context.Context, rev string, paths []string, n int) ([]CommitInfo, error) {
+	if n <= 0 || len(paths) == 0 {
+		return nil, nil
+	}
+	if len(paths) > maxRelatedPaths {
+		paths = paths[:maxRelatedPaths]
+	}
+	args := append([]string{"log", "-n", strconv.Itoa(n), "--no-merges", "--format=%h%x00%s", rev, "--"}, paths...)
+	out, err := gitCommand(ctx, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	var commits []CommitInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		commits = append(commits, CommitInfo{ShortHash: hash, Subject: subject})
+	}
+	return commits, nil
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+func TestRecentCommits_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := CommitChanges(context.Background(), "feat: add "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commits, err := RecentCommits(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Subject != "feat: add b.txt" || commits[1].Subject != "feat: add a.txt" {
+		t.Errorf("unexpected order: %q, %q", commits[0].Subject, commits[1].Subject)
+	}
+	if commits[0].Diff == "" {
+		t.Error("expected commit diff to be populated")
+	}
+}
+
+func TestRelatedCommits_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"a.txt", "b.txt", "a.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+strconv.Itoa(i)+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := CommitChanges(context.Background(), fmt.Sprintf("feat: change %s (%d)", name, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commits, err := RelatedCommits(context.Background(), "HEAD", []string{"a.txt", "gone.txt"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "feat: change a.txt (2)" || commits[1].Subject != "feat: change a.txt (0)" {
+		t.Errorf("related commits = %+v, want the two a.txt commits, newest first", commits)
+	}
+	if len(commits) > 0 && len(commits[0].ShortHash) < 7 {
+		t.Errorf("short hash = %q", commits[0].ShortHash)
+	}
+	if commits, err := RelatedCommits(context.Background(), "HEAD", []string{"a.txt"}, 0); err != nil || commits != nil {
+		t.Errorf("n = 0 returned %+v, %v", commits, err)
+	}
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// authorPattern matches git's "Name <email>" identity form.
+var authorPattern = regexp.MustCompile(`^\s*([^<>]*?)\s*<([^<>]*)>\s*$`)
+
+// rawDatePattern matches git's internal "<unix seconds> <+hhmm>" date, optionally prefixed by "@".
+var rawDatePattern = regexp.MustCompile(`^@?(\d+)(?:\s+([+-])(\d{2})(\d{2}))?$`)
+
+// dateLayouts are the human-readable date formats accepted by ParseDate.
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon Jan 2 15:04:05 2006 -0700",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseAuthor splits a "Name <email>" identity as accepted by `git commit --author`.
+func ParseAuthor(s string) (name, email string, err error) {
+	m := authorPattern.FindStringSubmatch(s)
+	if m == nil || m[1] == "" || m[2] == "" {
+		return "", "", fmt.Errorf("invalid author %q: expected \"Name <email>\"", s)
+	}
+	return m[1], m[2], nil
+}
+
+// ParseDate parses a commit date in the formats git accepts for --date and
+// GIT_AUTHOR_DATE: RFC 3339, RFC 2822, ISO 8601, and "<unix seconds> <+hhmm>".
+// Dates without a zone are local time.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if m := rawDatePattern.FindStringSubmatch(s); m != nil {
+		secs, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %w", s, err)
+		}
+		loc := time.Local
+		if m[2] != "" {
+			hours, _ := strconv.Atoi(m[3])
+			minutes, _ := strconv.Atoi(m[4])
+			offset := hours*3600 + minutes*60
+			if m[2] == "-" {
+				offset = -offset
+			}
+			loc = time.FixedZone("", offset)
+		}
+		return time.Unix(secs, 0).In(loc), nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: use RFC 3339 (2006-01-02T15:04:05Z07:00), RFC 2822, or \"<unix seconds> <+hhmm>\"", s)
+}
+
+// commitSignatures resolves the author and committer of a commit. Precedence for
+// the author is opts, then GIT_AUTHOR_NAME/EMAIL/DATE, then the configured identity
+// and the current time; the committer uses GIT_COMMITTER_* or the configured identity.
+func commitSignatures(opts CommitOptions, now time.Time) (author, committer *object.Signature, err error) {
+	author = &object.Signature{
+		Name:  firstNonEmpty(opts.AuthorName, os.Getenv("GIT_AUTHOR_NAME"), config.DefaultAuthorName),
+		Email: firstNonEmpty(opts.AuthorEmail, os.Getenv("GIT_AUTHOR_EMAIL"), config.DefaultAuthorEmail),
+		When:  opts.AuthorDate,
+	}
+	if author.When.IsZero() {
+		if author.When, err = envDate("GIT_AUTHOR_DATE", now); err != nil {
+			return nil, nil, err
+		}
+	}
+	committer = &object.Signature{
+		Name:  firstNonEmpty(os.Getenv("GIT_COMMITTER_NAME"), config.DefaultAuthorName),
+		Email: firstNonEmpty(os.Getenv("GIT_COMMITTER_EMAIL"), config.DefaultAuthorEmail),
+	}
+	if committer.When, err = envDate("GIT_COMMITTER_DATE", now); err != nil {
+		return nil, nil, err
+	}
+	return author, committer, nil
+}
+
+// TaggerSignature returns the identity annotated tags are created with, the
+// committer of commitSignatures: GIT_COMMITTER_NAME/EMAIL/DATE or the configured
+// identity and the current time.
+func TaggerSignature() (*object.Signature, error) {
+	_, committer, err := commitSignatures(CommitOptions{}, time.Now())
+	return committer, err
+}
+
+// envDate parses the date in environment variable key, returning fallback when unset.
+func envDate(key string, fallback time.Time) (time.Time, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return fallback, nil
+	}
+	t, err := ParseDate(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", key, err)
+	}
+	return t, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
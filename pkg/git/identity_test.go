@@ -0,0 +1,88 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func TestParseAuthor(t *testing.T) {
+	t.Parallel()
+	name, email, err := ParseAuthor("  Jane Q. Doe <jane@example.com> ")
+	if err != nil || name != "Jane Q. Doe" || email != "jane@example.com" {
+		t.Errorf("ParseAuthor() = %q, %q, %v", name, email, err)
+	}
+	for _, bad := range []string{"jane@example.com", "<jane@example.com>", "Jane <>"} {
+		if _, _, err := ParseAuthor(bad); err == nil {
+			t.Errorf("ParseAuthor(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+	want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	for _, s := range []string{
+		"2023-11-14T22:13:20Z",
+		"2023-11-15T00:13:20+02:00",
+		"Tue, 14 Nov 2023 22:13:20 +0000",
+		"2023-11-14 23:13:20 +0100",
+		"1700000000 +0000",
+		"@1700000000",
+	} {
+		got, err := ParseDate(s)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", s, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", s, got, want)
+		}
+	}
+	if got, err := ParseDate("1700000000 -0130"); err != nil || got.Format("-0700") != "-0130" {
+		t.Errorf("expected the raw offset to be kept, got %v, %v", got, err)
+	}
+	if _, err := ParseDate("yesterday"); err == nil {
+		t.Error("expected an error for an unsupported date")
+	}
+}
+
+func TestCommitSignatures(t *testing.T) {
+	origName, origEmail := config.DefaultAuthorName, config.DefaultAuthorEmail
+	config.DefaultAuthorName, config.DefaultAuthorEmail = "Config User", "config@example.com"
+	defer func() { config.DefaultAuthorName, config.DefaultAuthorEmail = origName, origEmail }()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Setenv("GIT_AUTHOR_NAME", "Env Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "")
+	t.Setenv("GIT_AUTHOR_DATE", "1700000000 +0000")
+	t.Setenv("GIT_COMMITTER_NAME", "")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bot@example.com")
+	t.Setenv("GIT_COMMITTER_DATE", "")
+
+	author, committer, err := commitSignatures(CommitOptions{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author.Name != "Env Author" || author.Email != "config@example.com" || author.When.Unix() != 1700000000 {
+		t.Errorf("unexpected author %+v", author)
+	}
+	if committer.Name != "Config User" || committer.Email != "bot@example.com" || !committer.When.Equal(now) {
+		t.Errorf("unexpected committer %+v", committer)
+	}
+
+	date := time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC)
+	author, _, err = commitSignatures(CommitOptions{AuthorName: "Flag Author", AuthorEmail: "flag@example.com", AuthorDate: date}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author.Name != "Flag Author" || author.Email != "flag@example.com" || !author.When.Equal(date) {
+		t.Errorf("options should override the environment, got %+v", author)
+	}
+
+	t.Setenv("GIT_COMMITTER_DATE", "not a date")
+	if _, _, err := commitSignatures(CommitOptions{}, now); err == nil {
+		t.Error("expected an invalid GIT_COMMITTER_DATE to fail")
+	}
+}
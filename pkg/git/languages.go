@@ -0,0 +1,160 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// languageByExt maps file extensions to the language reported in prompt hints.
+var languageByExt = map[string]string{
+	".go":     "Go",
+	".py":     "Python",
+	".rb":     "Ruby",
+	".rs":     "Rust",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".scala":  "Scala",
+	".swift":  "Swift",
+	".m":      "Objective-C",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".php":    "PHP",
+	".js":     "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".jsx":    "React",
+	".ts":     "TypeScript",
+	".tsx":    "React",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".html":   "HTML",
+	".css":    "CSS",
+	".scss":   "CSS",
+	".sass":   "CSS",
+	".less":   "CSS",
+	".sql":    "SQL",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".ps1":    "PowerShell",
+	".lua":    "Lua",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".hs":     "Haskell",
+	".clj":    "Clojure",
+	".dart":   "Dart",
+	".tf":     "Terraform",
+	".proto":  "Protobuf",
+	".md":     "Markdown",
+	".rst":    "reStructuredText",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".json":   "JSON",
+	".toml":   "TOML",
+}
+
+// languageByName covers files identified by name rather than extension.
+var languageByName = map[string]string{
+	"Dockerfile":     "Docker",
+	"Makefile":       "Make",
+	"CMakeLists.txt": "CMake",
+	"Jenkinsfile":    "Groovy",
+}
+
+// languageRole gives the conventional part of a project a language belongs to,
+// used to describe polyglot changes (e.g. "Go backend + React frontend").
+var languageRole = map[string]string{
+	"React":            "frontend",
+	"Vue":              "frontend",
+	"Svelte":           "frontend",
+	"HTML":             "frontend",
+	"CSS":              "frontend",
+	"Docker":           "infrastructure",
+	"Terraform":        "infrastructure",
+	"Markdown":         "docs",
+	"reStructuredText": "docs",
+}
+
+// LanguageStat is how much of a diff is written in one language.
+type LanguageStat struct {
+	Name  string
+	Files int
+	Lines int
+}
+
+// FileLanguage returns the language of filePath, or "" when it is not recognized.
+func FileLanguage(filePath string) string {
+	if lang, ok := languageByName[path.Base(filePath)]; ok {
+		return lang
+	}
+	return languageByExt[strings.ToLower(path.Ext(filePath))]
+}
+
+// DetectLanguages counts changed files and lines per language in diff, most
+// changed lines first. Files in unrecognized languages are ignored.
+func DetectLanguages(diff string) []LanguageStat {
+	stats := make(map[string]*LanguageStat)
+	var current *LanguageStat
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = nil
+			lang := FileLanguage(parseFilePath(line))
+			if lang == "" {
+				continue
+			}
+			if stats[lang] == nil {
+				stats[lang] = &LanguageStat{Name: lang}
+			}
+			current = stats[lang]
+			current.Files++
+		case current == nil, strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			current.Lines++
+		}
+	}
+
+	result := make([]LanguageStat, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines != result[j].Lines {
+			return result[i].Lines > result[j].Lines
+		}
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// DescribeLanguages summarizes stats for the prompt, e.g.
+// "Go (3 files, 120 lines) + React frontend (2 files, 40 lines)".
+func DescribeLanguages(stats []LanguageStat) string {
+	parts := make([]string, 0, len(stats))
+	for _, s := range stats {
+		name := s.Name
+		if role := languageRole[s.Name]; role != "" {
+			name += " " + role
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s, %s)", name, plural(s.Files, "file"), plural(s.Lines, "line")))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
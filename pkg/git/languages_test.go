@@ -0,0 +1,46 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguages(t *testing.T) {
+	t.Parallel()
+	diff := strings.Join([]string{
+		"diff --git a/server/api.go b/server/api.go",
+		"--- a/server/api.go",
+		"+++ b/server/api.go",
+		"+func a() {}",
+		"+func b() {}",
+		"-func c() {}",
+		"diff --git a/server/db.go b/server/db.go",
+		"+var x = 1",
+		"diff --git a/web/App.tsx b/web/App.tsx",
+		"+export const App = () => null",
+		"diff --git a/Dockerfile b/Dockerfile",
+		"+FROM golang:1.25",
+		"diff --git a/LICENSE b/LICENSE",
+		"+text",
+	}, "\n")
+
+	got := DetectLanguages(diff)
+	want := []LanguageStat{
+		{Name: "Go", Files: 2, Lines: 4},
+		{Name: "Docker", Files: 1, Lines: 1},
+		{Name: "React", Files: 1, Lines: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DetectLanguages() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("DetectLanguages()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	desc := DescribeLanguages(got)
+	if desc != "Go (2 files, 4 lines) + Docker infrastructure (1 file, 1 line) + React frontend (1 file, 1 line)" {
+		t.Errorf("DescribeLanguages() = %q", desc)
+	}
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// scissors marks the start of the diff "git commit --verbose" appends to the
+// message file, after the comment character.
+const scissors = " ------------------------ >8 ------------------------"
+
+// CommentChar returns the core.commentChar of the repository, "#" when it is
+// unset or "auto".
+func CommentChar(ctx context.Context) string {
+	out, err := gitCommand(ctx, "config", "--get", "core.commentChar").Output()
+	if c := strings.TrimSpace(string(out)); err == nil && c != "" && c != "auto" {
+		return c
+	}
+	return "#"
+}
+
+// CleanMessage strips a commit message file the way "git commit" does by
+// default: everything from the scissors line on, comment lines starting with
+// commentChar, trailing whitespace, and leading and trailing blank lines.
+func CleanMessage(text, commentChar string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line == commentChar+scissors {
+			break
+		}
+		if !strings.HasPrefix(line, commentChar) {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,20 @@
+package git
+
+import "testing"
+
+func TestCleanMessage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, text, char, want string
+	}{
+		{"comments", "\nfeat: add login  \n\nAdds the endpoint.\n# Please enter the commit message.\n#\n", "#", "feat: add login\n\nAdds the endpoint."},
+		{"scissors", "fix: typo\n# ------------------------ >8 ------------------------\ndiff --git a/a b/a\n", "#", "fix: typo"},
+		{"comment char", "; comment\nfix: #123 typo\r\n", ";", "fix: #123 typo"},
+		{"empty", "# only comments\n", "#", ""},
+	}
+	for _, tt := range tests {
+		if got := CleanMessage(tt.text, tt.char); got != tt.want {
+			t.Errorf("%s: CleanMessage = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package git
+
+import "strings"
+
+// HunkOverride is a per-session decision about whether a file or hunk reaches the prompt.
+type HunkOverride int
+
+const (
+	// HunkDefault leaves the decision to the configured filters.
+	HunkDefault HunkOverride = iota
+	// HunkExclude drops the hunk from the prompt even if the filters kept it.
+	HunkExclude
+	// HunkInclude sends the unfiltered hunk even if the filters dropped it.
+	HunkInclude
+)
+
+// HunkOverrides maps HunkKey/FileKey values to overrides. Hunk entries win over file entries.
+type HunkOverrides map[string]HunkOverride
+
+// FileKey identifies every hunk of a file in HunkOverrides.
+func FileKey(filePath string) string {
+	return filePath
+}
+
+// HunkKey identifies a single hunk in HunkOverrides. Hunk headers are shared between
+// the raw and the filtered diff, so the key matches in both.
+func HunkKey(c DiffChunk) string {
+	return c.FilePath + " " + c.HunkHeader
+}
+
+// Resolve returns the effective override for a hunk.
+func (o HunkOverrides) Resolve(c DiffChunk) HunkOverride {
+	if v, ok := o[HunkKey(c)]; ok && v != HunkDefault {
+		return v
+	}
+	return o[FileKey(c.FilePath)]
+}
+
+type diffFile struct {
+	path   string
+	header []string
+	hunks  []DiffChunk
+}
+
+// splitDiffFiles groups a diff into per-file sections, keeping the lines that
+// precede the first hunk (diff --git, rename from/to) as the file header.
+func splitDiffFiles(diff string) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, diffFile{path: parseFilePath(line), header: []string{line}})
+			cur = &files[len(files)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "@@") {
+			cur.hunks = append(cur.hunks, DiffChunk{FilePath: cur.path, HunkHeader: line})
+			continue
+		}
+		if len(cur.hunks) == 0 {
+			if line != "" {
+				cur.header = append(cur.header, line)
+			}
+			continue
+		}
+		h := &cur.hunks[len(cur.hunks)-1]
+		h.Lines = append(h.Lines, line)
+	}
+	return files
+}
+
+// ApplyHunkOverrides rebuilds the prompt diff from the filtered diff, dropping
+// excluded files/hunks and restoring included ones from the raw staged diff.
+// Without overrides the filtered diff is returned unchanged.
+func ApplyHunkOverrides(filtered, raw string, overrides HunkOverrides) string {
+	if len(overrides) == 0 {
+		return filtered
+	}
+	kept := make(map[string]diffFile)
+	for _, f := range splitDiffFiles(filtered) {
+		kept[f.path] = f
+	}
+
+	var out []string
+	for _, rf := range splitDiffFiles(raw) {
+		ff, inFiltered := kept[rf.path]
+		keptHunks := make(map[string]DiffChunk)
+		for _, h := range ff.hunks {
+			keptHunks[h.HunkHeader] = h
+		}
+
+		var body []string
+		for _, h := range rf.hunks {
+			var chosen *DiffChunk
+			switch overrides.Resolve(h) {
+			case HunkExclude:
+			case HunkInclude:
+				chosen = &h
+			default:
+				if fh, ok := keptHunks[h.HunkHeader]; ok {
+					chosen = &fh
+				}
+			}
+			if chosen != nil {
+				body = append(body, chosen.HunkHeader)
+				body = append(body, trimTrailingEmpty(chosen.Lines)...)
+			}
+		}
+
+		switch {
+		case len(body) > 0:
+			header := rf.header
+			if inFiltered {
+				header = ff.header
+			}
+			out = append(out, header...)
+			out = append(out, body...)
+		case len(rf.hunks) == 0 && inFiltered && overrides[FileKey(rf.path)] != HunkExclude:
+			// Header-only sections such as pure renames.
+			out = append(out, ff.header...)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func trimTrailingEmpty(lines []string) []string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
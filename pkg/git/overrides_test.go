@@ -0,0 +1,89 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const overridesRawDiff = "diff --git a/main.go b/main.go\n" +
+	"@@ -1,1 +1,2 @@\n+// note\n+x := 1\n" +
+	"@@ -10,1 +11,1 @@\n-y := 2\n+y := 3\n" +
+	"diff --git a/go.sum b/go.sum\n" +
+	"@@ -1 +1 @@\n-a v1\n+a v2\n"
+
+const overridesFilteredDiff = "diff --git a/main.go b/main.go\n" +
+	"@@ -1,1 +1,2 @@\n+x := 1\n" +
+	"@@ -10,1 +11,1 @@\n-y := 2\n+y := 3\n"
+
+func TestApplyHunkOverrides_NoOverrides(t *testing.T) {
+	t.Parallel()
+	if got := ApplyHunkOverrides(overridesFilteredDiff, overridesRawDiff, nil); got != overridesFilteredDiff {
+		t.Errorf("expected filtered diff unchanged, got:\n%s", got)
+	}
+}
+
+func TestApplyHunkOverrides(t *testing.T) {
+	t.Parallel()
+	second := DiffChunk{FilePath: "main.go", HunkHeader: "@@ -10,1 +11,1 @@"}
+	first := DiffChunk{FilePath: "main.go", HunkHeader: "@@ -1,1 +1,2 @@"}
+
+	tests := []struct {
+		name      string
+		overrides HunkOverrides
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "exclude hunk",
+			overrides: HunkOverrides{HunkKey(second): HunkExclude},
+			want:      []string{"+x := 1"},
+			notWant:   []string{"y := 3", "// note", "go.sum"},
+		},
+		{
+			name:      "include filtered hunk restores raw lines",
+			overrides: HunkOverrides{HunkKey(first): HunkInclude},
+			want:      []string{"+// note", "+y := 3"},
+			notWant:   []string{"go.sum"},
+		},
+		{
+			name:      "include filtered lock file",
+			overrides: HunkOverrides{FileKey("go.sum"): HunkInclude},
+			want:      []string{"diff --git a/go.sum b/go.sum", "+a v2", "+x := 1"},
+			notWant:   []string{"// note"},
+		},
+		{
+			name:      "hunk override wins over file override",
+			overrides: HunkOverrides{FileKey("main.go"): HunkExclude, HunkKey(second): HunkInclude},
+			want:      []string{"diff --git a/main.go b/main.go", "+y := 3"},
+			notWant:   []string{"x := 1"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := ApplyHunkOverrides(overridesFilteredDiff, overridesRawDiff, tc.overrides)
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("expected %q in:\n%s", w, got)
+				}
+			}
+			for _, nw := range tc.notWant {
+				if strings.Contains(got, nw) {
+					t.Errorf("did not expect %q in:\n%s", nw, got)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyHunkOverrides_KeepsRenameHeaders(t *testing.T) {
+	t.Parallel()
+	raw := "diff --git a/old.go b/new.go\nrename from old.go\nrename to new.go\n" + overridesRawDiff
+	filtered := "diff --git a/old.go b/new.go\nrename from old.go\nrename to new.go\n" + overridesFilteredDiff
+	got := ApplyHunkOverrides(filtered, raw, HunkOverrides{FileKey("go.sum"): HunkExclude})
+	if !strings.Contains(got, "rename to new.go") {
+		t.Errorf("expected rename header to be kept, got:\n%s", got)
+	}
+}
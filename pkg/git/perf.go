@@ -0,0 +1,146 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	loopPattern  = regexp.MustCompile(`^\s*(for|while|foreach|do)\b|\.(forEach|each|map)\s*\(|\bfor\s*\(`)
+	allocPattern = regexp.MustCompile(`\b(make|append|new)\s*\(|\bnew\s+[A-Z]\w*|\[\]\w+\{|\bmap\[\w+\]\w+\{`)
+	queryPattern = regexp.MustCompile(`(?i)\.(Query|QueryRow|Exec|Find|First|Where|Get|Fetch)\w*\s*\(|\bSELECT\b|\bfetch\s*\(|http\.(Get|Post|Do)\b`)
+	lockPattern  = regexp.MustCompile(`\.(R?Lock|R?Unlock)\s*\(\)|\bsync\.(RW)?Mutex\b|\bsynchronized\b|\bthreading\.(R?Lock)\b|\bMutex::new\b`)
+)
+
+// FilePerfStats holds cheap, locally computed hints about a changed file that help
+// a performance review focus on likely hot spots. Counts only cover added lines.
+type FilePerfStats struct {
+	Path        string
+	TotalLines  int // lines in the working-tree file; 0 when it could not be read
+	AddedLines  int
+	MaxNesting  int // deepest indentation level among added lines
+	Loops       int
+	NestedLoops int
+	// AllocsInLoops and QueriesInLoops count allocations and I/O-looking calls
+	// (queries, HTTP requests) that appear inside a loop body.
+	AllocsInLoops  int
+	QueriesInLoops int
+	LockOps        int
+}
+
+// PerfHeuristics computes FilePerfStats for each file in diff. Loop bodies are
+// tracked by indentation over the new side of each hunk, so results are
+// approximate and language-agnostic. readFile, when non-nil, is used to count
+// the total lines of each file.
+func PerfHeuristics(diff string, readFile func(path string) ([]byte, error)) []FilePerfStats {
+	var stats []FilePerfStats
+	var cur *FilePerfStats
+	var loopIndents []int
+
+	flush := func() {
+		if cur != nil && cur.AddedLines > 0 {
+			if readFile != nil {
+				if data, err := readFile(cur.Path); err == nil {
+					cur.TotalLines = strings.Count(string(data), "\n")
+				}
+			}
+			stats = append(stats, *cur)
+		}
+		cur = nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			cur = &FilePerfStats{Path: parseFilePath(line)}
+			loopIndents = nil
+			continue
+		case strings.HasPrefix(line, "@@"):
+			loopIndents = nil
+			continue
+		case cur == nil, strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "-"):
+			continue
+		}
+
+		added := strings.HasPrefix(line, "+")
+		code := line
+		if added || strings.HasPrefix(line, " ") {
+			code = line[1:]
+		}
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		indent := indentLevel(code)
+		for len(loopIndents) > 0 && indent <= loopIndents[len(loopIndents)-1] {
+			loopIndents = loopIndents[:len(loopIndents)-1]
+		}
+		inLoop := len(loopIndents) > 0
+		isLoop := loopPattern.MatchString(code)
+
+		if added {
+			cur.AddedLines++
+			if indent > cur.MaxNesting {
+				cur.MaxNesting = indent
+			}
+			if isLoop {
+				cur.Loops++
+				if inLoop {
+					cur.NestedLoops++
+				}
+			}
+			if inLoop && allocPattern.MatchString(code) {
+				cur.AllocsInLoops++
+			}
+			if inLoop && queryPattern.MatchString(code) {
+				cur.QueriesInLoops++
+			}
+			if lockPattern.MatchString(code) {
+				cur.LockOps++
+			}
+		}
+		if isLoop {
+			loopIndents = append(loopIndents, indent)
+		}
+	}
+	flush()
+	return stats
+}
+
+// indentLevel counts leading tabs, and groups of four spaces, as nesting levels.
+func indentLevel(line string) int {
+	level, spaces := 0, 0
+	for _, r := range line {
+		switch r {
+		case '\t':
+			level++
+		case ' ':
+			spaces++
+			if spaces == 4 {
+				level++
+				spaces = 0
+			}
+		default:
+			return level
+		}
+	}
+	return level
+}
+
+// FormatPerfHeuristics renders stats as a compact list for inclusion in a prompt.
+func FormatPerfHeuristics(stats []FilePerfStats) string {
+	if len(stats) == 0 {
+		return "No changed source files."
+	}
+	var b strings.Builder
+	for _, s := range stats {
+		size := "unknown size"
+		if s.TotalLines > 0 {
+			size = fmt.Sprintf("%d lines", s.TotalLines)
+		}
+		fmt.Fprintf(&b, "- %s (%s, +%d): max nesting %d, loops %d (nested %d), allocations in loops %d, I/O or queries in loops %d, lock operations %d\n",
+			s.Path, size, s.AddedLines, s.MaxNesting, s.Loops, s.NestedLoops, s.AllocsInLoops, s.QueriesInLoops, s.LockOps)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
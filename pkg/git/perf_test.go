@@ -0,0 +1,74 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPerfHeuristics(t *testing.T) {
+	t.Parallel()
+	diff := strings.Join([]string{
+		"diff --git a/store.go b/store.go",
+		"--- a/store.go",
+		"+++ b/store.go",
+		"@@ -1,3 +1,12 @@",
+		" func load(ids []int) {",
+		"+\tmu.Lock()",
+		"+\tfor _, id := range ids {",
+		"+\t\trow := db.QueryRow(\"SELECT 1\", id)",
+		"+\t\tfor _, c := range cols {",
+		"+\t\t\tout = append(out, c)",
+		"+\t\t}",
+		"+\t}",
+		"+\tvals := make([]int, 0)",
+		"-\told()",
+		" }",
+		"diff --git a/README.md b/README.md",
+		"@@ -1 +1 @@",
+		"-old",
+	}, "\n")
+
+	files := map[string]string{"store.go": "a\nb\nc\n"}
+	readFile := func(path string) ([]byte, error) {
+		if data, ok := files[path]; ok {
+			return []byte(data), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	stats := PerfHeuristics(diff, readFile)
+	if len(stats) != 1 {
+		t.Fatalf("expected stats for store.go only, got %+v", stats)
+	}
+	got := stats[0]
+	want := FilePerfStats{
+		Path:           "store.go",
+		TotalLines:     3,
+		AddedLines:     8,
+		MaxNesting:     3,
+		Loops:          2,
+		NestedLoops:    1,
+		AllocsInLoops:  1,
+		QueriesInLoops: 1,
+		LockOps:        1,
+	}
+	if got != want {
+		t.Errorf("PerfHeuristics() = %+v, want %+v", got, want)
+	}
+
+	out := FormatPerfHeuristics(stats)
+	if !strings.Contains(out, "store.go (3 lines, +8)") || !strings.Contains(out, "I/O or queries in loops 1") {
+		t.Errorf("unexpected formatted heuristics: %q", out)
+	}
+}
+
+func TestIndentLevel(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int{"x": 0, "\t\tx": 2, "        x": 2, "      x": 1, "\t    x": 2}
+	for in, want := range tests {
+		if got := indentLevel(in); got != want {
+			t.Errorf("indentLevel(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
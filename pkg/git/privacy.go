@@ -0,0 +1,71 @@
+package git
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/glob"
+)
+
+var neverSendPaths []string
+
+// ConfigurePrivacy sets the files whose content is never sent to a provider.
+func ConfigurePrivacy(settings config.PrivacySettings) {
+	neverSendPaths = settings.NeverSendPaths
+}
+
+// IsPrivatePath reports whether privacy.neverSendPaths covers file. A "**"
+// segment spans directories, and a pattern without "/" matches the file name
+// at any depth, so "*.pem" covers "certs/server.pem".
+func IsPrivatePath(file string) bool {
+	for _, pattern := range neverSendPaths {
+		if glob.Match(pattern, file) {
+			return true
+		}
+		if !strings.Contains(pattern, "/") && glob.Match(pattern, path.Base(file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactPrivateFiles replaces the content of every file of diff covered by
+// privacy.neverSendPaths with a one-line note. The diffs this package reads are
+// already redacted; any other diff headed for a prompt must go through it,
+// usually via PromptDiff.
+func RedactPrivateFiles(diff string) string {
+	return redactPrivateFiles(diff, nil)
+}
+
+// PromptDiff prepares a diff that does not come from this package, such as a
+// merge request diff or one given on stdin, for a prompt: the files of
+// privacy.neverSendPaths are redacted and lock files dropped.
+func PromptDiff(diff string, lockFiles []string) string {
+	return filterLockFiles(redactPrivateFiles(diff, nil), lockFiles, nil)
+}
+
+// PromptPatch prepares a patch of staged hunks, which is applied to the index
+// as is, for a prompt with the filters of the commit diff: never-send paths and
+// generated files are reduced to a note, and comment-only changes and moved
+// lines are dropped.
+func PromptPatch(ctx context.Context, patch string) string {
+	return cleanupDiff(filterGeneratedFiles(ctx, redactPrivateFiles(patch, nil), nil))
+}
+
+// redactPrivateFiles replaces the content of every file covered by
+// privacy.neverSendPaths with a one-line note, so the AI sees that the file
+// changed but never what it contains.
+func redactPrivateFiles(diff string, report *FilterReport) string {
+	if len(neverSendPaths) == 0 {
+		return diff
+	}
+	private := make(map[string]FilterReason)
+	for _, p := range DiffPaths(diff) {
+		if IsPrivatePath(p) {
+			private[p] = FilterPrivate
+		}
+	}
+	return summarizeGeneratedFiles(diff, private, report)
+}
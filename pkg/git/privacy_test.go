@@ -0,0 +1,102 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func TestIsPrivatePath(t *testing.T) {
+	ConfigurePrivacy(config.PrivacySettings{NeverSendPaths: []string{"secrets/**", "*.pem", "terraform/*.tfstate"}})
+	t.Cleanup(func() { ConfigurePrivacy(config.PrivacySettings{}) })
+
+	for file, want := range map[string]bool{
+		"secrets/prod/db.yaml":          true,
+		"certs/server.pem":              true,
+		"server.pem":                    true,
+		"terraform/main.tfstate":        true,
+		"terraform/modules/vpc.tfstate": false,
+		"app/secrets/readme.md":         false,
+		"main.go":                       false,
+	} {
+		if got := IsPrivatePath(file); got != want {
+			t.Errorf("IsPrivatePath(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestRedactPrivateFiles(t *testing.T) {
+	ConfigurePrivacy(config.PrivacySettings{NeverSendPaths: []string{"*.pem"}})
+	t.Cleanup(func() { ConfigurePrivacy(config.PrivacySettings{}) })
+
+	diff := strings.Join([]string{
+		"diff --git a/certs/key.pem b/certs/key.pem",
+		"--- a/certs/key.pem",
+		"+++ b/certs/key.pem",
+		"@@ -1 +1,2 @@",
+		"-MIIEowIBAAKCAQEA",
+		"+MIIEvQIBADANBgkq",
+		"+-----END PRIVATE KEY-----",
+		"diff --git a/main.go b/main.go",
+		"@@ -1 +1 @@",
+		"+func main() {}",
+	}, "\n")
+	want := strings.Join([]string{
+		"diff --git a/certs/key.pem b/certs/key.pem",
+		"[never-send path: 3 changed lines omitted]",
+		"diff --git a/main.go b/main.go",
+		"@@ -1 +1 @@",
+		"+func main() {}",
+	}, "\n")
+	if got := PromptDiff(diff, nil); got != want {
+		t.Errorf("PromptDiff() =\n%s\nwant\n%s", got, want)
+	}
+	// Lock file filtering alone keeps every file, e.g. for the splitter, which
+	// applies its hunks back to the index.
+	if got := FilterLockFiles(diff, nil); got != diff {
+		t.Errorf("FilterLockFiles() redacted the diff:\n%s", got)
+	}
+}
+
+func TestPromptDiffPrivacy_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	ConfigurePrivacy(config.PrivacySettings{NeverSendPaths: []string{"secrets/**"}})
+	t.Cleanup(func() { ConfigurePrivacy(config.PrivacySettings{}) })
+
+	if err := os.MkdirAll(filepath.Join(dir, "secrets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secrets", "token.txt"), []byte("hunter2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if out, err := runGit(ctx, "add", "-A"); err != nil {
+		t.Fatalf("git add: %s: %v", out, err)
+	}
+
+	diff, report, err := GetPromptDiff(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(diff, "hunter2") || !strings.Contains(diff, "[never-send path:") || !strings.Contains(diff, "package app") {
+		t.Errorf("prompt diff leaks or lacks content:\n%s", diff)
+	}
+	if !strings.Contains(report.String(), "secrets/token.txt") {
+		t.Errorf("report lacks the redacted file:\n%s", report)
+	}
+	if raw, _ := GetStagedDiff(ctx); strings.Contains(raw, "hunter2") {
+		t.Errorf("unfiltered diff leaks the secret:\n%s", raw)
+	}
+}
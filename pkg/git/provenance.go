@@ -0,0 +1,37 @@
+package git
+
+import "strings"
+
+// ProvenanceKey is the trailer recording how an AI-generated message was produced.
+const ProvenanceKey = "X-AI-Commit"
+
+// Provenance describes the generator of a commit message for audits.
+type Provenance struct {
+	Provider     string
+	Model        string
+	TemplateHash string
+	Version      string
+}
+
+// Trailer formats p as e.g. "X-AI-Commit: openai/gpt-4o tmpl=abc123 v1.4.0".
+// Empty fields are left out.
+func (p Provenance) Trailer() string {
+	parts := []string{ProvenanceKey + ":"}
+	generator := p.Provider
+	if p.Model != "" {
+		generator += "/" + p.Model
+	}
+	if generator != "" {
+		parts = append(parts, generator)
+	}
+	if p.TemplateHash != "" {
+		parts = append(parts, "tmpl="+p.TemplateHash)
+	}
+	if v := strings.TrimSpace(p.Version); v != "" {
+		if v[0] >= '0' && v[0] <= '9' {
+			v = "v" + v
+		}
+		parts = append(parts, v)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// PushedCommits returns the non-merge commits reachable from rev that no ref
+// reaches yet, oldest first: the commits a push of rev introduces. It runs the
+// git CLI rather than go-git so that, inside a pre-receive hook, the quarantined
+// objects of the push are visible.
+func PushedCommits(ctx context.Context, rev string) ([]CommitInfo, error) {
+	out, err := runGit(ctx, "rev-list", "--reverse", "--no-merges", rev, "--not", "--all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pushed commits: %s: %w", out, err)
+	}
+	var commits []CommitInfo
+	for _, hash := range strings.Fields(out) {
+		message, err := runGit(ctx, "log", "-1", "--format=%B", hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit %s: %s: %w", hash, message, err)
+		}
+		diff, err := runGit(ctx, "show", "--format=", "--no-color", "--no-ext-diff", hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the diff of %s: %s: %w", hash, diff, err)
+		}
+		subject, body, _ := strings.Cut(message, "\n")
+		commits = append(commits, CommitInfo{
+			Hash:      hash,
+			ShortHash: hash[:7],
+			Subject:   subject,
+			Body:      strings.TrimSpace(body),
+			Message:   message,
+			Diff:      redactPrivateFiles(diff, nil),
+		})
+	}
+	return commits, nil
+}
+
+// PushTag pushes the tag to remote and returns the pushed ref, e.g.
+// "origin refs/tags/v1.2.0". SSH remotes authenticate through the SSH agent and
+// HTTP(S) remotes with token, when set. force replaces a remote tag with the
+// same name; a tag that is already up to date is not an error.
+func PushTag(ctx context.Context, remote, tag, token string, force bool) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to read remote %q: %w", remote, err)
+	}
+	urls := r.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote %q has no URL", remote)
+	}
+	auth, err := pushAuth(urls[0], token)
+	if err != nil {
+		return "", err
+	}
+	ref := "refs/tags/" + tag
+	spec := ref + ":" + ref
+	if force {
+		spec = "+" + spec
+	}
+	err = repo.PushContext(ctx, &gogit.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(spec)},
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return "", fmt.Errorf("failed to push %s to %s: %w", tag, remote, err)
+	}
+	return remote + " " + ref, nil
+}
+
+// RemoteHost returns the host name of a remote URL in any form git accepts
+// (HTTPS, SSH, or scp-like "git@host:path"), or "" when it has none.
+func RemoteHost(url string) string {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(endpoint.Host)
+}
+
+// pushAuth picks the authentication for pushing to url: the SSH agent for SSH
+// URLs, token as basic auth for HTTP(S) ones, and none otherwise.
+func pushAuth(url, token string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote URL %q: %w", url, err)
+	}
+	switch endpoint.Protocol {
+	case "ssh":
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		auth, err := ssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use the SSH agent for %s: %w", url, err)
+		}
+		return auth, nil
+	case "http", "https":
+		if token == "" {
+			return nil, nil
+		}
+		// Hosts ignore the user name of token authentication, but it must be set.
+		return &http.BasicAuth{Username: "x-access-token", Password: token}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPushedCommits_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	ctx := context.Background()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runGit(ctx, "add", "a.txt"); err != nil {
+		t.Fatalf("git add: %s: %v", out, err)
+	}
+	if err := CommitChanges(ctx, "feat: add a.txt\n\nWith a body."); err != nil {
+		t.Fatal(err)
+	}
+	pushed, err := runGit(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Move the branch back so the commit is only reachable from the "pushed" hash.
+	if out, err := runGit(ctx, "update-ref", "HEAD", "HEAD~1"); err != nil {
+		t.Fatalf("git update-ref: %s: %v", out, err)
+	}
+
+	commits, err := PushedCommits(ctx, pushed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("PushedCommits() returned %d commits, want 1", len(commits))
+	}
+	c := commits[0]
+	if c.Hash != pushed || c.Subject != "feat: add a.txt" || c.Body != "With a body." {
+		t.Errorf("unexpected commit %+v", c)
+	}
+	if want := "\n+a"; !strings.Contains(c.Diff, want) {
+		t.Errorf("diff %q does not contain %q", c.Diff, want)
+	}
+
+	if commits, err := PushedCommits(ctx, "HEAD"); err != nil || len(commits) != 0 {
+		t.Errorf("PushedCommits(HEAD) = %d commits, %v; want none", len(commits), err)
+	}
+}
+
+func TestPushTag_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"remote", "add", "origin", remote},
+		{"tag", "v1.0.0"},
+	} {
+		if out, err := runGit(ctx, args...); err != nil {
+			t.Fatalf("git %s: %s: %v", strings.Join(args, " "), out, err)
+		}
+	}
+
+	pushed, err := PushTag(ctx, "origin", "v1.0.0", "", false)
+	if err != nil || pushed != "origin refs/tags/v1.0.0" {
+		t.Fatalf("PushTag() = %q, %v", pushed, err)
+	}
+	if out, err := runGit(ctx, "--git-dir", remote, "rev-parse", "v1.0.0"); err != nil {
+		t.Errorf("the remote has no tag v1.0.0: %s: %v", out, err)
+	}
+	if _, err := PushTag(ctx, "origin", "v1.0.0", "", false); err != nil {
+		t.Errorf("pushing an up-to-date tag: %v", err)
+	}
+	if _, err := PushTag(ctx, "upstream", "v1.0.0", "", false); err == nil {
+		t.Error("expected an error for a missing remote")
+	}
+}
+
+func TestPushAuth(t *testing.T) {
+	t.Parallel()
+	auth, err := pushAuth("https://github.com/octo/repo.git", "tok")
+	if err != nil || auth == nil || auth.Name() != "http-basic-auth" {
+		t.Errorf("https with a token: %v, %v", auth, err)
+	}
+	if auth, err := pushAuth("https://github.com/octo/repo.git", ""); err != nil || auth != nil {
+		t.Errorf("https without a token: %v, %v", auth, err)
+	}
+	if auth, err := pushAuth("/srv/repo.git", "tok"); err != nil || auth != nil {
+		t.Errorf("local path: %v, %v", auth, err)
+	}
+}
+
+func TestRemoteHost(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://github.com/o/r.git":        "github.com",
+		"https://user@GitLab.example.com/r": "gitlab.example.com",
+		"git@github.com:o/r.git":            "github.com",
+		"ssh://git@gitea.local:2222/o/r":    "gitea.local",
+		"/srv/repos/r.git":                  "",
+	} {
+		if got := RemoteHost(url); got != want {
+			t.Errorf("RemoteHost(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// QueuedCommit is one entry of a commit queue: a patch of staged hunks (as
+// accepted by `git apply --cached`) and the message to commit it with.
+type QueuedCommit struct {
+	Patch   string
+	Message string
+}
+
+// StagedPatch returns `git diff --cached` with full context lines, suitable for
+// splitting into hunks that can be applied back to the index.
+func StagedPatch(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "diff", "--cached", "--no-color", "--no-ext-diff").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read staged changes: %w", err)
+	}
+	return string(out), nil
+}
+
+// CommitQueue creates one commit per queue entry, in order, each containing only
+// its patch. Everything staged but not in the queue stays staged afterwards. If
+// an entry fails, the commits already created are kept, the index is restored to
+// the remaining changes, and the number of created commits is returned with the
+// error.
+func CommitQueue(ctx context.Context, queue []QueuedCommit, opts CommitOptions) (int, error) {
+	// The staged tree is restored at the end; with the queued commits now in
+	// HEAD, only the unqueued changes show as staged.
+	tree, err := runGit(ctx, "write-tree")
+	if err != nil {
+		return 0, fmt.Errorf("failed to record the staged tree: %s: %w", tree, err)
+	}
+	if out, err := runGit(ctx, "reset", "-q"); err != nil {
+		return 0, fmt.Errorf("failed to unstage changes: %s: %w", out, err)
+	}
+
+	done := 0
+	for i, entry := range queue {
+		if err = applyCached(ctx, entry.Patch); err != nil {
+			err = fmt.Errorf("commit %d: %w", i+1, err)
+			break
+		}
+		if err = CommitChangesWithOptions(ctx, entry.Message, opts); err != nil {
+			err = fmt.Errorf("commit %d: %w", i+1, err)
+			break
+		}
+		done++
+	}
+	if out, readErr := runGit(ctx, "read-tree", tree); readErr != nil && err == nil {
+		err = fmt.Errorf("failed to restore the remaining staged changes (tree %s): %s: %w", tree, out, readErr)
+	}
+	return done, err
+}
+
+// applyCached applies patch to the index only.
+func applyCached(ctx context.Context, patch string) error {
+	if strings.TrimSpace(patch) == "" {
+		return fmt.Errorf("empty patch")
+	}
+	cmd := gitCommand(ctx, "apply", "--cached", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply patch: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
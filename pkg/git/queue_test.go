@@ -0,0 +1,62 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitQueue_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	files := map[string]string{"README.md": "# Test\nmore\n", "a.txt": "a\n", "b.txt": "b\n"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	if out, err := runGit(ctx, "add", "-A"); err != nil {
+		t.Fatalf("git add: %s: %v", out, err)
+	}
+
+	queue := []QueuedCommit{
+		{Patch: "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1,2 @@\n # Test\n+more\n", Message: "docs: extend readme"},
+		{Patch: "diff --git a/a.txt b/a.txt\nnew file mode 100644\n--- /dev/null\n+++ b/a.txt\n@@ -0,0 +1 @@\n+a\n", Message: "feat: add a.txt"},
+	}
+	done, err := CommitQueue(ctx, queue, CommitOptions{})
+	if err != nil || done != 2 {
+		t.Fatalf("CommitQueue() = %d, %v; want 2, nil", done, err)
+	}
+
+	log, err := runGit(ctx, "log", "--format=%s", "-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "feat: add a.txt\ndocs: extend readme\ninitial commit"; log != want {
+		t.Errorf("log = %q, want %q", log, want)
+	}
+	staged, err := runGit(ctx, "diff", "--cached", "--name-only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staged != "b.txt" {
+		t.Errorf("still staged = %q, want b.txt", staged)
+	}
+
+	// A patch that does not apply stops the queue and keeps the rest staged.
+	done, err = CommitQueue(ctx, []QueuedCommit{{Patch: queue[0].Patch, Message: "again"}}, CommitOptions{})
+	if err == nil || done != 0 || !strings.Contains(err.Error(), "commit 1") {
+		t.Fatalf("CommitQueue() = %d, %v; want 0 and a commit 1 error", done, err)
+	}
+	if staged, _ := runGit(ctx, "diff", "--cached", "--name-only"); staged != "b.txt" {
+		t.Errorf("after failure still staged = %q, want b.txt", staged)
+	}
+}
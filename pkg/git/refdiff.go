@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// RefDiff returns the unified diff from ref from to ref to (hashes, branches,
+// tags, HEAD~1, ...). An empty to compares from with the working tree, covering
+// staged and unstaged changes to tracked files.
+func RefDiff(ctx context.Context, from, to string) (string, error) {
+	if to == "" {
+		diff, err := workingTreeDiff(ctx, from)
+		return redactPrivateFiles(diff, nil), err
+	}
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	commits := make([]*object.Commit, 2)
+	for i, rev := range []string{from, to} {
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return "", fmt.Errorf("cannot resolve %q: %w", rev, err)
+		}
+		if commits[i], err = repo.CommitObject(*hash); err != nil {
+			return "", fmt.Errorf("failed to load commit %s: %w", hash, err)
+		}
+	}
+	patch, err := commits[0].PatchContext(ctx, commits[1])
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s and %s: %w", from, to, err)
+	}
+	return redactPrivateFiles(patch.String(), nil), nil
+}
+
+// workingTreeDiff asks git for the diff between ref and the working tree, which
+// go-git cannot compute directly.
+func workingTreeDiff(ctx context.Context, ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+	out, err := exec.CommandContext(ctx, "git", "diff", "--no-color", "--no-ext-diff", ref, "--").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git diff %s: %s", ref, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git diff %s: %w", ref, err)
+	}
+	return string(out), nil
+}
+
+// GetRefPromptDiff is GetPromptDiff for the changes between two refs (see
+// RefDiff) instead of the staged ones.
+func GetRefPromptDiff(ctx context.Context, from, to string, lockFiles []string) (string, *FilterReport, error) {
+	raw, err := RefDiff(ctx, from, to)
+	if err != nil {
+		return "", nil, err
+	}
+	diff, report := promptDiffOf(ctx, raw, lockFiles)
+	return diff, report, nil
+}
+
+// promptDiffOf applies the prompt filters of GetPromptDiff to a unified diff.
+func promptDiffOf(ctx context.Context, raw string, lockFiles []string) (string, *FilterReport) {
+	report := &FilterReport{}
+	diff := cleanupDiffWithReport(raw, report)
+	diff = filterLockFiles(diff, lockFiles, report)
+	diff = filterGeneratedFiles(ctx, diff, report)
+	if strings.TrimSpace(diff) == "" {
+		return "", report
+	}
+	return diff, report
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestRefDiff_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("main.go"); err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit("add main", &gogit.CommitOptions{Author: sig}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	diff, err := RefDiff(ctx, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "diff --git a/main.go b/main.go") || !strings.Contains(diff, "+package main") {
+		t.Errorf("RefDiff(HEAD~1, HEAD) = %q", diff)
+	}
+
+	// Unstaged edits count when comparing with the working tree.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n\nUsage.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = RefDiff(ctx, "HEAD", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+Usage.") || strings.Contains(diff, "main.go") {
+		t.Errorf("RefDiff(HEAD, working tree) = %q", diff)
+	}
+
+	if _, err := RefDiff(ctx, "no-such-ref", "HEAD"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+	if _, err := RefDiff(ctx, "--output=x", ""); err == nil {
+		t.Error("expected an error for a ref that looks like an option")
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Rename describes a staged file move whose content did not change.
+type Rename struct {
+	From string
+	To   string
+}
+
+// detectStagedRenames pairs staged deletions with staged additions whose index
+// blob is identical to the deleted HEAD blob. go-git reports such moves as a
+// separate delete and add, which would otherwise show up as full-file hunks.
+func detectStagedRenames(repo *gogit.Repository, headTree *object.Tree, status gogit.Status) []Rename {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil
+	}
+	added := make(map[string][]string) // blob hash -> added paths
+	for p, fs := range status {
+		if fs.Staging != gogit.Added {
+			continue
+		}
+		entry, err := idx.Entry(p)
+		if err != nil {
+			continue
+		}
+		added[entry.Hash.String()] = append(added[entry.Hash.String()], p)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	var deleted []string
+	for p, fs := range status {
+		if fs.Staging == gogit.Deleted {
+			deleted = append(deleted, p)
+		}
+	}
+	sort.Strings(deleted)
+
+	var renames []Rename
+	for _, p := range deleted {
+		f, err := headTree.File(p)
+		if err != nil {
+			continue
+		}
+		candidates := added[f.Hash.String()]
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.Strings(candidates)
+		renames = append(renames, Rename{From: p, To: candidates[0]})
+		added[f.Hash.String()] = candidates[1:]
+	}
+	return renames
+}
+
+// writeRenameHeader emits a git-style header for a content-identical rename.
+func writeRenameHeader(sb *strings.Builder, r Rename) {
+	sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", r.From, r.To))
+	sb.WriteString("rename from " + r.From + "\n")
+	sb.WriteString("rename to " + r.To + "\n")
+}
+
+// ParseRenameOnlyDiff reports whether diff consists solely of rename headers
+// and returns the renames it describes.
+func ParseRenameOnlyDiff(diff string) ([]Rename, bool) {
+	var renames []Rename
+	var current Rename
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case strings.HasPrefix(line, "diff --git "):
+			current = Rename{}
+		case strings.HasPrefix(line, "rename from "):
+			current.From = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.To = strings.TrimPrefix(line, "rename to ")
+			if current.From == "" {
+				return nil, false
+			}
+			renames = append(renames, current)
+		default:
+			return nil, false
+		}
+	}
+	return renames, len(renames) > 0
+}
+
+// RenameCommitMessage builds a deterministic message (without type prefix)
+// for a set of pure renames, e.g. "rename pkg/foo to pkg/bar".
+func RenameCommitMessage(renames []Rename) string {
+	if len(renames) == 0 {
+		return ""
+	}
+	fromDir, toDir, shared := commonMove(renames)
+	if shared {
+		return fmt.Sprintf("rename %s to %s", fromDir, toDir)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("rename %d files\n\n", len(renames)))
+	for _, r := range renames {
+		b.WriteString(fmt.Sprintf("- %s -> %s\n", r.From, r.To))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// commonMove strips the longest shared trailing path from each rename and
+// reports whether every rename reduces to the same from/to prefix.
+func commonMove(renames []Rename) (string, string, bool) {
+	var fromDir, toDir string
+	for i, r := range renames {
+		from := strings.Split(r.From, "/")
+		to := strings.Split(r.To, "/")
+		for len(from) > 1 && len(to) > 1 && from[len(from)-1] == to[len(to)-1] {
+			from = from[:len(from)-1]
+			to = to[:len(to)-1]
+		}
+		f, t := path.Join(from...), path.Join(to...)
+		if i == 0 {
+			fromDir, toDir = f, t
+			continue
+		}
+		if f != fromDir || t != toDir {
+			return "", "", false
+		}
+	}
+	return fromDir, toDir, true
+}
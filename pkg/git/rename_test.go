@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRenameOnlyDiff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		diff   string
+		want   []Rename
+		wantOK bool
+	}{
+		{
+			name:   "single rename",
+			diff:   "diff --git a/a.go b/b.go\nrename from a.go\nrename to b.go\n",
+			want:   []Rename{{From: "a.go", To: "b.go"}},
+			wantOK: true,
+		},
+		{
+			name:   "rename mixed with content change",
+			diff:   "diff --git a/a.go b/b.go\nrename from a.go\nrename to b.go\ndiff --git a/c.go b/c.go\n@@ -1 +1 @@\n-x\n+y\n",
+			wantOK: false,
+		},
+		{
+			name:   "empty diff",
+			diff:   "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ParseRenameOnlyDiff(tt.diff)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d renames, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("rename[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRenameCommitMessage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		renames []Rename
+		want    string
+	}{
+		{
+			name:    "directory move",
+			renames: []Rename{{"pkg/foo/a.go", "pkg/bar/a.go"}, {"pkg/foo/b.go", "pkg/bar/b.go"}},
+			want:    "rename pkg/foo to pkg/bar",
+		},
+		{
+			name:    "single file rename",
+			renames: []Rename{{"pkg/x/util.go", "pkg/x/helpers.go"}},
+			want:    "rename pkg/x/util.go to pkg/x/helpers.go",
+		},
+		{
+			name:    "unrelated renames list every file",
+			renames: []Rename{{"a.go", "b.go"}, {"docs/x.md", "docs/y.md"}},
+			want:    "rename 2 files\n\n- a.go -> b.go\n- docs/x.md -> docs/y.md",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := RenameCommitMessage(tt.renames); got != tt.want {
+				t.Errorf("RenameCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGitDiffIgnoringMoves_RenameOnly_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "mv", "README.md", "docs/README.md").CombinedOutput(); err != nil {
+		t.Fatalf("git mv failed: %s", out)
+	}
+
+	diff, err := GetGitDiffIgnoringMoves(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	renames, ok := ParseRenameOnlyDiff(diff)
+	if !ok {
+		t.Fatalf("expected rename-only diff, got:\n%s", diff)
+	}
+	if len(renames) != 1 || renames[0].To != "docs/README.md" {
+		t.Errorf("unexpected renames: %+v", renames)
+	}
+	if strings.Contains(diff, "# Test") {
+		t.Error("expected file content to be omitted for identical renames")
+	}
+}
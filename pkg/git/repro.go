@@ -0,0 +1,55 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// ReproNotesRef holds the reproducibility reports of commits, so they stay out
+// of the messages and the default notes; view them with
+// "git log --notes=ai-commit" or "git notes --ref=ai-commit show".
+const ReproNotesRef = "refs/notes/ai-commit"
+
+// ReproRequest describes one provider request made to produce a commit.
+type ReproRequest struct {
+	Provider    string   `json:"provider"`
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	Seed        *int64   `json:"seed,omitempty"`
+	// PromptSHA256 and OutputSHA256 identify the exact prompt and raw reply
+	// without storing them.
+	PromptSHA256 string `json:"promptSha256"`
+	OutputSHA256 string `json:"outputSha256"`
+}
+
+// ReproReport lets an audit regenerate a commit message under the same
+// conditions and compare the result.
+type ReproReport struct {
+	Version      string         `json:"version,omitempty"`
+	TemplateHash string         `json:"templateHash,omitempty"`
+	Requests     []ReproRequest `json:"requests"`
+}
+
+// HashText returns the hex SHA-256 of text.
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Note formats r as the indented JSON stored in the commit's note.
+func (r ReproReport) Note() string {
+	data, _ := json.MarshalIndent(r, "", "  ")
+	return string(data)
+}
+
+// AddReproNote attaches r to rev under ReproNotesRef, replacing any report a
+// previous run left there (e.g. before an amend).
+func AddReproNote(ctx context.Context, rev string, r ReproReport) error {
+	if out, err := runGit(ctx, "notes", "--ref", ReproNotesRef, "add", "--force", "--message", r.Note(), rev); err != nil {
+		return fmt.Errorf("git notes add failed: %s: %w", out, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAddReproNote_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	ctx := context.Background()
+
+	temperature, seed := 0.0, int64(42)
+	report := ReproReport{
+		Version:      "1.4.0",
+		TemplateHash: "3f9a2c1",
+		Requests: []ReproRequest{{
+			Provider: "openai", Model: "gpt-4o", Temperature: &temperature, Seed: &seed,
+			PromptSHA256: HashText("prompt"), OutputSHA256: HashText("feat: x"),
+		}},
+	}
+	// A second report replaces the first rather than failing.
+	for i := 0; i < 2; i++ {
+		if err := AddReproNote(ctx, "HEAD", report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := runGit(ctx, "notes", "--ref", ReproNotesRef, "show", "HEAD")
+	if err != nil {
+		t.Fatalf("git notes show: %s: %v", out, err)
+	}
+	var got ReproReport
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, report) {
+		t.Errorf("note = %+v, want %+v", got, report)
+	}
+	if log, _ := runGit(ctx, "log", "-1", "--format=%B"); log != "initial commit" {
+		t.Errorf("commit message changed to %q", log)
+	}
+}
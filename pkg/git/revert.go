@@ -0,0 +1,197 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CommitInfo is a lightweight view of an existing commit used to build prompts.
+type CommitInfo struct {
+	Hash      string
+	ShortHash string
+	Subject   string
+	Body      string
+	Message   string
+	Author    string
+	Date      time.Time
+	Diff      string
+	// Parents is the number of parents; more than one makes it a merge.
+	Parents int
+}
+
+// GetCommitInfo resolves rev (hash, tag, HEAD~1, ...) and returns its metadata and patch.
+func GetCommitInfo(ctx context.Context, rev string) (*CommitInfo, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %q: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commit %s: %w", hash, err)
+	}
+	return newCommitInfo(commit)
+}
+
+// newCommitInfo builds a CommitInfo, including the patch, from a loaded commit.
+func newCommitInfo(commit *object.Commit) (*CommitInfo, error) {
+	diff, err := commitPatch(commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute diff for %s: %w", commit.Hash, err)
+	}
+	info := commitMeta(commit)
+	info.Diff = diff
+	return &info, nil
+}
+
+// commitMeta builds a CommitInfo without the patch.
+func commitMeta(commit *object.Commit) CommitInfo {
+	subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return CommitInfo{
+		Hash:      commit.Hash.String(),
+		ShortHash: commit.Hash.String()[:7],
+		Subject:   strings.TrimSpace(subject),
+		Body:      strings.TrimSpace(body),
+		Message:   commit.Message,
+		Author:    commit.Author.Name,
+		Date:      commit.Author.When,
+		Parents:   commit.NumParents(),
+	}
+}
+
+// commitPatch returns the textual patch a commit introduced relative to its first parent.
+func commitPatch(commit *object.Commit) (string, error) {
+	if commit.NumParents() == 0 {
+		tree, err := commit.Tree()
+		if err != nil {
+			return "", err
+		}
+		patch, err := (&object.Tree{}).Patch(tree)
+		if err != nil {
+			return "", err
+		}
+		return redactPrivateFiles(patch.String(), nil), nil
+	}
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return "", err
+	}
+	patch, err := parent.Patch(commit)
+	if err != nil {
+		return "", err
+	}
+	return redactPrivateFiles(patch.String(), nil), nil
+}
+
+// ParentDiff returns the patch the commit hash introduced relative to its
+// parent number parent (1 for the first), as `git revert -m parent` undoes it.
+func ParentDiff(hash string, parent int) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	commit, err := repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return "", fmt.Errorf("failed to load commit %s: %w", hash, err)
+	}
+	if parent < 1 || parent > commit.NumParents() {
+		return "", fmt.Errorf("commit %s has no parent %d", hash[:7], parent)
+	}
+	base, err := commit.Parent(parent - 1)
+	if err != nil {
+		return "", err
+	}
+	patch, err := base.Patch(commit)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute diff for %s: %w", hash, err)
+	}
+	return redactPrivateFiles(patch.String(), nil), nil
+}
+
+// CheckCleanIndex reports staged changes, which a revert must not be mixed with.
+func CheckCleanIndex() error {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	for path, fs := range status {
+		if fs.Staging != gogit.Unmodified && fs.Staging != gogit.Untracked {
+			return fmt.Errorf("staged changes present (%s); commit or stash them before reverting", path)
+		}
+	}
+	return nil
+}
+
+// RevertCommit stages the inverse of the given commit without committing it;
+// mainline picks the parent whose side a merge commit keeps (0 for a regular
+// commit). The index must be clean so the revert is not mixed with unrelated
+// staged work. On success the in-progress revert state is cleared, leaving a
+// regular set of staged changes that CommitChanges can record with a generated
+// message, or AbortRevert can drop.
+func RevertCommit(ctx context.Context, hash string, mainline int) error {
+	if err := CheckCleanIndex(); err != nil {
+		return err
+	}
+	args := []string{"revert", "--no-commit"}
+	if mainline > 0 {
+		args = append(args, "-m", strconv.Itoa(mainline))
+	}
+	if out, err := runGit(ctx, append(args, hash)...); err != nil {
+		_, _ = runGit(ctx, "revert", "--abort")
+		return fmt.Errorf("git revert failed: %s: %w", out, err)
+	}
+	if out, err := runGit(ctx, "revert", "--quit"); err != nil {
+		return fmt.Errorf("failed to clear revert state: %s: %w", out, err)
+	}
+	return nil
+}
+
+// AbortRevert drops a revert staged by RevertCommit: the index and the files
+// the revert changed go back to HEAD, while unstaged changes to other files
+// are kept.
+func AbortRevert(ctx context.Context) error {
+	if out, err := runGit(ctx, "reset", "--merge"); err != nil {
+		return fmt.Errorf("failed to undo the revert: %s: %w", out, err)
+	}
+	return nil
+}
+
+// EnsureRevertTrailer appends git's standard "This reverts commit <hash>." line
+// when the message does not already reference the reverted commit.
+func EnsureRevertTrailer(message, hash string) string {
+	message = strings.TrimSpace(message)
+	trailer := fmt.Sprintf("This reverts commit %s.", hash)
+	if strings.Contains(message, hash) {
+		return message
+	}
+	return message + "\n\n" + trailer
+}
+
+// runGit executes a git subcommand at the top of the work tree and returns its combined output.
+func runGit(ctx context.Context, args ...string) (string, error) {
+	cmd := gitCommand(ctx, args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+func TestEnsureRevertTrailer(t *testing.T) {
+	t.Parallel()
+	hash := "0123456789abcdef0123456789abcdef01234567"
+
+	got := EnsureRevertTrailer("revert: add cache\n\nCaused stale reads.", hash)
+	if !strings.HasSuffix(got, "This reverts commit "+hash+".") {
+		t.Errorf("expected trailer to be appended, got %q", got)
+	}
+
+	already := "revert: add cache\n\nThis reverts commit " + hash + "."
+	if EnsureRevertTrailer(already, hash) != already {
+		t.Error("expected message that already references the hash to be unchanged")
+	}
+}
+
+func TestRevertCommit_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("feature.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitChanges(context.Background(), "feat: add feature file"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := GetCommitInfo(context.Background(), "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Subject != "feat: add feature file" {
+		t.Errorf("Subject = %q", info.Subject)
+	}
+	if !strings.Contains(info.Diff, "feature.txt") {
+		t.Errorf("expected diff to mention feature.txt, got %q", info.Diff)
+	}
+
+	if err := RevertCommit(context.Background(), info.Hash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("expected feature.txt to be removed by the revert")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "REVERT_HEAD")); !os.IsNotExist(err) {
+		t.Error("expected revert state to be cleared")
+	}
+	if err := CheckCleanIndex(); err == nil {
+		t.Error("CheckCleanIndex() = nil with the revert staged")
+	}
+
+	if err := AbortRevert(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+		t.Errorf("AbortRevert did not restore feature.txt: %v", err)
+	}
+	if err := CheckCleanIndex(); err != nil {
+		t.Errorf("index after AbortRevert: %v", err)
+	}
+	if info.Parents != 1 {
+		t.Errorf("Parents = %d, want 1", info.Parents)
+	}
+	if _, err := ParentDiff(info.Hash, 2); err == nil {
+		t.Error("ParentDiff of a missing parent should fail")
+	}
+}
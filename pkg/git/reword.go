@@ -0,0 +1,177 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// defaultRangeLimit bounds RangeCommits when no start of the range is given.
+const defaultRangeLimit = 50
+
+// RangeCommits returns the non-merge commits of a "from..to" range along the
+// first-parent history of to, oldest first, without patches. A missing to (or a
+// bare "from") means HEAD; an empty rangeSpec means the last 50 commits of HEAD.
+func RangeCommits(ctx context.Context, rangeSpec string) ([]CommitInfo, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	from, to := rangeSpec, "HEAD"
+	if before, after, ok := strings.Cut(rangeSpec, ".."); ok {
+		from = before
+		if strings.TrimSpace(after) != "" {
+			to = after
+		}
+	}
+
+	toHash, err := repo.ResolveRevision(plumbing.Revision(strings.TrimSpace(to)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %q: %w", to, err)
+	}
+	stop := plumbing.ZeroHash
+	if strings.TrimSpace(from) != "" {
+		fromHash, err := repo.ResolveRevision(plumbing.Revision(strings.TrimSpace(from)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %q: %w", from, err)
+		}
+		stop = *fromHash
+	}
+
+	var commits []CommitInfo
+	c, err := repo.CommitObject(*toHash)
+	for err == nil && c.Hash != stop {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if stop.IsZero() && len(commits) >= defaultRangeLimit {
+			break
+		}
+		if c.NumParents() <= 1 {
+			commits = append(commits, commitMeta(c))
+		}
+		if c.NumParents() == 0 {
+			if !stop.IsZero() {
+				return nil, fmt.Errorf("%q is not on the first-parent history of %q", from, to)
+			}
+			break
+		}
+		c, err = c.Parent(0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// RewordCommits replaces the messages of the given commits (keyed by full hash)
+// by recreating them and every later commit on HEAD's first-parent history with
+// the same trees. HEAD's branch is moved to the new tip and the old tip is kept in
+// ORIG_HEAD. Rewritten commits that were signed, or all of them with
+// commit.gpgSign, are signed again with the signing setup of the git config.
+// Merge commits cannot be rewritten. It returns the new HEAD hash.
+func RewordCommits(ctx context.Context, messages map[string]string) (string, error) {
+	repo, err := DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if len(messages) == 0 {
+		return head.Hash().String(), nil
+	}
+
+	// Walk back from HEAD until every commit to reword has been seen.
+	var chain []*object.Commit
+	remaining := len(messages)
+	c, err := repo.CommitObject(head.Hash())
+	for err == nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		if c.NumParents() > 1 {
+			return "", fmt.Errorf("cannot reword across merge commit %s", c.Hash.String()[:7])
+		}
+		chain = append(chain, c)
+		if _, ok := messages[c.Hash.String()]; ok {
+			if remaining--; remaining == 0 {
+				break
+			}
+		}
+		if c.NumParents() == 0 {
+			return "", fmt.Errorf("some commits to reword are not on HEAD's first-parent history")
+		}
+		c, err = c.Parent(0)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+
+	signAll, err := signByDefault(ctx)
+	if err != nil {
+		return "", err
+	}
+	var signer *commitSigner
+	var parent plumbing.Hash
+	for i := len(chain) - 1; i >= 0; i-- {
+		rewritten := *chain[i]
+		if msg, ok := messages[rewritten.Hash.String()]; ok {
+			// Keep the Gerrit Change-Id so the reworded commit updates its change.
+			if id := FindChangeID(rewritten.Message); id != "" && FindChangeID(msg) == "" {
+				msg = AppendTrailer(msg, "Change-Id: "+id)
+			}
+			rewritten.Message = strings.TrimSpace(msg) + "\n"
+		}
+		if i < len(chain)-1 {
+			rewritten.ParentHashes = []plumbing.Hash{parent}
+		}
+		if rewritten.PGPSignature != "" || signAll {
+			if signer == nil {
+				if signer, err = rewordSigner(ctx); err != nil {
+					return "", fmt.Errorf("cannot re-sign %s: %w", rewritten.Hash.String()[:7], err)
+				}
+			}
+			if err := signCommit(signer, &rewritten); err != nil {
+				return "", fmt.Errorf("cannot re-sign %s: %w", rewritten.Hash.String()[:7], err)
+			}
+		}
+		obj := repo.Storer.NewEncodedObject()
+		if err := rewritten.Encode(obj); err != nil {
+			return "", fmt.Errorf("failed to encode commit: %w", err)
+		}
+		if parent, err = repo.Storer.SetEncodedObject(obj); err != nil {
+			return "", fmt.Errorf("failed to store commit: %w", err)
+		}
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("ORIG_HEAD"), head.Hash())); err != nil {
+		return "", fmt.Errorf("failed to save ORIG_HEAD: %w", err)
+	}
+	name := plumbing.HEAD
+	if head.Name().IsBranch() {
+		name = head.Name()
+	}
+	if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(name, parent), head); err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", name.Short(), err)
+	}
+	return parent.String(), nil
+}
+
+// rewordSigner returns the signer of rewritten commits, signing as the current
+// committer like `git rebase` does.
+func rewordSigner(ctx context.Context) (*commitSigner, error) {
+	_, committer, err := commitSignatures(CommitOptions{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return newCommitSigner(ctx, committer)
+}
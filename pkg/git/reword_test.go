@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestRewordCommits_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := CommitChanges(context.Background(), "add "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldHead, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldTip, err := repo.CommitObject(oldHead.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := RangeCommits(context.Background(), "HEAD~2..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "add b.txt" || commits[1].Subject != "add c.txt" {
+		t.Fatalf("unexpected range %+v", commits)
+	}
+
+	newHead, err := RewordCommits(context.Background(), map[string]string{commits[0].Hash: "feat: add b.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash().String() != newHead || !head.Name().IsBranch() {
+		t.Fatalf("expected the branch to point at %s, got %s (%s)", newHead, head.Hash(), head.Name())
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Message != "add c.txt" || tip.TreeHash != oldTip.TreeHash {
+		t.Errorf("expected the tip to keep its message and tree, got %q", tip.Message)
+	}
+	parent, err := tip.Parent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent.Message != "feat: add b.txt\n" {
+		t.Errorf("expected the reworded message, got %q", parent.Message)
+	}
+	grandparent, err := parent.Parent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grandparent.Message != "add a.txt" {
+		t.Errorf("expected older commits to be untouched, got %q", grandparent.Message)
+	}
+
+	orig, err := repo.Reference(plumbing.ReferenceName("ORIG_HEAD"), false)
+	if err != nil || orig.Hash() != oldHead.Hash() {
+		t.Errorf("expected ORIG_HEAD to keep the old tip, got %v, %v", orig, err)
+	}
+}
@@ -0,0 +1,184 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// sshKeyPrefix marks a user.signingKey holding a literal SSH public key rather
+// than the path of a key file.
+const sshKeyPrefix = "key::"
+
+// commitSigner signs commits like `git commit -S`, with the format, program,
+// and key of the git config: gpg.format, gpg.program (or
+// gpg.<format>.program), and user.signingKey. It implements go-git's Signer.
+type commitSigner struct {
+	ctx     context.Context
+	format  string
+	program string
+	key     string
+}
+
+// newCommitSigner reads the signing setup from the git config. Without
+// user.signingKey, OpenPGP and X.509 sign as committer, as git does; SSH
+// signing needs the key.
+func newCommitSigner(ctx context.Context, committer *object.Signature) (*commitSigner, error) {
+	lookup := func(key string) (string, error) { return gitConfigValue(ctx, key) }
+	format, err := lookup("gpg.format")
+	if err != nil {
+		return nil, err
+	}
+	s := &commitSigner{ctx: ctx, format: strings.ToLower(firstNonEmpty(format, "openpgp"))}
+	if s.key, err = lookup("user.signingKey"); err != nil {
+		return nil, err
+	}
+
+	programs := []string{"gpg." + s.format + ".program"}
+	var fallback string
+	switch s.format {
+	case "openpgp":
+		programs, fallback = append(programs, "gpg.program"), "gpg"
+	case "x509":
+		fallback = "gpgsm"
+	case "ssh":
+		fallback = "ssh-keygen"
+		if s.key == "" {
+			return nil, errors.New(`signing with SSH needs user.signingKey: a public key file or "key::<public key>"`)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported gpg.format %q (use openpgp, x509, or ssh)", format)
+	}
+	for _, name := range programs {
+		if s.program, err = lookup(name); err != nil {
+			return nil, err
+		}
+		if s.program != "" {
+			break
+		}
+	}
+	s.program = firstNonEmpty(s.program, fallback)
+	if s.key == "" {
+		s.key = fmt.Sprintf("%s <%s>", committer.Name, committer.Email)
+	}
+	return s, nil
+}
+
+// Sign returns an armored detached signature of message.
+func (s *commitSigner) Sign(message io.Reader) ([]byte, error) {
+	args := []string{"--status-fd=2", "-bsau", s.key}
+	if s.format == "ssh" {
+		keyFile, literal, cleanup, err := sshSigningKeyFile(s.key)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		args = []string{"-Y", "sign", "-n", "git", "-f", keyFile}
+		if literal {
+			// A literal key has its private half in the SSH agent.
+			args = append(args, "-U")
+		}
+	}
+
+	cmd := exec.CommandContext(s.ctx, s.program, args...)
+	cmd.Stdin = message
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	signature, err := cmd.Output()
+	if err == nil && len(bytes.TrimSpace(signature)) == 0 {
+		err = errors.New("no signature written")
+	}
+	if err != nil {
+		if detail := signerOutput(stderr.String()); detail != "" {
+			return nil, fmt.Errorf("%s failed to sign the commit: %s", s.program, detail)
+		}
+		return nil, fmt.Errorf("%s failed to sign the commit: %w", s.program, err)
+	}
+	return signature, nil
+}
+
+// sshSigningKeyFile returns a file holding the user.signingKey key for
+// ssh-keygen, writing a literal key to a temporary file that cleanup removes.
+func sshSigningKeyFile(key string) (path string, literal bool, cleanup func(), err error) {
+	if !strings.HasPrefix(key, sshKeyPrefix) {
+		return key, false, func() {}, nil
+	}
+	f, err := os.CreateTemp("", "ai-commit-signing-key-*.pub")
+	if err != nil {
+		return "", false, nil, fmt.Errorf("failed to write the SSH signing key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.TrimSpace(strings.TrimPrefix(key, sshKeyPrefix)) + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", false, nil, fmt.Errorf("failed to write the SSH signing key: %w", err)
+	}
+	return f.Name(), true, func() { os.Remove(f.Name()) }, nil
+}
+
+// signerOutput drops the "[GNUPG:]" status lines gpg writes with --status-fd,
+// keeping its human-readable messages.
+func signerOutput(stderr string) string {
+	var lines []string
+	for _, line := range strings.Split(stderr, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "[GNUPG:]") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
+
+// signByDefault reports whether commit.gpgSign asks for every commit to be
+// signed, as `git commit` does without -S.
+func signByDefault(ctx context.Context) (bool, error) {
+	out, err := gitCommand(ctx, "config", "--type=bool", "--get", "commit.gpgSign").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil // not set
+		}
+		return false, fmt.Errorf("failed to read commit.gpgSign: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// signCommit sets the signature of c, made by signer over c without it.
+func signCommit(signer *commitSigner, c *object.Commit) error {
+	c.PGPSignature = ""
+	obj := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(obj); err != nil {
+		return fmt.Errorf("failed to encode commit: %w", err)
+	}
+	r, err := obj.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	signature, err := signer.Sign(r)
+	if err != nil {
+		return err
+	}
+	c.PGPSignature = string(signature)
+	return nil
+}
+
+// gitConfigValue returns the value of a git config key, or "" when it is unset.
+// Paths starting with "~" are expanded, as for user.signingKey files.
+func gitConfigValue(ctx context.Context, key string) (string, error) {
+	out, err := gitCommand(ctx, "config", "--path", "--get", key).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil // not set
+		}
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,151 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// fakeSigner writes a script that records its arguments to args and prints a
+// fixed signature, standing in for gpg or ssh-keygen.
+func fakeSigner(t *testing.T, dir string) (program, args string) {
+	t.Helper()
+	program = filepath.Join(dir, "fake-signer")
+	args = filepath.Join(dir, "signer-args")
+	script := "#!/bin/sh\ncat >/dev/null\necho \"$@\" > " + args + "\n" +
+		"printf -- '-----BEGIN SIGNATURE-----\\nfake\\n-----END SIGNATURE-----\\n'\n"
+	if err := os.WriteFile(program, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return program, args
+}
+
+func gitConfig(t *testing.T, dir string, kv ...string) {
+	t.Helper()
+	for i := 0; i+1 < len(kv); i += 2 {
+		if out, err := exec.Command("git", "-C", dir, "config", kv[i], kv[i+1]).CombinedOutput(); err != nil {
+			t.Fatalf("git config %s: %s", kv[i], out)
+		}
+	}
+}
+
+func TestSignedCommit_Integration(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   []string
+		wantArgs string
+	}{
+		{
+			name:     "gpg signs as the committer",
+			config:   []string{"gpg.program"},
+			wantArgs: "--status-fd=2 -bsau Test Signer <signer@example.com>",
+		},
+		{
+			name:     "ssh key in the agent",
+			config:   []string{"gpg.ssh.program", "gpg.format", "ssh", "user.signingKey", "key::ssh-ed25519 AAAAC3Nza test"},
+			wantArgs: "-Y sign -n git -f ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := initTestRepo(t)
+			program, argsFile := fakeSigner(t, t.TempDir())
+			gitConfig(t, dir, append([]string{tt.config[0], program}, tt.config[1:]...)...)
+			t.Setenv("GIT_COMMITTER_NAME", "Test Signer")
+			t.Setenv("GIT_COMMITTER_EMAIL", "signer@example.com")
+
+			origDir, _ := os.Getwd()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(origDir)
+
+			if err := CommitChangesWithOptions(context.Background(), "chore: signed", CommitOptions{AllowEmpty: true, Sign: true}); err != nil {
+				t.Fatal(err)
+			}
+			repo, err := gogit.PlainOpen(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			head, _ := repo.Head()
+			commit, err := repo.CommitObject(head.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(commit.PGPSignature, "fake") {
+				t.Errorf("signature = %q, want the signer's output", commit.PGPSignature)
+			}
+			args, _ := os.ReadFile(argsFile)
+			if !strings.HasPrefix(string(args), tt.wantArgs) {
+				t.Errorf("signer args = %q, want prefix %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNewCommitSignerErrors_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	gitConfig(t, dir, "gpg.format", "ssh")
+	if err := CommitChangesWithOptions(context.Background(), "chore: x", CommitOptions{AllowEmpty: true, Sign: true}); err == nil || !strings.Contains(err.Error(), "user.signingKey") {
+		t.Errorf("SSH signing without a key = %v, want an error naming user.signingKey", err)
+	}
+	gitConfig(t, dir, "gpg.format", "pgp")
+	if err := CommitChangesWithOptions(context.Background(), "chore: x", CommitOptions{AllowEmpty: true, Sign: true}); err == nil || !strings.Contains(err.Error(), "unsupported gpg.format") {
+		t.Errorf("unknown format = %v, want an unsupported gpg.format error", err)
+	}
+}
+
+func TestSignByDefaultAndReword_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	program, _ := fakeSigner(t, t.TempDir())
+	gitConfig(t, dir, "gpg.program", program, "commit.gpgSign", "true")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Signer")
+	t.Setenv("GIT_COMMITTER_EMAIL", "signer@example.com")
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := CommitChangesWithOptions(context.Background(), "chore: signed by config", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := repo.Head()
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(commit.PGPSignature, "fake") {
+		t.Fatalf("signature = %q, want commit.gpgSign to sign the commit", commit.PGPSignature)
+	}
+
+	gitConfig(t, dir, "commit.gpgSign", "false")
+	newHead, err := RewordCommits(context.Background(), map[string]string{commit.Hash.String(): "chore: reworded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reworded, err := repo.CommitObject(plumbing.NewHash(newHead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reworded.Message != "chore: reworded\n" || !strings.Contains(reworded.PGPSignature, "fake") {
+		t.Errorf("reworded commit = %q signed %q, want the new message and a new signature", reworded.Message, reworded.PGPSignature)
+	}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// trailerLine matches a git trailer such as "Signed-off-by: Name <email>" or "Refs: PROJ-1",
+// or a Conventional Commits "BREAKING CHANGE:" footer.
+var trailerLine = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z0-9][A-Za-z0-9-]*): \S`)
+
+// AppendSignoff adds a "Signed-off-by: name <email>" trailer for the DCO.
+func AppendSignoff(message, name, email string) string {
+	return AppendTrailer(message, fmt.Sprintf("Signed-off-by: %s <%s>", name, email))
+}
+
+// AppendSignoffFor adds the sign-off of the author a commit made with opts
+// would have, for messages printed rather than committed.
+func AppendSignoffFor(message string, opts CommitOptions) (string, error) {
+	author, _, err := commitSignatures(opts, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return AppendSignoff(message, author.Name, author.Email), nil
+}
+
+// AppendTrailer adds a "Key: value" trailer line to message. It joins an existing
+// trailer block at the end of the message and does nothing if the exact trailer
+// is already present.
+func AppendTrailer(message, trailer string) string {
+	message = strings.TrimSpace(message)
+	lines := strings.Split(message, "\n")
+	for _, line := range lines {
+		if strings.TrimSpace(line) == trailer {
+			return message
+		}
+	}
+	if message == "" {
+		return trailer
+	}
+	if endsWithTrailerBlock(lines) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+// Trailers returns the git trailers of message's final trailer block, such as
+// "Signed-off-by", "Change-Id", or "Co-authored-by", each with its
+// continuation lines. "BREAKING CHANGE" footers describe the change and are
+// not included.
+func Trailers(message string) []string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	if !endsWithTrailerBlock(lines) {
+		return nil
+	}
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	var trailers []string
+	skip := false
+	for _, line := range lines[start:] {
+		if line[0] == ' ' || line[0] == '\t' {
+			if !skip && len(trailers) > 0 {
+				trailers[len(trailers)-1] += "\n" + line
+			}
+			continue
+		}
+		skip = strings.HasPrefix(line, "BREAKING")
+		if !skip {
+			trailers = append(trailers, line)
+		}
+	}
+	return trailers
+}
+
+// KeepTrailers appends the trailers of original (see Trailers) that message
+// lacks, so a regenerated message keeps its sign-offs and Change-Id.
+func KeepTrailers(message, original string) string {
+	for _, trailer := range Trailers(original) {
+		message = AppendTrailer(message, trailer)
+	}
+	return message
+}
+
+// endsWithTrailerBlock reports whether the last paragraph of a multi-paragraph
+// message consists only of trailers.
+func endsWithTrailerBlock(lines []string) bool {
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == 0 {
+		// The message is a single paragraph, so its last lines are the subject/body.
+		return false
+	}
+	for i, line := range lines[start:] {
+		continuation := i > 0 && (line[0] == ' ' || line[0] == '\t')
+		if !continuation && !trailerLine.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package git
+
+import "testing"
+
+func TestAppendSignoff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{
+			name: "subject only",
+			msg:  "feat: add login",
+			want: "feat: add login\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name: "joins existing trailers",
+			msg:  "feat: add login\n\nBody text.\n\nRefs: PROJ-1",
+			want: "feat: add login\n\nBody text.\n\nRefs: PROJ-1\nSigned-off-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name: "body that looks like a trailer is not joined",
+			msg:  "fix: handle note: empty input",
+			want: "fix: handle note: empty input\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name: "already signed off",
+			msg:  "fix: typo\n\nSigned-off-by: Jane Doe <jane@example.com>\n",
+			want: "fix: typo\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		},
+	}
+	for _, tc := range tests {
+		if got := AppendSignoff(tc.msg, "Jane Doe", "jane@example.com"); got != tc.want {
+			t.Errorf("%s: AppendSignoff() =\n%q\nwant\n%q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAppendSignoffFor(t *testing.T) {
+	got, err := AppendSignoffFor("feat: add login", CommitOptions{AuthorName: "Jane Doe", AuthorEmail: "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "feat: add login\n\nSigned-off-by: Jane Doe <jane@example.com>"; got != want {
+		t.Errorf("AppendSignoffFor() = %q, want %q", got, want)
+	}
+}
+
+func TestProvenanceTrailer(t *testing.T) {
+	t.Parallel()
+	p := Provenance{Provider: "openai", Model: "gpt-4o", TemplateHash: "abc1234", Version: "1.4.0"}
+	if got, want := p.Trailer(), "X-AI-Commit: openai/gpt-4o tmpl=abc1234 v1.4.0"; got != want {
+		t.Errorf("Trailer() = %q, want %q", got, want)
+	}
+	if got, want := (Provenance{Provider: "ollama", Version: "dev"}).Trailer(), "X-AI-Commit: ollama dev"; got != want {
+		t.Errorf("Trailer() = %q, want %q", got, want)
+	}
+
+	msg := AppendSignoff(AppendTrailer("feat: add login", p.Trailer()), "Jane Doe", "jane@example.com")
+	want := "feat: add login\n\nX-AI-Commit: openai/gpt-4o tmpl=abc1234 v1.4.0\nSigned-off-by: Jane Doe <jane@example.com>"
+	if msg != want {
+		t.Errorf("trailers =\n%q\nwant\n%q", msg, want)
+	}
+}
+
+func TestKeepTrailers(t *testing.T) {
+	t.Parallel()
+	original := "fix: old wording\n\nBody.\n\nBREAKING CHANGE: drops v1\nChange-Id: I0123456789abcdef0123456789abcdef01234567\nCo-authored-by: Ann <ann@example.com>\nSigned-off-by: Jane Doe <jane@example.com>"
+	got := KeepTrailers("fix(parser): handle empty input\n\nSigned-off-by: Jane Doe <jane@example.com>", original)
+	want := "fix(parser): handle empty input\n\nSigned-off-by: Jane Doe <jane@example.com>\nChange-Id: I0123456789abcdef0123456789abcdef01234567\nCo-authored-by: Ann <ann@example.com>"
+	if got != want {
+		t.Errorf("KeepTrailers() =\n%q\nwant\n%q", got, want)
+	}
+	if got := KeepTrailers("feat: x", "feat: y\n\nNo trailers here."); got != "feat: x" {
+		t.Errorf("KeepTrailers() without trailers = %q", got)
+	}
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// UnstagedTrackedFiles lists the tracked files with modifications or
+// deletions that are not staged, relative to the top of the work tree.
+func UnstagedTrackedFiles(ctx context.Context) ([]string, error) {
+	out, err := runGit(ctx, "diff", "--name-only", "--no-renames")
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %s: %w", out, err)
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// StageTracked stages every modification and deletion of tracked files, as
+// "git commit -a" does; untracked files stay untracked.
+func StageTracked(ctx context.Context) error {
+	if out, err := runGit(ctx, "add", "--update", "--", "."); err != nil {
+		return fmt.Errorf("git add --update failed: %s: %w", out, err)
+	}
+	return nil
+}
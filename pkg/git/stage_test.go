@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStageTracked_Integration(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	ctx := context.Background()
+
+	if files, err := UnstagedTrackedFiles(ctx); err != nil || len(files) != 0 {
+		t.Fatalf("UnstagedTrackedFiles() in a clean tree = %q, %v", files, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Staging from a subdirectory still covers the whole work tree.
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := UnstagedTrackedFiles(ctx)
+	if err != nil || !reflect.DeepEqual(files, []string{"README.md"}) {
+		t.Fatalf("UnstagedTrackedFiles() = %q, %v; want README.md", files, err)
+	}
+	if err := StageTracked(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if staged, err := runGit(ctx, "diff", "--cached", "--name-only"); err != nil || staged != "README.md" {
+		t.Errorf("staged %q (%v), want only README.md", staged, err)
+	}
+	if files, _ := UnstagedTrackedFiles(ctx); len(files) != 0 {
+		t.Errorf("UnstagedTrackedFiles() after staging = %q", files)
+	}
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Todo is a TODO-style marker on a line a diff adds.
+type Todo struct {
+	Path string
+	// Line is the line number in the new version of the file.
+	Line int
+	// Kind is the marker: TODO, FIXME, HACK, or XXX.
+	Kind string
+	Text string
+}
+
+func (t Todo) String() string {
+	if t.Text == "" {
+		return fmt.Sprintf("%s %s:%d", t.Kind, t.Path, t.Line)
+	}
+	return fmt.Sprintf("%s %s:%d: %s", t.Kind, t.Path, t.Line, t.Text)
+}
+
+var (
+	// todoMarker matches a marker with an optional "(owner)" and colon, and the
+	// text after it.
+	todoMarker = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX)\b(?:\([^)]*\))?:?\s*(.*)`)
+	// newHunkStart reads the first new-file line number of a hunk header.
+	newHunkStart = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)`)
+	// commentCloser trails the text of markers in block comments.
+	commentCloser = regexp.MustCompile(`\s*(\*/|-->|#\}|%\}\}?)\s*$`)
+)
+
+// AddedTodos returns the TODO, FIXME, HACK, and XXX markers on the lines diff
+// adds, in diff order. Pass the unfiltered diff: the comment filter of the
+// prompt diff drops most of them.
+func AddedTodos(diff string) []Todo {
+	var todos []Todo
+	path, line := "", 0
+	for _, l := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			path, line = parseFilePath(l), 0
+		case strings.HasPrefix(l, "+++ "), strings.HasPrefix(l, "--- "):
+		case strings.HasPrefix(l, "@@"):
+			if m := newHunkStart.FindStringSubmatch(l); m != nil {
+				line, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(l, "+"):
+			if m := todoMarker.FindStringSubmatch(l[1:]); m != nil && path != "" {
+				text := commentCloser.ReplaceAllString(strings.TrimSpace(m[2]), "")
+				todos = append(todos, Todo{Path: path, Line: line, Kind: m[1], Text: text})
+			}
+			line++
+		case strings.HasPrefix(l, " "):
+			line++
+		}
+	}
+	return todos
+}
+
+// AppendTodoSection appends a "TODO:" section listing todos to the body of
+// message, before its trailers. A message without todos is returned unchanged.
+func AppendTodoSection(message string, todos []Todo) string {
+	if len(todos) == 0 {
+		return message
+	}
+	var b strings.Builder
+	b.WriteString("TODO:")
+	for _, t := range todos {
+		b.WriteString("\n- " + t.String())
+	}
+	body, footers := splitFooters(message)
+	return joinFooters(body+"\n\n"+b.String(), footers)
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+const todoDiff = `diff --git a/pkg/retry.go b/pkg/retry.go
+index 1..2 100644
+--- a/pkg/retry.go
++++ b/pkg/retry.go
+@@ -10,3 +10,6 @@ func Retry() {
+ 	n := 3
+-	// TODO: old marker, removed
++	// TODO(ana): make the attempts configurable
++	backoff()
++	/* FIXME handle context cancellation */
+ 	return
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -0,0 +1 @@
++Mentions todo lists and TODOS without a marker. XXX
+`
+
+func TestAddedTodos(t *testing.T) {
+	t.Parallel()
+	want := []Todo{
+		{Path: "pkg/retry.go", Line: 11, Kind: "TODO", Text: "make the attempts configurable"},
+		{Path: "pkg/retry.go", Line: 13, Kind: "FIXME", Text: "handle context cancellation"},
+		{Path: "README.md", Line: 1, Kind: "XXX"},
+	}
+	if got := AddedTodos(todoDiff); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddedTodos() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAppendTodoSection(t *testing.T) {
+	t.Parallel()
+	todos := []Todo{{Path: "a.go", Line: 3, Kind: "TODO", Text: "retry"}, {Path: "b.go", Line: 9, Kind: "FIXME"}}
+	tests := []struct{ msg, want string }{
+		{"feat: add retry", "feat: add retry\n\nTODO:\n- TODO a.go:3: retry\n- FIXME b.go:9"},
+		{"feat: add retry\n\nBody.\n\nRefs: #12", "feat: add retry\n\nBody.\n\nTODO:\n- TODO a.go:3: retry\n- FIXME b.go:9\n\nRefs: #12"},
+	}
+	for _, tt := range tests {
+		if got := AppendTodoSection(tt.msg, todos); got != tt.want {
+			t.Errorf("AppendTodoSection(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+	if got := AppendTodoSection("fix: x", nil); got != "fix: x" {
+		t.Errorf("no todos: %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/glob"
+)
+
+// DefaultTypeRules are the built-in file categories checked after the configured ones.
+var DefaultTypeRules = []config.TypeRule{
+	{Type: "test", Paths: []string{
+		"**/*_test.go", "**/test_*.py", "**/*_test.py", "**/*.test.*", "**/*.spec.*",
+		"**/test/**", "**/tests/**", "**/__tests__/**", "**/testdata/**", "**/spec/**",
+	}},
+	{Type: "ci", Paths: []string{
+		".github/workflows/**", ".github/actions/**", ".gitlab-ci.yml", ".gitlab/ci/**",
+		".circleci/**", ".travis.yml", "Jenkinsfile", "azure-pipelines.yml", ".buildkite/**",
+	}},
+	{Type: "docs", Paths: []string{
+		"**/*.md", "**/*.markdown", "**/*.rst", "**/*.adoc", "docs/**", "doc/**",
+	}},
+}
+
+// CategoryCommitType returns the commit type shared by every file changed in diff,
+// using the first rule (configured rules, then DefaultTypeRules) each file matches.
+// It reports false when a file matches no rule or files fall into different types.
+func CategoryCommitType(diff string, rules []config.TypeRule) (string, bool) {
+	all := append(append([]config.TypeRule{}, rules...), DefaultTypeRules...)
+	commitType := ""
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "diff --git ") {
+			continue
+		}
+		fileType := typeForFile(parseFilePath(line), all)
+		if fileType == "" || (commitType != "" && fileType != commitType) {
+			return "", false
+		}
+		commitType = fileType
+	}
+	return commitType, commitType != ""
+}
+
+func typeForFile(file string, rules []config.TypeRule) string {
+	if file == "" {
+		return ""
+	}
+	for _, rule := range rules {
+		for _, pattern := range rule.Paths {
+			if glob.Match(pattern, file) {
+				return rule.Type
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func diffForFiles(files ...string) string {
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString("diff --git a/" + f + " b/" + f + "\n+change\n")
+	}
+	return b.String()
+}
+
+func TestCategoryCommitType(t *testing.T) {
+	t.Parallel()
+	custom := []config.TypeRule{{Type: "build", Paths: []string{"Makefile", "scripts/**"}}}
+	tests := []struct {
+		name  string
+		files []string
+		rules []config.TypeRule
+		want  string
+	}{
+		{name: "only tests", files: []string{"pkg/git/git_test.go", "web/src/__tests__/App.js"}, want: "test"},
+		{name: "only ci", files: []string{".github/workflows/ci.yml"}, want: "ci"},
+		{name: "only docs", files: []string{"README.md", "docs/setup.txt"}, want: "docs"},
+		{name: "test markdown counts as test", files: []string{"pkg/testdata/golden.md", "pkg/x_test.go"}, want: "test"},
+		{name: "mixed categories", files: []string{"README.md", "pkg/x_test.go"}},
+		{name: "code change", files: []string{"pkg/x.go", "pkg/x_test.go"}},
+		{name: "custom rule", files: []string{"Makefile", "scripts/release.sh"}, rules: custom, want: "build"},
+		{name: "no files"},
+	}
+	for _, tc := range tests {
+		got, ok := CategoryCommitType(diffForFiles(tc.files...), tc.rules)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s: CategoryCommitType() = %q, %v; want %q", tc.name, got, ok, tc.want)
+		}
+	}
+}
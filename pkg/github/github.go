@@ -0,0 +1,221 @@
+// Package github implements the GitHub Actions side of "ai-commit ci-review":
+// reading the pull request from the workflow event, fetching its diff, posting
+// the review, and setting step outputs. It also creates the GitHub releases of
+// semantic-release.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultAPIURL is used when GITHUB_API_URL is not set.
+const DefaultAPIURL = "https://api.github.com"
+
+// PullRequest identifies the pull request a workflow runs for.
+type PullRequest struct {
+	Repo    string // "owner/name"
+	Number  int
+	HeadSHA string
+}
+
+// LoadPullRequest reads the pull request from the event payload at path (the
+// file named by GITHUB_EVENT_PATH). repo is used when the payload does not name
+// the repository. Events without a pull request are an error.
+func LoadPullRequest(path, repo string) (*PullRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event payload: %w", err)
+	}
+	var event struct {
+		PullRequest *struct {
+			Number int `json:"number"`
+			Head   struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse event payload: %w", err)
+	}
+	if event.PullRequest == nil || event.PullRequest.Number == 0 {
+		return nil, fmt.Errorf("event payload %s has no pull request (run on pull_request events or pass --patch)", path)
+	}
+	if event.Repository.FullName != "" {
+		repo = event.Repository.FullName
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("cannot tell the repository of the pull request (set GITHUB_REPOSITORY)")
+	}
+	return &PullRequest{Repo: repo, Number: event.PullRequest.Number, HeadSHA: event.PullRequest.Head.SHA}, nil
+}
+
+// Client is a minimal GitHub REST API client authenticated with a token.
+type Client struct {
+	token  string
+	apiURL string
+	http   *http.Client
+}
+
+// NewClient returns a client for apiURL (DefaultAPIURL when empty).
+func NewClient(token, apiURL string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{token: token, apiURL: strings.TrimRight(apiURL, "/"), http: http.DefaultClient}
+}
+
+// PullRequestDiff returns the unified diff of the pull request.
+func (c *Client) PullRequestDiff(ctx context.Context, pr PullRequest) (string, error) {
+	body, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", pr.Repo, pr.Number), "application/vnd.github.diff", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the pull request diff: %w", err)
+	}
+	return string(body), nil
+}
+
+// ReviewComment is a review comment on one line of the pull request's new version.
+type ReviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// CreateReview posts a review (without approving or requesting changes) with
+// body and line comments, and returns its URL.
+func (c *Client) CreateReview(ctx context.Context, pr PullRequest, body string, comments []ReviewComment) (string, error) {
+	payload := struct {
+		CommitID string          `json:"commit_id,omitempty"`
+		Body     string          `json:"body"`
+		Event    string          `json:"event"`
+		Comments []ReviewComment `json:"comments,omitempty"`
+	}{pr.HeadSHA, body, "COMMENT", comments}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls/%d/reviews", pr.Repo, pr.Number), "application/vnd.github+json", data)
+	if err != nil {
+		return "", fmt.Errorf("failed to post the review: %w", err)
+	}
+	var review struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(resp, &review); err != nil {
+		return "", fmt.Errorf("failed to parse the review response: %w", err)
+	}
+	return review.HTMLURL, nil
+}
+
+// Release is a GitHub release to create for an existing or new tag.
+type Release struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body,omitempty"`
+	Prerelease      bool   `json:"prerelease,omitempty"`
+}
+
+// CreateRelease publishes r in repo ("owner/name") and returns its URL.
+func (c *Client) CreateRelease(ctx context.Context, repo string, r Release) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/releases", repo), "application/vnd.github+json", data)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the release: %w", err)
+	}
+	var release struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(resp, &release); err != nil {
+		return "", fmt.Errorf("failed to parse the release response: %w", err)
+	}
+	return release.HTMLURL, nil
+}
+
+// RepoFromRemote returns the "owner/name" of a github.com remote URL in SSH
+// ("git@github.com:owner/name.git") or HTTPS form.
+func RepoFromRemote(url string) (string, bool) {
+	var rest string
+	for _, prefix := range []string{"git@github.com:", "ssh://git@github.com/", "https://github.com/", "http://github.com/"} {
+		if strings.HasPrefix(url, prefix) {
+			rest = strings.TrimPrefix(url, prefix)
+			break
+		}
+	}
+	rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+	owner, name, ok := strings.Cut(rest, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return rest, true
+}
+
+func (c *Client) do(ctx context.Context, method, path, accept string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// SetOutputs appends step outputs to the GITHUB_OUTPUT file at path, using the
+// delimiter syntax so values may span several lines.
+func SetOutputs(path string, outputs map[string]string) error {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		delim := "AI_COMMIT_EOF"
+		for strings.Contains(outputs[name], delim) {
+			delim += "_"
+		}
+		fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", name, delim, outputs[name], delim)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open step outputs: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write step outputs: %w", err)
+	}
+	return f.Close()
+}
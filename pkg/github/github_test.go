@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := a / b
+ 	fmt.Println(a, b)
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+
+func TestLoadPullRequest(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "event.json")
+	payload := `{"pull_request":{"number":42,"head":{"sha":"abc123"}},"repository":{"full_name":"octo/repo"}}`
+	if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := LoadPullRequest(path, "fallback/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (PullRequest{Repo: "octo/repo", Number: 42, HeadSHA: "abc123"}); *pr != want {
+		t.Errorf("LoadPullRequest() = %+v, want %+v", *pr, want)
+	}
+
+	push := filepath.Join(dir, "push.json")
+	if err := os.WriteFile(push, []byte(`{"ref":"refs/heads/main"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPullRequest(push, "octo/repo"); err == nil {
+		t.Error("expected an error for an event without a pull request")
+	}
+}
+
+func TestBuildReview(t *testing.T) {
+	t.Parallel()
+	findings := []review.Finding{
+		{Path: "main.go", Line: 13, Body: "division by zero"},
+		{Path: "main.go", Line: 40, Body: "outside the diff"},
+		{Body: "no tests"},
+	}
+	body, comments := BuildReview(findings, testDiff)
+	if want := []ReviewComment{{Path: "main.go", Line: 13, Side: "RIGHT", Body: "division by zero"}}; !reflect.DeepEqual(comments, want) {
+		t.Errorf("comments = %+v, want %+v", comments, want)
+	}
+	want := "**ai-commit review**: 3 finding(s), 1 as line comments.\n\n- `main.go:40`: outside the diff\n- no tests"
+	if body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	var posted struct {
+		CommitID string          `json:"commit_id"`
+		Event    string          `json:"event"`
+		Comments []ReviewComment `json:"comments"`
+	}
+	var release Release
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/repo/pulls/42":
+			if r.Header.Get("Accept") != "application/vnd.github.diff" {
+				http.Error(w, "want diff", http.StatusNotAcceptable)
+				return
+			}
+			io.WriteString(w, testDiff)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/repo/pulls/42/reviews":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `{"html_url":"https://github.com/octo/repo/pull/42#pullrequestreview-1"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/repo/releases":
+			if err := json.NewDecoder(r.Body).Decode(&release); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `{"html_url":"https://github.com/octo/repo/releases/tag/v1.2.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("tok", srv.URL)
+	pr := PullRequest{Repo: "octo/repo", Number: 42, HeadSHA: "abc123"}
+	diff, err := client.PullRequestDiff(context.Background(), pr)
+	if err != nil || diff != testDiff {
+		t.Fatalf("PullRequestDiff() = %q, %v", diff, err)
+	}
+	url, err := client.CreateReview(context.Background(), pr, "body", []ReviewComment{{Path: "main.go", Line: 13, Side: "RIGHT", Body: "x"}})
+	if err != nil || url != "https://github.com/octo/repo/pull/42#pullrequestreview-1" {
+		t.Fatalf("CreateReview() = %q, %v", url, err)
+	}
+	if posted.CommitID != "abc123" || posted.Event != "COMMENT" || len(posted.Comments) != 1 {
+		t.Errorf("posted review = %+v", posted)
+	}
+
+	url, err = client.CreateRelease(context.Background(), "octo/repo", Release{TagName: "v1.2.0", Body: "notes"})
+	if err != nil || url != "https://github.com/octo/repo/releases/tag/v1.2.0" {
+		t.Fatalf("CreateRelease() = %q, %v", url, err)
+	}
+	if release.TagName != "v1.2.0" || release.Body != "notes" {
+		t.Errorf("posted release = %+v", release)
+	}
+
+	if _, err := NewClient("wrong", srv.URL).PullRequestDiff(context.Background(), pr); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
+
+func TestSetOutputs(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "output")
+	if err := SetOutputs(path, map[string]string{"review": "line 1\nline 2", "findings": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "findings<<AI_COMMIT_EOF\n2\nAI_COMMIT_EOF\nreview<<AI_COMMIT_EOF\nline 1\nline 2\nAI_COMMIT_EOF\n"
+	if string(data) != want {
+		t.Errorf("outputs = %q, want %q", data, want)
+	}
+}
+
+func TestRepoFromRemote(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		url, want string
+		ok        bool
+	}{
+		{"git@github.com:octo/repo.git", "octo/repo", true},
+		{"https://github.com/octo/repo", "octo/repo", true},
+		{"ssh://git@github.com/octo/repo.git", "octo/repo", true},
+		{"https://gitlab.com/octo/repo.git", "", false},
+		{"https://github.com/octo", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := RepoFromRemote(tt.url); got != tt.want || ok != tt.ok {
+			t.Errorf("RepoFromRemote(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
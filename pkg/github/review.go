@@ -0,0 +1,37 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+// BuildReview turns findings into line comments where the line is part of diff;
+// the rest are listed in the review body.
+func BuildReview(findings []review.Finding, diff string) (string, []ReviewComment) {
+	if len(findings) == 0 {
+		return "**ai-commit review**: " + review.NoFindings, nil
+	}
+	lines := review.DiffLines(diff)
+	var comments []ReviewComment
+	var general []string
+	for _, f := range findings {
+		if _, ok := lines[f.Path][f.Line]; ok && f.Line > 0 {
+			comments = append(comments, ReviewComment{Path: f.Path, Line: f.Line, Side: "RIGHT", Body: f.Body})
+			continue
+		}
+		general = append(general, "- "+f.String())
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**ai-commit review**: %d finding(s)", len(findings))
+	if len(comments) > 0 {
+		fmt.Fprintf(&b, ", %d as line comments", len(comments))
+	}
+	b.WriteString(".")
+	if len(general) > 0 {
+		b.WriteString("\n\n" + strings.Join(general, "\n"))
+	}
+	return b.String(), comments
+}
@@ -0,0 +1,213 @@
+// Package gitlab posts review findings to GitLab merge requests as discussion
+// notes from a merge request pipeline.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+// MergeRequest identifies the merge request of a pipeline.
+type MergeRequest struct {
+	ProjectID string
+	IID       int
+}
+
+// MergeRequestFromEnv reads the merge request from the predefined CI variables,
+// which GitLab only sets in merge request pipelines.
+func MergeRequestFromEnv() (*MergeRequest, error) {
+	project := os.Getenv("CI_PROJECT_ID")
+	iid, _ := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID"))
+	if project == "" || iid == 0 {
+		return nil, fmt.Errorf("CI_PROJECT_ID and CI_MERGE_REQUEST_IID are not set (run in a merge request pipeline)")
+	}
+	return &MergeRequest{ProjectID: project, IID: iid}, nil
+}
+
+// Client is a minimal GitLab REST API client.
+type Client struct {
+	apiURL   string
+	token    string
+	jobToken string
+	http     *http.Client
+}
+
+// NewClientFromEnv returns a client for CI_API_V4_URL. It authenticates with
+// GITLAB_TOKEN when set and with the CI job token otherwise.
+func NewClientFromEnv() (*Client, error) {
+	apiURL := os.Getenv("CI_API_V4_URL")
+	if apiURL == "" {
+		return nil, fmt.Errorf("CI_API_V4_URL is not set")
+	}
+	return NewClient(apiURL, os.Getenv("GITLAB_TOKEN"), os.Getenv("CI_JOB_TOKEN")), nil
+}
+
+// NewClient returns a client for apiURL (e.g. https://gitlab.com/api/v4) using
+// token as a private/project access token, or jobToken when token is empty.
+func NewClient(apiURL, token, jobToken string) *Client {
+	return &Client{apiURL: strings.TrimRight(apiURL, "/"), token: token, jobToken: jobToken, http: http.DefaultClient}
+}
+
+// Diff is a merge request's changes as one unified diff.
+type Diff struct {
+	Text string
+	// OldPaths maps the new path of renamed files to their old path.
+	OldPaths map[string]string
+}
+
+// MergeRequestDiff fetches the changes of the merge request.
+func (c *Client) MergeRequestDiff(ctx context.Context, mr MergeRequest) (*Diff, error) {
+	diff := &Diff{OldPaths: make(map[string]string)}
+	var b strings.Builder
+	for page := "1"; page != ""; {
+		var files []struct {
+			OldPath     string `json:"old_path"`
+			NewPath     string `json:"new_path"`
+			NewFile     bool   `json:"new_file"`
+			DeletedFile bool   `json:"deleted_file"`
+			Diff        string `json:"diff"`
+		}
+		next, err := c.do(ctx, http.MethodGet, mr.path("/diffs?per_page=100&page="+page), nil, &files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the merge request diff: %w", err)
+		}
+		for _, f := range files {
+			oldPath, newPath := "a/"+f.OldPath, "b/"+f.NewPath
+			if f.NewFile {
+				oldPath = "/dev/null"
+			}
+			if f.DeletedFile {
+				newPath = "/dev/null"
+			}
+			if f.OldPath != f.NewPath {
+				diff.OldPaths[f.NewPath] = f.OldPath
+			}
+			fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- %s\n+++ %s\n%s", f.OldPath, f.NewPath, oldPath, newPath, f.Diff)
+			if !strings.HasSuffix(f.Diff, "\n") {
+				b.WriteString("\n")
+			}
+		}
+		page = next
+	}
+	diff.Text = b.String()
+	return diff, nil
+}
+
+type diffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+type position struct {
+	diffRefs
+	PositionType string `json:"position_type"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+	OldLine      int    `json:"old_line,omitempty"`
+}
+
+// PostFindings starts one discussion per finding. Findings on a line of diff
+// are attached to that line; the others, and any the API refuses to place, are
+// posted as general notes. It returns the number of discussions created.
+func (c *Client) PostFindings(ctx context.Context, mr MergeRequest, findings []review.Finding, diff *Diff) (int, error) {
+	var mrInfo struct {
+		DiffRefs diffRefs `json:"diff_refs"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, mr.path(""), nil, &mrInfo); err != nil {
+		return 0, fmt.Errorf("failed to read the merge request: %w", err)
+	}
+
+	lines := review.DiffLines(diff.Text)
+	posted := 0
+	for _, f := range findings {
+		if oldLine, ok := lines[f.Path][f.Line]; ok && f.Line > 0 {
+			oldPath := f.Path
+			if p, renamed := diff.OldPaths[f.Path]; renamed {
+				oldPath = p
+			}
+			pos := &position{
+				diffRefs:     mrInfo.DiffRefs,
+				PositionType: "text",
+				OldPath:      oldPath,
+				NewPath:      f.Path,
+				NewLine:      f.Line,
+				OldLine:      oldLine,
+			}
+			if err := c.createDiscussion(ctx, mr, "**ai-commit review**: "+f.Body, pos); err == nil {
+				posted++
+				continue
+			}
+		}
+		if err := c.createDiscussion(ctx, mr, "**ai-commit review**: "+f.String(), nil); err != nil {
+			return posted, err
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+func (c *Client) createDiscussion(ctx context.Context, mr MergeRequest, body string, pos *position) error {
+	payload := struct {
+		Body     string    `json:"body"`
+		Position *position `json:"position,omitempty"`
+	}{body, pos}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if _, err := c.do(ctx, http.MethodPost, mr.path("/discussions"), data, nil); err != nil {
+		return fmt.Errorf("failed to post a merge request note: %w", err)
+	}
+	return nil
+}
+
+func (mr MergeRequest) path(suffix string) string {
+	return fmt.Sprintf("/projects/%s/merge_requests/%d%s", url.PathEscape(mr.ProjectID), mr.IID, suffix)
+}
+
+// do sends a request, decodes the JSON response into out (when not nil), and
+// returns the X-Next-Page header.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	} else if c.jobToken != "" {
+		req.Header.Set("JOB-TOKEN", c.jobToken)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return "", fmt.Errorf("failed to parse %s response: %w", path, err)
+		}
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
@@ -0,0 +1,91 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+func TestMergeRequestFromEnv(t *testing.T) {
+	t.Setenv("CI_PROJECT_ID", "7")
+	t.Setenv("CI_MERGE_REQUEST_IID", "12")
+	mr, err := MergeRequestFromEnv()
+	if err != nil || *mr != (MergeRequest{ProjectID: "7", IID: 12}) {
+		t.Fatalf("MergeRequestFromEnv() = %+v, %v", mr, err)
+	}
+	t.Setenv("CI_MERGE_REQUEST_IID", "")
+	if _, err := MergeRequestFromEnv(); err == nil {
+		t.Error("expected an error outside merge request pipelines")
+	}
+}
+
+func TestPostFindings(t *testing.T) {
+	var notes []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("JOB-TOKEN") != "job" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/7/merge_requests/12/diffs":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				io.WriteString(w, `[{"old_path":"main.go","new_path":"main.go","diff":"@@ -10,2 +10,3 @@\n a := 1\n+b := a / 0\n c := 2\n"}]`)
+				return
+			}
+			io.WriteString(w, `[{"old_path":"old.go","new_path":"new.go","diff":"@@ -1 +1 @@\n-x\n+y\n"}]`)
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/7/merge_requests/12":
+			io.WriteString(w, `{"diff_refs":{"base_sha":"b","head_sha":"h","start_sha":"s"}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/7/merge_requests/12/discussions":
+			var note map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			notes = append(notes, note)
+			io.WriteString(w, `{"id":"1"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "", "job")
+	mr := MergeRequest{ProjectID: "7", IID: 12}
+	diff, err := client.MergeRequestDiff(context.Background(), mr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff.Text, "+++ b/main.go\n@@ -10,2 +10,3 @@") || !strings.Contains(diff.Text, "--- a/old.go\n+++ b/new.go") {
+		t.Errorf("diff text missing files:\n%s", diff.Text)
+	}
+	if diff.OldPaths["new.go"] != "old.go" {
+		t.Errorf("OldPaths = %v", diff.OldPaths)
+	}
+
+	findings := []review.Finding{
+		{Path: "main.go", Line: 11, Body: "division by zero"},
+		{Path: "new.go", Line: 1, Body: "unclear name"},
+		{Body: "no tests"},
+	}
+	posted, err := client.PostFindings(context.Background(), mr, findings, diff)
+	if err != nil || posted != 3 {
+		t.Fatalf("PostFindings() = %d, %v", posted, err)
+	}
+	pos, ok := notes[0]["position"].(map[string]any)
+	if !ok || pos["new_path"] != "main.go" || pos["new_line"] != float64(11) || pos["head_sha"] != "h" || pos["old_line"] != nil {
+		t.Errorf("first note position = %v", notes[0]["position"])
+	}
+	if pos, _ := notes[1]["position"].(map[string]any); pos["old_path"] != "old.go" {
+		t.Errorf("renamed file position = %v", notes[1]["position"])
+	}
+	if _, ok := notes[2]["position"]; ok || notes[2]["body"] != "**ai-commit review**: no tests" {
+		t.Errorf("general note = %v", notes[2])
+	}
+}
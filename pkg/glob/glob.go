@@ -0,0 +1,39 @@
+// Package glob matches repository paths against the patterns used throughout
+// the config: routing rules, type rules, and private files.
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether file matches pattern. Patterns use path.Match syntax
+// per segment, and a "**" segment matches zero or more directories.
+func Match(pattern, file string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
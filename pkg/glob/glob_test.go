@@ -0,0 +1,25 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/**", "docs", true},
+		{"docs/**", "src/docs/a.md", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/a/b.md", true},
+		{"pkg/crypto/**/*.go", "pkg/crypto/aes/gcm.go", true},
+		{"pkg/crypto/**/*.go", "pkg/crypto/aes/README.md", false},
+		{"pkg/*/x.go", "pkg/a/b/x.go", false},
+	}
+	for _, tc := range tests {
+		if got := Match(tc.pattern, tc.file); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.pattern, tc.file, got, tc.want)
+		}
+	}
+}
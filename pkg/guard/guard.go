@@ -0,0 +1,146 @@
+// Package guard scans generated commit messages for content that must never be
+// committed: likely secrets, internal hostnames, and profanity.
+package guard
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+// Finding kinds.
+const (
+	KindSecret    = "secret"
+	KindHost      = "internal hostname"
+	KindProfanity = "profanity"
+)
+
+// Finding is one problem in a message. Detail never contains a full secret.
+type Finding struct {
+	Kind   string
+	Detail string
+}
+
+func (f Finding) String() string {
+	return f.Kind + ": " + f.Detail
+}
+
+type secretPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var secretPatterns = []secretPattern{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"Slack token", regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}`)},
+	{"API key", regexp.MustCompile(`\b(sk-(ant-|proj-)?[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35})`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"URL credentials", regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^/\s:@]+:[^/\s@]+@`)},
+	{"credential assignment", regexp.MustCompile(`(?i)\b(password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\s*[:=]\s*['"]?[^\s'"]{8,}`)},
+}
+
+var hostPattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]\b`)
+
+var wordPattern = regexp.MustCompile(`[\p{L}']+`)
+
+// defaultProfanity is deliberately short; extend it with guardrails.profanity.
+var defaultProfanity = []string{
+	"fuck", "fucking", "fucked", "motherfucker", "shit", "shitty", "bullshit",
+	"bitch", "bastard", "asshole", "dickhead", "cunt", "wtf",
+}
+
+// Scanner checks messages against the built-in rules plus configured settings.
+type Scanner struct {
+	disabled bool
+	hosts    []string
+	words    map[string]bool
+}
+
+// NewScanner builds a Scanner from the guardrails config section.
+func NewScanner(settings config.GuardrailSettings) *Scanner {
+	s := &Scanner{disabled: settings.Disabled, words: make(map[string]bool)}
+	for _, h := range settings.DeniedHosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			s.hosts = append(s.hosts, h)
+		}
+	}
+	for _, w := range append(append([]string{}, defaultProfanity...), settings.Profanity...) {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			s.words[w] = true
+		}
+	}
+	return s
+}
+
+// Scan returns every finding in msg, secrets first. A nil or disabled Scanner finds nothing.
+func (s *Scanner) Scan(msg string) []Finding {
+	if s == nil || s.disabled {
+		return nil
+	}
+	var findings []Finding
+	for _, p := range secretPatterns {
+		if m := p.re.FindString(msg); m != "" {
+			findings = append(findings, Finding{Kind: KindSecret, Detail: fmt.Sprintf("%s (%s)", p.name, mask(m))})
+		}
+	}
+	seen := make(map[string]bool)
+	for _, host := range hostPattern.FindAllString(msg, -1) {
+		host = strings.ToLower(host)
+		if !seen[host] && s.deniedHost(host) {
+			seen[host] = true
+			findings = append(findings, Finding{Kind: KindHost, Detail: host})
+		}
+	}
+	for _, w := range wordPattern.FindAllString(msg, -1) {
+		w = strings.ToLower(w)
+		if s.words[w] && !seen[w] {
+			seen[w] = true
+			findings = append(findings, Finding{Kind: KindProfanity, Detail: fmt.Sprintf("%q", w)})
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) deniedHost(host string) bool {
+	for _, pattern := range s.hosts {
+		if strings.Contains(pattern, "*") {
+			if ok, _ := path.Match(pattern, host); ok {
+				return true
+			}
+			continue
+		}
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask keeps only the first four characters of a matched secret.
+func mask(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return secret[:4] + strings.Repeat("*", min(len(secret)-4, 8))
+}
+
+// Explain renders findings as a bullet list for the user.
+func Explain(findings []Finding) string {
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = "- " + f.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RegenerationHint is appended to the prompt when asking for a replacement message,
+// so the model knows what to leave out.
+func RegenerationHint(findings []Finding) string {
+	return "\n\n[Your previous commit message was rejected]\nIt contained:\n" + Explain(findings) +
+		"\nWrite a new message that does not include secrets, credentials, internal hostnames, or profanity."
+}
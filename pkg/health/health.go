@@ -0,0 +1,142 @@
+// Package health remembers recent provider failures across runs and trips a
+// circuit breaker that skips a provider for a cooldown after several
+// consecutive failures, so a provider that is down does not cost a timeout on
+// every run.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+const (
+	// DefaultThreshold is the number of consecutive failures that trips the breaker.
+	DefaultThreshold = 3
+	// DefaultCooldown is how long a tripped provider is skipped.
+	DefaultCooldown = 5 * time.Minute
+)
+
+// Status is the health of one provider.
+type Status struct {
+	// Failures counts the consecutive failed requests; a success resets it.
+	Failures int `json:"failures"`
+	// LastError is the error of the last failed request.
+	LastError string `json:"lastError,omitempty"`
+	// OpenUntil is when the breaker closes again; zero if it never tripped.
+	OpenUntil time.Time `json:"openUntil,omitzero"`
+}
+
+// Open reports whether the breaker is tripped at now.
+func (s Status) Open(now time.Time) bool {
+	return now.Before(s.OpenUntil)
+}
+
+// Breaker is a circuit breaker per provider, stored as a JSON file so it holds
+// across runs. Once the cooldown passes the provider is tried again, and one
+// more failure trips the breaker again.
+type Breaker struct {
+	mu        sync.Mutex
+	path      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// DefaultPath returns the health file location next to config.yaml.
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "health.json"), nil
+}
+
+// NewBreaker returns a breaker stored at path that trips after threshold
+// consecutive failures and stays open for cooldown. Zero values use
+// DefaultThreshold and DefaultCooldown.
+func NewBreaker(path string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Breaker{path: path, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Status returns the health of provider.
+func (b *Breaker) Status(provider string) Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.load()[provider]
+}
+
+// Open reports whether provider should be skipped now.
+func (b *Breaker) Open(provider string) bool {
+	return b.Status(provider).Open(b.now())
+}
+
+// Success records a successful request, closing the breaker of provider.
+func (b *Breaker) Success(provider string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	states := b.load()
+	if _, ok := states[provider]; !ok {
+		return nil
+	}
+	delete(states, provider)
+	return b.save(states)
+}
+
+// Failure records a failed request of provider and reports whether it tripped
+// the breaker.
+func (b *Breaker) Failure(provider string, reqErr error) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	states := b.load()
+	s := states[provider]
+	s.Failures++
+	if reqErr != nil {
+		s.LastError = reqErr.Error()
+	}
+	tripped := s.Failures >= b.threshold
+	if tripped {
+		s.OpenUntil = b.now().Add(b.cooldown)
+	}
+	states[provider] = s
+	return tripped, b.save(states)
+}
+
+// load reads the stored states; a missing or unreadable file means every
+// provider is healthy.
+func (b *Breaker) load() map[string]Status {
+	states := map[string]Status{}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return states
+	}
+	if err := json.Unmarshal(data, &states); err != nil || states == nil {
+		return map[string]Status{}
+	}
+	return states
+}
+
+func (b *Breaker) save(states map[string]Status) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create provider health directory: %w", err)
+	}
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode provider health: %w", err)
+	}
+	if err := os.WriteFile(b.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write provider health: %w", err)
+	}
+	return nil
+}
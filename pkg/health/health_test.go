@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "health.json")
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	b := NewBreaker(path, 2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	if tripped, err := b.Failure("openai", errors.New("timeout")); err != nil || tripped {
+		t.Fatalf("first failure: tripped=%v err=%v", tripped, err)
+	}
+	if b.Open("openai") {
+		t.Error("breaker open before the threshold")
+	}
+	if tripped, _ := b.Failure("openai", errors.New("503")); !tripped {
+		t.Fatal("second failure did not trip the breaker")
+	}
+
+	// The state is read from disk, so a new run sees the open breaker.
+	next := NewBreaker(path, 2, time.Minute)
+	next.now = b.now
+	if !next.Open("openai") || next.Open("anthropic") {
+		t.Errorf("open = %v/%v, want only openai open", next.Open("openai"), next.Open("anthropic"))
+	}
+	if s := next.Status("openai"); s.Failures != 2 || s.LastError != "503" || !s.OpenUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("status = %+v", s)
+	}
+
+	// After the cooldown the provider is tried again; one more failure re-trips it.
+	now = now.Add(2 * time.Minute)
+	if b.Open("openai") {
+		t.Error("breaker still open after the cooldown")
+	}
+	if tripped, _ := b.Failure("openai", errors.New("503")); !tripped {
+		t.Error("a failure after the cooldown did not trip the breaker again")
+	}
+	if err := b.Success("openai"); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.Status("openai"); s != (Status{}) {
+		t.Errorf("status after success = %+v, want reset", s)
+	}
+}
+
+type fakeClient struct {
+	ai.BaseAIClient
+	err error
+}
+
+func (f *fakeClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return "feat: x", f.err
+}
+
+type fakeStreamingClient struct{ fakeClient }
+
+func (f *fakeStreamingClient) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	return "feat: x", f.err
+}
+
+func TestTrack(t *testing.T) {
+	t.Parallel()
+	b := NewBreaker(filepath.Join(t.TempDir(), "health.json"), 2, time.Minute)
+
+	failing := Track(&fakeClient{err: errors.New("boom")}, b, "openai")
+	if _, ok := failing.(ai.StreamingAIClient); ok {
+		t.Error("expected a non-streaming client to stay non-streaming")
+	}
+	if _, err := failing.GetCommitMessage(context.Background(), "p"); err == nil {
+		t.Error("expected the underlying error to be returned")
+	}
+	canceled := Track(&fakeClient{err: context.Canceled}, b, "openai")
+	canceled.GetCommitMessage(context.Background(), "p")
+	if s := b.Status("openai"); s.Failures != 1 {
+		t.Errorf("failures = %d, want 1 (canceled requests are not failures)", s.Failures)
+	}
+
+	streaming := Track(&fakeStreamingClient{}, b, "openai")
+	s, ok := streaming.(ai.StreamingAIClient)
+	if !ok {
+		t.Fatal("expected streaming support to be preserved")
+	}
+	if _, err := s.StreamCommitMessage(context.Background(), "p", func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.Status("openai"); s.Failures != 0 {
+		t.Errorf("failures after a success = %d, want 0", s.Failures)
+	}
+}
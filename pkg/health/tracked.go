@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+// trackedClient records the outcome of every request in a Breaker.
+type trackedClient struct {
+	ai.Wrapper
+	breaker  *Breaker
+	provider string
+}
+
+// Track wraps client so each request counts as a success or failure of provider
+// in breaker. Requests canceled by the user or too large for the model are not
+// counted, since they say nothing about the provider's health. Streaming and
+// embedding support and token usage of the underlying client are preserved.
+func Track(client ai.AIClient, breaker *Breaker, provider string) ai.AIClient {
+	return ai.Decorate(&trackedClient{Wrapper: ai.Wrapper{AIClient: client}, breaker: breaker, provider: provider}, client)
+}
+
+func (t *trackedClient) observe(err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ai.ErrContextTooLarge) {
+		return
+	}
+	if err == nil {
+		if saveErr := t.breaker.Success(t.provider); saveErr != nil {
+			log.Debug().Err(saveErr).Msg("Failed to record provider health")
+		}
+		return
+	}
+	tripped, saveErr := t.breaker.Failure(t.provider, err)
+	if saveErr != nil {
+		log.Debug().Err(saveErr).Msg("Failed to record provider health")
+	}
+	if tripped {
+		s := t.breaker.Status(t.provider)
+		log.Warn().Str("provider", t.provider).Int("failures", s.Failures).
+			Msgf("Provider keeps failing; it is skipped in favor of the fallback until %s", s.OpenUntil.Format("15:04"))
+	}
+}
+
+func (t *trackedClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	msg, err := t.AIClient.GetCommitMessage(ctx, prompt)
+	t.observe(err)
+	return msg, err
+}
+
+// EmbedThrough counts an embedding request made through next.
+func (t *trackedClient) EmbedThrough(ctx context.Context, next ai.EmbeddingAIClient, model string, texts []string) ([][]float64, error) {
+	vectors, err := next.Embed(ctx, model, texts)
+	t.observe(err)
+	return vectors, err
+}
+
+// StreamThrough counts a streamed request made through next.
+func (t *trackedClient) StreamThrough(ctx context.Context, next ai.StreamingAIClient, prompt string, onDelta func(string)) (string, error) {
+	msg, err := next.StreamCommitMessage(ctx, prompt, onDelta)
+	t.observe(err)
+	return msg, err
+}
+
+var _ ai.Decorator = (*trackedClient)(nil)
@@ -13,15 +13,15 @@ const hookName = "prepare-commit-msg"
 // hookMarker is embedded in the generated script for identification.
 const hookMarker = "# ai-commit-managed-hook"
 
-// HooksDir returns the path to the git hooks directory for the current repo.
+// HooksDir returns the path to the git hooks directory for the current repo,
+// honoring core.hooksPath.
 func HooksDir() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks")
 	out, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("not a git repository: %w", err)
 	}
-	gitDir := strings.TrimSpace(string(out))
-	return filepath.Join(gitDir, "hooks"), nil
+	return strings.TrimSpace(string(out)), nil
 }
 
 // HookPath returns the full path to the prepare-commit-msg hook file.
@@ -75,8 +75,11 @@ func binaryName() string {
 }
 
 // HookScript returns the shell script content for the prepare-commit-msg hook.
+// It replaces the message of plain "git commit" runs, and of runs starting from
+// commit.template (which ai-commit follows itself), with the --msg-only output,
+// keeping git's comment lines below it for the editor.
 func HookScript() string {
-	bin := binaryName()
+	bin := shellQuote(binaryName())
 	return fmt.Sprintf(`#!/bin/sh
 %s
 # Installed by ai-commit. Do not edit manually.
@@ -84,16 +87,34 @@ func HookScript() string {
 COMMIT_MSG_FILE=$1
 COMMIT_SOURCE=$2
 
-# Only generate for normal commits (not merge, squash, amend, or -m flag)
-if [ -z "$COMMIT_SOURCE" ]; then
+# Only generate for plain and commit.template commits (not merge, squash,
+# amend, -m, -F, or -c)
+case "$COMMIT_SOURCE" in
+"" | template)
     MSG=$(%s --msg-only 2>/dev/null)
     if [ $? -eq 0 ] && [ -n "$MSG" ]; then
-        printf '%%s\n' "$MSG" > "$COMMIT_MSG_FILE"
+        COMMENT_CHAR=$(git config --get core.commentChar)
+        case "$COMMENT_CHAR" in
+        "" | auto) COMMENT_CHAR='#' ;;
+        esac
+        COMMENTS=$(awk -v c="$COMMENT_CHAR" 'index($0, c) == 1' "$COMMIT_MSG_FILE")
+        if [ -n "$COMMENTS" ]; then
+            printf '%%s\n\n%%s\n' "$MSG" "$COMMENTS" > "$COMMIT_MSG_FILE"
+        else
+            printf '%%s\n' "$MSG" > "$COMMIT_MSG_FILE"
+        fi
     fi
-fi
+    ;;
+esac
+exit 0
 `, hookMarker, bin)
 }
 
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // Install writes the hook script. Returns an error if a third-party
 // hook exists and overwrite is false.
 func Install(overwrite bool) error {
@@ -167,3 +167,122 @@ func TestUninstallNoHook(t *testing.T) {
 		t.Error("expected error when no hook exists")
 	}
 }
+
+func TestInstallHonorsHooksPath(t *testing.T) {
+	dir := initTestRepo(t)
+	if err := exec.Command("git", "-C", dir, "config", "core.hooksPath", ".githooks").Run(); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := Install(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".githooks", hookName)); err != nil {
+		t.Errorf("hook not installed under core.hooksPath: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "hooks", hookName)); err == nil {
+		t.Error("hook should not be installed in .git/hooks when core.hooksPath is set")
+	}
+}
+
+// TestHookScriptRun runs the hook with a stub ai-commit on PATH.
+func TestHookScriptRun(t *testing.T) {
+	if binaryName() != "ai-commit" {
+		t.Skip("the test binary is not run from a temporary directory")
+	}
+	dir := initTestRepo(t)
+	bin := t.TempDir()
+	stub := "#!/bin/sh\nprintf 'feat: add login\\n\\nAdds the endpoint.'\n"
+	if err := os.WriteFile(filepath.Join(bin, "ai-commit"), []byte(stub), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "hook.sh")
+	if err := os.WriteFile(script, []byte(HookScript()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		source, initial, want string
+	}{
+		{"", "\n# Please enter the commit message.\n# On branch main\n", "feat: add login\n\nAdds the endpoint.\n\n# Please enter the commit message.\n# On branch main\n"},
+		{"template", "Why:\n\n# template comment\n", "feat: add login\n\nAdds the endpoint.\n\n# template comment\n"},
+		{"", "", "feat: add login\n\nAdds the endpoint.\n"},
+		{"message", "fix: typed with -m\n", "fix: typed with -m\n"},
+		{"merge", "Merge branch 'x'\n", "Merge branch 'x'\n"},
+	}
+	for _, tt := range tests {
+		msgFile := filepath.Join(dir, "COMMIT_EDITMSG")
+		if err := os.WriteFile(msgFile, []byte(tt.initial), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("sh", script, msgFile, tt.source)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("source %q: hook failed: %v\n%s", tt.source, err, out)
+		}
+		got, _ := os.ReadFile(msgFile)
+		if string(got) != tt.want {
+			t.Errorf("source %q: message file = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+	if got := shellQuote("/opt/my tools/it's/ai-commit"); got != `'/opt/my tools/it'\''s/ai-commit'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	dir := initTestRepo(t)
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	ctx := t.Context()
+
+	// Missing hooks are skipped.
+	if err := Run(ctx, "pre-commit"); err != nil {
+		t.Fatalf("Run without a hook: %v", err)
+	}
+	if msg, err := RunCommitMsg(ctx, "feat: x\n"); err != nil || msg != "feat: x\n" {
+		t.Fatalf("RunCommitMsg without a hook = %q, %v", msg, err)
+	}
+
+	hooks := filepath.Join(dir, ".git", "hooks")
+	write := func(name, script string, mode os.FileMode) {
+		path := filepath.Join(hooks, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("pre-commit", "echo 'lint failed'\nexit 1\n", 0o755)
+	err := Run(ctx, "pre-commit")
+	if err == nil || !strings.Contains(err.Error(), "lint failed") {
+		t.Errorf("Run(pre-commit) = %v, want the hook output", err)
+	}
+	write("pre-commit", "exit 1\n", 0o644)
+	if err := Run(ctx, "pre-commit"); err != nil {
+		t.Errorf("a non-executable hook should be skipped: %v", err)
+	}
+
+	write("commit-msg", "test -f .git/HEAD && printf '\\nReviewed-by: Pat\\n' >> \"$1\"\n", 0o755)
+	msg, err := RunCommitMsg(ctx, "feat: x\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "feat: x\n\nReviewed-by: Pat\n" {
+		t.Errorf("RunCommitMsg = %q", msg)
+	}
+}
@@ -0,0 +1,84 @@
+package hook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Run runs the named git hook of the current repository with args, from the
+// top of the worktree as git does, when the hook exists and is executable.
+// A hook that exits non-zero aborts with an error carrying its output.
+func Run(ctx context.Context, name string, args ...string) error {
+	path, err := executableHook(name)
+	if err != nil || path == "" {
+		return err
+	}
+	root, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = strings.TrimSpace(string(root))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s hook failed: %w\n%s", name, err, msg)
+		}
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// RunCommitMsg runs the commit-msg hook on message, passing it in
+// COMMIT_EDITMSG as git commit does, and returns the message the hook left
+// there. Without a commit-msg hook, message is returned unchanged.
+func RunCommitMsg(ctx context.Context, message string) (string, error) {
+	if path, err := executableHook("commit-msg"); err != nil || path == "" {
+		return message, err
+	}
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "COMMIT_EDITMSG").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	file, err := filepath.Abs(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", err
+	}
+	// git hands the hook a newline-terminated message.
+	written := strings.TrimRight(message, "\n") + "\n"
+	if err := os.WriteFile(file, []byte(written), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if err := Run(ctx, "commit-msg", file); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if string(data) == written {
+		return message, nil
+	}
+	return string(data), nil
+}
+
+// executableHook returns the absolute path of the named hook, or "" when the
+// hook is missing or not executable (git skips those too).
+func executableHook(name string) (string, error) {
+	dir, err := HooksDir()
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(filepath.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+		return "", nil
+	}
+	return path, nil
+}
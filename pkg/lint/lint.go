@@ -0,0 +1,152 @@
+// Package lint checks commit messages against the configured Conventional
+// Commits types and emoji, and fixes the problems that need no AI.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+)
+
+// DefaultMaxSubject is the subject length limit used when Options.MaxSubject is zero.
+const DefaultMaxSubject = 72
+
+// headerPattern splits a subject into optional emoji, type, scope, breaking marker,
+// and description. The type is matched loosely so unknown types can be reported.
+var headerPattern = regexp.MustCompile(`^(?:(\p{So}|\p{Sk}|:\w+:)\s*)?([A-Za-z]+)(\([^)]*\))?(!)?:\s*(.*)$`)
+
+// Options controls which rules apply.
+type Options struct {
+	// EnableEmoji requires the configured emoji of each type; when false, emoji prefixes are reported.
+	EnableEmoji bool
+	MaxSubject  int
+	// Imperative reports descriptions that do not start with a verb in the
+	// imperative mood ("added", "adds", "adding").
+	Imperative bool
+}
+
+type header struct {
+	emoji, typ, scope, bang, description string
+}
+
+func parseHeader(subject string) (header, bool) {
+	m := headerPattern.FindStringSubmatch(subject)
+	if m == nil {
+		return header{}, false
+	}
+	return header{emoji: m[1], typ: m[2], scope: m[3], bang: m[4], description: strings.TrimSpace(m[5])}, true
+}
+
+func splitMessage(message string) (subject string, rest []string) {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(lines[0]), lines[1:]
+}
+
+// Check returns the problems found in message; none means it conforms.
+func Check(message string, opts Options) []string {
+	subject, rest := splitMessage(message)
+	if subject == "" {
+		return []string{"empty message"}
+	}
+
+	var problems []string
+	h, ok := parseHeader(subject)
+	switch {
+	case !ok:
+		problems = append(problems, "missing Conventional Commits prefix (type(scope): description)")
+	case !committypes.IsValidCommitType(h.typ):
+		if committypes.IsValidCommitType(strings.ToLower(h.typ)) {
+			problems = append(problems, fmt.Sprintf("type %q must be lowercase", h.typ))
+		} else {
+			problems = append(problems, fmt.Sprintf("unknown type %q", h.typ))
+		}
+	}
+	if ok {
+		if h.description == "" {
+			problems = append(problems, "empty description")
+		}
+		problems = append(problems, emojiProblems(h, opts)...)
+	}
+	if opts.Imperative {
+		description := subject
+		if ok {
+			description = h.description
+		}
+		if word, form := moodForm(description); form != "" {
+			problems = append(problems, fmt.Sprintf("%q reads as %s; write the description as a command (\"add\", not \"added\")", word, form))
+		}
+	}
+
+	maxSubject := opts.MaxSubject
+	if maxSubject <= 0 {
+		maxSubject = DefaultMaxSubject
+	}
+	if n := utf8.RuneCountInString(subject); n > maxSubject {
+		problems = append(problems, fmt.Sprintf("subject is %d characters (max %d)", n, maxSubject))
+	}
+	if len(rest) > 0 && strings.TrimSpace(rest[0]) != "" {
+		problems = append(problems, "missing blank line after the subject")
+	}
+	return problems
+}
+
+// exemptPrefixes start the messages git writes itself, which are not linted.
+var exemptPrefixes = []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "}
+
+// Exempt reports whether message was written by git (a merge, a revert, or an
+// autosquash fixup) and should not be held to the conventions.
+func Exempt(message string) bool {
+	subject, _ := splitMessage(message)
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func emojiProblems(h header, opts Options) []string {
+	typ := strings.ToLower(h.typ)
+	want := committypes.GetEmojiForType(typ)
+	switch {
+	case !opts.EnableEmoji && h.emoji != "":
+		return []string{"unexpected emoji prefix " + h.emoji}
+	case opts.EnableEmoji && want != "" && h.emoji == "":
+		return []string{fmt.Sprintf("missing emoji %s for type %q", want, typ)}
+	case opts.EnableEmoji && want != "" && h.emoji != want:
+		return []string{fmt.Sprintf("emoji %s does not match type %q (want %s)", h.emoji, typ, want)}
+	}
+	return nil
+}
+
+// Fix rewrites the formatting problems of message — type case, emoji, spacing,
+// and the blank line after the subject — keeping its scope, description, and body.
+// It reports false when the result would still not conform (e.g. no recognizable
+// type or an overlong subject), in which case the message needs rewording.
+func Fix(message string, opts Options) (string, bool) {
+	subject, rest := splitMessage(message)
+	h, ok := parseHeader(subject)
+	if !ok || h.description == "" {
+		return "", false
+	}
+	typ := strings.ToLower(h.typ)
+	if !committypes.IsValidCommitType(typ) {
+		return "", false
+	}
+
+	prefix := typ
+	if emoji := committypes.GetEmojiForType(typ); opts.EnableEmoji && emoji != "" {
+		prefix = emoji + " " + typ
+	}
+	fixed := fmt.Sprintf("%s%s%s: %s", prefix, h.scope, h.bang, h.description)
+	if body := strings.TrimSpace(strings.Join(rest, "\n")); body != "" {
+		fixed += "\n\n" + body
+	}
+	if len(Check(fixed, opts)) > 0 {
+		return "", false
+	}
+	return fixed, true
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func init() {
+	committypes.InitCommitTypes([]config.CommitTypeConfig{
+		{Type: "feat", Emoji: "✨"},
+		{Type: "fix", Emoji: "🐛"},
+		{Type: "docs", Emoji: "📚"},
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		msg   string
+		emoji bool
+		want  string
+	}{
+		{name: "conforming", msg: "feat(api): add pagination\n\nDetails."},
+		{name: "conforming with emoji", msg: "✨ feat: add pagination", emoji: true},
+		{name: "no prefix", msg: "Add pagination", want: "missing Conventional Commits prefix"},
+		{name: "unknown type", msg: "feature: add pagination", want: `unknown type "feature"`},
+		{name: "uppercase type", msg: "Fix: handle nil", want: "must be lowercase"},
+		{name: "empty description", msg: "fix: ", want: "empty description"},
+		{name: "unexpected emoji", msg: "🐛 fix: handle nil", want: "unexpected emoji"},
+		{name: "missing emoji", msg: "fix: handle nil", emoji: true, want: "missing emoji 🐛"},
+		{name: "wrong emoji", msg: "✨ fix: handle nil", emoji: true, want: "does not match"},
+		{name: "long subject", msg: "fix: " + strings.Repeat("a", 80), want: "subject is 85 characters"},
+		{name: "no blank line", msg: "fix: handle nil\nbody", want: "missing blank line"},
+	}
+	for _, tc := range tests {
+		problems := Check(tc.msg, Options{EnableEmoji: tc.emoji})
+		if tc.want == "" {
+			if len(problems) != 0 {
+				t.Errorf("%s: expected no problems, got %v", tc.name, problems)
+			}
+			continue
+		}
+		if !strings.Contains(strings.Join(problems, "; "), tc.want) {
+			t.Errorf("%s: expected %q in %v", tc.name, tc.want, problems)
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		msg   string
+		emoji bool
+		want  string
+	}{
+		{name: "lowercase type and blank line", msg: "Fix(db)!:  drop column\nMigration needed.", want: "fix(db)!: drop column\n\nMigration needed."},
+		{name: "add emoji", msg: "feat: add export", emoji: true, want: "✨ feat: add export"},
+		{name: "replace emoji", msg: "✨ fix: handle nil", emoji: true, want: "🐛 fix: handle nil"},
+		{name: "strip emoji", msg: "🐛 fix: handle nil", want: "fix: handle nil"},
+		{name: "no type needs rewording", msg: "Add pagination"},
+		{name: "unknown type needs rewording", msg: "feature: add pagination"},
+	}
+	for _, tc := range tests {
+		got, ok := Fix(tc.msg, Options{EnableEmoji: tc.emoji})
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s: Fix() = %q, %v; want %q", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestCheck_Imperative(t *testing.T) {
+	t.Parallel()
+	opts := Options{Imperative: true}
+	if problems := Check("feat: added pagination", opts); !strings.Contains(strings.Join(problems, "; "), `"added" reads as past tense`) {
+		t.Errorf("expected a mood problem, got %v", problems)
+	}
+	for _, msg := range []string{"feat: add pagination", "fix: always close the file", "fix: succeed on retry", "feat: embed the schema"} {
+		if problems := Check(msg, opts); len(problems) != 0 {
+			t.Errorf("Check(%q): expected no problems, got %v", msg, problems)
+		}
+	}
+	if problems := Check("feat: added pagination", Options{}); len(problems) != 0 {
+		t.Errorf("mood is opt-in, got %v", problems)
+	}
+}
+
+func TestExempt(t *testing.T) {
+	t.Parallel()
+	for msg, want := range map[string]bool{
+		"Merge branch 'main' into feature":  true,
+		"Revert \"feat: add pagination\"":   true,
+		"fixup! feat: add pagination":       true,
+		"squash! feat: add pagination":      true,
+		"feat: merge pagination into lists": false,
+		"Added pagination":                  false,
+	} {
+		if got := Exempt(msg); got != want {
+			t.Errorf("Exempt(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// ruleMax is what each rule of Score is worth; the five rules add up to 100.
+const ruleMax = 20
+
+// idealSubject is the subject length that earns full marks.
+const idealSubject = 50
+
+// bodyThreshold is the number of changed lines above which a message is expected
+// to have a body.
+const bodyThreshold = 30
+
+// Rule is the result of one rule of Score.
+type Rule struct {
+	Name   string
+	Points int
+	Max    int
+	// Note explains the points, e.g. what cost some.
+	Note string
+}
+
+// Quality is the local quality score of a message: Total out of 100, and the
+// rules it adds up.
+type Quality struct {
+	Total int
+	Rules []Rule
+}
+
+// Score rates message for diff with local heuristics only, so it is instant and
+// free: subject length, imperative mood, a known Conventional Commits type, a
+// body when the change is large, and how much of the diff the message covers.
+func Score(message, diff string, opts Options) Quality {
+	subject, rest := splitMessage(message)
+	h, ok := parseHeader(subject)
+	description := subject
+	if ok {
+		description = h.description
+	}
+	stats := git.DiffFileStats(diff)
+	rules := []Rule{
+		subjectRule(subject, opts),
+		moodRule(description),
+		typeRule(h, ok),
+		bodyRule(rest, stats),
+		coverageRule(message, h.scope, stats),
+	}
+	q := Quality{Rules: rules}
+	for _, r := range rules {
+		q.Total += r.Points
+	}
+	return q
+}
+
+func subjectRule(subject string, opts Options) Rule {
+	r := Rule{Name: "subject length", Max: ruleMax}
+	maxSubject := opts.MaxSubject
+	if maxSubject <= 0 {
+		maxSubject = DefaultMaxSubject
+	}
+	n := utf8.RuneCountInString(subject)
+	switch {
+	case n == 0:
+		r.Note = "empty subject"
+	case n <= idealSubject:
+		r.Points, r.Note = ruleMax, fmt.Sprintf("%d characters", n)
+	case n <= maxSubject:
+		r.Points, r.Note = ruleMax/2, fmt.Sprintf("%d characters; aim for %d", n, idealSubject)
+	default:
+		r.Note = fmt.Sprintf("%d characters; over the limit of %d", n, maxSubject)
+	}
+	return r
+}
+
+// nonImperative are words that look like a past tense, gerund, or third person
+// by their ending but are not.
+var nonImperative = map[string]bool{
+	"embed": true, "feed": true, "seed": true, "shed": true, "speed": true, "need": true,
+	"succeed": true, "proceed": true, "exceed": true, "bleed": true, "breed": true,
+	"bring": true, "ping": true, "string": true, "sing": true, "ring": true,
+	"always": true, "sometimes": true, "perhaps": true, "towards": true, "afterwards": true,
+}
+
+func moodRule(description string) Rule {
+	r := Rule{Name: "imperative mood", Max: ruleMax}
+	word, form := moodForm(description)
+	switch {
+	case word == "":
+		r.Note = "no description"
+	case form != "":
+		r.Note = fmt.Sprintf("%q reads as %s; write it as a command (\"add\", not \"added\")", word, form)
+	default:
+		r.Points, r.Note = ruleMax, fmt.Sprintf("starts with %q", word)
+	}
+	return r
+}
+
+// moodForm returns the first word of description and, when it is not in the
+// imperative mood, the form it reads as: "past tense", "gerund", or "third
+// person".
+func moodForm(description string) (word, form string) {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	word = strings.ToLower(strings.Trim(fields[0], ".,:;!?\"'`"))
+	switch {
+	case nonImperative[word] || len(word) <= 3:
+	case strings.HasSuffix(word, "ed"):
+		form = "past tense"
+	case strings.HasSuffix(word, "ing"):
+		form = "gerund"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is") && !strings.HasSuffix(word, "as"):
+		form = "third person"
+	}
+	return word, form
+}
+
+func typeRule(h header, ok bool) Rule {
+	r := Rule{Name: "commit type", Max: ruleMax}
+	switch {
+	case !ok:
+		r.Note = "no Conventional Commits prefix"
+	case committypes.IsValidCommitType(h.typ):
+		r.Points, r.Note = ruleMax, fmt.Sprintf("%q", h.typ)
+	case committypes.IsValidCommitType(strings.ToLower(h.typ)):
+		r.Points, r.Note = ruleMax/2, fmt.Sprintf("type %q should be lowercase", h.typ)
+	default:
+		r.Note = fmt.Sprintf("unknown type %q", h.typ)
+	}
+	return r
+}
+
+func bodyRule(rest []string, stats []git.FileStat) Rule {
+	r := Rule{Name: "body", Max: ruleMax}
+	var lines int
+	for _, s := range stats {
+		lines += s.Added + s.Deleted
+	}
+	body := strings.TrimSpace(strings.Join(rest, "\n"))
+	switch {
+	case body != "" && strings.TrimSpace(rest[0]) != "":
+		r.Points, r.Note = ruleMax/2, "missing blank line after the subject"
+	case body != "":
+		r.Points, r.Note = ruleMax, fmt.Sprintf("%d line(s)", len(strings.Split(body, "\n")))
+	case lines > bodyThreshold || len(stats) > 2:
+		r.Note = fmt.Sprintf("no body for a change of %d lines in %d files", lines, len(stats))
+	default:
+		r.Points, r.Note = ruleMax, "not needed for a small change"
+	}
+	return r
+}
+
+// genericDirs are directory names too common to show that a message is about
+// the files under them.
+var genericDirs = map[string]bool{
+	"src": true, "pkg": true, "cmd": true, "lib": true, "internal": true, "app": true,
+	"test": true, "tests": true, "docs": true, "main": true,
+}
+
+// coverageRule estimates how much of the diff the message describes: a file is
+// covered when the message names it, its directory, or the scope names it, and
+// files weigh by their changed lines.
+func coverageRule(message, scope string, stats []git.FileStat) Rule {
+	r := Rule{Name: "diff coverage", Max: ruleMax}
+	if len(stats) == 0 {
+		r.Points, r.Note = ruleMax, "no diff to compare"
+		return r
+	}
+	text := strings.ToLower(message + " " + scope)
+	var total, covered, files int
+	for _, s := range stats {
+		weight := max(s.Added+s.Deleted, 1)
+		total += weight
+		if mentionsFile(text, s.Path) {
+			covered += weight
+			files++
+		}
+	}
+	r.Points = ruleMax * covered / total
+	r.Note = fmt.Sprintf("mentions %d of %d file(s), %d%% of the changed lines", files, len(stats), 100*covered/total)
+	return r
+}
+
+// mentionsFile reports whether text (lowercase) names filePath by its base name
+// without extension or by one of its directories.
+func mentionsFile(text, filePath string) bool {
+	filePath = strings.ToLower(filePath)
+	base := path.Base(filePath)
+	names := []string{strings.TrimSuffix(base, path.Ext(base))}
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		if !genericDirs[dir] {
+			names = append(names, dir)
+		}
+	}
+	for _, name := range names {
+		if len(name) >= 3 && strings.Contains(text, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+const scoreDiff = `diff --git a/pkg/auth/login.go b/pkg/auth/login.go
++++ b/pkg/auth/login.go
++func Login() {}
+diff --git a/README.md b/README.md
++++ b/README.md
++Login docs
+`
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+	q := Score("feat(auth): add login endpoint", scoreDiff, Options{})
+	if q.Total != 100-ruleMax/2 {
+		t.Errorf("total = %d, want %d; rules: %+v", q.Total, 100-ruleMax/2, q.Rules)
+	}
+	for _, r := range q.Rules {
+		if r.Name == "diff coverage" && (r.Points != ruleMax/2 || !strings.Contains(r.Note, "1 of 2")) {
+			t.Errorf("coverage = %+v, want half the points for the auth file", r)
+		}
+	}
+
+	tests := []struct {
+		name, msg string
+		rule      string
+		points    int
+		note      string
+	}{
+		{"past tense", "feat: added login", "imperative mood", 0, "past tense"},
+		{"third person", "feat: adds login", "imperative mood", 0, "third person"},
+		{"gerund", "feat: adding login", "imperative mood", 0, "gerund"},
+		{"imperative", "fix: address login race", "imperative mood", ruleMax, `"address"`},
+		{"no prefix", "Add login", "commit type", 0, "no Conventional Commits prefix"},
+		{"uppercase type", "Feat: add login", "commit type", ruleMax / 2, "lowercase"},
+		{"long subject", "feat: add a login endpoint that accepts passwords and tokens", "subject length", ruleMax / 2, "aim for 50"},
+		{"body", "feat: add login\n\nAdds the endpoint.", "body", ruleMax, "1 line"},
+		{"cramped body", "feat: add login\nAdds the endpoint.", "body", ruleMax / 2, "blank line"},
+		{"readme covered", "docs: document login in the readme", "diff coverage", ruleMax, "2 of 2"},
+	}
+	for _, tt := range tests {
+		var got *Rule
+		q := Score(tt.msg, scoreDiff, Options{})
+		for i := range q.Rules {
+			if q.Rules[i].Name == tt.rule {
+				got = &q.Rules[i]
+			}
+		}
+		if got == nil || got.Points != tt.points || !strings.Contains(got.Note, tt.note) {
+			t.Errorf("%s: %s = %+v, want %d points noting %q", tt.name, tt.rule, got, tt.points, tt.note)
+		}
+	}
+}
+
+func TestScore_BodyForLargeChanges(t *testing.T) {
+	t.Parallel()
+	large := "diff --git a/a.go b/a.go\n" + strings.Repeat("+x\n", bodyThreshold+1)
+	q := Score("feat: add a", large, Options{})
+	if body := q.Rules[3]; body.Name != "body" || body.Points != 0 {
+		t.Errorf("body = %+v, want no points without a body for a large change", body)
+	}
+	if small := Score("feat: add a", "diff --git a/a.go b/a.go\n+x\n", Options{}); small.Rules[3].Points != ruleMax {
+		t.Errorf("body = %+v, want full points for a small change", small.Rules[3])
+	}
+}
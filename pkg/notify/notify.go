@@ -0,0 +1,120 @@
+// Package notify tells the user that a slow generation or commit has finished,
+// with a desktop notification and/or a terminal bell, and tells the team about
+// commits and releases through a webhook.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+// Notifier sends notifications for events that took at least minWait.
+// A nil Notifier sends nothing.
+type Notifier struct {
+	desktop bool
+	bell    bool
+	minWait time.Duration
+	out     io.Writer
+	run     func(ctx context.Context, name string, args ...string) error
+}
+
+// New returns a Notifier for settings, or nil when neither notification kind is enabled.
+func New(settings config.NotifySettings) *Notifier {
+	if !settings.Desktop && !settings.Bell {
+		return nil
+	}
+	return &Notifier{
+		desktop: settings.Desktop,
+		bell:    settings.Bell,
+		minWait: time.Duration(settings.MinSeconds) * time.Second,
+		out:     os.Stderr,
+		run:     runCommand,
+	}
+}
+
+// Notify reports title and body if elapsed reaches the configured minimum.
+// Failures to reach the desktop are logged at debug level and otherwise ignored.
+func (n *Notifier) Notify(title, body string, elapsed time.Duration) {
+	if n == nil || elapsed < n.minWait {
+		return
+	}
+	if n.bell {
+		fmt.Fprint(n.out, "\a")
+	}
+	if !n.desktop {
+		return
+	}
+	name, args, ok := desktopCommand(runtime.GOOS, title, body)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.run(ctx, name, args...); err != nil {
+		log.Debug().Err(err).Str("command", name).Msg("Desktop notification failed")
+	}
+}
+
+// Subscribe notifies about the messages that become ready and the commits
+// created without the TUI, using their first line as the body.
+func (n *Notifier) Subscribe(bus *events.Bus) {
+	if n == nil {
+		return
+	}
+	events.On(bus, func(e events.MessageReady) {
+		n.Notify("ai-commit: commit message ready", subject(e.Message), e.Elapsed)
+	})
+	events.On(bus, func(e events.CommitCreated) {
+		// In the TUI the user is there to see the commit.
+		if !e.Interactive {
+			n.Notify("ai-commit: commit created", subject(e.Message), e.Elapsed)
+		}
+	})
+}
+
+func subject(msg string) string {
+	line, _, _ := strings.Cut(msg, "\n")
+	return line
+}
+
+// desktopCommand returns the command that shows a notification on goos.
+func desktopCommand(goos, title, body string) (string, []string, bool) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, true
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=ai-commit", title, body}, true
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms; `+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; `+
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; `+
+			`$n.ShowBalloonTip(5000, %s, %s, 'Info'); Start-Sleep -Seconds 5; $n.Dispose()`,
+			powerShellString(title), powerShellString(body))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, true
+	}
+	return "", nil, false
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	return exec.CommandContext(ctx, name, args...).Run()
+}
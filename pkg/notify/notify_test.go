@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+func TestNew_Disabled(t *testing.T) {
+	if n := New(config.NotifySettings{MinSeconds: 5}); n != nil {
+		t.Fatalf("expected nil notifier, got %+v", n)
+	}
+	var n *Notifier
+	n.Notify("title", "body", time.Hour) // must not panic
+}
+
+func TestNotify(t *testing.T) {
+	var out bytes.Buffer
+	var calls []string
+	n := New(config.NotifySettings{Desktop: true, Bell: true, MinSeconds: 10})
+	n.out = &out
+	n.run = func(_ context.Context, name string, args ...string) error {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	n.Notify("ai-commit", "ready", 3*time.Second)
+	if out.Len() != 0 || len(calls) != 0 {
+		t.Fatalf("expected nothing below the threshold, got %q and %v", out.String(), calls)
+	}
+
+	n.Notify("ai-commit", "ready", 12*time.Second)
+	if out.String() != "\a" {
+		t.Errorf("expected a bell, got %q", out.String())
+	}
+	if _, _, ok := desktopCommand("plan9", "t", "b"); ok {
+		t.Error("expected no desktop command on an unsupported OS")
+	}
+	if _, _, ok := desktopCommand(runtime.GOOS, "t", "b"); ok && len(calls) != 1 {
+		t.Errorf("expected one desktop command, got %v", calls)
+	}
+}
+
+func TestDesktopCommand_Quoting(t *testing.T) {
+	_, args, _ := desktopCommand("darwin", `say "hi"`, `it's \ done`)
+	if want := `display notification "it's \\ done" with title "say \"hi\""`; args[1] != want {
+		t.Errorf("darwin script = %q, want %q", args[1], want)
+	}
+	_, args, _ = desktopCommand("windows", "t", "it's done")
+	if !strings.Contains(args[3], "'it''s done'") {
+		t.Errorf("windows script does not quote the body: %q", args[3])
+	}
+	name, args, _ := desktopCommand("linux", "t", "b")
+	if name != "notify-send" || args[len(args)-2] != "t" || args[len(args)-1] != "b" {
+		t.Errorf("linux command = %s %v", name, args)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	var bodies []string
+	n := New(config.NotifySettings{Desktop: true})
+	n.run = func(_ context.Context, name string, args ...string) error {
+		bodies = append(bodies, args[len(args)-1])
+		return nil
+	}
+	if _, _, ok := desktopCommand(runtime.GOOS, "t", "b"); !ok {
+		t.Skip("no desktop notifications on " + runtime.GOOS)
+	}
+	bus := events.New()
+	n.Subscribe(bus)
+
+	bus.Publish(events.MessageReady{Message: "feat: ready\n\nbody"})
+	bus.Publish(events.CommitCreated{Message: "feat: from the TUI", Interactive: true})
+	bus.Publish(events.CommitCreated{Message: "feat: forced"})
+	if len(bodies) != 2 || !strings.Contains(bodies[0], "feat: ready") || strings.Contains(bodies[0], "body") || !strings.Contains(bodies[1], "feat: forced") {
+		t.Errorf("notified %q, want the ready message and the forced commit", bodies)
+	}
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+// Webhook events of NotifySettings.Events.
+const (
+	WebhookCommit  = "commit"
+	WebhookRelease = "release"
+)
+
+// DefaultPayload is the webhook body when none is configured; Slack, Mattermost,
+// and Rocket.Chat incoming webhooks accept it.
+const DefaultPayload = `{"text": "{text}"}`
+
+// webhookTimeout bounds one webhook request.
+const webhookTimeout = 10 * time.Second
+
+// Webhook posts a JSON payload to a URL after commits and semantic-release
+// tags. A nil Webhook posts nothing.
+type Webhook struct {
+	url     string
+	payload string
+	headers map[string]string
+	events  []string
+	// repo and branch fill the {repo} and {branch} placeholders.
+	repo, branch string
+	client       *http.Client
+}
+
+// NewWebhook returns the webhook of settings for commits to branch of repo, or
+// nil when no URL is configured.
+func NewWebhook(settings config.NotifySettings, repo, branch string) *Webhook {
+	if settings.URL == "" {
+		return nil
+	}
+	w := &Webhook{
+		url:     settings.URL,
+		payload: settings.Payload,
+		headers: settings.Headers,
+		events:  settings.Events,
+		repo:    repo,
+		branch:  branch,
+		client:  &http.Client{Timeout: webhookTimeout},
+	}
+	if w.payload == "" {
+		w.payload = DefaultPayload
+	}
+	if len(w.events) == 0 {
+		w.events = []string{WebhookCommit, WebhookRelease}
+	}
+	return w
+}
+
+// Subscribe posts after every commit and semantic-release tag published on
+// bus, as enabled by the configured events. Failures are logged as warnings
+// and otherwise ignored.
+func (w *Webhook) Subscribe(bus *events.Bus) {
+	if w == nil {
+		return
+	}
+	if slices.Contains(w.events, WebhookCommit) {
+		events.On(bus, func(e events.CommitCreated) {
+			subject := subject(e.Message)
+			w.send(map[string]string{
+				"event":   WebhookCommit,
+				"hash":    e.Hash,
+				"short":   shortHash(e.Hash),
+				"subject": subject,
+				"message": strings.TrimSpace(e.Message),
+				"text":    fmt.Sprintf("%s committed %s: %s", w.where(), shortHash(e.Hash), subject),
+			})
+		})
+	}
+	if slices.Contains(w.events, WebhookRelease) {
+		events.On(bus, func(e events.ReleaseTagged) {
+			text := fmt.Sprintf("%s released %s", w.where(), e.Tag)
+			if notes := strings.TrimSpace(e.Notes); notes != "" {
+				text += "\n" + notes
+			}
+			w.send(map[string]string{
+				"event":    WebhookRelease,
+				"hash":     e.Commit,
+				"short":    shortHash(e.Commit),
+				"tag":      e.Tag,
+				"previous": e.Previous,
+				"notes":    strings.TrimSpace(e.Notes),
+				"text":     text,
+			})
+		})
+	}
+}
+
+func (w *Webhook) send(fields map[string]string) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	if err := w.Post(ctx, fields); err != nil {
+		log.Warn().Err(err).Str("event", fields["event"]).Msg("Webhook notification failed")
+	}
+}
+
+// where names the repository and branch for the default text, e.g. "[api@main]".
+func (w *Webhook) where() string {
+	switch {
+	case w.repo != "" && w.branch != "":
+		return "[" + w.repo + "@" + w.branch + "]"
+	case w.repo != "":
+		return "[" + w.repo + "]"
+	}
+	return "ai-commit"
+}
+
+// Render fills the payload template with fields and the repository and
+// branch. Values are JSON-escaped, so placeholders belong inside JSON strings;
+// placeholders without a value become empty.
+func (w *Webhook) Render(fields map[string]string) string {
+	values := map[string]string{"repo": w.repo, "branch": w.branch}
+	for k, v := range fields {
+		values[k] = v
+	}
+	var pairs []string
+	for _, name := range []string{"event", "hash", "short", "subject", "message", "tag", "previous", "notes", "text", "repo", "branch"} {
+		pairs = append(pairs, "{"+name+"}", jsonEscape(values[name]))
+	}
+	return strings.NewReplacer(pairs...).Replace(w.payload)
+}
+
+// Post sends the payload rendered for fields to the webhook URL.
+func (w *Webhook) Post(ctx context.Context, fields map[string]string) error {
+	body := w.Render(fields)
+	if !json.Valid([]byte(body)) {
+		return fmt.Errorf("notify.payload is not valid JSON once rendered: %s", body)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader([]byte(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook answered %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// jsonEscape returns s escaped for the inside of a JSON string.
+func jsonEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+func TestNewWebhook_Disabled(t *testing.T) {
+	if w := NewWebhook(config.NotifySettings{Desktop: true}, "api", "main"); w != nil {
+		t.Fatalf("expected nil webhook, got %+v", w)
+	}
+	var w *Webhook
+	w.Subscribe(events.New()) // must not panic
+}
+
+func TestWebhook(t *testing.T) {
+	var bodies []string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(config.NotifySettings{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer t"}}, "api", "main")
+	bus := events.New()
+	w.Subscribe(bus)
+	bus.Publish(events.CommitCreated{Hash: "0123456789", Message: "feat: add \"login\"\n\nbody", Interactive: true})
+	bus.Publish(events.ReleaseTagged{Tag: "v1.2.0", Previous: "v1.1.0", Commit: "0123456789", Notes: "- login"})
+
+	if len(bodies) != 2 || auth != "Bearer t" {
+		t.Fatalf("posted %q with Authorization %q, want a commit and a release", bodies, auth)
+	}
+	var commit, release struct{ Text string }
+	if err := json.Unmarshal([]byte(bodies[0]), &commit); err != nil || commit.Text != `[api@main] committed 0123456: feat: add "login"` {
+		t.Errorf("commit payload = %s (%v)", bodies[0], err)
+	}
+	if err := json.Unmarshal([]byte(bodies[1]), &release); err != nil || release.Text != "[api@main] released v1.2.0\n- login" {
+		t.Errorf("release payload = %s (%v)", bodies[1], err)
+	}
+}
+
+func TestWebhook_PayloadAndEvents(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(config.NotifySettings{
+		URL:     srv.URL,
+		Payload: `{"title": "{repo} {tag}", "body": "{notes}"}`,
+		Events:  []string{WebhookRelease},
+	}, "api", "")
+	bus := events.New()
+	w.Subscribe(bus)
+	bus.Publish(events.CommitCreated{Hash: "0123456789", Message: "feat: add login"})
+	bus.Publish(events.ReleaseTagged{Tag: "v1.2.0", Notes: "line \"one\"\nline two"})
+
+	if len(bodies) != 1 || bodies[0] != `{"title": "api v1.2.0", "body": "line \"one\"\nline two"}` {
+		t.Errorf("posted %q, want only the release", bodies)
+	}
+}
+
+func TestWebhook_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(config.NotifySettings{URL: srv.URL}, "api", "main")
+	if err := w.Post(t.Context(), map[string]string{"text": "hi"}); err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Post = %v, want the server's error", err)
+	}
+	w = NewWebhook(config.NotifySettings{URL: srv.URL, Payload: `{"text": {text}}`}, "api", "main")
+	if err := w.Post(t.Context(), map[string]string{"text": "hi"}); err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Errorf("Post = %v, want an invalid payload error", err)
+	}
+}
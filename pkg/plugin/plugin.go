@@ -0,0 +1,229 @@
+// Package plugin runs the executables configured under "plugins:" at four
+// lifecycle points, so teams can add their own policies without forking:
+//
+//   - pre-prompt, before the prompt is sent; the plugin may replace it
+//   - post-generate, after a message is generated; the plugin may replace it
+//   - pre-commit, just before the commit is created; the plugin may replace the
+//     final message (with footers and trailers) or veto the commit
+//   - post-commit, after the commit is created; the output is ignored
+//
+// A plugin receives an Input as JSON on stdin and may print an Output as JSON
+// on stdout; empty output changes nothing. Exiting with a non-zero status fails
+// the step, which at pre-commit vetoes the commit with stderr as the reason.
+//
+// A plugin may instead be a Starlark script, which is sandboxed: it transforms
+// or rejects the message without access to files, commands, or the network,
+// which makes it safe to run from a shared checkout (see runScript).
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+// Point is a lifecycle point at which plugins run.
+type Point string
+
+const (
+	PrePrompt    Point = "pre-prompt"
+	PostGenerate Point = "post-generate"
+	PreCommit    Point = "pre-commit"
+	PostCommit   Point = "post-commit"
+)
+
+// DefaultTimeout bounds a plugin run without a configured timeout.
+const DefaultTimeout = 30 * time.Second
+
+// ErrVetoed is wrapped by the error of a plugin that rejected the prompt,
+// message, or commit.
+var ErrVetoed = errors.New("vetoed")
+
+// Input is what a plugin reads on stdin. Only the fields that apply to Point
+// are set: Prompt at pre-prompt, Message from post-generate on, and Hash at
+// post-commit. Diff is the staged diff.
+type Input struct {
+	Point   Point  `json:"point"`
+	Prompt  string `json:"prompt,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+	Message string `json:"message,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+// Output is what a plugin may print on stdout. Prompt and Message replace the
+// input's; Veto rejects it with Reason.
+type Output struct {
+	Prompt  *string `json:"prompt,omitempty"`
+	Message *string `json:"message,omitempty"`
+	Veto    bool    `json:"veto,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+// Runner runs the configured plugins. A nil Runner runs nothing.
+type Runner struct {
+	plugins []config.Plugin
+}
+
+// New returns a Runner for plugins, or nil when none is configured.
+func New(plugins []config.Plugin) *Runner {
+	if len(plugins) == 0 {
+		return nil
+	}
+	return &Runner{plugins: plugins}
+}
+
+// Has reports whether a plugin runs at point.
+func (r *Runner) Has(point Point) bool {
+	if r == nil {
+		return false
+	}
+	for _, p := range r.plugins {
+		if slices.Contains(p.Points, string(point)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run passes in through the plugins of in.Point in the configured order, each
+// seeing the changes of the ones before, and returns the result.
+func (r *Runner) Run(ctx context.Context, in Input) (Input, error) {
+	if r == nil {
+		return in, nil
+	}
+	for _, p := range r.plugins {
+		if !slices.Contains(p.Points, string(in.Point)) {
+			continue
+		}
+		out, err := runPlugin(ctx, p, in)
+		if err != nil {
+			return in, err
+		}
+		if out.Veto {
+			reason := strings.TrimSpace(out.Reason)
+			if reason == "" {
+				reason = "no reason given"
+			}
+			return in, fmt.Errorf("plugin %s: %w at %s: %s", Name(p), ErrVetoed, in.Point, reason)
+		}
+		if out.Prompt != nil && in.Point == PrePrompt {
+			in.Prompt = *out.Prompt
+		}
+		if out.Message != nil && in.Point != PrePrompt && in.Point != PostCommit {
+			in.Message = strings.TrimSpace(*out.Message)
+		}
+	}
+	return in, nil
+}
+
+// PostGenerate runs the post-generate plugins on each of msgs, alternatives
+// for the same diff. A message vetoed by a plugin is dropped while others
+// remain.
+func (r *Runner) PostGenerate(ctx context.Context, diff string, msgs []string) ([]string, error) {
+	var out []string
+	var vetoErr error
+	for _, msg := range msgs {
+		res, err := r.Run(ctx, Input{Point: PostGenerate, Diff: diff, Message: msg})
+		if errors.Is(err, ErrVetoed) {
+			vetoErr = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.Message)
+	}
+	if len(out) == 0 && vetoErr != nil {
+		return nil, vetoErr
+	}
+	if vetoErr != nil {
+		log.Warn().Err(vetoErr).Msgf("Dropped %d vetoed candidate(s)", len(msgs)-len(out))
+	}
+	return out, nil
+}
+
+// Subscribe runs the post-commit plugins for every commit published on bus.
+// Their failures are logged, since the commit already exists.
+func (r *Runner) Subscribe(bus *events.Bus) {
+	if !r.Has(PostCommit) {
+		return
+	}
+	events.On(bus, func(e events.CommitCreated) {
+		ctx := context.Background()
+		if _, err := r.Run(ctx, Input{Point: PostCommit, Message: e.Message, Hash: e.Hash}); err != nil {
+			log.Warn().Err(err).Msg("Post-commit plugin failed")
+		}
+	})
+}
+
+// Name returns the configured name of p, or the base name of its command or
+// script.
+func Name(p config.Plugin) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if p.Script != "" {
+		return filepath.Base(p.Script)
+	}
+	return filepath.Base(p.Command)
+}
+
+// runPlugin runs p with in on stdin and decodes its stdout.
+func runPlugin(ctx context.Context, p config.Plugin, in Input) (Output, error) {
+	timeout := DefaultTimeout
+	if raw := strings.TrimSpace(p.Timeout); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return Output{}, fmt.Errorf("plugin %s: invalid timeout %q", Name(p), p.Timeout)
+		}
+		timeout = d
+	}
+	if p.Script != "" {
+		return runScript(ctx, p, in, timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		return Output{}, fmt.Errorf("plugin %s: failed to encode input: %w", Name(p), err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return Output{}, fmt.Errorf("plugin %s: timed out after %s", Name(p), timeout)
+		}
+		reason := strings.TrimSpace(stderr.String())
+		if reason == "" {
+			reason = err.Error()
+		}
+		if in.Point == PreCommit {
+			return Output{}, fmt.Errorf("plugin %s: %w at %s: %s", Name(p), ErrVetoed, in.Point, reason)
+		}
+		return Output{}, fmt.Errorf("plugin %s failed at %s: %s", Name(p), in.Point, reason)
+	}
+
+	var out Output
+	if raw := bytes.TrimSpace(stdout.Bytes()); len(raw) > 0 {
+		if err := json.Unmarshal(raw, &out); err != nil {
+			return Output{}, fmt.Errorf("plugin %s: invalid output: %w", Name(p), err)
+		}
+	}
+	return out, nil
+}
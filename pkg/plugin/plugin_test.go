@@ -0,0 +1,171 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+func shPlugin(name, script string, points ...string) config.Plugin {
+	return config.Plugin{Name: name, Command: "sh", Args: []string{"-c", script}, Points: points}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := New([]config.Plugin{
+		// Checks the input and replaces the message.
+		shPlugin("ticket", `grep -q '"point":"post-generate"' && printf '{"message":"feat: add login\\n\\nRefs: PROJ-1"}'`, "post-generate"),
+		// Sees the change of the plugin before it.
+		shPlugin("check", `grep -q 'PROJ-1' || exit 1`, "post-generate", "pre-commit"),
+		shPlugin("prompt", `printf '{"prompt":"new prompt","message":"ignored"}'`, "pre-prompt"),
+	})
+
+	if !r.Has(PrePrompt) || r.Has(PostCommit) || New(nil).Has(PreCommit) {
+		t.Error("Has does not match the configured points")
+	}
+	out, err := r.Run(ctx, Input{Point: PostGenerate, Message: "feat: add login"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Message != "feat: add login\n\nRefs: PROJ-1" {
+		t.Errorf("message = %q", out.Message)
+	}
+	if out, err := r.Run(ctx, Input{Point: PrePrompt, Prompt: "old"}); err != nil || out.Prompt != "new prompt" || out.Message != "" {
+		t.Errorf("pre-prompt = %+v, %v", out, err)
+	}
+
+	// A failing pre-commit plugin vetoes the commit.
+	_, err = r.Run(ctx, Input{Point: PreCommit, Message: "feat: no ticket"})
+	if !errors.Is(err, ErrVetoed) || !strings.Contains(err.Error(), "check") {
+		t.Errorf("pre-commit err = %v, want a veto by check", err)
+	}
+
+	var none *Runner
+	if out, err := none.Run(ctx, Input{Point: PreCommit, Message: "m"}); err != nil || out.Message != "m" {
+		t.Errorf("nil runner changed the input: %+v, %v", out, err)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tests := []struct {
+		plugin config.Plugin
+		veto   bool
+		want   string
+	}{
+		{shPlugin("veto", `printf '{"veto":true,"reason":"subject too vague"}'`, "post-generate"), true, "subject too vague"},
+		{shPlugin("crash", `echo boom >&2; exit 3`, "post-generate"), false, "boom"},
+		{shPlugin("garbage", `echo not json`, "post-generate"), false, "invalid output"},
+		{config.Plugin{Name: "slow", Command: "sleep", Args: []string{"5"}, Points: []string{"post-generate"}, Timeout: "50ms"}, false, "timed out"},
+	}
+	for _, tt := range tests {
+		_, err := New([]config.Plugin{tt.plugin}).Run(ctx, Input{Point: PostGenerate, Message: "m"})
+		if err == nil || errors.Is(err, ErrVetoed) != tt.veto || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want veto=%v containing %q", tt.plugin.Name, err, tt.veto, tt.want)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+	file := t.TempDir() + "/post-commit"
+	r := New([]config.Plugin{shPlugin("log", `cat > `+file, "post-commit")})
+	bus := events.New()
+	r.Subscribe(bus)
+	bus.Publish(events.CommitCreated{Hash: "abc1234", Message: "feat: x"})
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := string(raw); !strings.Contains(data, `"hash":"abc1234"`) || !strings.Contains(data, `"point":"post-commit"`) {
+		t.Errorf("post-commit input = %s", raw)
+	}
+}
+
+func TestPostGenerate(t *testing.T) {
+	t.Parallel()
+	r := New([]config.Plugin{shPlugin("no-wip", `grep -q WIP && printf '{"veto":true}' || true`, "post-generate")})
+	got, err := r.PostGenerate(context.Background(), "diff", []string{"feat: WIP login", "feat: add login"})
+	if err != nil || len(got) != 1 || got[0] != "feat: add login" {
+		t.Errorf("PostGenerate = %q, %v; want the vetoed candidate dropped", got, err)
+	}
+	if _, err := r.PostGenerate(context.Background(), "diff", []string{"feat: WIP"}); !errors.Is(err, ErrVetoed) {
+		t.Errorf("PostGenerate with every candidate vetoed = %v, want ErrVetoed", err)
+	}
+}
+
+func scriptPlugin(t *testing.T, name, src string, points ...string) config.Plugin {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name+".star")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return config.Plugin{Script: path, Points: points}
+}
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	diff := "diff --git a/api/login.go b/api/login.go\n+++ b/api/login.go\n+func Login() {}\n-func login() {}\n"
+	r := New([]config.Plugin{
+		scriptPlugin(t, "footer", `
+def transform(message, diff):
+    paths = [f.path for f in diff.files]
+    return message + "\n\nFiles: %s (+%d/-%d, %s)" % (", ".join(paths), diff.additions, diff.deletions, diff.languages[0])
+`, "post-generate"),
+		scriptPlugin(t, "keep", "def transform(message, diff):\n    return None\n", "post-generate"),
+		scriptPlugin(t, "no-wip", `
+def transform(message, diff):
+    if "WIP" in message:
+        reject("work in progress")
+`, "pre-commit"),
+		// Skipped: the repository has no such script.
+		{Script: "missing/format.star", Points: []string{"post-generate"}},
+	})
+
+	out, err := r.Run(ctx, Input{Point: PostGenerate, Diff: diff, Message: "feat: add login"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "feat: add login\n\nFiles: api/login.go (+1/-1, Go)"; out.Message != want {
+		t.Errorf("message = %q, want %q", out.Message, want)
+	}
+	_, err = r.Run(ctx, Input{Point: PreCommit, Message: "feat: WIP login"})
+	if !errors.Is(err, ErrVetoed) || !strings.Contains(err.Error(), "work in progress") || !strings.Contains(err.Error(), "no-wip.star") {
+		t.Errorf("pre-commit err = %v, want a veto by no-wip.star", err)
+	}
+}
+
+func TestScript_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, src string
+		point     Point
+		veto      bool
+		want      string
+	}{
+		{"syntax", "def transform(message, diff)\n", PostGenerate, false, "got newline"},
+		{"missing", "x = 1\n", PostGenerate, false, "does not define transform"},
+		{"number", "def transform(message, diff):\n    return 1\n", PostGenerate, false, "want a string or None"},
+		{"loop", "def transform(message, diff):\n    for i in range(100000000):\n        pass\n", PostGenerate, false, "too many steps"},
+		{"fail", "def transform(message, diff):\n    fail(\"boom\")\n", PreCommit, true, "boom"},
+		{"point", "def transform(message, diff):\n    return None\n", PrePrompt, false, "scripts run only"},
+		{"io", "def transform(message, diff):\n    return open(\"/etc/passwd\")\n", PostGenerate, false, "undefined: open"},
+	}
+	for _, tt := range tests {
+		p := scriptPlugin(t, tt.name, tt.src, string(tt.point))
+		_, err := New([]config.Plugin{p}).Run(context.Background(), Input{Point: tt.point, Message: "m"})
+		if err == nil || errors.Is(err, ErrVetoed) != tt.veto || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want veto=%v containing %q", tt.name, err, tt.veto, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// scriptMaxSteps bounds the computation of one script run, so a runaway loop
+// fails instead of hanging until the timeout.
+const scriptMaxSteps = 10_000_000
+
+// runScript runs the Starlark script of p. A script cannot read files, run
+// commands, or reach the network: it defines
+//
+//	def transform(message, diff):
+//
+// which gets the message and a struct describing the diff (files, each with
+// path, language, added, and deleted; additions; deletions; languages) and
+// returns the new message, or None to keep it. Calling reject(reason) vetoes
+// the message. Scripts run only where there is a message to transform, at
+// post-generate and pre-commit. A relative script that the repository does
+// not have is skipped.
+func runScript(ctx context.Context, p config.Plugin, in Input, timeout time.Duration) (Output, error) {
+	if in.Point != PostGenerate && in.Point != PreCommit {
+		return Output{}, fmt.Errorf("plugin %s: scripts run only at %s and %s, not %s", Name(p), PostGenerate, PreCommit, in.Point)
+	}
+	path := p.Script
+	if !filepath.IsAbs(path) {
+		root, err := git.RepoRoot()
+		if err != nil {
+			return Output{}, fmt.Errorf("plugin %s: %w", Name(p), err)
+		}
+		path = filepath.Join(root, path)
+	}
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !filepath.IsAbs(p.Script) {
+		log.Debug().Str("script", p.Script).Msg("Repository has no script; skipping plugin")
+		return Output{}, nil
+	}
+	if err != nil {
+		return Output{}, fmt.Errorf("plugin %s: %w", Name(p), err)
+	}
+
+	thread := &starlark.Thread{
+		Name: Name(p),
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Debug().Str("plugin", Name(p)).Msg(msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel("timed out after " + timeout.String())
+		case <-done:
+		}
+	}()
+
+	globals, err := starlark.ExecFile(thread, path, src, starlark.StringDict{
+		"reject": starlark.NewBuiltin("reject", reject),
+	})
+	if err != nil {
+		return Output{}, scriptError(p, in, err)
+	}
+	transform, ok := globals["transform"].(starlark.Callable)
+	if !ok {
+		return Output{}, fmt.Errorf("plugin %s: %s does not define transform(message, diff)", Name(p), p.Script)
+	}
+	res, err := starlark.Call(thread, transform, starlark.Tuple{starlark.String(in.Message), diffValue(in.Diff)}, nil)
+	if err != nil {
+		return Output{}, scriptError(p, in, err)
+	}
+	switch v := res.(type) {
+	case starlark.NoneType:
+		return Output{}, nil
+	case starlark.String:
+		msg := string(v)
+		return Output{Message: &msg}, nil
+	default:
+		return Output{}, fmt.Errorf("plugin %s: transform returned %s, want a string or None", Name(p), res.Type())
+	}
+}
+
+// scriptError turns a failed script run into a veto when the script called
+// reject, or at pre-commit, as a failing command would be.
+func scriptError(p config.Plugin, in Input, err error) error {
+	var evalErr *starlark.EvalError
+	reason := err.Error()
+	if errors.As(err, &evalErr) {
+		reason = evalErr.Msg
+	}
+	var r *rejection
+	if errors.As(err, &r) {
+		reason = r.reason
+	}
+	if r != nil || in.Point == PreCommit {
+		return fmt.Errorf("plugin %s: %w at %s: %s", Name(p), ErrVetoed, in.Point, reason)
+	}
+	return fmt.Errorf("plugin %s failed at %s: %s", Name(p), in.Point, reason)
+}
+
+// reject is the builtin scripts call to veto a message.
+func reject(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var reason string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0, &reason); err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return nil, &rejection{reason}
+}
+
+// rejection is the error of the reject builtin, carrying its reason.
+type rejection struct{ reason string }
+
+func (r *rejection) Error() string { return r.reason }
+
+// diffValue describes diff for a script.
+func diffValue(diff string) *starlarkstruct.Struct {
+	stats := git.DiffFileStats(diff)
+	files := make([]starlark.Value, len(stats))
+	var additions, deletions int
+	for i, s := range stats {
+		files[i] = starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"path":     starlark.String(s.Path),
+			"language": starlark.String(git.FileLanguage(s.Path)),
+			"added":    starlark.MakeInt(s.Added),
+			"deleted":  starlark.MakeInt(s.Deleted),
+		})
+		additions += s.Added
+		deletions += s.Deleted
+	}
+	var languages []starlark.Value
+	for _, l := range git.DetectLanguages(diff) {
+		languages = append(languages, starlark.String(l.Name))
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"files":     starlark.NewList(files),
+		"additions": starlark.MakeInt(additions),
+		"deletions": starlark.MakeInt(deletions),
+		"languages": starlark.NewList(languages),
+	})
+}
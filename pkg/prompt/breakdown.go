@@ -0,0 +1,105 @@
+package prompt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Section is one part of a prompt together with its size.
+type Section struct {
+	Name   string
+	Chars  int
+	Tokens int
+}
+
+// EstimateTokens approximates the token count of s using the common
+// four-characters-per-token rule of thumb. It is meant for comparing sections,
+// not for billing.
+func EstimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + 3) / 4
+}
+
+func newSection(name, text string) Section {
+	return Section{Name: name, Chars: utf8.RuneCountInString(text), Tokens: EstimateTokens(text)}
+}
+
+// Breakdown splits promptText into the template instructions, the author's
+// intent, one section per file of diff, and the user-provided context. When the diff was truncated to fit
+// the prompt limit, the part that survived is reported as a single section.
+func Breakdown(promptText, diff, additionalText string) []Section {
+	var sections []Section
+	rest := utf8.RuneCountInString(promptText)
+
+	if strings.HasPrefix(promptText, intentHeader) {
+		if i := strings.Index(promptText, intentRule); i >= 0 {
+			sections = append(sections, newSection("intent", promptText[:i+len(intentRule)]))
+		}
+	}
+	if diff != "" {
+		if strings.Contains(promptText, diff) {
+			for _, part := range splitDiffByFile(diff) {
+				sections = append(sections, newSection("diff: "+part.path, part.text))
+			}
+		} else if kept := keptDiffPrefix(promptText, diff); kept != "" {
+			sections = append(sections, newSection("diff (truncated)", kept))
+		}
+	}
+	if ctx := additionalContext(additionalText); ctx != "" && strings.Contains(promptText, ctx) {
+		sections = append(sections, newSection("additional context", ctx))
+	}
+	for _, s := range sections {
+		rest -= s.Chars
+	}
+	if rest < 0 {
+		rest = 0
+	}
+	instructions := Section{Name: "instructions", Chars: rest, Tokens: (rest + 3) / 4}
+	return append([]Section{instructions}, sections...)
+}
+
+type diffPart struct {
+	path string
+	text string
+}
+
+// splitDiffByFile cuts a diff at each "diff --git" header.
+func splitDiffByFile(diff string) []diffPart {
+	var parts []diffPart
+	for _, chunk := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(chunk, "diff --git ") || len(parts) == 0 {
+			parts = append(parts, diffPart{path: diffHeaderPath(chunk)})
+		}
+		parts[len(parts)-1].text += chunk
+	}
+	return parts
+}
+
+// diffHeaderPath extracts the new path from a "diff --git a/X b/Y" header.
+func diffHeaderPath(header string) string {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[0] != "diff" {
+		return "(preamble)"
+	}
+	return strings.TrimPrefix(fields[3], "b/")
+}
+
+// keptDiffPrefix returns the longest leading part of diff that appears in promptText.
+func keptDiffPrefix(promptText, diff string) string {
+	probe := diff
+	if i := strings.IndexByte(probe, '\n'); i > 0 {
+		probe = probe[:i]
+	}
+	if len(probe) > 80 {
+		probe = probe[:80]
+	}
+	idx := strings.Index(promptText, probe)
+	if idx < 0 {
+		return ""
+	}
+	tail := promptText[idx:]
+	n := 0
+	for n < len(tail) && n < len(diff) && tail[n] == diff[n] {
+		n++
+	}
+	return diff[:n]
+}
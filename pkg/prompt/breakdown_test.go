@@ -0,0 +1,60 @@
+package prompt
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int{"": 0, "abc": 1, "abcd": 1, "abcde": 2}
+	for in, want := range tests {
+		if got := EstimateTokens(in); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/a.go b/a.go\n+x := 1\ndiff --git a/b.go b/b.go\n+y := 2\n"
+	promptText := BuildCommitPrompt(diff, "English", "", "fixes login", "", "")
+
+	sections := Breakdown(promptText, diff, "fixes login")
+	if len(sections) != 4 {
+		t.Fatalf("expected instructions, two files, and context; got %+v", sections)
+	}
+	names := []string{"instructions", "diff: a.go", "diff: b.go", "additional context"}
+	total := 0
+	for i, s := range sections {
+		if s.Name != names[i] {
+			t.Errorf("section %d = %q, want %q", i, s.Name, names[i])
+		}
+		total += s.Chars
+	}
+	if want := utf8.RuneCountInString(promptText); total != want {
+		t.Errorf("sections add up to %d chars, prompt has %d", total, want)
+	}
+}
+
+func TestBreakdown_Intent(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/a.go b/a.go\n+x := 1\n"
+	promptText := WithIntent(BuildCommitPrompt(diff, "English", "", "", "", ""), "add retries")
+
+	sections := Breakdown(promptText, diff, "")
+	if len(sections) != 3 || sections[1].Name != "intent" || sections[1].Chars != utf8.RuneCountInString(IntentHint("add retries")) {
+		t.Errorf("unexpected sections: %+v", sections)
+	}
+}
+
+func TestBreakdown_TruncatedDiff(t *testing.T) {
+	t.Parallel()
+	diff := "diff --git a/a.go b/a.go\n+x := 1\n+y := 2\n"
+	promptText := "Describe:\n" + diff[:30] + "..."
+
+	sections := Breakdown(promptText, diff, "")
+	if len(sections) != 2 || sections[1].Name != "diff (truncated)" || sections[1].Chars != 30 {
+		t.Errorf("unexpected sections: %+v", sections)
+	}
+}
@@ -1,11 +1,14 @@
 package prompt
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	gogitobj "github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/renatogalera/ai-commit/pkg/ai"
 	"github.com/renatogalera/ai-commit/pkg/committypes"
 	"github.com/renatogalera/ai-commit/pkg/config"
 )
@@ -81,6 +84,21 @@ func TestBuildCommitPrompt_AdditionalContext(t *testing.T) {
 	}
 }
 
+func TestWithIntent(t *testing.T) {
+	t.Parallel()
+	base := BuildCommitPrompt("diff", "English", "", "", "", "")
+	result := WithIntent(base, "  implement retry backoff for the phind client\n")
+	if !strings.HasPrefix(result, "[Author's intent]") || !strings.HasSuffix(result, base) {
+		t.Error("expected the intent section ahead of the prompt")
+	}
+	if !strings.Contains(result, "as:\n\nimplement retry backoff for the phind client\n\nBuild the message") {
+		t.Errorf("expected the trimmed intent in the section, got %q", result[:len(result)-len(base)])
+	}
+	if WithIntent(base, " ") != base {
+		t.Error("expected a blank intent to leave the prompt unchanged")
+	}
+}
+
 func TestBuildCodeReviewPrompt_Default(t *testing.T) {
 	t.Parallel()
 	result := BuildCodeReviewPrompt("review diff", "English", "")
@@ -96,16 +114,74 @@ func TestBuildCodeReviewPrompt_Default(t *testing.T) {
 	}
 }
 
+func TestBuildCIReviewPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildCIReviewPrompt("ci diff", "English", "Nothing to report.")
+
+	for _, want := range []string{"ci diff", "English", `reply exactly "Nothing to report."`, "path/to/file:LINE: finding"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in CI review prompt", want)
+		}
+	}
+}
+
+func TestBuildReviewerPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildReviewerPrompt("owners diff", "English", "- @octo: code owner of main.go")
+
+	for _, want := range []string{"owners diff", "English", "- @octo: code owner of main.go", `"- reviewer: reason"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in reviewer prompt", want)
+		}
+	}
+}
+
+func TestBuildVerifyPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildVerifyPrompt("verify diff", "feat: add login", "PASS", "FAIL")
+
+	for _, want := range []string{FenceUntrusted("verify diff"), FenceUntrusted("feat: add login"), `exactly "PASS"`, `"FAIL: "`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in verify prompt", want)
+		}
+	}
+}
+
 func TestBuildCodeReviewPrompt_Custom(t *testing.T) {
 	t.Parallel()
 	tmpl := "Review this: {DIFF} in {LANGUAGE}"
 	result := BuildCodeReviewPrompt("my diff", "French", tmpl)
 
-	if result != "Review this: my diff in French" {
+	if result != "Review this: "+FenceUntrusted("my diff")+" in French" {
 		t.Errorf("got %q, expected custom template with substitutions", result)
 	}
 }
 
+func TestBuildPerformanceReviewPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildPerformanceReviewPrompt("perf diff", "English", "- a.go (10 lines, +3): loops 1")
+
+	for _, want := range []string{"perf diff", "English", "- a.go (10 lines, +3): loops 1", "N+1 queries"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in performance review prompt", want)
+		}
+	}
+	if strings.Contains(result, "{HEURISTICS}") {
+		t.Error("expected heuristics placeholder to be replaced")
+	}
+}
+
+func TestBuildArchitectureReviewPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildArchitectureReviewPrompt("arch diff", "English", "- pkg/ui must not depend on pkg/provider/*", "NO_VIOLATIONS")
+
+	for _, want := range []string{"arch diff", "English", "pkg/ui must not depend on pkg/provider/*", `reply exactly "NO_VIOLATIONS"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in architecture review prompt", want)
+		}
+	}
+}
+
 func TestBuildCommitStyleReviewPrompt_Default(t *testing.T) {
 	t.Parallel()
 	result := BuildCommitStyleReviewPrompt("feat: add login", "English", "")
@@ -170,8 +246,8 @@ func TestBuildCommitSummaryPrompt_CustomTemplate(t *testing.T) {
 	if !strings.Contains(result, "Author: Dev") {
 		t.Error("expected author substitution")
 	}
-	if !strings.Contains(result, "Diff: the diff") {
-		t.Error("expected diff substitution")
+	if !strings.Contains(result, "Diff: "+FenceUntrusted("the diff")) {
+		t.Error("expected fenced diff substitution")
 	}
 	if !strings.Contains(result, "Lang: German") {
 		t.Error("expected language substitution")
@@ -222,3 +298,164 @@ func TestExtractSummaryAfterGeneral(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildRevertPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildRevertPrompt("abc1234", "feat: add cache", "", "diff body", "caused stale reads", "English")
+
+	for _, want := range []string{"abc1234", "feat: add cache", "diff body", "caused stale reads", "English"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected prompt to contain %q", want)
+		}
+	}
+
+	noReason := BuildRevertPrompt("abc1234", "feat: add cache", "", "diff", "", "English")
+	if !strings.Contains(noReason, "(not provided)") {
+		t.Error("expected placeholder when no reason is given")
+	}
+}
+
+func TestBuildEmptyCommitPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildEmptyCommitPrompt("trigger CI rebuild", "English", "chore")
+
+	if !strings.Contains(result, "trigger CI rebuild") {
+		t.Error("expected prompt to contain the intent")
+	}
+	if !strings.Contains(result, "Use the commit type 'chore'") {
+		t.Error("expected commit type hint for valid type")
+	}
+	if strings.Contains(BuildEmptyCommitPrompt("x", "English", ""), "Use the commit type") {
+		t.Error("expected no type hint when commit type is empty")
+	}
+}
+
+func TestBuildConsensusPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildConsensusPrompt("diff content", "English", []Candidate{
+		{Source: "openai", Message: "feat: add login\n"},
+		{Source: "anthropic", Message: "feat(auth): add login endpoint"},
+	})
+	for _, want := range []string{"[1] from openai:\nfeat: add login", "[2] from anthropic:", "diff content", "English"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in prompt:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "{CANDIDATES}") || strings.Contains(result, "{DIFF}") {
+		t.Error("expected all placeholders to be replaced")
+	}
+}
+
+func TestBuildEvalGradePrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildEvalGradePrompt("diff content", "fix: real\n", "fix: generated")
+	for _, want := range []string{"fix: real\n", "fix: generated", "diff content"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in prompt:\n%s", want, result)
+		}
+	}
+}
+
+func TestTemplateHash(t *testing.T) {
+	t.Parallel()
+	def := TemplateHash("")
+	if len(def) != 7 || def != TemplateHash(DefaultPromptTemplate) {
+		t.Errorf("expected the default template hash for an empty template, got %q", def)
+	}
+	if TemplateHash("custom {DIFF}") == def {
+		t.Error("expected a custom template to hash differently")
+	}
+}
+
+func TestBuildCommitPrompt_LanguagesHint(t *testing.T) {
+	ConfigureLanguageHints(map[string]string{"go": "Use the Go package name as the scope."})
+	defer ConfigureLanguageHints(nil)
+
+	diff := "diff --git a/pkg/api/api.go b/pkg/api/api.go\n+func New() {}\ndiff --git a/web/App.tsx b/web/App.tsx\n+export {}"
+	result := BuildCommitPrompt(diff, "English", "", "", "", "")
+	if !strings.Contains(result, "- Changed languages: Go (1 file, 1 line) + React frontend (1 file, 1 line).") {
+		t.Errorf("expected the changed languages in the prompt, got:\n%s", result)
+	}
+	if !strings.Contains(result, "- Go: Use the Go package name as the scope.") {
+		t.Error("expected the configured Go hint in the prompt")
+	}
+	if strings.Contains(BuildCommitPrompt("no headers", "English", "", "", "", ""), "Changed languages") {
+		t.Error("expected no languages hint without recognized files")
+	}
+}
+
+func TestHasStyleIssues(t *testing.T) {
+	t.Parallel()
+	for review, want := range map[string]bool{
+		"":                            false,
+		"No issues found.":            false,
+		"  no issues found  ":         false,
+		"- The subject is too vague.": true,
+	} {
+		if got := HasStyleIssues(review); got != want {
+			t.Errorf("HasStyleIssues(%q) = %v, want %v", review, got, want)
+		}
+	}
+}
+
+func TestBuildFixupPrompt(t *testing.T) {
+	t.Parallel()
+	result := BuildFixupPrompt("diff content", "English", "NONE", []FixupCandidate{
+		{ShortHash: "abc1234", Subject: "feat: add login", Files: []string{"auth.go", "auth_test.go"}},
+		{ShortHash: "def5678", Subject: "docs: update README"},
+	})
+	for _, want := range []string{"abc1234 feat: add login\n  files: auth.go, auth_test.go", "def5678 docs: update README", `"NONE"`, "diff content", "English"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in prompt:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "{COMMITS}") || strings.Contains(result, "{DIFF}") {
+		t.Error("expected all placeholders to be replaced")
+	}
+}
+
+func TestSplitCandidates(t *testing.T) {
+	t.Parallel()
+	if hint := CandidatesHint(3); !strings.Contains(hint, "3 alternative") || !strings.Contains(hint, candidateSeparator) {
+		t.Errorf("CandidatesHint(3) = %q", hint)
+	}
+	reply := "feat: add login\n\nWith a body.\n%%%\nfeat(auth): add login\n %%% \n\n%%%\nfeat: add login\n\nWith a body."
+	got := SplitCandidates(reply)
+	want := []string{"feat: add login\n\nWith a body.", "feat(auth): add login"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitCandidates = %q, want %q", got, want)
+	}
+	if got := SplitCandidates("fix: one message"); len(got) != 1 || got[0] != "fix: one message" {
+		t.Errorf("SplitCandidates without separators = %q", got)
+	}
+}
+
+type replyClient struct {
+	ai.BaseAIClient
+	reply string
+}
+
+func (c replyClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return c.reply, nil
+}
+
+func TestGenerateCandidates(t *testing.T) {
+	t.Parallel()
+	client := replyClient{reply: "feat: add login\n%%%\nnot a message\n%%%\nFeat: add login\n%%%\nfix: repair login\n%%%\nchore: extra"}
+	finish := func(reply string) (string, error) {
+		if !strings.Contains(reply, ":") {
+			return "", errors.New("rejected")
+		}
+		return strings.ToLower(reply), nil
+	}
+	got, err := GenerateCandidates(context.Background(), &client, "prompt", 2, finish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "|") != "feat: add login|fix: repair login" {
+		t.Errorf("GenerateCandidates = %q, want the finished messages without repeats", got)
+	}
+	if _, err := GenerateCandidates(context.Background(), &replyClient{reply: "nothing"}, "prompt", 2, finish); err == nil || err.Error() != "rejected" {
+		t.Errorf("GenerateCandidates with every reply rejected = %v, want the rejection", err)
+	}
+}
@@ -0,0 +1,41 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildSplitPlanPrompt(t *testing.T) {
+	t.Parallel()
+	p := BuildSplitPlanPrompt("### Hunk 1: a.go\n+x", "english")
+	if !strings.Contains(p, "### Hunk 1: a.go") || !strings.Contains(p, "MUST be english") || !strings.Contains(p, splitPlanMarker+" 1, 4") {
+		t.Errorf("BuildSplitPlanPrompt() = %q", p)
+	}
+}
+
+func TestParseSplitPlan(t *testing.T) {
+	t.Parallel()
+	reply := strings.Join([]string{
+		"Here is the plan:",
+		"%%% hunks: 2, #3",
+		"refactor(db): extract the pool",
+		"",
+		"Shares one pool between handlers.",
+		"%%% hunks: 1,3, 9, x",
+		"feat(api): add login",
+		"%%% hunks: 2",
+		"fix: claimed already",
+	}, "\n")
+	got := ParseSplitPlan(reply, 4)
+	want := []SplitGroup{
+		{Hunks: []int{1, 2}, Message: "refactor(db): extract the pool\n\nShares one pool between handlers."},
+		{Hunks: []int{0}, Message: "feat(api): add login"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSplitPlan() = %+v, want %+v", got, want)
+	}
+	if got := ParseSplitPlan("feat: no markers", 2); got != nil {
+		t.Errorf("ParseSplitPlan() without markers = %+v, want none", got)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 type AnthropicClient struct {
     ai.BaseAIClient
+    ai.UsageRecorder
     client anthropic.Client
     model  string
 }
@@ -28,13 +29,14 @@ func NewAnthropicClient(provider, apiKey, model, baseURL string) (*AnthropicClie
     }
     c := anthropic.NewClient(opts...)
     return &AnthropicClient{
-        BaseAIClient: ai.BaseAIClient{Provider: provider},
+        BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model},
         client:       c,
         model:        model,
     }, nil
 }
 
 func (ac *AnthropicClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+    ac.ClearLastUsage()
     params := anthropic.MessageNewParams{
         MaxTokens: 1024,
         Messages: []anthropic.MessageParam{
@@ -42,13 +44,15 @@ func (ac *AnthropicClient) GetCommitMessage(ctx context.Context, prompt string)
         },
         Model: anthropic.Model(ac.model),
     }
+    ac.applySampling(&params)
     resp, err := ac.client.Messages.New(ctx, params)
     if err != nil {
-        return "", fmt.Errorf("failed to get message from Anthropic: %w", err)
+        return "", fmt.Errorf("failed to get message from Anthropic: %w", classify(err))
     }
     if resp == nil || len(resp.Content) == 0 {
         return "", errors.New("no response from Anthropic")
     }
+    ac.recordUsage(resp.Usage)
     var sb strings.Builder
     for _, blk := range resp.Content {
         switch v := blk.AsAny().(type) {
@@ -65,6 +69,7 @@ func (ac *AnthropicClient) GetCommitMessage(ctx context.Context, prompt string)
 
 // StreamCommitMessage streams text deltas from Anthropic SDK.
 func (ac *AnthropicClient) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+    ac.ClearLastUsage()
     params := anthropic.MessageNewParams{
         MaxTokens: 1024,
         Messages: []anthropic.MessageParam{
@@ -72,6 +77,7 @@ func (ac *AnthropicClient) StreamCommitMessage(ctx context.Context, prompt strin
         },
         Model: anthropic.Model(ac.model),
     }
+    ac.applySampling(&params)
     stream := ac.client.Messages.NewStreaming(ctx, params)
     msg := anthropic.Message{}
     for stream.Next() {
@@ -99,8 +105,9 @@ func (ac *AnthropicClient) StreamCommitMessage(ctx context.Context, prompt strin
                 sb.WriteString(v.Text)
             }
         }
-        return sb.String(), err
+        return sb.String(), classify(err)
     }
+    ac.recordUsage(msg.Usage)
     // Build final text
     var sb strings.Builder
     for _, blk := range msg.Content {
@@ -112,6 +119,41 @@ func (ac *AnthropicClient) StreamCommitMessage(ctx context.Context, prompt strin
     return sb.String(), nil
 }
 
+// applySampling sets the temperature of params, when configured. The
+// Messages API has no seed.
+func (ac *AnthropicClient) applySampling(params *anthropic.MessageNewParams) {
+    if t := ac.Sampling.Temperature; t != nil {
+        params.Temperature = anthropic.Float(*t)
+    }
+}
+
+// CheckSampling rejects temperatures above 1, the maximum of the Messages API.
+func (ac *AnthropicClient) CheckSampling(s ai.Sampling) error {
+    if t := s.Temperature; t != nil && (*t < 0 || *t > 1) {
+        return fmt.Errorf("anthropic accepts a temperature between 0 and 1, got %g", *t)
+    }
+    return nil
+}
+
+// classify marks authentication, rate limit, context size, and timeout errors
+// of the API.
+func classify(err error) error {
+    var apiErr *anthropic.Error
+    if errors.As(err, &apiErr) {
+        return ai.ClassifyStatus(err, apiErr.StatusCode)
+    }
+    return err
+}
+
+// recordUsage keeps the token counts Anthropic reported; cache reads count as input.
+func (ac *AnthropicClient) recordUsage(u anthropic.Usage) {
+    ac.SetLastUsage(ai.Usage{
+        InputTokens:  int(u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens),
+        OutputTokens: int(u.OutputTokens),
+        CachedTokens: int(u.CacheReadInputTokens),
+    })
+}
+
 func (ac *AnthropicClient) SanitizeResponse(message, commitType string) string {
     return ac.BaseAIClient.SanitizeResponse(message, commitType)
 }
@@ -122,3 +164,4 @@ func (ac *AnthropicClient) MaybeSummarizeDiff(diff string, maxLength int) (strin
 
 var _ ai.AIClient = (*AnthropicClient)(nil)
 var _ ai.StreamingAIClient = (*AnthropicClient)(nil)
+var _ ai.UsageAIClient = (*AnthropicClient)(nil)
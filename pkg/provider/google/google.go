@@ -2,7 +2,11 @@ package google
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"strings"
 
 	"google.golang.org/genai"
 
@@ -28,16 +32,16 @@ func NewGoogleClient(ctx context.Context, provider, apiKey, model, baseURL strin
 		return nil, fmt.Errorf("error creating google client: %w", err)
 	}
 	return &GoogleClient{
-		BaseAIClient: ai.BaseAIClient{Provider: provider},
+		BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model},
 		client:       client,
 		model:        model,
 	}, nil
 }
 
 func (gc *GoogleClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
-	resp, err := gc.client.Models.GenerateContent(ctx, gc.model, genai.Text(prompt), nil)
+	resp, err := gc.client.Models.GenerateContent(ctx, gc.model, genai.Text(prompt), gc.generateConfig())
 	if err != nil {
-		return "", fmt.Errorf("failed to generate content: %w", err)
+		return "", fmt.Errorf("failed to generate content: %w", classify(err))
 	}
 	text := resp.Text()
 	if text == "" {
@@ -46,6 +50,45 @@ func (gc *GoogleClient) GetCommitMessage(ctx context.Context, prompt string) (st
 	return text, nil
 }
 
+// generateConfig returns the temperature and seed of the requests, or nil to
+// keep the model defaults.
+func (gc *GoogleClient) generateConfig() *genai.GenerateContentConfig {
+	if gc.Sampling.IsZero() {
+		return nil
+	}
+	cfg := &genai.GenerateContentConfig{}
+	if t := gc.Sampling.Temperature; t != nil {
+		cfg.Temperature = genai.Ptr(float32(*t))
+	}
+	if s := gc.Sampling.Seed; s != nil {
+		cfg.Seed = genai.Ptr(int32(*s))
+	}
+	return cfg
+}
+
+// CheckSampling rejects seeds that do not fit the 32 bits Gemini takes.
+func (gc *GoogleClient) CheckSampling(s ai.Sampling) error {
+	if seed := s.Seed; seed != nil && (*seed < math.MinInt32 || *seed > math.MaxInt32) {
+		return fmt.Errorf("google accepts a seed between %d and %d, got %d", math.MinInt32, math.MaxInt32, *seed)
+	}
+	return nil
+}
+
+// classify marks authentication, rate limit, context size, and timeout errors
+// of the API. Gemini answers an invalid API key with 400 API_KEY_INVALID rather
+// than 401.
+func classify(err error) error {
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	status := apiErr.Code
+	if strings.Contains(apiErr.Message, "API_KEY_INVALID") {
+		status = http.StatusUnauthorized
+	}
+	return ai.ClassifyStatus(err, status)
+}
+
 func (gc *GoogleClient) SanitizeResponse(message, commitType string) string {
 	return gc.BaseAIClient.SanitizeResponse(message, commitType)
 }
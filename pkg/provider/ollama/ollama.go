@@ -28,7 +28,7 @@ func NewOllamaClient(provider, baseURL, model string) (*OllamaClient, error) {
     }
     client := api.NewClient(u, http.DefaultClient)
     return &OllamaClient{
-        BaseAIClient: ai.BaseAIClient{Provider: provider},
+        BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model},
         client:       client,
         model:        model,
     }, nil
@@ -41,13 +41,22 @@ func (oc *OllamaClient) GetCommitMessage(ctx context.Context, prompt string) (st
 		Prompt: prompt,
 		Stream: &stream,
 	}
+	if !oc.Sampling.IsZero() {
+		req.Options = make(map[string]any)
+		if t := oc.Sampling.Temperature; t != nil {
+			req.Options["temperature"] = *t
+		}
+		if s := oc.Sampling.Seed; s != nil {
+			req.Options["seed"] = *s
+		}
+	}
 	var response string
 	err := oc.client.Generate(ctx, req, func(resp api.GenerateResponse) error {
 		response = resp.Response
 		return nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("ollama generate failed: %w", err)
+		return "", fmt.Errorf("ollama generate failed: %w", classify(err))
 	}
 	if strings.TrimSpace(response) == "" {
 		return "", errors.New("empty response from Ollama")
@@ -55,6 +64,42 @@ func (oc *OllamaClient) GetCommitMessage(ctx context.Context, prompt string) (st
 	return strings.TrimSpace(response), nil
 }
 
+// classify marks authentication, rate limit, and timeout errors of the server.
+func classify(err error) error {
+	var authErr api.AuthorizationError
+	if errors.As(err, &authErr) {
+		return ai.ClassifyStatus(err, http.StatusUnauthorized)
+	}
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) {
+		return ai.ClassifyStatus(err, statusErr.StatusCode)
+	}
+	return err
+}
+
+// Embed returns one embedding vector per text using the /api/embed endpoint.
+// Without an explicit model the generation model is used.
+func (oc *OllamaClient) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	resp, err := oc.client.Embed(ctx, &api.EmbedRequest{
+		Model: pick(model, oc.model),
+		Input: texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed failed: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+	vectors := make([][]float64, len(resp.Embeddings))
+	for i, e := range resp.Embeddings {
+		vectors[i] = make([]float64, len(e))
+		for j, v := range e {
+			vectors[i][j] = float64(v)
+		}
+	}
+	return vectors, nil
+}
+
 func (oc *OllamaClient) SanitizeResponse(message, commitType string) string {
 	return oc.BaseAIClient.SanitizeResponse(message, commitType)
 }
@@ -71,3 +116,4 @@ func pick(s, dft string) string {
 }
 
 var _ ai.AIClient = (*OllamaClient)(nil)
+var _ ai.EmbeddingAIClient = (*OllamaClient)(nil)
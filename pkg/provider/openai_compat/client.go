@@ -15,6 +15,7 @@ import (
 // It uses the official openai-go SDK and accepts a custom baseURL.
 type Client struct {
     ai.BaseAIClient
+    ai.UsageRecorder
     client openai.Client
     model  string
 }
@@ -24,49 +25,61 @@ func NewCompatClient(provider, apiKey, model, baseURL string) *Client {
     switch {
     case strings.TrimSpace(apiKey) != "" && strings.TrimSpace(baseURL) != "":
         c := openai.NewClient(option.WithAPIKey(apiKey), option.WithBaseURL(strings.TrimRight(baseURL, "/")))
-        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider}, client: c, model: model}
+        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model}, client: c, model: model}
     case strings.TrimSpace(apiKey) != "":
         c := openai.NewClient(option.WithAPIKey(apiKey))
-        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider}, client: c, model: model}
+        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model}, client: c, model: model}
     case strings.TrimSpace(baseURL) != "":
         c := openai.NewClient(option.WithBaseURL(strings.TrimRight(baseURL, "/")))
-        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider}, client: c, model: model}
+        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model}, client: c, model: model}
     default:
         c := openai.NewClient()
-        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider}, client: c, model: model}
+        return &Client{BaseAIClient: ai.BaseAIClient{Provider: provider, Model: model}, client: c, model: model}
     }
 }
 
 func (c *Client) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+    c.ClearLastUsage()
     params := openai.ChatCompletionNewParams{
         Messages: []openai.ChatCompletionMessageParamUnion{
             openai.UserMessage(prompt),
         },
         Model: openai.ChatModel(c.model),
     }
+    c.applySampling(&params)
     resp, err := c.client.Chat.Completions.New(ctx, params)
     if err != nil {
-        return "", fmt.Errorf("failed to get chat completion: %w", err)
+        return "", fmt.Errorf("failed to get chat completion: %w", classify(err))
     }
     if len(resp.Choices) == 0 {
         return "", errors.New("no response from OpenAI-compatible provider")
     }
+    c.recordUsage(resp.Usage)
     return strings.TrimSpace(resp.Choices[0].Message.Content), nil
 }
 
 // StreamCommitMessage streams text deltas via onDelta and returns the final text.
 func (c *Client) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+    c.ClearLastUsage()
     params := openai.ChatCompletionNewParams{
         Messages: []openai.ChatCompletionMessageParamUnion{
             openai.UserMessage(prompt),
         },
         Model: openai.ChatModel(c.model),
     }
+    // Without include_usage, streams report no token counts at all.
+    params.StreamOptions.IncludeUsage = openai.Bool(true)
+    c.applySampling(&params)
     stream := c.client.Chat.Completions.NewStreaming(ctx, params)
     acc := openai.ChatCompletionAccumulator{}
+    // The usage chunk comes last; the accumulator drops its cached tokens.
+    var usage openai.CompletionUsage
     for stream.Next() {
         chunk := stream.Current()
         acc.AddChunk(chunk)
+        if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 {
+            usage = chunk.Usage
+        }
         if len(chunk.Choices) > 0 {
             if d := chunk.Choices[0].Delta.Content; d != "" {
                 onDelta(d)
@@ -74,6 +87,7 @@ func (c *Client) StreamCommitMessage(ctx context.Context, prompt string, onDelta
         }
     }
     if err := stream.Err(); err != nil {
+        err = classify(err)
         // Return whatever was accumulated with error
         if len(acc.Choices) > 0 {
             return acc.Choices[0].Message.Content, err
@@ -83,9 +97,71 @@ func (c *Client) StreamCommitMessage(ctx context.Context, prompt string, onDelta
     if len(acc.Choices) == 0 {
         return "", errors.New("no response from OpenAI-compatible provider")
     }
+    c.recordUsage(usage)
     return acc.Choices[0].Message.Content, nil
 }
 
+// applySampling sets the temperature and seed of params, when configured.
+func (c *Client) applySampling(params *openai.ChatCompletionNewParams) {
+    if t := c.Sampling.Temperature; t != nil {
+        params.Temperature = openai.Float(*t)
+    }
+    if s := c.Sampling.Seed; s != nil {
+        params.Seed = openai.Int(*s)
+    }
+}
+
+// classify marks authentication, rate limit, context size, and timeout errors
+// of the API.
+func classify(err error) error {
+    var apiErr *openai.Error
+    if errors.As(err, &apiErr) {
+        return ai.ClassifyStatus(err, apiErr.StatusCode)
+    }
+    return err
+}
+
+// recordUsage keeps the token counts of a response; streams only carry them
+// when the provider honors stream_options.include_usage.
+func (c *Client) recordUsage(u openai.CompletionUsage) {
+    if u.PromptTokens == 0 && u.CompletionTokens == 0 {
+        return
+    }
+    c.SetLastUsage(ai.Usage{
+        InputTokens:  int(u.PromptTokens),
+        OutputTokens: int(u.CompletionTokens),
+        CachedTokens: int(u.PromptTokensDetails.CachedTokens),
+    })
+}
+
+// DefaultEmbeddingModel is used by Embed when no model is given.
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// Embed returns one embedding vector per text, in input order.
+func (c *Client) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+    if strings.TrimSpace(model) == "" {
+        model = DefaultEmbeddingModel
+    }
+    resp, err := c.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+        Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+        Model: openai.EmbeddingModel(model),
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to create embeddings: %w", err)
+    }
+    if len(resp.Data) != len(texts) {
+        return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
+    }
+    vectors := make([][]float64, len(texts))
+    for _, d := range resp.Data {
+        if d.Index < 0 || int(d.Index) >= len(vectors) {
+            return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+        }
+        vectors[d.Index] = d.Embedding
+    }
+    return vectors, nil
+}
+
 func (c *Client) SanitizeResponse(message, commitType string) string {
     return c.BaseAIClient.SanitizeResponse(message, commitType)
 }
@@ -96,3 +172,5 @@ func (c *Client) MaybeSummarizeDiff(diff string, maxLength int) (string, bool) {
 
 var _ ai.AIClient = (*Client)(nil)
 var _ ai.StreamingAIClient = (*Client)(nil)
+var _ ai.EmbeddingAIClient = (*Client)(nil)
+var _ ai.UsageAIClient = (*Client)(nil)
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// htmlFormatter writes the report as a self-contained, styled HTML page.
+type htmlFormatter struct{}
+
+var htmlPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; color: #24292f; line-height: 1.5; }
+h1 { border-bottom: 2px solid #6f42c1; padding-bottom: .3rem; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: .2rem 1rem; color: #57606a; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+code { background: #f6f8fa; padding: .1rem .3rem; border-radius: 4px; }
+footer { margin-top: 2rem; color: #8c959f; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Fields}}<dl>
+{{range .Fields}}<dt>{{.Name}}</dt><dd>{{.Value}}</dd>
+{{end}}</dl>
+{{end}}{{.Body}}
+<footer>Generated by ai-commit{{if .Version}} {{.Version}}{{end}}{{if .Source}} with {{.Source}}{{end}}</footer>
+</body>
+</html>
+`))
+
+func (htmlFormatter) Format(w io.Writer, r Report) error {
+	return htmlPage.Execute(w, struct {
+		Title   string
+		Fields  []Field
+		Body    template.HTML
+		Version string
+		Source  string
+	}{r.Title, r.Fields, markdownToHTML(r.Body), r.Version, r.Source.String()})
+}
+
+var (
+	headingLine  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listItemLine = regexp.MustCompile(`^\s*(?:[-*]|\d+\.)\s+(.*)$`)
+	inlineCode   = regexp.MustCompile("`([^`]+)`")
+	boldText     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// markdownToHTML converts the markdown the review and summary prompts ask for
+// (headings, bullet lists, paragraphs, code spans, and bold text) to HTML. Other
+// markup is kept as escaped text.
+func markdownToHTML(md string) template.HTML {
+	var b strings.Builder
+	var paragraph []string
+	inList := false
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(md), "\n") {
+		switch m := headingLine.FindStringSubmatch(line); {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case m != nil:
+			flush()
+			level := string(rune('0' + min(len(m[1])+1, 6)))
+			b.WriteString("<h" + level + ">" + inlineHTML(m[2]) + "</h" + level + ">\n")
+		case listItemLine.MatchString(line):
+			if len(paragraph) > 0 {
+				flush()
+			}
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			b.WriteString("<li>" + inlineHTML(listItemLine.FindStringSubmatch(line)[1]) + "</li>\n")
+		default:
+			if inList {
+				flush()
+			}
+			paragraph = append(paragraph, inlineHTML(strings.TrimSpace(line)))
+		}
+	}
+	flush()
+	return template.HTML(b.String())
+}
+
+func inlineHTML(text string) string {
+	text = html.EscapeString(text)
+	text = inlineCode.ReplaceAllString(text, "<code>$1</code>")
+	return boldText.ReplaceAllString(text, "<strong>$1</strong>")
+}
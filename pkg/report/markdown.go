@@ -0,0 +1,57 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownFormatter writes the report as markdown, ready to paste into a pull
+// request description.
+type markdownFormatter struct{}
+
+func (markdownFormatter) Format(w io.Writer, r Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", r.Title)
+	for _, f := range r.Fields {
+		fmt.Fprintf(&b, "**%s:** %s  \n", f.Name, f.Value)
+	}
+	if len(r.Fields) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.TrimSpace(r.Body) + "\n")
+	if !r.Source.IsZero() {
+		fmt.Fprintf(&b, "\n<sub>Generated by %s</sub>\n", r.Source)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// jsonFormatter writes the report as an indented JSON object.
+type jsonFormatter struct{}
+
+type jsonFinding struct {
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+	Body string `json:"body"`
+}
+
+func (jsonFormatter) Format(w io.Writer, r Report) error {
+	out := struct {
+		Title    string        `json:"title"`
+		Fields   []Field       `json:"fields,omitempty"`
+		Body     string        `json:"body"`
+		Findings []jsonFinding `json:"findings"`
+		Source   *Source       `json:"source,omitempty"`
+	}{Title: r.Title, Fields: r.Fields, Body: strings.TrimSpace(r.Body), Findings: []jsonFinding{}}
+	if !r.Source.IsZero() {
+		out.Source = &r.Source
+	}
+	for _, f := range r.Findings {
+		out.Findings = append(out.Findings, jsonFinding(f))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,132 @@
+// Package report renders the output of review and summarize for sharing:
+// markdown for pull request descriptions, JSON for scripts, SARIF for code
+// scanning uploads, and a styled HTML page.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+// Report is the result of a review or a commit summary.
+type Report struct {
+	// Title names the report, e.g. "AI Code Review".
+	Title string
+	// Fields are facts shown above the body, such as the summarized commit.
+	Fields []Field
+	// Body is the AI response, in markdown.
+	Body string
+	// Findings are the review findings parsed from Body; summaries have none.
+	Findings []review.Finding
+	// Version is the ai-commit version, recorded as the SARIF tool version.
+	Version string
+	// Source records which provider and model produced Body, and when.
+	Source Source
+}
+
+// Source identifies the AI response behind a report, so results can be traced
+// when comparing providers or models.
+type Source struct {
+	Provider    string    `json:"provider,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	GeneratedAt time.Time `json:"generatedAt,omitzero"`
+	// Tokens is the token usage of the request, when the provider client
+	// tracks it.
+	Tokens *Tokens `json:"tokens,omitempty"`
+	// ElapsedMS is how long the request took, in milliseconds.
+	ElapsedMS int64 `json:"elapsedMs,omitempty"`
+}
+
+// Tokens is the token usage of an AI request.
+type Tokens struct {
+	Input  int `json:"input"`
+	Output int `json:"output"`
+	// Cached is the part of Input served from the provider's prompt cache.
+	Cached int `json:"cached,omitempty"`
+	// Estimated is set when the provider did not report usage and the counts
+	// were estimated from the text length.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// SourceOf records that client produced a response just now, with the usage
+// and latency of its last request when the client keeps them.
+func SourceOf(client ai.AIClient) Source {
+	s := Source{
+		Provider:    client.ProviderName(),
+		Model:       ai.ModelOf(client),
+		GeneratedAt: time.Now(),
+	}
+	if stats, ok := ai.StatsOf(client); ok {
+		s.Tokens = &Tokens{Input: stats.InputTokens, Output: stats.OutputTokens, Cached: stats.CachedTokens, Estimated: stats.Estimated}
+		s.ElapsedMS = stats.Latency.Milliseconds()
+	}
+	return s
+}
+
+// IsZero reports whether nothing is known about the source.
+func (s Source) IsZero() bool {
+	return s.Provider == "" && s.Model == "" && s.GeneratedAt.IsZero()
+}
+
+// String describes the source, e.g. "openai (gpt-4o), 2026-10-16 09:30 UTC".
+func (s Source) String() string {
+	name := s.Provider
+	if s.Model != "" {
+		name = strings.TrimSpace(name + " (" + s.Model + ")")
+	}
+	if s.GeneratedAt.IsZero() {
+		return name
+	}
+	when := s.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC")
+	if name == "" {
+		return when
+	}
+	return name + ", " + when
+}
+
+// Field is a named fact of a report.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Formatter writes a report in one output format.
+type Formatter interface {
+	Format(w io.Writer, r Report) error
+}
+
+var formatters = map[string]Formatter{
+	"markdown": markdownFormatter{},
+	"json":     jsonFormatter{},
+	"sarif":    sarifFormatter{},
+	"html":     htmlFormatter{},
+}
+
+// New returns the formatter for format ("md" is short for "markdown").
+func New(format string) (Formatter, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "md" {
+		format = "markdown"
+	}
+	f, ok := formatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q (use %s)", format, strings.Join(Formats(), ", "))
+	}
+	return f, nil
+}
+
+// Formats lists the supported formats.
+func Formats() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
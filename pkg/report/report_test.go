@@ -0,0 +1,204 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/review"
+)
+
+var sample = Report{
+	Title:  "AI Code Review",
+	Fields: []Field{{Name: "Diff", Value: "staged changes"}},
+	Body:   "### Findings\n- main.go:13: division by zero when `b` is 0\n- The change lacks tests.",
+	Findings: []review.Finding{
+		{Path: "main.go", Line: 13, Body: "division by zero when `b` is 0"},
+		{Body: "The change lacks tests."},
+	},
+	Version: "1.2.3",
+}
+
+func format(t *testing.T, name string, r Report) string {
+	t.Helper()
+	f, err := New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Format(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	if _, err := New("MD"); err != nil {
+		t.Errorf("New(MD) = %v", err)
+	}
+	if _, err := New("pdf"); err == nil || !strings.Contains(err.Error(), "html, json, markdown, sarif") {
+		t.Errorf("New(pdf) = %v, want an error listing the formats", err)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+	want := "## AI Code Review\n\n**Diff:** staged changes  \n\n" + sample.Body + "\n"
+	if got := format(t, "markdown", sample); got != want {
+		t.Errorf("markdown =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	var got struct {
+		Title    string
+		Findings []struct {
+			Path string
+			Line int
+			Body string
+		}
+	}
+	if err := json.Unmarshal([]byte(format(t, "json", sample)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != sample.Title || len(got.Findings) != 2 || got.Findings[0].Path != "main.go" || got.Findings[0].Line != 13 {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct{ Name, Version string }
+			}
+			Results []struct {
+				RuleID    string
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(format(t, "sarif", sample)), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Version != "1.2.3" {
+		t.Fatalf("sarif = %+v", log)
+	}
+	results := log.Runs[0].Results
+	// The finding without a file has no location for code scanning.
+	if len(results) != 1 {
+		t.Fatalf("sarif results = %+v, want only the located finding", results)
+	}
+	loc := results[0].Locations[0].PhysicalLocation
+	if results[0].RuleID != sarifRuleID || loc.ArtifactLocation.URI != "main.go" || loc.Region.StartLine != 13 {
+		t.Errorf("sarif result = %+v", results[0])
+	}
+}
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+	r := sample
+	r.Body += "\n\n<script>alert(1)</script> is **bad**"
+	got := format(t, "html", r)
+	for _, want := range []string{
+		"<title>AI Code Review</title>",
+		"<dt>Diff</dt><dd>staged changes</dd>",
+		"<h4>Findings</h4>",
+		"<li>main.go:13: division by zero when <code>b</code> is 0</li>",
+		"<p>&lt;script&gt;alert(1)&lt;/script&gt; is <strong>bad</strong></p>",
+		"Generated by ai-commit 1.2.3",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("html lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	r := sample
+	r.Source = Source{Provider: "openai", Model: "gpt-4o", GeneratedAt: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)}
+	const want = "openai (gpt-4o), 2026-10-16 09:30 UTC"
+	if got := r.Source.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := format(t, "markdown", r); !strings.HasSuffix(got, "\n<sub>Generated by "+want+"</sub>\n") {
+		t.Errorf("markdown lacks the source:\n%s", got)
+	}
+	if got := format(t, "html", r); !strings.Contains(got, "Generated by ai-commit 1.2.3 with "+want) {
+		t.Errorf("html lacks the source:\n%s", got)
+	}
+
+	var doc struct{ Source Source }
+	if err := json.Unmarshal([]byte(format(t, "json", r)), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Source != r.Source {
+		t.Errorf("json source = %+v, want %+v", doc.Source, r.Source)
+	}
+
+	var log struct {
+		Runs []struct {
+			Invocations []struct{ EndTimeUTC string }
+			Properties  map[string]string
+		}
+	}
+	if err := json.Unmarshal([]byte(format(t, "sarif", r)), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if run.Properties["provider"] != "openai" || run.Properties["model"] != "gpt-4o" ||
+		len(run.Invocations) != 1 || run.Invocations[0].EndTimeUTC != "2026-10-16T09:30:00Z" {
+		t.Errorf("sarif run = %+v", run)
+	}
+
+	// Without a source the output is unchanged.
+	if got := format(t, "json", sample); strings.Contains(got, `"source"`) {
+		t.Errorf("json without a source = %s", got)
+	}
+}
+
+type statsClient struct {
+	ai.BaseAIClient
+	stats ai.CallStats
+}
+
+func (c *statsClient) GetCommitMessage(context.Context, string) (string, error) { return "", nil }
+
+func (c *statsClient) LastCallStats() (ai.CallStats, bool) { return c.stats, true }
+
+func TestSourceOf(t *testing.T) {
+	t.Parallel()
+	client := &statsClient{
+		BaseAIClient: ai.BaseAIClient{Provider: "openai", Model: "gpt-4o"},
+		stats:        ai.CallStats{Latency: 1500 * time.Millisecond, Usage: ai.Usage{InputTokens: 1200, OutputTokens: 40, CachedTokens: 1000}},
+	}
+	s := SourceOf(client)
+	if s.Provider != "openai" || s.Model != "gpt-4o" || s.GeneratedAt.IsZero() || s.ElapsedMS != 1500 {
+		t.Errorf("SourceOf = %+v", s)
+	}
+	if s.Tokens == nil || *s.Tokens != (Tokens{Input: 1200, Output: 40, Cached: 1000}) {
+		t.Errorf("Tokens = %+v", s.Tokens)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"tokens":{"input":1200,"output":40,"cached":1000},"elapsedMs":1500`) {
+		t.Errorf("json = %s", data)
+	}
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// sarifRuleID identifies AI review findings in SARIF output.
+const sarifRuleID = "ai-review"
+
+// sarifFormatter writes the findings as a SARIF 2.1.0 log, which GitHub code
+// scanning accepts. Code scanning needs a location for every result, so findings
+// not tied to a file are left out; the full review stays in the other formats.
+type sarifFormatter struct{}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	EndTimeUTC          string `json:"endTimeUtc,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+func (sarifFormatter) Format(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "ai-commit",
+			Version:        r.Version,
+			InformationURI: "https://github.com/renatogalera/ai-commit",
+			Rules:          []sarifRule{{ID: sarifRuleID, ShortDescription: sarifMessage{Text: "AI code review finding"}}},
+		}},
+		Results: []sarifResult{},
+	}
+	// The provider and model go in the run's property bag; the generation time
+	// is the end time of the invocation.
+	if r.Source.Provider != "" || r.Source.Model != "" {
+		run.Properties = map[string]string{}
+		if r.Source.Provider != "" {
+			run.Properties["provider"] = r.Source.Provider
+		}
+		if r.Source.Model != "" {
+			run.Properties["model"] = r.Source.Model
+		}
+	}
+	if !r.Source.GeneratedAt.IsZero() {
+		run.Invocations = []sarifInvocation{{
+			ExecutionSuccessful: true,
+			EndTimeUTC:          r.Source.GeneratedAt.UTC().Format(time.RFC3339),
+		}}
+	}
+	for _, f := range r.Findings {
+		if f.Path == "" {
+			continue
+		}
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = f.Path
+		// A finding on the whole file points at its first line.
+		loc.PhysicalLocation.Region.StartLine = max(f.Line, 1)
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    sarifRuleID,
+			Level:     "warning",
+			Message:   sarifMessage{Text: f.Body},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,114 @@
+// Package review parses AI code review responses into findings and maps them
+// onto the lines of a diff, for posting to pull and merge requests.
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NoFindings is the exact reply the review prompts ask for when nothing is wrong.
+const NoFindings = "No issues found."
+
+// Finding is one review finding; Line is 0 when it is not tied to a line.
+type Finding struct {
+	Path string
+	Line int
+	Body string
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return f.Body
+	}
+	if f.Line == 0 {
+		return fmt.Sprintf("`%s`: %s", f.Path, f.Body)
+	}
+	return fmt.Sprintf("`%s:%d`: %s", f.Path, f.Line, f.Body)
+}
+
+var (
+	bulletPattern   = regexp.MustCompile(`^\s*(?:[-*]|\d+\.)\s+(.+)$`)
+	locationPattern = regexp.MustCompile("^`?([^\\s`:]+\\.[^\\s`:]+|[^\\s`:]+/[^\\s`:]+)(?::(\\d+)(?:-\\d+)?)?`?:\\s*(.+)$")
+)
+
+// ParseFindings reads the bullets of a CI review response; a NoFindings reply
+// has none. Bullets of the form "path:line: text" (or "path: text") are tied to
+// that file and line.
+func ParseFindings(response string) []Finding {
+	var findings []Finding
+	for _, line := range strings.Split(response, "\n") {
+		m := bulletPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		text := strings.TrimSpace(m[1])
+		if strings.EqualFold(strings.TrimSuffix(text, "."), strings.TrimSuffix(NoFindings, ".")) {
+			continue
+		}
+		if loc := locationPattern.FindStringSubmatch(text); loc != nil {
+			n, _ := strconv.Atoi(loc[2])
+			findings = append(findings, Finding{Path: loc[1], Line: n, Body: strings.TrimSpace(loc[3])})
+			continue
+		}
+		findings = append(findings, Finding{Body: text})
+	}
+	return findings
+}
+
+// DiffLines returns, per file, the lines of the new version that appear in diff
+// and can carry review comments, mapped to their line in the old version: the
+// old line for context lines and 0 for added lines.
+func DiffLines(diff string) map[string]map[int]int {
+	lines := make(map[string]map[int]int)
+	var path string
+	oldNext, newNext := 0, 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path, oldNext, newNext = "", 0, 0
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if path == "/dev/null" {
+				path = ""
+			}
+		case strings.HasPrefix(line, "@@ "):
+			oldNext, newNext = hunkStarts(line)
+		case path == "" || newNext == 0:
+		case strings.HasPrefix(line, "+"):
+			fileLines(lines, path)[newNext] = 0
+			newNext++
+		case strings.HasPrefix(line, " "):
+			fileLines(lines, path)[newNext] = oldNext
+			oldNext++
+			newNext++
+		case strings.HasPrefix(line, "-"):
+			oldNext++
+		}
+	}
+	return lines
+}
+
+func fileLines(lines map[string]map[int]int, path string) map[int]int {
+	if lines[path] == nil {
+		lines[path] = make(map[int]int)
+	}
+	return lines[path]
+}
+
+// hunkStarts returns the first old and new line of a "@@ -a,b +c,d @@" header.
+func hunkStarts(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	return rangeStart(fields[1], "-"), rangeStart(fields[2], "+")
+}
+
+func rangeStart(field, sign string) int {
+	start, _, _ := strings.Cut(strings.TrimPrefix(field, sign), ",")
+	n, _ := strconv.Atoi(start)
+	return n
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFindings(t *testing.T) {
+	t.Parallel()
+	resp := "Here is the review:\n" +
+		"- main.go:13: division by zero when b is 0\n" +
+		"- `pkg/a/b.go:7-9`: error is ignored\n" +
+		"* README.md: typo in heading\n" +
+		"- The change lacks tests.\n"
+	want := []Finding{
+		{Path: "main.go", Line: 13, Body: "division by zero when b is 0"},
+		{Path: "pkg/a/b.go", Line: 7, Body: "error is ignored"},
+		{Path: "README.md", Body: "typo in heading"},
+		{Body: "The change lacks tests."},
+	}
+	if got := ParseFindings(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFindings() = %+v, want %+v", got, want)
+	}
+	if got := ParseFindings(NoFindings); len(got) != 0 {
+		t.Errorf("ParseFindings(NoFindings) = %+v, want none", got)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := a / b
+ 	fmt.Println(a, b)
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+	want := map[string]map[int]int{"main.go": {10: 10, 11: 0, 12: 0, 13: 12}}
+	if got := DiffLines(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines() = %v, want %v", got, want)
+	}
+}
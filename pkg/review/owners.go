@@ -0,0 +1,192 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CodeownersPaths are where GitHub and GitLab look for a CODEOWNERS file, in
+// the order they look.
+var CodeownersPaths = []string{".github/CODEOWNERS", ".gitlab/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// OwnerRule is one CODEOWNERS line: the owners of the paths matching Pattern.
+type OwnerRule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// ParseCodeowners reads a CODEOWNERS file. Comments and GitLab section headers
+// are skipped; a pattern without owners leaves its paths unowned.
+func ParseCodeowners(text string) []OwnerRule {
+	var rules []OwnerRule
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		rules = append(rules, OwnerRule{Pattern: fields[0], Owners: fields[1:], re: ownerPattern(fields[0])})
+	}
+	return rules
+}
+
+// ownerPattern compiles a CODEOWNERS pattern, which follows the gitignore
+// rules: a pattern with a leading or inner slash is relative to the repository
+// root, others match at any depth, and a directory matches everything in it.
+func ownerPattern(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dir {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(b.String())
+}
+
+// Owners returns the owners of path, those of the last rule matching it, as
+// GitHub resolves them.
+func Owners(rules []OwnerRule, path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].re.MatchString(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Reviewer is a suggested reviewer of a change.
+type Reviewer struct {
+	// Name is a CODEOWNERS owner ("@octo", "@org/team", or an email) or a
+	// "Name <email>" identity from git blame.
+	Name string
+	// Owns lists the changed paths CODEOWNERS assigns to the reviewer.
+	Owns []string
+	// Lines counts the lines of the changed files git blame credits to the
+	// reviewer.
+	Lines int
+}
+
+// Reason explains why the reviewer was suggested.
+func (r Reviewer) Reason() string {
+	var reasons []string
+	if len(r.Owns) > 0 {
+		reasons = append(reasons, "code owner of "+summarizePaths(r.Owns))
+	}
+	if r.Lines > 0 {
+		reasons = append(reasons, fmt.Sprintf("wrote %d line(s) of the changed files", r.Lines))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// summarizePaths lists up to three paths, then how many more there are.
+func summarizePaths(paths []string) string {
+	if len(paths) <= 3 {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:3], ", "), len(paths)-3)
+}
+
+// SuggestReviewers ranks reviewers of the changed paths: code owners first, by
+// how many paths they own, then the authors blame credits with the most lines.
+// blame maps each path to its authors' line counts. An owner given by email and
+// the blame author with that email are one reviewer. Reviewers whose name or
+// email is in exclude, such as the author of the change, are left out; at most
+// limit reviewers are returned.
+func SuggestReviewers(paths []string, rules []OwnerRule, blame map[string]map[string]int, exclude []string, limit int) []Reviewer {
+	byKey := make(map[string]*Reviewer)
+	reviewer := func(name string) *Reviewer {
+		key := strings.ToLower(name)
+		if email := identityEmail(name); email != "" {
+			key = strings.ToLower(email)
+		}
+		if r, ok := byKey[key]; ok {
+			return r
+		}
+		r := &Reviewer{Name: name}
+		byKey[key] = r
+		return r
+	}
+	for _, path := range paths {
+		for _, owner := range Owners(rules, path) {
+			r := reviewer(owner)
+			r.Owns = append(r.Owns, path)
+		}
+	}
+	for _, path := range paths {
+		for author, lines := range blame[path] {
+			r := reviewer(author)
+			r.Lines += lines
+			if strings.Contains(author, "<") {
+				r.Name = author
+			}
+		}
+	}
+
+	excluded := make(map[string]bool)
+	for _, e := range exclude {
+		excluded[strings.ToLower(e)] = true
+	}
+	var reviewers []Reviewer
+	for key, r := range byKey {
+		if excluded[key] || excluded[strings.ToLower(r.Name)] {
+			continue
+		}
+		reviewers = append(reviewers, *r)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		a, b := reviewers[i], reviewers[j]
+		if len(a.Owns) != len(b.Owns) {
+			return len(a.Owns) > len(b.Owns)
+		}
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return a.Name < b.Name
+	})
+	if limit > 0 && len(reviewers) > limit {
+		reviewers = reviewers[:limit]
+	}
+	return reviewers
+}
+
+// identityEmail returns the email of a "Name <email>" identity or a bare email
+// address, and "" for handles such as "@octo".
+func identityEmail(name string) string {
+	if i := strings.LastIndex(name, "<"); i >= 0 && strings.HasSuffix(name, ">") {
+		return name[i+1 : len(name)-1]
+	}
+	if strings.Contains(name, "@") && !strings.HasPrefix(name, "@") {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+)
+
+const codeowners = `# Default owners
+*                 @octo/core
+*.md              @docs-team # docs
+/build/           @ops
+apps/             @apps
+pkg/**/auth.go    pat@example.com
+/vendor/
+
+[Frontend]
+/web/*.ts         @web
+`
+
+func TestOwners(t *testing.T) {
+	t.Parallel()
+	rules := ParseCodeowners(codeowners)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@octo/core"}},
+		{"docs/guide.md", []string{"@docs-team"}},
+		{"build/ci/run.sh", []string{"@ops"}},
+		{"tools/build/run.sh", []string{"@octo/core"}},
+		{"services/apps/api.go", []string{"@apps"}},
+		{"pkg/auth.go", []string{"pat@example.com"}},
+		{"pkg/a/b/auth.go", []string{"pat@example.com"}},
+		{"vendor/lib/x.go", []string{}},
+		{"web/app.ts", []string{"@web"}},
+		{"web/lib/app.ts", []string{"@octo/core"}},
+	}
+	for _, tt := range tests {
+		if got := Owners(rules, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+	if got := Owners(ParseCodeowners("/docs/ @docs"), "main.go"); got != nil {
+		t.Errorf("Owners of an unmatched path = %q, want none", got)
+	}
+}
+
+func TestSuggestReviewers(t *testing.T) {
+	t.Parallel()
+	rules := ParseCodeowners("* @octo\npkg/ pat@example.com\n")
+	blame := map[string]map[string]int{
+		"pkg/a.go": {"Pat Doe <pat@example.com>": 10, "Sam Roe <sam@example.com>": 30, "Me <me@example.com>": 50},
+		"main.go":  {"Sam Roe <sam@example.com>": 5, "Kim Poe <kim@example.com>": 2},
+	}
+	got := SuggestReviewers([]string{"pkg/a.go", "main.go"}, rules, blame, []string{"me@example.com"}, 3)
+	want := []Reviewer{
+		{Name: "Pat Doe <pat@example.com>", Owns: []string{"pkg/a.go"}, Lines: 10},
+		{Name: "@octo", Owns: []string{"main.go"}},
+		{Name: "Sam Roe <sam@example.com>", Lines: 35},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestReviewers() = %+v, want %+v", got, want)
+	}
+	if reason := got[0].Reason(); reason != "code owner of pkg/a.go; wrote 10 line(s) of the changed files" {
+		t.Errorf("Reason() = %q", reason)
+	}
+}
@@ -0,0 +1,106 @@
+// Package routing picks a provider and model for a diff based on which paths
+// dominate it. It sits above the provider registry: it only decides which
+// provider/model to use and leaves client construction to the caller.
+package routing
+
+import (
+	"strings"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/glob"
+)
+
+// minShare is the fraction of changed lines a rule must cover to be selected.
+const minShare = 0.5
+
+// Decision is the rule selected for a diff and how much of the diff it covers.
+type Decision struct {
+	Rule config.RouteRule
+	// Pattern is the first pattern of Rule that matched, for display.
+	Pattern string
+	// Share is the fraction of changed lines in files matched by Rule (0..1).
+	Share float64
+}
+
+// Select returns the rule whose paths cover the largest share of changed lines
+// in diff, provided that share is more than half. Earlier rules win ties.
+func Select(diff string, rules []config.RouteRule) (Decision, bool) {
+	if len(rules) == 0 {
+		return Decision{}, false
+	}
+	files, order := changedLines(diff)
+	total := 0
+	for _, n := range files {
+		total += n
+	}
+	if total == 0 {
+		return Decision{}, false
+	}
+
+	var best Decision
+	bestLines := 0
+	for _, rule := range rules {
+		lines, pattern := 0, ""
+		for _, f := range order {
+			if p, ok := matchAny(rule.Paths, f); ok {
+				lines += files[f]
+				if pattern == "" {
+					pattern = p
+				}
+			}
+		}
+		if lines > bestLines {
+			bestLines = lines
+			best = Decision{Rule: rule, Pattern: pattern, Share: float64(lines) / float64(total)}
+		}
+	}
+	if best.Share <= minShare {
+		return Decision{}, false
+	}
+	return best, true
+}
+
+// changedLines counts added and removed lines per file. A file with only header
+// changes (e.g. a rename or mode change) counts as one line.
+func changedLines(diff string) (map[string]int, []string) {
+	files := make(map[string]int)
+	var order []string
+	current := ""
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = filePath(line)
+			if _, seen := files[current]; current != "" && !seen {
+				files[current] = 0
+				order = append(order, current)
+			}
+		case current == "", strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			files[current]++
+		}
+	}
+	for f, n := range files {
+		if n == 0 {
+			files[f] = 1
+		}
+	}
+	return files, order
+}
+
+// filePath extracts the new path from a "diff --git a/X b/Y" header.
+func filePath(header string) string {
+	parts := strings.Fields(header)
+	if len(parts) < 4 {
+		return ""
+	}
+	return strings.TrimPrefix(parts[3], "b/")
+}
+
+func matchAny(patterns []string, file string) (string, bool) {
+	for _, p := range patterns {
+		if glob.Match(p, file) {
+			return p, true
+		}
+	}
+	return "", false
+}
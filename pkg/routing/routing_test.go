@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+)
+
+func fileDiff(path string, lines int) string {
+	var b strings.Builder
+	b.WriteString("diff --git a/" + path + " b/" + path + "\n--- a/" + path + "\n+++ b/" + path + "\n@@ -1 +1 @@\n")
+	for i := 0; i < lines; i++ {
+		b.WriteString("+line\n")
+	}
+	return b.String()
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	rules := []config.RouteRule{
+		{Paths: []string{"docs/**", "**/*.md"}, Provider: "ollama", Model: "llama3"},
+		{Paths: []string{"pkg/crypto/**"}, Provider: "anthropic", CodeReview: true},
+	}
+
+	decision, ok := Select(fileDiff("docs/a.md", 8)+fileDiff("main.go", 2), rules)
+	if !ok || decision.Rule.Provider != "ollama" || decision.Pattern != "docs/**" {
+		t.Fatalf("expected docs rule, got %+v, %v", decision, ok)
+	}
+	if decision.Share != 0.8 {
+		t.Errorf("Share = %f, want 0.8", decision.Share)
+	}
+
+	decision, ok = Select(fileDiff("pkg/crypto/aes.go", 6)+fileDiff("README.md", 4), rules)
+	if !ok || !decision.Rule.CodeReview {
+		t.Errorf("expected crypto rule, got %+v, %v", decision, ok)
+	}
+
+	if _, ok := Select(fileDiff("docs/a.md", 5)+fileDiff("main.go", 5), rules); ok {
+		t.Error("expected no rule when no paths dominate")
+	}
+	if _, ok := Select(fileDiff("docs/a.md", 5), nil); ok {
+		t.Error("expected no rule without routing config")
+	}
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// Session is a snapshot of an in-progress commit message that can be resumed later.
+type Session struct {
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"savedAt"`
+
+	// Diff is the diff the prompt was built from (after filters and overrides),
+	// BaseDiff the filtered diff before overrides, and RawDiff the unfiltered staged diff.
+	Diff      string            `json:"diff"`
+	BaseDiff  string            `json:"baseDiff,omitempty"`
+	RawDiff   string            `json:"rawDiff,omitempty"`
+	Overrides git.HunkOverrides `json:"overrides,omitempty"`
+
+	Prompt      string `json:"prompt"`
+	Language    string `json:"language,omitempty"`
+	CommitType  string `json:"commitType,omitempty"`
+	UserContext string `json:"userContext,omitempty"`
+	Intent      string `json:"intent,omitempty"`
+
+	// Message is the current (possibly edited) message; Candidates are all messages generated so far.
+	Message    string   `json:"message,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// Dir returns the directory sessions are stored in for the current repository.
+func Dir() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", git.ErrNotARepo, err)
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), "ai-commit", "sessions"), nil
+}
+
+// DefaultName builds a session name from the current time.
+func DefaultName(now time.Time) string {
+	return "session-" + now.Format("20060102-150405")
+}
+
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("session name is empty")
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	return nil
+}
+
+// Save writes s to dir as <name>.json, replacing any session with the same name.
+func Save(dir string, s Session) (string, error) {
+	if err := validateName(s.Name); err != nil {
+		return "", err
+	}
+	if s.SavedAt.IsZero() {
+		s.SavedAt = time.Now()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+	path := filepath.Join(dir, s.Name+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write session: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads the session called name from dir.
+func Load(dir, name string) (*Session, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("session %q not found", name)
+		}
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode session %q: %w", name, err)
+	}
+	return &s, nil
+}
+
+// List returns the sessions in dir, most recently saved first.
+func List(dir string) ([]Session, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	var sessions []Session
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		s, err := Load(dir, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].SavedAt.After(sessions[j].SavedAt) })
+	return sessions, nil
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	in := Session{
+		Name:       "big-refactor",
+		Diff:       "diff --git a/a.go b/a.go\n+x := 1",
+		Prompt:     "prompt text",
+		CommitType: "refactor",
+		Message:    "refactor(a): extract helper",
+		Candidates: []string{"refactor: first", "refactor(a): extract helper"},
+		Overrides:  git.HunkOverrides{"go.sum": git.HunkInclude},
+	}
+	path, err := Save(dir, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, "big-refactor.json") {
+		t.Errorf("unexpected session path %q", path)
+	}
+
+	out, err := Load(dir, "big-refactor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Message != in.Message || out.Diff != in.Diff || len(out.Candidates) != 2 {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+	if out.Overrides["go.sum"] != git.HunkInclude {
+		t.Errorf("expected overrides to survive, got %+v", out.Overrides)
+	}
+	if out.SavedAt.IsZero() {
+		t.Error("expected SavedAt to be set")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	t.Parallel()
+	if _, err := Load(t.TempDir(), "nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"", " ", "../x", "a/b", ".."} {
+		if _, err := Save(t.TempDir(), Session{Name: name}); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+}
+
+func TestListNewestFirst(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"old", "new"} {
+		if _, err := Save(dir, Session{Name: name, SavedAt: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sessions, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].Name != "new" {
+		t.Errorf("unexpected order: %+v", sessions)
+	}
+}
+
+func TestListMissingDir(t *testing.T) {
+	t.Parallel()
+	sessions, err := List(t.TempDir() + "/missing")
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("expected empty list, got %v, %v", sessions, err)
+	}
+}
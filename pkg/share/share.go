@@ -0,0 +1,166 @@
+// Package share renders a short, chat-ready summary of a commit (hash, subject,
+// key bullets, and a link) and copies it to the clipboard.
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultTemplate is the snippet used when no shareTemplate is configured.
+const DefaultTemplate = "{short} {subject}\n{bullets}\n{url}"
+
+// maxBullets caps the body bullets a snippet repeats.
+const maxBullets = 5
+
+// Commit is what a snippet can show.
+type Commit struct {
+	Hash    string
+	Message string
+	// URL is the web page of the commit, if known.
+	URL string
+}
+
+// Render fills tmpl with c. The placeholders are {hash}, {short} (the first 7
+// characters of the hash), {subject}, {bullets} (the bullet lines of the body,
+// at most five), {body} (the body without trailers), and {url}. Lines left
+// empty by empty placeholders are dropped.
+func Render(tmpl string, c Commit) string {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultTemplate
+	}
+	short := c.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	subject, body, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	body = stripTrailers(strings.TrimSpace(body))
+	out := strings.NewReplacer(
+		"{hash}", c.Hash,
+		"{short}", short,
+		"{subject}", strings.TrimSpace(subject),
+		"{bullets}", strings.Join(bullets(body), "\n"),
+		"{body}", body,
+		"{url}", c.URL,
+	).Replace(tmpl)
+
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bullets returns the "-", "*", or "•" lines of body, normalized to "- ".
+func bullets(body string) []string {
+	var out []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"- ", "* ", "• "} {
+			if strings.HasPrefix(line, marker) {
+				out = append(out, "- "+strings.TrimSpace(strings.TrimPrefix(line, marker)))
+				break
+			}
+		}
+		if len(out) == maxBullets {
+			break
+		}
+	}
+	return out
+}
+
+// stripTrailers drops the last paragraph of body when every line of it is a
+// "Key: value" trailer.
+func stripTrailers(body string) string {
+	i := strings.LastIndex(body, "\n\n")
+	last := body[i+1:]
+	for _, line := range strings.Split(strings.TrimSpace(last), "\n") {
+		key, _, ok := strings.Cut(line, ": ")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return body
+		}
+	}
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(body[:i])
+}
+
+// CommitURL returns the web page of commit hash for a remote URL in SSH or
+// HTTPS form: "/-/commit/" on GitLab hosts, "/commits/" on Bitbucket, and
+// "/commit/" elsewhere (GitHub, Gitea, and most others). It returns "" for
+// local paths and file URLs.
+func CommitURL(remote, hash string) string {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	switch {
+	case strings.HasPrefix(remote, "https://"), strings.HasPrefix(remote, "http://"), strings.HasPrefix(remote, "ssh://"):
+		rest := remote[strings.Index(remote, "://")+3:]
+		host, path, _ = strings.Cut(rest, "/")
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+		if strings.HasPrefix(remote, "ssh://") {
+			host, _, _ = strings.Cut(host, ":")
+		}
+	case strings.Contains(remote, "@") && strings.Contains(remote, ":") && !strings.Contains(remote, "://"):
+		_, rest, _ := strings.Cut(remote, "@")
+		host, path, _ = strings.Cut(rest, ":")
+	default:
+		return ""
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	if host == "" || path == "" || hash == "" {
+		return ""
+	}
+	sep := "/commit/"
+	switch {
+	case strings.Contains(host, "gitlab"):
+		sep = "/-/commit/"
+	case strings.Contains(host, "bitbucket"):
+		sep = "/commits/"
+	}
+	return "https://" + host + "/" + path + sep + hash
+}
+
+// ErrNoClipboard is returned by Copy when no clipboard command is available.
+var ErrNoClipboard = errors.New("no clipboard command found (pbcopy, wl-copy, xclip, xsel, or clip)")
+
+// clipboardCommands lists the commands tried by Copy on goos, in order.
+func clipboardCommands(goos string) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+	return [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+}
+
+// Copy puts text on the system clipboard with the first clipboard command
+// found on PATH.
+func Copy(text string) error {
+	for _, c := range clipboardCommands(runtime.GOOS) {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Output is not captured: xclip and wl-copy leave a process serving the
+		// clipboard that keeps inherited pipes open, and Run would wait for it.
+		cmd := exec.CommandContext(ctx, c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", c[0], err)
+		}
+		return nil
+	}
+	return ErrNoClipboard
+}
@@ -0,0 +1,82 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogitobj "github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
+)
+
+// CacheMode says how summaries are cached. A commit never changes, so its
+// summary is only regenerated when the summary prompt or language changes.
+type CacheMode int
+
+const (
+	// CacheUse reads cached summaries and stores new ones.
+	CacheUse CacheMode = iota
+	// CacheRefresh regenerates the summary and replaces the cached one.
+	CacheRefresh
+	// CacheOff neither reads nor stores cached summaries.
+	CacheOff
+)
+
+// summaryCachePath is where the summary of commit is cached in the state
+// directory, keyed by commit hash and prompt; "" when there is no git directory.
+func summaryCachePath(ctx context.Context, commit *gogitobj.Commit, promptTemplate, language string) string {
+	name := commit.Hash.String() + "-" + prompt.SummaryTemplateHash(promptTemplate, language) + ".json"
+	path, err := git.StatePath(ctx, filepath.Join("summaries", name))
+	if err != nil {
+		log.Debug().Err(err).Msg("Summary cache unavailable")
+		return ""
+	}
+	return path
+}
+
+// cachedSummary is a cache entry: the summary and the response it came from.
+type cachedSummary struct {
+	Text   string        `json:"text"`
+	Source report.Source `json:"source"`
+}
+
+func readCachedSummary(path string) (cachedSummary, bool) {
+	var entry cachedSummary
+	if path == "" {
+		return entry, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil || strings.TrimSpace(entry.Text) == "" {
+		return cachedSummary{}, false
+	}
+	return entry, true
+}
+
+// writeCachedSummary stores entry at path. A failure only costs a later
+// regeneration, so it is logged and otherwise ignored.
+func writeCachedSummary(path string, entry cachedSummary) {
+	if path == "" || strings.TrimSpace(entry.Text) == "" {
+		return
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		log.Debug().Err(err).Msg("Cannot encode the summary")
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Debug().Err(err).Msg("Cannot create the summary cache")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Debug().Err(err).Msg("Cannot cache the summary")
+	}
+}
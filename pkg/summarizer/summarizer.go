@@ -12,26 +12,59 @@ import (
 	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/renatogalera/ai-commit/pkg/ai"
 	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
 	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
 )
 
+// Summary is the AI summary of a commit.
+type Summary struct {
+	Commit *gogitobj.Commit
+	// Text is the summary in markdown, with "###" section titles.
+	Text string
+	// Source is the provider and model that wrote Text, and when; a cached
+	// summary keeps the source of the response it was cached from.
+	Source report.Source
+	// Cached is set when Text was read from the summary cache.
+	Cached bool
+}
+
 // SummarizeCommits lists all commits in the current repository, allows the user to pick one via a fuzzy finder,
 // retrieves its diff, builds an AI prompt, and prints the AI-generated summary.
 // Now receives an extra parameter "language" for the summary prompt.
-func SummarizeCommits(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, language string) error {
+func SummarizeCommits(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, language string, cache CacheMode) error {
+	summary, err := PickAndSummarize(ctx, aiClient, cfg, language, cache)
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		fmt.Println("No diff found for this commit (maybe an empty or merge commit).")
+		return nil
+	}
+	printFormattedSummary(summary.Commit, summary.Text, summary.Source)
+	if summary.Cached {
+		fmt.Println("(cached summary; use --refresh to regenerate it)")
+	}
+	return nil
+}
+
+// PickAndSummarize lets the user pick a commit like SummarizeCommits and returns
+// its summary instead of printing it. The summary is nil when the commit has no
+// diff (an empty or merge commit).
+func PickAndSummarize(ctx context.Context, aiClient ai.AIClient, cfg *config.Config, language string, cache CacheMode) (*Summary, error) {
 	// Open the current git repository.
-	repo, err := gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{DetectDotGit: true})
+	repo, err := git.DiscoverRepo()
 	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
 	}
 
 	// List all commits.
 	commits, err := listAllCommits(repo)
 	if err != nil {
-		return fmt.Errorf("failed to list commits: %w", err)
+		return nil, fmt.Errorf("failed to list commits: %w", err)
 	}
 	if len(commits) == 0 {
-		return fmt.Errorf("no commits found in this repository")
+		return nil, fmt.Errorf("no commits found in this repository")
 	}
 
 	// Use fuzzyfinder to let the user select a commit.
@@ -46,18 +79,23 @@ func SummarizeCommits(ctx context.Context, aiClient ai.AIClient, cfg *config.Con
 		fuzzyfinder.WithPromptString("Select a commit> "),
 	)
 	if err != nil {
-		return fmt.Errorf("fuzzyfinder error: %w", err)
+		return nil, fmt.Errorf("fuzzyfinder error: %w", err)
 	}
 
 	// Get the selected commit and its diff.
 	selectedCommit := commits[idx]
+	cachePath := summaryCachePath(ctx, selectedCommit, cfg.PromptTemplate, language)
+	if cache == CacheUse {
+		if entry, ok := readCachedSummary(cachePath); ok {
+			return &Summary{Commit: selectedCommit, Text: entry.Text, Source: entry.Source, Cached: true}, nil
+		}
+	}
     diffStr, err := getCommitDiff(repo, selectedCommit)
     if err != nil {
-        return fmt.Errorf("failed to get commit diff: %w", err)
+        return nil, fmt.Errorf("failed to get commit diff: %w", err)
     }
     if strings.TrimSpace(diffStr) == "" {
-        fmt.Println("No diff found for this commit (maybe an empty or merge commit).")
-        return nil
+        return nil, nil
     }
 
     if cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
@@ -77,17 +115,18 @@ func SummarizeCommits(ctx context.Context, aiClient ai.AIClient, cfg *config.Con
     }
     summary, err := aiClient.GetCommitMessage(ctx, commitSummaryPrompt)
 	if err != nil {
-		return fmt.Errorf("failed to summarize commit with AI: %w", err)
+		return nil, fmt.Errorf("failed to summarize commit with AI: %w", err)
 	}
 	summary = aiClient.SanitizeResponse(summary, "")
-
-	// Print the formatted summary.
-	printFormattedSummary(selectedCommit, summary)
-	return nil
+	source := report.SourceOf(aiClient)
+	if cache != CacheOff {
+		writeCachedSummary(cachePath, cachedSummary{Text: summary, Source: source})
+	}
+	return &Summary{Commit: selectedCommit, Text: summary, Source: source}, nil
 }
 
 // printFormattedSummary renders the commit summary with styling.
-func printFormattedSummary(commit *gogitobj.Commit, summary string) {
+func printFormattedSummary(commit *gogitobj.Commit, summary string, source report.Source) {
 	// Define styles.
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -114,6 +153,9 @@ func printFormattedSummary(commit *gogitobj.Commit, summary string) {
 		commit.Hash.String()[:7],
 		commit.Author.Name,
 		commit.Author.When.Format("Mon Jan 2 15:04:05 MST 2006"))
+	if !source.IsZero() {
+		info += "\nGenerated by: " + source.String()
+	}
 	fmt.Println(infoStyle.Render(info))
 	fmt.Println()
 
@@ -160,8 +202,18 @@ func listAllCommits(repo *gogit.Repository) ([]*gogitobj.Commit, error) {
 	return commits, nil
 }
 
-// getCommitDiff obtains the diff for a given commit.
+// getCommitDiff obtains the diff for a given commit, with the files of
+// privacy.neverSendPaths redacted.
 func getCommitDiff(repo *gogit.Repository, commit *gogitobj.Commit) (string, error) {
+	diff, err := commitPatch(commit)
+	if err != nil {
+		return "", err
+	}
+	return git.RedactPrivateFiles(diff), nil
+}
+
+// commitPatch returns the unified diff of commit against its first parent.
+func commitPatch(commit *gogitobj.Commit) (string, error) {
 	if commit.NumParents() == 0 {
 		tree, err := commit.Tree()
 		if err != nil {
@@ -2,17 +2,24 @@ package template
 
 import (
 	"context"
+	"regexp"
 	"strings"
 
 	"github.com/renatogalera/ai-commit/pkg/git"
 )
 
+// footerToken matches a {FOOTER:Key=value} token.
+var footerToken = regexp.MustCompile(`\{FOOTER:([^=}]*)=([^}]*)\}`)
+
 // ApplyTemplate replaces well-known tokens in a commit template.
 // Supported tokens:
 //
-//	{COMMIT_MESSAGE} - replaced with the generated commit message
-//	{GIT_BRANCH}     - replaced with the current branch name
-//	{TICKET_ID}      - replaced with a ticket ID extracted from the branch name
+//	{COMMIT_MESSAGE}    - replaced with the generated commit message
+//	{GIT_BRANCH}        - replaced with the current branch name
+//	{TICKET_ID}         - replaced with a ticket ID extracted from the branch name
+//	{FOOTER:Key=value}  - removed and set as a trailer with git.SetFooter; the
+//	                      value may use the tokens above, and an empty value
+//	                      (e.g. no ticket in the branch name) adds nothing
 func ApplyTemplate(templateStr, commitMessage, ticketPattern string) (string, error) {
 	result := templateStr
 	if strings.Contains(result, "{COMMIT_MESSAGE}") {
@@ -36,5 +43,33 @@ func ApplyTemplate(templateStr, commitMessage, ticketPattern string) (string, er
 		ticketID := git.ExtractTicketID(branch, ticketPattern)
 		result = strings.ReplaceAll(result, "{TICKET_ID}", ticketID)
 	}
-	return result, nil
+	return applyFooterTokens(result)
+}
+
+// applyFooterTokens removes the {FOOTER:Key=value} tokens from result and sets
+// their footers on what remains.
+func applyFooterTokens(result string) (string, error) {
+	matches := footerToken.FindAllStringSubmatch(result, -1)
+	if matches == nil {
+		return result, nil
+	}
+	var footers []git.Footer
+	for _, m := range matches {
+		if strings.TrimSpace(m[2]) == "" {
+			continue
+		}
+		footer, err := git.NewFooter(m[1], m[2])
+		if err != nil {
+			return "", err
+		}
+		footers = append(footers, footer)
+	}
+	var lines []string
+	for _, line := range strings.Split(result, "\n") {
+		stripped := footerToken.ReplaceAllString(line, "")
+		if strings.TrimSpace(stripped) != "" || stripped == line {
+			lines = append(lines, stripped)
+		}
+	}
+	return git.ApplyFooters(strings.Join(lines, "\n"), footers), nil
 }
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+type replyClient struct {
+	ai.BaseAIClient
+	reply string
+}
+
+func (c replyClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return c.reply, nil
+}
+
+func TestChoices(t *testing.T) {
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	m := NewUIModel("", "", "english", "", "", "", "", false, &stubClient{}, false, "", "", "").
+		WithChoices([]string{"feat: add login", "fix: repair login", "docs: describe login"}, 3)
+	if m.commitMsg != "feat: add login" || len(m.candidates) != 3 {
+		t.Fatalf("initial message = %q, candidates = %d", m.commitMsg, len(m.candidates))
+	}
+
+	next, _ := m.Update(right)
+	m = next.(Model)
+	if m.commitMsg != "fix: repair login" {
+		t.Errorf("after right: message = %q", m.commitMsg)
+	}
+	m.commitMsg = "fix: repair the login form"
+	next, _ = m.Update(left)
+	next, _ = next.(Model).Update(left)
+	m = next.(Model)
+	if m.commitMsg != "docs: describe login" {
+		t.Errorf("left should wrap around to the last candidate, got %q", m.commitMsg)
+	}
+	next, _ = m.Update(right)
+	next, _ = next.(Model).Update(right)
+	if m = next.(Model); m.commitMsg != "fix: repair the login form" {
+		t.Errorf("an edit should be kept with its candidate, got %q", m.commitMsg)
+	}
+
+	next, _ = m.Update(regenMsg{msg: "feat: add sign-in"})
+	if m = next.(Model); len(m.choices) != 0 {
+		t.Errorf("a single regenerated message should replace the choices, got %q", m.choices)
+	}
+	next, _ = m.Update(candidatesMsg{msgs: []string{"feat: a", "feat: b"}})
+	if m = next.(Model); m.commitMsg != "feat: a" || len(m.choices) != 2 || m.choice != 0 {
+		t.Errorf("candidatesMsg: message = %q, choices = %q", m.commitMsg, m.choices)
+	}
+}
+
+func TestRegenerateCandidates(t *testing.T) {
+	client := replyClient{reply: "feat: add login\n%%%\n\n%%%\nfeat: add login\n%%%\nfix: repair login\n%%%\nchore: extra"}
+	got, err := regenerateCandidates("prompt", 2, &client, "", "", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "feat: add login" || got[1] != "fix: repair login" {
+		t.Errorf("regenerateCandidates = %q", got)
+	}
+
+	if _, err := regenerateCandidates("prompt", 2, &replyClient{reply: "%%%"}, "", "", false, ""); err == nil {
+		t.Error("expected an error for a reply without messages")
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpGroup is one screen's section of the help overlay.
+type helpGroup struct {
+	title    string
+	states   []uiState
+	bindings []key.Binding
+	// hint is a line of advice shown under the keys.
+	hint string
+}
+
+var helpTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("63")).Bold(true)
+
+// relabel returns b with desc as its help text, for keys whose meaning depends
+// on the screen (quit goes back from the diff view).
+func relabel(b key.Binding, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(b.Keys()...), key.WithHelp(b.Help().Key, desc))
+}
+
+// reservedBinding describes a key from reservedKeys, optionally with a more
+// specific desc.
+func reservedBinding(desc string, keys ...string) key.Binding {
+	if desc == "" {
+		desc = reservedKeys[keys[0]]
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), desc))
+}
+
+// helpGroups titles the columns of FullHelp, one per screen, so the overlay
+// lists the same bindings as the help line and follows the "keys" config.
+func (m Model) helpGroups() []helpGroup {
+	groups := []helpGroup{
+		{
+			title:  "Message",
+			states: []uiState{stateShowCommit, stateGenerating},
+			hint:   fmt.Sprintf("Regenerations are limited to %d per run.", m.maxRegens),
+		},
+		{title: "Diff view", states: []uiState{stateShowDiff}, hint: "Pressing an exclude or include key again undoes it."},
+		{title: "Prompt preview", states: []uiState{statePreview}},
+		{title: "Code review", states: []uiState{stateReview}, hint: "The review is kept until the diff changes."},
+		{title: "Filtered files", states: []uiState{stateShowFiltered}},
+		{title: "Commit type", states: []uiState{stateSelectType}},
+		{
+			title:  "Editing the message or prompt",
+			states: []uiState{stateEditing, stateEditingPrompt},
+			hint:   "Saving an edited prompt regenerates the message with it.",
+		},
+		{title: "Scope", states: []uiState{stateEditingScope}},
+		{title: "Intent", states: []uiState{stateEditingIntent}},
+	}
+	for i, bindings := range m.FullHelp() {
+		groups[i].bindings = bindings
+	}
+	return groups
+}
+
+// viewHelpOverlay renders the full keymap, the section for the screen the
+// overlay was opened from first.
+func (m Model) viewHelpOverlay() string {
+	groups := m.helpGroups()
+	current := slices.IndexFunc(groups, func(g helpGroup) bool { return slices.Contains(g.states, m.state) })
+	if current > 0 {
+		g := groups[current]
+		groups = append([]helpGroup{g}, slices.Delete(groups, current, current+1)...)
+		current = 0
+	}
+
+	var b strings.Builder
+	for i, g := range groups {
+		title := g.title
+		if i == current {
+			title += " (this screen)"
+		}
+		b.WriteString(helpTitleStyle.Render(title) + "\n")
+		width := 0
+		for _, binding := range g.bindings {
+			width = max(width, len(binding.Help().Key))
+		}
+		for _, binding := range g.bindings {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, binding.Help().Key, binding.Help().Desc)
+		}
+		if g.hint != "" {
+			b.WriteString(infoLineStyle.Render(g.hint) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("Press %s or esc to close.", keyMap.Help.Help().Key))
+
+	header := logoStyle.Render(logoText)
+	body := commitBoxStyle.Width(min(m.width-4, 100)).Render(strings.TrimRight(b.String(), "\n"))
+	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+}
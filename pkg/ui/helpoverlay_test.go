@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// TestHelpGroupsCoverKeyActions keeps the overlay in step with the keymap: every
+// configurable action appears under some screen.
+func TestHelpGroupsCoverKeyActions(t *testing.T) {
+	m := Model{draftSource: "saved", styleReview: "- The subject is too vague.", todos: []git.Todo{{Kind: "TODO"}}, width: splitPaneMinWidth}
+	listed := map[string]bool{}
+	for _, g := range m.helpGroups() {
+		if len(g.bindings) == 0 {
+			t.Errorf("no FullHelp column for the %q section", g.title)
+		}
+		for _, b := range g.bindings {
+			listed[strings.Join(b.Keys(), ",")] = true
+		}
+	}
+	for name, b := range keyActions() {
+		if !listed[strings.Join(b.Keys(), ",")] {
+			t.Errorf("key action %q (%s) is missing from the help overlay", name, b.Help().Key)
+		}
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	saved := keyMap
+	t.Cleanup(func() { keyMap = saved })
+	if err := ConfigureKeys(map[string]string{"excludeHunk": "z"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewUIModel("feat: add login", "", "english", "", "", "", "", false, &stubClient{}, false, "", "", "")
+	m.state = stateShowDiff
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m = next.(Model)
+	if !m.showHelp {
+		t.Fatal("? should open the help overlay")
+	}
+	view := m.View()
+	if !strings.Contains(view, "Diff view (this screen)") || strings.Index(view, "Diff view") > strings.Index(view, "Message") {
+		t.Errorf("the diff view keys should come first:\n%s", view)
+	}
+	if !regexp.MustCompile(`\bz +exclude hunk`).MatchString(view) {
+		t.Errorf("the overlay should show the rebound exclude key:\n%s", view)
+	}
+
+	// Keys other than help and quit are ignored while the overlay is open.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if m = next.(Model); len(m.overrides) != 0 || !m.showHelp {
+		t.Error("the overlay should swallow other keys")
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(Model); m.showHelp || m.state != stateShowDiff {
+		t.Errorf("esc should close the overlay and stay on the diff view, state = %v", m.state)
+	}
+}
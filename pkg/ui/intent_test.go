@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIntentKey(t *testing.T) {
+	m := NewUIModel("feat: add backoff", "diff --git a/a.go b/a.go\n+x\n", "english", "prompt", "", "", "", false, &stubClient{}, false, "", "", "")
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if m = next.(Model); m.state != stateEditingIntent {
+		t.Fatalf("i: state = %v, want the intent input", m.state)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("retry the phind client")})
+	next, cmd := next.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != stateGenerating || cmd == nil || m.regenCount != 1 {
+		t.Fatalf("enter: state = %v, regenerations = %d, want a regeneration", m.state, m.regenCount)
+	}
+	if !strings.HasPrefix(m.prompt, "[Author's intent]") || !strings.Contains(m.prompt, "retry the phind client") {
+		t.Errorf("prompt does not lead with the intent:\n%s", m.prompt)
+	}
+	if m.Snapshot("s").Intent != "retry the phind client" {
+		t.Error("the intent is not saved with the session")
+	}
+}
+
+func TestCommitPromptKeepsContext(t *testing.T) {
+	m := NewUIModel("feat: add backoff", "diff --git a/a.go b/a.go\n+x\n", "english", "prompt", "", "", "", false, &stubClient{}, false, "", "", "").
+		WithContextFiles("Decision: retries use jitter.")
+	m.userContext = "mention the phind client"
+	got := m.commitPrompt(m.diff, m.userContext)
+	for _, want := range []string{"Decision: retries use jitter.", "mention the phind client"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt lacks %q:\n%s", want, got)
+		}
+	}
+	if got := m.commitPrompt(m.diff, ""); !strings.Contains(got, "Decision: retries use jitter.") {
+		t.Errorf("prompt without a prompt edit lacks the context files:\n%s", got)
+	}
+}
+
+func TestUnfilteredRegenerationKeepsPromptEdit(t *testing.T) {
+	m := NewUIModel("feat: add backoff", "diff --git a/a.go b/a.go\n+x\n", "english", "prompt", "", "", "", false, &stubClient{}, false, "", "", "")
+	m.userContext = "mention the phind client"
+	next, cmd := m.Update(unfilteredMsg{diff: "diff --git a/go.sum b/go.sum\n+h1:abc\n"})
+	m = next.(Model)
+	if cmd == nil || !strings.Contains(m.prompt, "go.sum") {
+		t.Fatalf("unfiltered diff did not regenerate:\n%s", m.prompt)
+	}
+	if !strings.Contains(m.prompt, "mention the phind client") {
+		t.Errorf("the prompt edit was dropped:\n%s", m.prompt)
+	}
+}
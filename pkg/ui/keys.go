@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyActions maps the action names accepted under "keys" in config.yaml to the
+// bindings they replace.
+func keyActions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"commit":      &keyMap.Commit,
+		"regenerate":  &keyMap.Regenerate,
+		"improve":     &keyMap.Improve,
+		"edit":        &keyMap.Edit,
+		"type":        &keyMap.TypeSelect,
+		"scope":       &keyMap.Scope,
+		"intent":      &keyMap.Intent,
+		"prompt":      &keyMap.PromptEdit,
+		"diff":        &keyMap.ViewDiff,
+		"filtered":    &keyMap.Filtered,
+		"preview":     &keyMap.Preview,
+		"review":      &keyMap.Review,
+		"quality":     &keyMap.Quality,
+		"todos":       &keyMap.Todos,
+		"fixStyle":    &keyMap.FixStyle,
+		"save":        &keyMap.SaveSession,
+		"unfilter":    &keyMap.Unfilter,
+		"excludeHunk": &keyMap.ExcludeHunk,
+		"excludeFile": &keyMap.ExcludeFile,
+		"includeHunk": &keyMap.IncludeHunk,
+		"includeFile": &keyMap.IncludeFile,
+		"focus":       &keyMap.Focus,
+		"quit":        &keyMap.Quit,
+		"help":        &keyMap.Help,
+	}
+}
+
+// reservedKeys are handled directly by the views (list navigation, confirming,
+// saving edits) and cannot be bound to an action.
+var reservedKeys = map[string]string{
+	"enter":  "confirm",
+	"esc":    "back/quit",
+	"ctrl+c": "quit",
+	"ctrl+s": "save edit",
+	"up":     "move up",
+	"down":   "move down",
+	"left":   "switch candidate",
+	"right":  "switch candidate",
+	"k":      "move up",
+	"j":      "move down",
+	"pgup":   "scroll up",
+	"pgdown": "scroll down",
+}
+
+// diffViewActions only act in the diff view and the prompt preview, and
+// sharedActions act there as well as on the message screen. A key may serve
+// one diff view action and one message screen action, as "i" does by default
+// (force-include a hunk, state the intent).
+var (
+	diffViewActions = map[string]bool{"excludeHunk": true, "excludeFile": true, "includeHunk": true, "includeFile": true}
+	sharedActions   = map[string]bool{"regenerate": true, "quit": true, "help": true}
+)
+
+// clash reports whether actions a and b cannot share a key.
+func clash(a, b string) bool {
+	if sharedActions[a] || sharedActions[b] {
+		return true
+	}
+	return diffViewActions[a] == diffViewActions[b]
+}
+
+// ConfigureKeys rebinds TUI actions from the "keys" config section, where each
+// value is a key or a comma-separated list of keys (e.g. "ctrl+y" or "y,Y").
+// esc and ctrl+c always quit in addition to the configured quit keys. It returns
+// an error, leaving the defaults in place, for unknown actions, reserved keys, or
+// a key bound to two actions.
+func ConfigureKeys(custom map[string]string) error {
+	actions := keyActions()
+	bound := make(map[string][]string, len(actions))
+	for name, b := range actions {
+		bound[name] = b.Keys()
+		if name == "quit" {
+			bound[name] = []string{"q"}
+		}
+	}
+	for name, value := range custom {
+		if _, ok := actions[name]; !ok {
+			return fmt.Errorf("unknown key action %q (valid: %s)", name, strings.Join(actionNames(actions), ", "))
+		}
+		var keys []string
+		for _, k := range strings.Split(value, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no key given for action %q", name)
+		}
+		for _, k := range keys {
+			if use, ok := reservedKeys[k]; ok {
+				return fmt.Errorf("key %q for action %q is reserved for %s", k, name, use)
+			}
+		}
+		bound[name] = keys
+	}
+
+	owners := make(map[string][]string)
+	for _, name := range actionNames(actions) {
+		for _, k := range bound[name] {
+			for _, other := range owners[k] {
+				if clash(other, name) {
+					return fmt.Errorf("key %q is bound to both %q and %q", k, other, name)
+				}
+			}
+			owners[k] = append(owners[k], name)
+		}
+	}
+
+	for name, keys := range bound {
+		if _, ok := custom[name]; !ok {
+			continue
+		}
+		b := actions[name]
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+		if name == "quit" {
+			keys = append(keys, "ctrl+c", "esc")
+		}
+		b.SetKeys(keys...)
+	}
+	return nil
+}
+
+func actionNames(actions map[string]*key.Binding) []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
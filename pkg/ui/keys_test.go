@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigureKeys(t *testing.T) {
+	saved := keyMap
+	t.Cleanup(func() { keyMap = saved })
+
+	tests := []struct {
+		name    string
+		keys    map[string]string
+		wantErr string
+	}{
+		{name: "unknown action", keys: map[string]string{"push": "p"}, wantErr: "unknown key action"},
+		{name: "reserved key", keys: map[string]string{"commit": "enter"}, wantErr: "reserved"},
+		{name: "clash with default", keys: map[string]string{"commit": "r"}, wantErr: `bound to both "commit" and "regenerate"`},
+		{name: "empty", keys: map[string]string{"commit": " , "}, wantErr: "no key given"},
+		{name: "clash in the diff view", keys: map[string]string{"excludeHunk": "r"}, wantErr: `bound to both "excludeHunk" and "regenerate"`},
+	}
+	for _, tc := range tests {
+		err := ConfigureKeys(tc.keys)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: got %v, want error containing %q", tc.name, err, tc.wantErr)
+		}
+	}
+	if got := keyMap.Commit.Keys(); len(got) != 1 || got[0] != "y" {
+		t.Fatalf("failed validation changed the commit keys to %v", got)
+	}
+
+	if err := ConfigureKeys(map[string]string{"commit": "c, ctrl+y", "type": "y", "quit": "Q"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(keyMap.Commit.Keys(), " "); got != "c ctrl+y" {
+		t.Errorf("commit keys = %q", got)
+	}
+	if got := keyMap.Commit.Help().Key; got != "c/ctrl+y" {
+		t.Errorf("commit help = %q", got)
+	}
+	if got := strings.Join(keyMap.Quit.Keys(), " "); got != "Q ctrl+c esc" {
+		t.Errorf("quit keys = %q", got)
+	}
+
+	// Diff view actions may share keys with message screen actions.
+	if err := ConfigureKeys(map[string]string{"excludeFile": "e"}); err != nil {
+		t.Errorf("excludeFile on the edit key: %v", err)
+	}
+}
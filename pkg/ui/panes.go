@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// splitPaneMinWidth is the terminal width from which the main screen shows the
+// diff beside the message instead of only on the separate diff screen.
+const splitPaneMinWidth = 140
+
+var (
+	diffPaneStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1).
+			Margin(1, 0, 1, 1)
+
+	diffAddStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("71"))
+	diffDelStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("167"))
+	diffHunkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("67"))
+)
+
+// splitPane reports whether the terminal is wide enough for the two-pane layout.
+func (m Model) splitPane() bool {
+	return m.width >= splitPaneMinWidth
+}
+
+// diffPaneWidth is the content width of the left pane; the message pane takes the rest.
+func (m Model) diffPaneWidth() int {
+	return m.width/2 - 4
+}
+
+func (m Model) messagePaneWidth() int {
+	return min(m.width-m.diffPaneWidth()-12, 100)
+}
+
+// diffPaneHeight leaves room for the banner, info line, and help bar.
+func (m Model) diffPaneHeight() int {
+	return max(m.height-10, 5)
+}
+
+// scrollDiff moves the diff pane by delta lines, clamped to the diff length.
+func (m Model) scrollDiff(delta int) Model {
+	last := max(len(strings.Split(m.diff, "\n"))-m.diffPaneHeight(), 0)
+	m.diffScroll = min(max(m.diffScroll+delta, 0), last)
+	return m
+}
+
+// viewDiffPane renders the visible window of the prompt diff, colored and cut to the pane width.
+func (m Model) viewDiffPane() string {
+	width, height := m.diffPaneWidth(), m.diffPaneHeight()
+	lines := strings.Split(strings.TrimRight(m.diff, "\n"), "\n")
+	start := min(m.diffScroll, max(len(lines)-height, 0))
+	end := min(start+height, len(lines))
+
+	rendered := make([]string, 0, height)
+	for _, line := range lines[start:end] {
+		line = truncateRunes(strings.ReplaceAll(line, "\t", "    "), width)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff --git"):
+			line = highlightStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			line = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = diffDelStyle.Render(line)
+		default:
+			line = diffStyle.Render(line)
+		}
+		rendered = append(rendered, line)
+	}
+	for len(rendered) < height {
+		rendered = append(rendered, "")
+	}
+
+	style := diffPaneStyle.Width(width + 2)
+	if m.focusDiff {
+		style = style.BorderForeground(lipgloss.Color("212"))
+	}
+	return style.Render(strings.Join(rendered, "\n"))
+}
+
+func truncateRunes(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
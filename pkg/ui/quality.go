@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/lint"
+)
+
+var qualityBadgeStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("232")).
+	Padding(0, 1)
+
+// qualityColor grades a score: green from 80, yellow from 60, red below.
+func qualityColor(points, max int) lipgloss.Color {
+	switch {
+	case points*100 >= 80*max:
+		return lipgloss.Color("42")
+	case points*100 >= 60*max:
+		return lipgloss.Color("214")
+	default:
+		return lipgloss.Color("196")
+	}
+}
+
+// qualityBadge renders the total of q as a colored badge for the info line.
+func qualityBadge(q lint.Quality) string {
+	return qualityBadgeStyle.Background(qualityColor(q.Total, 100)).Render(fmt.Sprintf("Quality %d", q.Total))
+}
+
+// viewQuality lists the rules of q with their points and notes.
+func viewQuality(q lint.Quality, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Quality %d/100 (local heuristics; press %s to hide)\n", q.Total, keyMap.Quality.Help().Key)
+	for _, r := range q.Rules {
+		points := lipgloss.NewStyle().Foreground(qualityColor(r.Points, r.Max)).Render(fmt.Sprintf("%2d/%d", r.Points, r.Max))
+		fmt.Fprintf(&b, "\n%s  %-16s %s", points, r.Name, r.Note)
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 2).
+		Margin(0, 1).
+		Width(width).
+		Render(b.String())
+}
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQualityBreakdown(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n+func main() {}\n"
+	m := NewUIModel("feat: added main", diff, "english", "", "", "", "", false, &stubClient{}, false, "", "", "")
+	m.width = 120
+
+	if view := m.View(); !strings.Contains(view, "Quality ") || strings.Contains(view, "imperative mood") {
+		t.Errorf("want only the badge before toggling, view = %q", view)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = next.(Model)
+	if view := m.View(); !strings.Contains(view, "imperative mood") || !strings.Contains(view, "past tense") {
+		t.Errorf("g should show the breakdown, view = %q", view)
+	}
+}
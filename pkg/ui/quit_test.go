@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitConfirmsManualEdits(t *testing.T) {
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}
+	m := NewUIModel("feat: add login", "", "english", "", "", "", "", false, &stubClient{}, false, "", "", "")
+
+	if _, cmd := m.Update(q); cmd == nil {
+		t.Error("q without edits should quit")
+	}
+
+	m = m.applyScope("auth")
+	if !m.edited {
+		t.Fatal("setting the scope should count as an edit")
+	}
+	next, cmd := m.Update(q)
+	m = next.(Model)
+	if cmd != nil || m.state != stateConfirmQuit {
+		t.Fatalf("q after an edit: state = %v, want the confirmation", m.state)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m = next.(Model); m.state != stateShowCommit || m.commitMsg != "feat(auth): add login" {
+		t.Errorf("n should keep the edited message, state = %v, message = %q", m.state, m.commitMsg)
+	}
+	next, _ = m.Update(q)
+	if _, cmd := next.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("enter in the confirmation should quit")
+	}
+
+	m.state = stateShowCommit
+	if _, cmd := m.WithInstantQuit(true).Update(q); cmd == nil {
+		t.Error("q with instantQuit should quit")
+	}
+
+	next, _ = m.Update(regenMsg{msg: "feat: add sign-in"})
+	if next.(Model).edited {
+		t.Error("a regenerated message has no manual edits")
+	}
+}
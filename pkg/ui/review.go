@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
+)
+
+// startReview requests a code review of the prompt diff, as `ai-commit review`
+// does, and shows it on the review screen when it arrives.
+func (m Model) startReview() (Model, tea.Cmd) {
+	m.reviewing = true
+	m.review, m.reviewScroll = "", 0
+	reviewPrompt := prompt.BuildCodeReviewPrompt(m.diff, m.language, m.promptTemplate)
+	return m, tea.Batch(m.spinner.Tick, reviewCmd(m.aiClient, m.diff, reviewPrompt))
+}
+
+// reviewCmd asks client for a review of diff with reviewPrompt.
+func reviewCmd(client ai.AIClient, diff, reviewPrompt string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		review, err := client.GetCommitMessage(ctx, reviewPrompt)
+		source := report.SourceOf(client)
+		return reviewMsg{diff: diff, review: review, source: source, err: err}
+	}
+}
+
+// reviewPaneHeight leaves room for the banner, title, and help bar.
+func (m Model) reviewPaneHeight() int {
+	return max(m.height-12, 5)
+}
+
+// scrollReview moves the review by delta lines, clamped to its length.
+func (m Model) scrollReview(delta int) Model {
+	last := max(len(strings.Split(m.review, "\n"))-m.reviewPaneHeight(), 0)
+	m.reviewScroll = min(max(m.reviewScroll+delta, 0), last)
+	return m
+}
+
+func (m Model) viewReview() string {
+	header := logoStyle.Render(logoText)
+	boxWidth := min(m.width-4, 100)
+	if m.reviewing {
+		body := fmt.Sprintf("Reviewing the staged changes...\n\n%s", m.spinner.View())
+		return lipgloss.JoinVertical(lipgloss.Left, header, body, m.help.View(m))
+	}
+
+	lines := strings.Split(m.review, "\n")
+	height := m.reviewPaneHeight()
+	start := min(m.reviewScroll, max(len(lines)-height, 0))
+	end := min(start+height, len(lines))
+	title := "Code review"
+	if !m.reviewSource.IsZero() {
+		title += " by " + m.reviewSource.String()
+	}
+	if len(lines) > height {
+		title += fmt.Sprintf(" (lines %d-%d of %d)", start+1, end, len(lines))
+	}
+
+	body := commitBoxStyle.Width(boxWidth).Render(strings.Join(lines[start:end], "\n"))
+	footer := fmt.Sprintf("Use up/down (or j/k, pgup/pgdown) to scroll, %s to review again, ESC/%s to return.",
+		keyMap.Regenerate.Help().Key, keyMap.Quit.Help().Key)
+	return lipgloss.JoinVertical(lipgloss.Left, header, helpTitleStyle.Render(title), body, infoLineStyle.Render(footer), m.help.View(m))
+}
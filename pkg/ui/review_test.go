@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReviewPane(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n+func main() {}\n"
+	m := NewUIModel("feat: add main", diff, "english", "", "", "", "", false, &stubClient{}, false, "", "", "")
+	m.height = 20
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = next.(Model)
+	if cmd == nil || m.state != stateReview || !m.reviewing {
+		t.Fatalf("v should request a review, state = %v, reviewing = %v", m.state, m.reviewing)
+	}
+
+	findings := strings.TrimSpace(strings.Repeat("- main.go: finding\n", 30))
+	next, _ = m.Update(reviewMsg{diff: diff, review: findings})
+	m = next.(Model)
+	if m.reviewing || !strings.Contains(m.View(), "lines 1-8 of 30") {
+		t.Errorf("review view = %q", m.View())
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m = next.(Model); m.reviewScroll != 8 {
+		t.Errorf("pgdown scrolled to %d, want 8", m.reviewScroll)
+	}
+
+	// Going back and reopening shows the same review without asking again.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(Model); m.state != stateShowCommit {
+		t.Fatalf("esc: state = %v, want the message", m.state)
+	}
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m = next.(Model); cmd != nil || m.state != stateReview || m.review != findings {
+		t.Errorf("reopening the review should reuse it, cmd = %v, review = %q", cmd, m.review)
+	}
+}
+
+func TestFixStyleRegenerates(t *testing.T) {
+	a := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}
+	m := NewUIModel("fix: stuff", "", "english", "", "", "", "No issues found.", false, &stubClient{}, false, "", "", "")
+	if _, cmd := m.Update(a); cmd != nil {
+		t.Error("a without style issues should do nothing")
+	}
+
+	m.styleReview = "- The subject is too vague."
+	next, cmd := m.Update(a)
+	m = next.(Model)
+	if cmd == nil || m.state != stateGenerating || m.regenCount != 1 || m.styleReview != "" {
+		t.Errorf("a should regenerate with the suggestions, state = %v, regenCount = %d, styleReview = %q", m.state, m.regenCount, m.styleReview)
+	}
+}
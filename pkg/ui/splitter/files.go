@@ -0,0 +1,125 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fileEntry is one changed file in the per-file view, with the indices of
+// its chunks.
+type fileEntry struct {
+	path   string
+	chunks []int
+}
+
+// groupFiles lists the files of m.chunks in diff order.
+func (m Model) groupFiles() []fileEntry {
+	var files []fileEntry
+	index := make(map[string]int)
+	for i, c := range m.chunks {
+		n, ok := index[c.FilePath]
+		if !ok {
+			n = len(files)
+			index[c.FilePath] = n
+			files = append(files, fileEntry{path: c.FilePath})
+		}
+		files[n].chunks = append(files[n].chunks, i)
+	}
+	return files
+}
+
+// toggleView switches between the hunk and file views, keeping the cursor on
+// the same file.
+func (m Model) toggleView() Model {
+	if m.byFile {
+		m.byFile = false
+		m.cursor = m.files[m.cursor].chunks[0]
+		return m
+	}
+	m.byFile = true
+	for n, f := range m.files {
+		if f.path == m.chunks[m.cursor].FilePath {
+			m.cursor = n
+			break
+		}
+	}
+	return m
+}
+
+// toggleFile selects every chunk of the file under the cursor that is not
+// queued, or deselects them when all already are.
+func (m Model) toggleFile() Model {
+	var free []int
+	all := true
+	for _, i := range m.files[m.cursor].chunks {
+		if _, ok := m.queued[i]; !ok {
+			free = append(free, i)
+			all = all && m.selected[i]
+		}
+	}
+	for _, i := range free {
+		m.selected[i] = !all
+	}
+	m.updateSelectedCount()
+	return m
+}
+
+// fileMarker shows the queued commits of f's chunks, "x" when all of its
+// other chunks are selected, and "~" when only some are.
+func (m Model) fileMarker(f fileEntry) string {
+	var queued []string
+	seen := make(map[int]bool)
+	selected, free := 0, 0
+	for _, i := range f.chunks {
+		if n, ok := m.queued[i]; ok {
+			if !seen[n] {
+				seen[n] = true
+				queued = append(queued, fmt.Sprint(n))
+			}
+			continue
+		}
+		free++
+		if m.selected[i] {
+			selected++
+		}
+	}
+	switch {
+	case free == 0:
+		return strings.Join(queued, ",")
+	case selected == free:
+		return "x"
+	case selected > 0:
+		return "~"
+	}
+	return " "
+}
+
+// fileListView renders the per-file view.
+func (m Model) fileListView() string {
+	header := "Select files to commit (space to toggle, enter to queue them as a commit, 'u' to unqueue the last, 'c' to commit the queue and selection, 'a' to select all, 'i' to invert selection, 'm' to edit the message of the commit under the cursor, 'f' to select hunks, 'q' to quit):\n\n"
+	footer := fmt.Sprintf("\nSelected chunks: %d/%d  Files: %d  Queued commits: %d", m.selectedCount, m.totalChunks, len(m.files), len(m.queue))
+	start, end := m.listWindow(header, footer)
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString(m.moreRows(start, end, true))
+	for n := start; n < end; n++ {
+		f := m.files[n]
+		cursor := "  "
+		if n == m.cursor {
+			cursor = "> "
+		}
+		marker := m.fileMarker(f)
+		style := unselectedChunkStyle
+		switch marker {
+		case "x", "~":
+			style = selectedChunkStyle
+		case " ":
+		default:
+			style = queuedChunkStyle
+		}
+		b.WriteString(fmt.Sprintf("%s[%s] %s %s\n", cursor, marker, style.Render(f.path), hunkHeaderStyle.Render(fmt.Sprintf("(%d hunk(s))", len(f.chunks)))))
+	}
+	b.WriteString(m.moreRows(start, end, false))
+	b.WriteString(footer)
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package splitter
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFileView(t *testing.T) {
+	key := func(m Model, k string) Model {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(k)}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		next, _ := m.Update(msg)
+		return next.(Model)
+	}
+
+	m := NewSplitterModel(testChunks(), nil)
+	if len(m.files) != 2 || m.files[0].path != "a.go" || len(m.files[0].chunks) != 2 || m.files[1].path != "b.go" {
+		t.Fatalf("files = %+v, want a.go with two chunks and b.go", m.files)
+	}
+
+	// Switching views keeps the cursor on the same file.
+	m = key(m, "j")
+	m = key(m, "j")
+	m = key(m, "f")
+	if !m.byFile || m.cursor != 1 {
+		t.Fatalf("byFile = %v, cursor = %d; want the file view on b.go", m.byFile, m.cursor)
+	}
+	if m = key(m, "j"); m.cursor != 1 {
+		t.Errorf("cursor moved past the last file to %d", m.cursor)
+	}
+
+	// A partly selected file is completed first, then deselected.
+	m = key(m, "k")
+	m.selected[1] = true
+	if got := m.fileMarker(m.files[0]); got != "~" {
+		t.Errorf("marker of a partly selected file = %q, want ~", got)
+	}
+	if m = key(m, " "); !m.selected[0] || !m.selected[1] || m.selectedCount != 2 {
+		t.Errorf("after selecting a.go: selected = %v", m.selected)
+	}
+	if m = key(m, " "); m.selected[0] || m.selected[1] || m.selectedCount != 0 {
+		t.Errorf("after deselecting a.go: selected = %v", m.selected)
+	}
+
+	// Each queued group of files becomes one commit.
+	m = key(m, " ")
+	m = key(m, "enter")
+	m = key(m, "j")
+	m = key(m, " ")
+	m = key(m, "enter")
+	if len(m.queue) != 2 || len(m.queue[0]) != 2 || m.queue[1][0] != 2 {
+		t.Fatalf("queue = %v, want [[0 1] [2]]", m.queue)
+	}
+	if got := m.fileMarker(m.files[0]); got != "1" {
+		t.Errorf("marker of a queued file = %q, want 1", got)
+	}
+
+	m = key(m, "f")
+	if m.byFile || m.cursor != 2 {
+		t.Errorf("byFile = %v, cursor = %d; want the hunk view on b.go's chunk", m.byFile, m.cursor)
+	}
+}
@@ -0,0 +1,126 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// formatHunks lists chunks numbered from 1 for the split plan prompt.
+func formatHunks(chunks []git.DiffChunk) string {
+	var b strings.Builder
+	for i, c := range chunks {
+		b.WriteString(fmt.Sprintf("### Hunk %d: %s %s\n", i+1, c.FilePath, c.HunkHeader))
+		if !slices.ContainsFunc(c.Lines, func(line string) bool { return !strings.HasPrefix(line, " ") }) {
+			b.WriteString("[only comments or moved lines changed]\n")
+			continue
+		}
+		for _, line := range c.Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// promptChunks returns chunks, which stay raw so they apply to the index, with
+// their lines as the commit prompt would show them (see git.PromptPatch). All
+// chunks are filtered in one pass, each as its own file section.
+func promptChunks(ctx context.Context, chunks []git.DiffChunk) []git.DiffChunk {
+	var b strings.Builder
+	for _, c := range chunks {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n%s\n", c.FilePath, c.FilePath, c.HunkHeader)
+		for _, line := range c.Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	sections := strings.Split("\n"+git.PromptPatch(ctx, b.String()), "\ndiff --git ")[1:]
+	out := make([]git.DiffChunk, len(chunks))
+	for i, c := range chunks {
+		out[i] = git.DiffChunk{FilePath: c.FilePath, HunkHeader: c.HunkHeader}
+		if i >= len(sections) {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(sections[i], "\n"), "\n")[1:] {
+			if !strings.HasPrefix(line, "@@") {
+				out[i].Lines = append(out[i].Lines, line)
+			}
+		}
+	}
+	return out
+}
+
+// planSplit asks client to group chunks into commits with draft messages.
+func planSplit(ctx context.Context, chunks []git.DiffChunk, client ai.AIClient, cfg *config.Config, language string) ([]prompt.SplitGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, queueTimeout)
+	defer cancel()
+	planPrompt, truncated := limitPrompt(cfg, prompt.BuildSplitPlanPrompt(formatHunks(promptChunks(ctx, chunks)), language))
+	if truncated {
+		log.Warn().Int("maxChars", cfg.Limits.Prompt.MaxChars).Msg("The split plan prompt exceeded limits.prompt.maxChars and was truncated; the last hunks were not sent and may be left out of the plan")
+	}
+	reply, err := client.GetCommitMessage(ctx, planPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI error: %w", err)
+	}
+	groups := prompt.ParseSplitPlan(reply, len(chunks))
+	if len(groups) == 0 {
+		return nil, errors.New("the AI reply contained no split plan")
+	}
+	for i := range groups {
+		groups[i].Message = client.SanitizeResponse(groups[i].Message, "")
+	}
+	return groups, nil
+}
+
+// applyPlan queues each group of the plan as a commit with its draft message.
+func (m Model) applyPlan(groups []prompt.SplitGroup) Model {
+	for _, g := range groups {
+		hunks := append([]int(nil), g.Hunks...)
+		sort.Ints(hunks)
+		m.queue = append(m.queue, hunks)
+		m.drafts = append(m.drafts, g.Message)
+		for _, i := range hunks {
+			m.queued[i] = len(m.queue)
+		}
+	}
+	m.planned = true
+	return m
+}
+
+// planView introduces a queue proposed by the AI.
+func (m Model) planView() string {
+	if !m.planned {
+		return ""
+	}
+	s := fmt.Sprintf("AI split plan: %d commit(s). Review the groups and messages below, then press 'c' to create them in order; 'u' takes the last one back to regroup its hunks.\n", len(m.queue))
+	if left := len(m.chunks) - len(m.queued); left > 0 {
+		s += fmt.Sprintf("%d hunk(s) are in no commit and stay staged unless you queue them.\n", left)
+	}
+	return s + "\n"
+}
+
+// RunAutoSplit asks client to group the staged hunks into commits with draft
+// messages in language and opens the plan in the splitter, where the user can
+// regroup hunks and edit messages before the commits are created in order.
+func RunAutoSplit(ctx context.Context, client ai.AIClient, opts git.CommitOptions, byFile bool, language string) error {
+	cfg, _ := config.LoadOrCreateConfig()
+	chunks, err := stagedChunks(ctx, cfg)
+	if err != nil || len(chunks) == 0 {
+		return err
+	}
+	fmt.Printf("Asking %s for a split plan of %d hunk(s)...\n", client.ProviderName(), len(chunks))
+	groups, err := planSplit(ctx, chunks, client, cfg, language)
+	if err != nil {
+		return err
+	}
+	return runSplitter(NewSplitterModel(chunks, client).applyPlan(groups), cfg, opts, byFile)
+}
@@ -0,0 +1,123 @@
+package splitter
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/internal/testutil"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+func TestPlanSplit(t *testing.T) {
+	hunks := formatHunks(testChunks())
+	if !strings.Contains(hunks, "### Hunk 2: a.go @@ -20,1 +20,1 @@\n-// old\n+// new\n") || !strings.Contains(hunks, "### Hunk 3: b.go") {
+		t.Errorf("formatHunks() =\n%s", hunks)
+	}
+	git.ConfigurePrivacy(config.PrivacySettings{NeverSendPaths: []string{"b.go"}})
+	hunks = formatHunks(promptChunks(context.Background(), testChunks()))
+	git.ConfigurePrivacy(config.PrivacySettings{})
+	if strings.Contains(hunks, "package b") || !strings.Contains(hunks, "### Hunk 3: b.go @@ -0,0 +1 @@\n[never-send path") {
+		t.Errorf("formatHunks() sent a never-send file:\n%s", hunks)
+	}
+	if strings.Contains(hunks, "// new") || !strings.Contains(hunks, "### Hunk 2: a.go @@ -20,1 +20,1 @@\n[only comments") {
+		t.Errorf("formatHunks() sent a comment-only hunk:\n%s", hunks)
+	}
+	if !strings.Contains(hunks, "### Hunk 1: a.go @@ -1,2 +1,2 @@\n package a\n-var x = 1\n+var x = 2\n") {
+		t.Errorf("formatHunks() changed a code hunk:\n%s", hunks)
+	}
+
+	client := &testutil.MockAIClient{
+		GetCommitMessageFunc: func(ctx context.Context, prompt string) (string, error) {
+			return "%%% hunks: 3, 1\nfeat(b): add package b\n%%% hunks: 2\ndocs(a): update comment", nil
+		},
+	}
+	groups, err := planSplit(context.Background(), testChunks(), client, nil, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewSplitterModel(testChunks(), client).applyPlan(groups)
+	if !slices.Equal(m.queue[0], []int{0, 2}) || !slices.Equal(m.queue[1], []int{1}) || m.queued[2] != 1 {
+		t.Fatalf("queue = %v, queued = %v; want [[0 2] [1]]", m.queue, m.queued)
+	}
+	if !slices.Equal(m.drafts, []string{"feat(b): add package b", "docs(a): update comment"}) {
+		t.Errorf("drafts = %q", m.drafts)
+	}
+	if !strings.Contains(m.View(), "AI split plan: 2 commit(s)") || !strings.Contains(m.View(), "2. docs(a): update comment") {
+		t.Errorf("View() =\n%s", m.View())
+	}
+
+	client.GetCommitMessageFunc = func(ctx context.Context, prompt string) (string, error) {
+		return "Sure, here is a commit message.", nil
+	}
+	if _, err := planSplit(context.Background(), testChunks(), client, nil, "english"); err == nil {
+		t.Error("expected an error for a reply without a plan")
+	}
+}
+
+func TestEditDraft(t *testing.T) {
+	m := NewSplitterModel(testChunks(), nil)
+	m.selected[1] = true
+	m = m.enqueueSelection()
+	if _, cmd := m.editDraft(); cmd != nil {
+		t.Error("editing on a chunk that is not queued should do nothing")
+	}
+
+	m.cursor = 1
+	next, _ := m.editDraft()
+	m = next.(Model)
+	if m.state != stateEditMessage || m.editing != 0 {
+		t.Fatalf("state = %v, editing = %d; want the editor for commit 1", m.state, m.editing)
+	}
+	m.textarea.SetValue("  fix(a): reword comment \n")
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = next.(Model)
+	if m.state != stateList || m.drafts[0] != "fix(a): reword comment" {
+		t.Errorf("after saving: state = %v, drafts = %q", m.state, m.drafts)
+	}
+
+	m = m.unqueueLast()
+	if len(m.drafts) != 0 {
+		t.Errorf("drafts after unqueue = %q", m.drafts)
+	}
+}
+
+func TestExecuteQueue_Drafts_Integration(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Write("a.go", "package a\n")
+	repo.Stage()
+	repo.Commit("feat: add a")
+	repo.Write("a.go", "package a\n\nvar x = 2\n")
+	repo.Write("b.go", "package b\n")
+	repo.Stage()
+
+	patch, err := git.StagedPatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := git.ParseDiffToChunks(strings.TrimRight(patch, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	client := &testutil.MockAIClient{
+		GetCommitMessageFunc: func(ctx context.Context, prompt string) (string, error) {
+			calls++
+			return "feat(b): add b", nil
+		},
+	}
+	done := executeQueue(chunks, [][]int{{0}, {1}}, []string{"fix(a): set x", ""}, client, git.CommitOptions{})
+	if done.err != nil {
+		t.Fatal(done.err)
+	}
+	if calls != 1 {
+		t.Errorf("generated %d message(s), want only the one without a draft", calls)
+	}
+	if got := repo.Subjects(3); !slices.Equal(got, []string{"feat(b): add b", "fix(a): set x", "feat: add a"}) {
+		t.Errorf("log = %q", got)
+	}
+}
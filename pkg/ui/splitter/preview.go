@@ -0,0 +1,144 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+var (
+	previewStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1)
+
+	diffAddStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("71"))
+	diffDelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("167"))
+)
+
+// newPreview returns the viewport that shows the highlighted row, sized for a
+// terminal of width by height (0 when unknown).
+func newPreview(width, height int) viewport.Model {
+	if width <= 0 {
+		width = 80
+	}
+	rows := 10
+	if height > 0 {
+		rows = min(max(height/3, 5), 20)
+	}
+	vp := viewport.New(width, rows+previewStyle.GetVerticalFrameSize())
+	vp.Style = previewStyle
+	return vp
+}
+
+// listWindow returns the rows [start, end) of the list that fit the terminal
+// along with the list's header and footer, the plan, the preview, and the
+// queue, keeping the cursor in view. Every row is shown when the terminal
+// height is unknown or the list fits.
+func (m Model) listWindow(header, footer string) (start, end int) {
+	total := m.rows()
+	if m.height <= 0 {
+		return 0, total
+	}
+	avail := m.height - screenLines(m.planView()+header+footer+m.previewView()+m.queueView(), m.width)
+	if total <= avail {
+		return 0, total
+	}
+	// Two lines note the rows above and below the window.
+	avail = max(avail-2, 1)
+	start = min(max(m.cursor-avail/2, 0), total-avail)
+	return start, start + avail
+}
+
+// moreRows notes the rows of the list hidden above (or below) the window, on
+// the line listWindow keeps for it.
+func (m Model) moreRows(start, end int, above bool) string {
+	total := m.rows()
+	if start == 0 && end == total {
+		return ""
+	}
+	if above && start > 0 {
+		return hunkHeaderStyle.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n"
+	}
+	if !above && end < total {
+		return hunkHeaderStyle.Render(fmt.Sprintf("  ↓ %d more", total-end)) + "\n"
+	}
+	return "\n"
+}
+
+// screenLines returns the terminal lines s takes at width, counting wrapped
+// lines.
+func screenLines(s string, width int) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		if w := lipgloss.Width(line); width > 0 && w > width {
+			n += (w + width - 1) / width
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// colorHunk renders the header and lines of c with diff coloring.
+func colorHunk(c git.DiffChunk) string {
+	lines := make([]string, 0, len(c.Lines)+1)
+	lines = append(lines, hunkHeaderStyle.Render(c.HunkHeader))
+	for _, line := range c.Lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		switch {
+		case strings.HasPrefix(line, "+"):
+			line = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = diffDelStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// previewContent returns the hunk under the cursor, or every hunk of the file
+// under it in the per-file view.
+func (m Model) previewContent() string {
+	if len(m.chunks) == 0 {
+		return ""
+	}
+	if !m.byFile {
+		return colorHunk(m.chunks[m.cursor])
+	}
+	hunks := make([]string, 0, len(m.files[m.cursor].chunks))
+	for _, i := range m.files[m.cursor].chunks {
+		hunks = append(hunks, colorHunk(m.chunks[i]))
+	}
+	return strings.Join(hunks, "\n\n")
+}
+
+// syncPreview loads the row under the cursor into the preview, scrolled to its
+// top when the row changed.
+func (m Model) syncPreview(moved bool) Model {
+	m.preview.SetContent(m.previewContent())
+	if moved {
+		m.preview.GotoTop()
+	}
+	return m
+}
+
+// previewView renders the preview below the list with its scroll position.
+func (m Model) previewView() string {
+	if len(m.chunks) == 0 {
+		return ""
+	}
+	title := m.chunks[m.cursor].FilePath
+	if m.byFile {
+		title = m.files[m.cursor].path
+	}
+	scroll := ""
+	if total, visible := m.preview.TotalLineCount(), m.preview.VisibleLineCount(); total > visible {
+		scroll = fmt.Sprintf(" %d%% (pgup/pgdown or shift+up/down to scroll)", int(m.preview.ScrollPercent()*100))
+	}
+	return fmt.Sprintf("\n\n%s%s\n%s", selectedChunkStyle.Render(title), hunkHeaderStyle.Render(scroll), m.preview.View())
+}
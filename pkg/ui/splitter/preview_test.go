@@ -0,0 +1,64 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+func TestPreview(t *testing.T) {
+	m := NewSplitterModel(testChunks(), nil)
+	if got := m.preview.View(); !strings.Contains(got, "var x = 2") || strings.Contains(got, "// new") {
+		t.Fatalf("preview = %q, want only the first hunk", got)
+	}
+
+	// Moving the cursor shows the next hunk of the same file from its top.
+	m.preview.Height = 5
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(Model)
+	if got := m.preview.View(); !strings.Contains(got, "// new") || strings.Contains(got, "var x = 2") {
+		t.Fatalf("preview = %q, want the second hunk", got)
+	}
+
+	// The file view shows every hunk of the file, and shift+down scrolls it.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = next.(Model)
+	if got := m.preview.View(); !strings.Contains(got, "@@ -1,2 +1,2 @@") || m.preview.TotalLineCount() != 8 {
+		t.Fatalf("preview = %q (%d lines), want both hunks of a.go", got, m.preview.TotalLineCount())
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftDown})
+	m = next.(Model)
+	if m.preview.YOffset != 1 {
+		t.Errorf("YOffset = %d after shift+down, want 1", m.preview.YOffset)
+	}
+	if view := m.View(); !strings.Contains(view, "to scroll") {
+		t.Errorf("View() = %q, want a scroll hint", view)
+	}
+}
+
+func TestListWindow(t *testing.T) {
+	var chunks []git.DiffChunk
+	for i := 0; i < 100; i++ {
+		chunks = append(chunks, git.DiffChunk{FilePath: fmt.Sprintf("f%02d.go", i), HunkHeader: "@@ -1 +1 @@", Lines: []string{"+x"}})
+	}
+	next, _ := NewSplitterModel(chunks, nil).Update(tea.WindowSizeMsg{Width: 300, Height: 40})
+	m := next.(Model)
+	for i := 0; i < 60; i++ {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = next.(Model)
+	}
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > 40 {
+		t.Errorf("View() has %d lines, want at most the terminal's 40", lines)
+	}
+	if !strings.Contains(view, "> [ ] f60.go") || strings.Contains(view, "f00.go") {
+		t.Errorf("View() = %q, want the rows around the cursor", view)
+	}
+	if !strings.Contains(view, "more") {
+		t.Errorf("View() = %q, want a note of the hidden rows", view)
+	}
+}
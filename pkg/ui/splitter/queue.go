@@ -0,0 +1,198 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// queueTimeout bounds generating every queued message and creating the commits.
+const queueTimeout = 2 * time.Minute
+
+// queueDoneMsg reports the subjects of the commits created from the queue.
+type queueDoneMsg struct {
+	subjects []string
+	total    int
+	err      error
+	// truncated numbers the commits whose message prompt limits.prompt cut short.
+	truncated []int
+}
+
+func (msg queueDoneMsg) String() string {
+	var b strings.Builder
+	for i, subject := range msg.subjects {
+		b.WriteString(fmt.Sprintf("%d. %s\n", i+1, subject))
+	}
+	if len(msg.truncated) > 0 {
+		numbers := make([]string, len(msg.truncated))
+		for i, n := range msg.truncated {
+			numbers[i] = strconv.Itoa(n)
+		}
+		b.WriteString(fmt.Sprintf("Warning: the prompt of commit(s) %s exceeded limits.prompt.maxChars and was truncated; the AI saw only part of their changes.\n", strings.Join(numbers, ", ")))
+	}
+	if msg.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n", msg.err))
+		if len(msg.subjects) < msg.total {
+			b.WriteString(fmt.Sprintf("%d of %d queued commits were created; the rest of the changes are still staged.\n", len(msg.subjects), msg.total))
+		}
+		return b.String()
+	}
+	if msg.total == 1 {
+		return b.String() + "Selected chunks committed successfully!"
+	}
+	return b.String() + fmt.Sprintf("%d queued commits created successfully!", msg.total)
+}
+
+// enqueueSelection moves the selected chunks into a new queued commit.
+func (m Model) enqueueSelection() Model {
+	var group []int
+	for i, isSelected := range m.selected {
+		if _, ok := m.queued[i]; isSelected && !ok {
+			group = append(group, i)
+		}
+	}
+	if len(group) == 0 {
+		return m
+	}
+	sort.Ints(group)
+	m.queue = append(m.queue, group)
+	m.drafts = append(m.drafts, "")
+	for _, i := range group {
+		m.queued[i] = len(m.queue)
+		delete(m.selected, i)
+	}
+	m.updateSelectedCount()
+	return m
+}
+
+// unqueueLast returns the chunks of the last queued commit to the selection.
+func (m Model) unqueueLast() Model {
+	if len(m.queue) == 0 {
+		return m
+	}
+	last := m.queue[len(m.queue)-1]
+	m.queue = m.queue[:len(m.queue)-1]
+	m.drafts = m.drafts[:len(m.drafts)-1]
+	for _, i := range last {
+		delete(m.queued, i)
+		m.selected[i] = true
+	}
+	m.updateSelectedCount()
+	return m
+}
+
+// editDraft opens the message of the queued commit under the cursor for
+// editing; in the file view that is the first queued commit of the file.
+func (m Model) editDraft() (tea.Model, tea.Cmd) {
+	rows := []int{m.cursor}
+	if m.byFile {
+		rows = m.files[m.cursor].chunks
+	}
+	for _, i := range rows {
+		if n, ok := m.queued[i]; ok {
+			m.editing = n - 1
+			m.state = stateEditMessage
+			m.textarea.SetValue(m.drafts[m.editing])
+			return m, m.textarea.Focus()
+		}
+	}
+	return m, nil
+}
+
+// queueView lists the queued commits with the subject of their drafts.
+func (m Model) queueView() string {
+	if len(m.queue) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nQueued commits:\n")
+	for i, group := range m.queue {
+		subject, _, _ := strings.Cut(m.drafts[i], "\n")
+		if subject == "" {
+			subject = "(message generated when committing)"
+		}
+		b.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, subject, hunkHeaderStyle.Render(fmt.Sprintf("(%d hunk(s))", len(group)))))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// executeQueue generates the messages of the queued commits without a draft
+// concurrently and, when every one succeeded, creates the commits in queue
+// order.
+func executeQueue(chunks []git.DiffChunk, queue [][]int, drafts []string, client ai.AIClient, opts git.CommitOptions) queueDoneMsg {
+	ctx, cancel := context.WithTimeout(context.Background(), queueTimeout)
+	defer cancel()
+
+	entries := make([]git.QueuedCommit, len(queue))
+	errs := make([]error, len(queue))
+	truncated := make([]bool, len(queue))
+	var wg sync.WaitGroup
+	for i, group := range queue {
+		entries[i].Patch = buildPatch(chunks, group)
+		if drafts[i] != "" {
+			entries[i].Message = drafts[i]
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			msg, cut, err := generatePartialCommitMessage(ctx, entries[i].Patch, client)
+			if err != nil {
+				errs[i] = fmt.Errorf("commit %d: %w", i+1, err)
+				return
+			}
+			entries[i].Message, truncated[i] = msg, cut
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return queueDoneMsg{total: len(queue), err: err}
+	}
+
+	done, err := git.CommitQueue(ctx, entries, opts)
+	subjects := make([]string, done)
+	var cut []int
+	for i := range subjects {
+		subjects[i], _, _ = strings.Cut(entries[i].Message, "\n")
+		if truncated[i] {
+			cut = append(cut, i+1)
+		}
+	}
+	return queueDoneMsg{subjects: subjects, total: len(queue), err: err, truncated: cut}
+}
+
+// buildPatch joins the chunks at indices into a patch for `git apply --cached`.
+// Hunks starting at line 0 create or delete the whole file.
+func buildPatch(chunks []git.DiffChunk, indices []int) string {
+	var sb strings.Builder
+	for _, i := range indices {
+		c := chunks[i]
+		oldPath, newPath := "a/"+c.FilePath, "b/"+c.FilePath
+		sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", c.FilePath, c.FilePath))
+		switch {
+		case strings.HasPrefix(c.HunkHeader, "@@ -0,0 "):
+			sb.WriteString("new file mode 100644\n")
+			oldPath = "/dev/null"
+		case strings.Contains(c.HunkHeader, " +0,0 @@"):
+			sb.WriteString("deleted file mode 100644\n")
+			newPath = "/dev/null"
+		}
+		sb.WriteString("--- " + oldPath + "\n")
+		sb.WriteString("+++ " + newPath + "\n")
+		sb.WriteString(c.HunkHeader + "\n")
+		for _, line := range c.Lines {
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
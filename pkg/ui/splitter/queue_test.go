@@ -0,0 +1,135 @@
+package splitter
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/internal/testutil"
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+func testChunks() []git.DiffChunk {
+	return []git.DiffChunk{
+		{FilePath: "a.go", HunkHeader: "@@ -1,2 +1,2 @@", Lines: []string{" package a", "-var x = 1", "+var x = 2"}},
+		{FilePath: "a.go", HunkHeader: "@@ -20,1 +20,1 @@", Lines: []string{"-// old", "+// new"}},
+		{FilePath: "b.go", HunkHeader: "@@ -0,0 +1 @@", Lines: []string{"+package b"}},
+	}
+}
+
+func TestEnqueueSelection(t *testing.T) {
+	m := NewSplitterModel(testChunks(), nil)
+	m.selected[2] = true
+	m.selected[0] = true
+	m = m.enqueueSelection()
+	if len(m.queue) != 1 || len(m.queue[0]) != 2 || m.queue[0][0] != 0 || m.queue[0][1] != 2 {
+		t.Fatalf("queue = %v, want [[0 2]]", m.queue)
+	}
+	if m.selectedCount != 0 || m.queued[2] != 1 {
+		t.Errorf("selectedCount = %d, queued = %v", m.selectedCount, m.queued)
+	}
+
+	// Queued chunks cannot be selected again.
+	m.selected[0] = true
+	m.selected[1] = true
+	m = m.enqueueSelection()
+	if len(m.queue) != 2 || len(m.queue[1]) != 1 || m.queue[1][0] != 1 {
+		t.Fatalf("queue = %v, want [[0 2] [1]]", m.queue)
+	}
+
+	m = m.unqueueLast()
+	if len(m.queue) != 1 || !m.selected[1] {
+		t.Errorf("after unqueue: queue = %v, selected = %v", m.queue, m.selected)
+	}
+	if _, ok := m.queued[1]; ok {
+		t.Error("chunk 1 still queued after unqueue")
+	}
+}
+
+func TestBuildPatch(t *testing.T) {
+	patch := buildPatch(testChunks(), []int{1, 2})
+	want := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -20,1 +20,1 @@\n-// old\n+// new\n" +
+		"diff --git a/b.go b/b.go\nnew file mode 100644\n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+package b\n"
+	if patch != want {
+		t.Errorf("buildPatch() =\n%s\nwant\n%s", patch, want)
+	}
+	if strings.Contains(buildPatch(testChunks(), nil), "diff") {
+		t.Error("empty selection should give an empty patch")
+	}
+}
+
+func TestQuitConfirmsQueuedCommits(t *testing.T) {
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}
+
+	m := NewSplitterModel(testChunks(), nil)
+	if _, cmd := m.Update(q); cmd == nil {
+		t.Error("q with an empty queue should quit")
+	}
+
+	m.selected[0] = true
+	m = m.enqueueSelection()
+	next, cmd := m.Update(q)
+	m = next.(Model)
+	if cmd != nil || m.state != stateConfirmQuit {
+		t.Fatalf("q with a queued commit: state = %v, want the confirmation", m.state)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(Model); m.state != stateList || len(m.queue) != 1 {
+		t.Errorf("esc should return to the list with the queue kept, state = %v, queue = %v", m.state, m.queue)
+	}
+	if _, cmd := m.Update(q); cmd != nil {
+		t.Error("q should ask again")
+	}
+
+	m.instantQuit = true
+	if _, cmd := m.Update(q); cmd == nil {
+		t.Error("q with instantQuit should quit")
+	}
+}
+
+func TestExecuteQueue_Integration(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Write("a.go", "package a\n\nvar x = 1\n")
+	repo.Write("b.go", "package b\n")
+	repo.Stage()
+	repo.Commit("feat: add a and b")
+	repo.Write("a.go", "package a\n\nvar x = 2\n")
+	repo.Write("b.go", "package b\n\nvar y = 3\n")
+	repo.Write("c.go", "package c\n")
+	repo.Stage()
+
+	patch, err := git.StagedPatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := git.ParseDiffToChunks(strings.TrimRight(patch, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := map[string]int{}
+	for i, c := range chunks {
+		index[c.FilePath] = i
+	}
+	client := &testutil.MockAIClient{
+		GetCommitMessageFunc: func(ctx context.Context, prompt string) (string, error) {
+			if strings.Contains(prompt, "var x = 2") {
+				return "fix(a): bump x", nil
+			}
+			return "feat(b): add y", nil
+		},
+	}
+
+	done := executeQueue(chunks, [][]int{{index["a.go"]}, {index["b.go"]}}, []string{"", ""}, client, git.CommitOptions{})
+	if done.err != nil {
+		t.Fatal(done.err)
+	}
+	if got := repo.Subjects(3); !slices.Equal(got, []string{"feat(b): add y", "fix(a): bump x", "feat: add a and b"}) {
+		t.Errorf("log = %q", got)
+	}
+	if got := repo.Staged(); !slices.Equal(got, []string{"c.go"}) {
+		t.Errorf("still staged = %q, want the unqueued c.go", got)
+	}
+}
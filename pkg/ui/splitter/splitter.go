@@ -3,11 +3,10 @@ package splitter
 import (
     "context"
     "fmt"
-    "os"
-    "os/exec"
     "strings"
-    "time"
 
+    "github.com/charmbracelet/bubbles/textarea"
+    "github.com/charmbracelet/bubbles/viewport"
     tea "github.com/charmbracelet/bubbletea"
     "github.com/charmbracelet/lipgloss"
 
@@ -22,6 +21,8 @@ const (
 	stateList splitterState = iota
 	stateSpinner
 	stateCommitted
+	stateConfirmQuit
+	stateEditMessage
 )
 
 var (
@@ -29,6 +30,9 @@ var (
 				Foreground(lipgloss.Color("212")) // Highlight color for selected chunks
 
 	unselectedChunkStyle = lipgloss.NewStyle() // Default style for unselected chunks
+
+	queuedChunkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	hunkHeaderStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("67"))
 )
 
 // Model for interactive splitting.
@@ -36,11 +40,38 @@ type Model struct {
 	state         splitterState
 	chunks        []git.DiffChunk
 	selected      map[int]bool
+	cursor        int
 	aiClient      ai.AIClient
+	commitOpts    git.CommitOptions
 	commitResult  string
 	totalChunks   int // Total chunks count for status
 	selectedCount int // Count of selected chunks for status
-	
+
+	// queue holds the chunk indices of each queued commit, in commit order;
+	// queued maps a chunk to its 1-based position in the queue.
+	queue  [][]int
+	queued map[int]int
+	// drafts holds the message of each queued commit; an empty one is
+	// generated when committing.
+	drafts []string
+
+	// planned is set when the queue came from an AI split plan.
+	planned bool
+	// editing is the queue index whose draft the textarea edits.
+	editing  int
+	textarea textarea.Model
+
+	// instantQuit skips the confirmation when quitting with queued commits.
+	instantQuit bool
+
+	// byFile shows files instead of hunks, so whole files are selected; the
+	// cursor then indexes files.
+	byFile bool
+	files  []fileEntry
+
+	// preview shows the hunks of the row under the cursor.
+	preview viewport.Model
+
 	// Terminal dimensions
 	width  int
 	height int
@@ -48,15 +79,23 @@ type Model struct {
 
 // NewSplitterModel creates a new splitter model.
 func NewSplitterModel(chunks []git.DiffChunk, client ai.AIClient) Model {
-	return Model{
+	m := Model{
 		state:         stateList,
 		chunks:        chunks,
 		selected:      make(map[int]bool),
+		queued:        make(map[int]int),
 		aiClient:      client,
 		commitResult:  "",
 		totalChunks:   len(chunks), // Initialize total chunks
 		selectedCount: 0,           // Initialize selected count to 0
 	}
+	m.files = m.groupFiles()
+	m.textarea = textarea.New()
+	m.textarea.ShowLineNumbers = false
+	m.textarea.SetWidth(80)
+	m.textarea.SetHeight(8)
+	m.preview = newPreview(0, 0)
+	return m.syncPreview(true)
 }
 
 // NewProgram creates a new Bubble Tea program for splitting.
@@ -75,31 +114,111 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		return m, nil
+		if msg.Width > 4 {
+			m.textarea.SetWidth(msg.Width - 4)
+		}
+		m.preview = newPreview(msg.Width, msg.Height)
+		return m.syncPreview(false), nil
 		
+	case queueDoneMsg:
+		m.state = stateCommitted
+		m.commitResult = msg.String()
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.state == stateEditMessage {
+			switch msg.String() {
+			case "ctrl+s":
+				m.drafts[m.editing] = strings.TrimSpace(m.textarea.Value())
+				m.textarea.Blur()
+				m.state = stateList
+				return m, nil
+			case "esc":
+				m.textarea.Blur()
+				m.state = stateList
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.textarea, cmd = m.textarea.Update(msg)
+			return m, cmd
+		}
+		if m.state == stateConfirmQuit {
+			switch msg.String() {
+			case "y", "enter", "ctrl+c":
+				return m, tea.Quit
+			case "n", "esc":
+				m.state = stateList
+			}
+			return m, nil
+		}
+		if m.state != stateList {
+			if msg.String() == "q" || msg.String() == "esc" || msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		row, byFile := m.cursor, m.byFile
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
+			if len(m.queue) > 0 && !m.instantQuit {
+				m.state = stateConfirmQuit
+				return m, nil
+			}
 			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < m.rows()-1 {
+				m.cursor++
+			}
+		case "pgup":
+			m.preview.PageUp()
+		case "pgdown":
+			m.preview.PageDown()
+		case "shift+up":
+			m.preview.ScrollUp(1)
+		case "shift+down":
+			m.preview.ScrollDown(1)
+		case "f":
+			m = m.toggleView()
 		case " ":
-			// Toggle selection for all chunks.
-			for i := range m.chunks {
-				m.selected[i] = !m.selected[i]
+			if m.byFile {
+				m = m.toggleFile()
+				break
+			}
+			// Toggle selection for the chunk under the cursor.
+			if _, ok := m.queued[m.cursor]; !ok {
+				m.selected[m.cursor] = !m.selected[m.cursor]
 			}
 			m.updateSelectedCount() // Update selected count
+		case "enter":
+			m = m.enqueueSelection()
+		case "u":
+			m = m.unqueueLast()
+		case "m":
+			return m.editDraft()
 		case "c":
 			return m.updateCommit()
 		case "a":
 			for i := range m.chunks {
-				m.selected[i] = true
+				if _, ok := m.queued[i]; !ok {
+					m.selected[i] = true
+				}
 			}
 			m.updateSelectedCount() // Update count
 		case "i":
 			for i := range m.chunks {
-				m.selected[i] = !m.selected[i]
+				if _, ok := m.queued[i]; !ok {
+					m.selected[i] = !m.selected[i]
+				}
 			}
 			m.updateSelectedCount() // Update count
 		}
+		if m.cursor != row || m.byFile != byFile {
+			m = m.syncPreview(true)
+		}
 	}
 	return m, nil
 }
@@ -107,44 +226,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) View() string {
 	switch m.state {
 	case stateList:
-		return m.listView()
+		if m.byFile {
+			return m.planView() + m.fileListView() + m.previewView() + m.queueView()
+		}
+		return m.planView() + m.listView() + m.previewView() + m.queueView()
 	case stateSpinner:
+		if len(m.queue) > 1 {
+			return fmt.Sprintf("Generating %d commit messages and committing...", len(m.queue))
+		}
 		return "Committing selected chunks..."
 	case stateCommitted:
 		return m.commitResult + "\nPress 'q' to exit."
+	case stateConfirmQuit:
+		return fmt.Sprintf("%d queued commit(s) have not been created.\n\nQuit and discard the queue? y/Enter to quit, n/ESC to go back.", len(m.queue))
+	case stateEditMessage:
+		return fmt.Sprintf("Message of commit %d (ctrl+s to save, esc to cancel; leave it empty to generate it when committing):\n\n%s", m.editing+1, m.textarea.View())
 	}
 	return ""
 }
 
 func (m Model) listView() string {
+	header := "Select chunks to commit (space to toggle, enter to queue them as a commit, 'u' to unqueue the last, 'c' to commit the queue and selection, 'a' to select all, 'i' to invert selection, 'm' to edit the message of the commit under the cursor, 'f' to select whole files, 'q' to quit):\n\n"
+	footer := fmt.Sprintf("\nSelected chunks: %d/%d  Queued commits: %d", m.selectedCount, m.totalChunks, len(m.queue)) // Show status footer
+	start, end := m.listWindow(header, footer)
 	var b strings.Builder
-	b.WriteString("Select chunks to commit (space to toggle, 'c' to commit, 'a' to select all, 'i' to invert selection, 'q' to quit):\n\n")
-	for i, chunk := range m.chunks {
+	b.WriteString(header)
+	b.WriteString(m.moreRows(start, end, true))
+	for i := start; i < end; i++ {
+		chunk := m.chunks[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
 		marker := " "
 		style := unselectedChunkStyle // Default unselected style
-		if m.selected[i] {
+		if n, ok := m.queued[i]; ok {
+			marker = fmt.Sprint(n)
+			style = queuedChunkStyle
+		} else if m.selected[i] {
 			marker = "x"
 			style = selectedChunkStyle // Apply selected style if chunk is selected
 		}
-		b.WriteString(fmt.Sprintf("[%s] %s\n", marker, style.Render(chunk.FilePath))) // Apply style to file path
+		b.WriteString(fmt.Sprintf("%s[%s] %s %s\n", cursor, marker, style.Render(chunk.FilePath), hunkHeaderStyle.Render(chunk.HunkHeader))) // Apply style to file path
 	}
-	footer := fmt.Sprintf("\nSelected chunks: %d/%d", m.selectedCount, m.totalChunks) // Show status footer
+	b.WriteString(m.moreRows(start, end, false))
 	b.WriteString(footer)
 
 	return b.String()
 }
 
+// rows returns the number of rows of the current view.
+func (m Model) rows() int {
+	if m.byFile {
+		return len(m.files)
+	}
+	return len(m.chunks)
+}
+
+// updateCommit queues the current selection, if any, and commits the whole queue.
 func (m Model) updateCommit() (tea.Model, tea.Cmd) {
+	m = m.enqueueSelection()
+	if len(m.queue) == 0 {
+		return m, nil
+	}
 	m.state = stateSpinner
+	chunks, queue, drafts, client, opts := m.chunks, m.queue, m.drafts, m.aiClient, m.commitOpts
 	return m, func() tea.Msg {
-		err := partialCommit(m.chunks, m.selected, m.aiClient)
-		if err != nil {
-			m.commitResult = fmt.Sprintf("Error: %v", err)
-		} else {
-			m.commitResult = "Selected chunks committed successfully!"
-		}
-		m.state = stateCommitted
-		return nil
+		return executeQueue(chunks, queue, drafts, client, opts)
 	}
 }
 
@@ -159,91 +307,66 @@ func (m *Model) updateSelectedCount() {
 	m.selectedCount = count
 }
 
-func partialCommit(chunks []git.DiffChunk, selected map[int]bool, client ai.AIClient) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	patch, err := buildPatch(chunks, selected)
-	if err != nil {
-		return err
-	}
-	if strings.TrimSpace(patch) == "" {
-		return fmt.Errorf("no chunks selected")
-	}
-	cmd := exec.CommandContext(ctx, "git", "apply", "--cached", "-")
-	cmd.Stdin = strings.NewReader(patch)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to apply patch: %w", err)
-	}
-
-	partialDiff, err := git.GetGitDiffIgnoringMoves(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get partial diff: %w", err)
-	}
-
-	commitMsg, err := generatePartialCommitMessage(ctx, partialDiff, client)
-	if err != nil {
-		return err
-	}
-	if err := git.CommitChanges(ctx, commitMsg); err != nil {
-		return err
-	}
-	return nil
-}
-
-func buildPatch(chunks []git.DiffChunk, selected map[int]bool) (string, error) {
-	var sb strings.Builder
-	for i, c := range chunks {
-		if !selected[i] {
-			continue
-		}
-		sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", c.FilePath, c.FilePath))
-		sb.WriteString("--- a/" + c.FilePath + "\n")
-		sb.WriteString("+++ b/" + c.FilePath + "\n")
-		sb.WriteString(c.HunkHeader + "\n")
-		for _, line := range c.Lines {
-			sb.WriteString(line + "\n")
-		}
-	}
-	return sb.String(), nil
-}
-
-func generatePartialCommitMessage(ctx context.Context, diff string, client ai.AIClient) (string, error) {
+// generatePartialCommitMessage asks client for the message of the hunks in
+// diff and reports whether limits.prompt cut the prompt short.
+func generatePartialCommitMessage(ctx context.Context, diff string, client ai.AIClient) (string, bool, error) {
     cfg, _ := config.LoadOrCreateConfig()
+    // The patch is committed as is; only the prompt is filtered like the
+    // commit diff.
+    diff = git.PromptPatch(ctx, diff)
     if cfg != nil && cfg.Limits.Diff.Enabled && cfg.Limits.Diff.MaxChars > 0 {
         if summarized, did := client.MaybeSummarizeDiff(diff, cfg.Limits.Diff.MaxChars); did {
             diff = summarized
         }
     }
-    prompt := fmt.Sprintf(`Generate a commit message for the following partial diff.
+    prompt, truncated := limitPrompt(cfg, fmt.Sprintf(`Generate a commit message for the following partial diff.
 The message must follow Conventional Commits style.
 Output only the commit message.
 
 Diff:
 %s
-`, diff)
+`, diff))
+    msg, err := client.GetCommitMessage(ctx, prompt)
+    if err != nil {
+        return "", false, fmt.Errorf("AI error: %w", err)
+    }
+    return strings.TrimSpace(msg), truncated, nil
+}
+
+// limitPrompt truncates prompt to the configured prompt limit, if any, and
+// reports whether it did.
+func limitPrompt(cfg *config.Config, prompt string) (string, bool) {
     if cfg != nil && cfg.Limits.Prompt.Enabled && cfg.Limits.Prompt.MaxChars > 0 {
         if len(prompt) > cfg.Limits.Prompt.MaxChars {
             limit := cfg.Limits.Prompt.MaxChars
             if limit > 3 { limit -= 3 }
-            prompt = prompt[:limit] + "..."
+            return prompt[:limit] + "...", true
         }
     }
-    msg, err := client.GetCommitMessage(ctx, prompt)
-    if err != nil {
-        return "", fmt.Errorf("AI error: %w", err)
-    }
-    return strings.TrimSpace(msg), nil
+    return prompt, false
 }
 
-func RunInteractiveSplit(ctx context.Context, client ai.AIClient) error {
+// RunInteractiveSplit lets the user commit selected chunks of the staged diff
+// using opts. With byFile it starts in the per-file view, where whole files
+// are selected and each queued group of files gets its own message.
+func RunInteractiveSplit(ctx context.Context, client ai.AIClient, opts git.CommitOptions, byFile bool) error {
     cfg, _ := config.LoadOrCreateConfig()
-    diff, err := git.GetGitDiffIgnoringMoves(ctx)
-    if err != nil {
+    chunks, err := stagedChunks(ctx, cfg)
+    if err != nil || len(chunks) == 0 {
         return err
     }
+    return runSplitter(NewSplitterModel(chunks, client), cfg, opts, byFile)
+}
+
+// stagedChunks returns the hunks of the staged changes without lock files,
+// printing why when there are none.
+func stagedChunks(ctx context.Context, cfg *config.Config) ([]git.DiffChunk, error) {
+    // Chunks are applied back to the index, so they come from git's own diff
+    // rather than the cleaned prompt diff.
+    diff, err := git.StagedPatch(ctx)
+    if err != nil {
+        return nil, err
+    }
     lockFiles := []string{"go.mod", "go.sum"}
     if cfg != nil && len(cfg.LockFiles) > 0 {
         lockFiles = cfg.LockFiles
@@ -251,17 +374,23 @@ func RunInteractiveSplit(ctx context.Context, client ai.AIClient) error {
     diff = git.FilterLockFiles(diff, lockFiles)
     if strings.TrimSpace(diff) == "" {
         fmt.Println("No changes to commit (after filtering lock files). Did you stage your changes?")
-        return nil
+        return nil, nil
     }
-	chunks, err := git.ParseDiffToChunks(diff)
+	chunks, err := git.ParseDiffToChunks(strings.TrimRight(diff, "\n"))
 	if err != nil {
-		return fmt.Errorf("parseDiffToChunks error: %w", err)
+		return nil, fmt.Errorf("parseDiffToChunks error: %w", err)
 	}
 	if len(chunks) == 0 {
 		fmt.Println("No diff chunks found.")
-		return nil
 	}
-	model := NewSplitterModel(chunks, client)
-	prog := NewProgram(model)
+	return chunks, nil
+}
+
+// runSplitter runs model until the user quits.
+func runSplitter(model Model, cfg *config.Config, opts git.CommitOptions, byFile bool) error {
+	model.commitOpts = opts
+	model.instantQuit = cfg != nil && cfg.InstantQuit
+	model.byFile = byFile
+	prog := NewProgram(model.syncPreview(true))
 	return prog.Start()
 }
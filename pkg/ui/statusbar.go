@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+var statusBarStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("252")).
+	Background(lipgloss.Color("236")).
+	Padding(0, 1)
+
+// viewStatusBar renders the footer with the provider and model and, once a request
+// has been made, its latency, token usage, and prompt cache status.
+func (m Model) viewStatusBar() string {
+	if m.aiClient == nil {
+		return ""
+	}
+	name := m.aiClient.ProviderName()
+	if model := ai.ModelOf(m.aiClient); model != "" {
+		name += "/" + model
+	}
+	fields := []string{name}
+
+	if stats, ok := ai.StatsOf(m.aiClient); ok {
+		tokens := fmt.Sprintf("%d in / %d out tokens", stats.InputTokens, stats.OutputTokens)
+		cache := "cache: n/a"
+		if stats.Estimated {
+			tokens += " (est.)"
+		} else if stats.CachedTokens > 0 {
+			cache = fmt.Sprintf("cache: hit (%d tokens)", stats.CachedTokens)
+		} else {
+			cache = "cache: miss"
+		}
+		fields = append(fields, "last request "+formatLatency(stats.Latency), tokens, cache)
+	} else {
+		fields = append(fields, "no request yet")
+	}
+
+	style := statusBarStyle
+	if m.width > 0 {
+		style = style.Width(m.width)
+	}
+	return style.Render(strings.Join(fields, " │ "))
+}
+
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
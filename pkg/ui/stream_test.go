@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+)
+
+type stubClient struct{ ai.BaseAIClient }
+
+func (stubClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return "", nil
+}
+
+func TestStableStreamText(t *testing.T) {
+	tests := []struct {
+		raw, commitType, want string
+	}{
+		{"``", "", ""},
+		{"```te", "", ""},
+		{"```text\nfix", "", "```text\nfix"},
+		{"```\nAdd login``", "", "```\nAdd login"},
+		{"fe", "feat", ""},
+		{"✨ feat(ui", "feat", ""},
+		{"feat(ui): add", "feat", "feat(ui): add"},
+		{"Add", "feat", "Add"},
+		{"fix\n", "feat", "fix\n"},
+	}
+	for _, tt := range tests {
+		m := Model{aiClient: &stubClient{}, commitType: tt.commitType}
+		if got := m.stableStreamText(tt.raw); got != tt.want {
+			t.Errorf("stableStreamText(%q, %q) = %q, want %q", tt.raw, tt.commitType, got, tt.want)
+		}
+	}
+}
+
+// TestPreviewStreamed feeds a reply one character at a time: the preview must
+// never show a fence or the model's own type, and must end as the final message.
+func TestPreviewStreamed(t *testing.T) {
+	raw := "```text\nfeat(auth)!: add login endpoint\n\nValidates the password hash.\n```"
+	m := Model{aiClient: &stubClient{}, commitType: "fix"}
+
+	var preview string
+	for i := 1; i <= len(raw); i++ {
+		preview = m.previewStreamed(raw[:i])
+		if strings.Contains(preview, "`") || strings.Contains(preview, "feat") {
+			t.Fatalf("preview after %q = %q", raw[:i], preview)
+		}
+		if preview != "" && !strings.HasPrefix(preview, "fix(auth)!: ") {
+			t.Fatalf("preview after %q = %q, want the forced type with the model's scope", raw[:i], preview)
+		}
+	}
+	if final := m.finalizeStreamed(raw); preview != final {
+		t.Errorf("last preview = %q, final = %q", preview, final)
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// viewTodos lists the markers the commit adds and whether the message carries
+// them as a TODO section.
+func (m Model) viewTodos(width int) string {
+	state := "not in the message"
+	if m.todoBody {
+		state = "appended to the message"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "TODO markers added: %d (%s; press %s to toggle)\n", len(m.todos), state, keyMap.Todos.Help().Key)
+	for _, t := range m.todos {
+		b.WriteString("\n- " + t.String())
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 2).
+		Margin(0, 1).
+		Width(width).
+		Render(b.String())
+}
+
+// todoList renders todos for the result screen, or "" without any.
+func todoList(todos []git.Todo) string {
+	if len(todos) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nFollow-up markers added by this commit (%d):", len(todos))
+	for _, t := range todos {
+		b.WriteString("\n  - " + t.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/ai-commit/pkg/git"
+)
+
+func TestTodosKey(t *testing.T) {
+	todos := []git.Todo{{Path: "a.go", Line: 3, Kind: "TODO", Text: "handle retries"}}
+	m := NewUIModel("feat: add backoff", "diff --git a/a.go b/a.go\n+x\n", "english", "prompt", "", "", "", false, &stubClient{}, false, "", "", "").WithTodos(todos, true)
+
+	if got := m.finalMessage(); !strings.Contains(got, "TODO:\n- TODO a.go:3: handle retries") {
+		t.Errorf("final message lacks the TODO section:\n%s", got)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if m = next.(Model); m.finalMessage() != "feat: add backoff" {
+		t.Errorf("T: final message = %q, want the section removed", m.finalMessage())
+	}
+
+	next, _ = m.Update(commitResultMsg{})
+	if m = next.(Model); !strings.Contains(m.result, "TODO a.go:3: handle retries") {
+		t.Errorf("result does not list the markers:\n%s", m.result)
+	}
+}
+
+func TestTodosKey_NoTodos(t *testing.T) {
+	m := NewUIModel("feat: add backoff", "", "english", "prompt", "", "", "", false, &stubClient{}, false, "", "", "").WithTodos(nil, true)
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if m = next.(Model); m.todoBody || m.finalMessage() != "feat: add backoff" {
+		t.Errorf("without markers the message changed: %q", m.finalMessage())
+	}
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// tutorialRawDiff is the sample staged diff shown by the tutorial; the go.sum
+// hunk plays the part of a filtered lock file.
+const tutorialRawDiff = `diff --git a/pkg/cache/cache.go b/pkg/cache/cache.go
+index 3b18e51..a9d4c02 100644
+--- a/pkg/cache/cache.go
++++ b/pkg/cache/cache.go
+@@ -12,9 +12,14 @@ type Cache struct {
+ func (c *Cache) Get(key string) (string, bool) {
+ 	c.mu.RLock()
+ 	defer c.mu.RUnlock()
+-	e, ok := c.items[key]
+-	return e.value, ok
++	e, ok := c.items[key]
++	if !ok || time.Now().After(e.expires) {
++		return "", false
++	}
++	return e.value, true
+ }
+@@ -31,6 +36,7 @@ func (c *Cache) Set(key, value string) {
+ 	c.mu.Lock()
+ 	defer c.mu.Unlock()
+-	c.items[key] = entry{value: value}
++	c.items[key] = entry{value: value, expires: time.Now().Add(c.ttl)}
+ }
+diff --git a/go.sum b/go.sum
+index 5f2c1d0..8e4b7a3 100644
+--- a/go.sum
++++ b/go.sum
+@@ -40,2 +40,4 @@
+ golang.org/x/sync v0.7.0 h1:YsImfSBoP9QPYL0xyKJPq0gcaJdG3rInoqxTWbfQu9M=
+ golang.org/x/sync v0.7.0/go.mod h1:Czt+wKu1gCyEFDUtn0jG5QVvpJ6rzVqr5aXyt9drQfk=
++golang.org/x/time v0.5.0 h1:o7cqy6amK/52YcAKIPlM3a+Fpj35zvRj2TP+e1xFSfk=
++golang.org/x/time v0.5.0/go.mod h1:3BpzKBy/shNhVucY/MWOyx10tF3SFh9QdLuxbVysPQM=
+`
+
+// tutorialReplies are returned in turn by the tutorial's mock provider.
+var tutorialReplies = []string{
+	"fix(cache): expire entries after their TTL\n\nGet treated expired entries as hits. Set now records an expiry and Get\nreports a miss once it has passed.",
+	"fix(cache): stop returning expired entries from Get",
+	"fix(cache): honor the configured TTL\n\nEntries are stamped with an expiry on Set and ignored by Get afterwards.",
+}
+
+// tutorialClient is an offline stand-in provider that cycles through tutorialReplies.
+type tutorialClient struct {
+	ai.BaseAIClient
+	mu sync.Mutex
+	n  int
+}
+
+func (c *tutorialClient) GetCommitMessage(ctx context.Context, _ string) (string, error) {
+	// A short pause so the generating screen is visible.
+	select {
+	case <-time.After(700 * time.Millisecond):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+	return tutorialReplies[c.n%len(tutorialReplies)], nil
+}
+
+// tutorialStep is one lesson: pressing binding while in state completes it;
+// effect then explains what the key did.
+type tutorialStep struct {
+	state   uiState
+	binding key.Binding
+	task    string
+	effect  string
+}
+
+// tutorialSteps builds the lessons from the current keyMap, so configured keys are taught.
+func tutorialSteps() []tutorialStep {
+	k := func(b key.Binding) string { return b.Help().Key }
+	return []tutorialStep{
+		{
+			state:   stateShowCommit,
+			binding: keyMap.Regenerate,
+			task:    fmt.Sprintf("Press %s to ask the provider for another message.", k(keyMap.Regenerate)),
+			effect:  "The same prompt was sent again and the message replaced. Each regeneration uses one of the \"Regens Left\".",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.TypeSelect,
+			task:    fmt.Sprintf("Press %s to change the Conventional Commits type.", k(keyMap.TypeSelect)),
+			effect:  "The type list opened.",
+		},
+		{
+			state:   stateSelectType,
+			binding: keyMap.Enter,
+			task:    "Move with up/down (or j/k) and press enter to pick a type.",
+			effect:  "The prompt was rebuilt for the chosen type and the message regenerated.",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.Edit,
+			task:    fmt.Sprintf("Press %s to edit the message yourself.", k(keyMap.Edit)),
+			effect:  "The editor opened with the current message.",
+		},
+		{
+			state:   stateEditing,
+			binding: key.NewBinding(key.WithKeys("ctrl+s")),
+			task:    "Change some text, then press ctrl+s to keep it (esc discards the edit).",
+			effect:  "ctrl+s saved your edit as the message; nothing was sent to the provider.",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.ViewDiff,
+			task:    fmt.Sprintf("Press %s to look at the staged diff.", k(keyMap.ViewDiff)),
+			effect:  "Every hunk is listed; [ ] hunks are in the prompt and [-] ones were filtered out.",
+		},
+		{
+			state:   stateShowDiff,
+			binding: keyMap.ExcludeHunk,
+			task: fmt.Sprintf("Select a hunk with up/down and press %s to exclude it from the prompt (%s excludes the whole file).",
+				k(keyMap.ExcludeHunk), k(keyMap.ExcludeFile)),
+			effect: fmt.Sprintf("The hunk is marked [x]; press %s again to undo. %s/%s would force a filtered hunk or file back in.",
+				k(keyMap.ExcludeHunk), k(keyMap.IncludeHunk), k(keyMap.IncludeFile)),
+		},
+		{
+			state:   stateShowDiff,
+			binding: keyMap.Regenerate,
+			task:    fmt.Sprintf("Press %s to regenerate with your hunk selection.", k(keyMap.Regenerate)),
+			effect:  "The prompt was rebuilt without the excluded hunk and a new message requested.",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.Preview,
+			task:    fmt.Sprintf("Press %s to preview exactly what would be sent.", k(keyMap.Preview)),
+			effect:  "The prompt is shown section by section with its size in characters and tokens.",
+		},
+		{
+			state:   statePreview,
+			binding: keyMap.Quit,
+			task:    fmt.Sprintf("Press %s (or esc) to go back.", k(keyMap.Quit)),
+			effect:  "Back to the message. On the main screen the same key quits.",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.Filtered,
+			task:    fmt.Sprintf("Press %s to see what was filtered out of the prompt.", k(keyMap.Filtered)),
+			effect:  fmt.Sprintf("The go.sum lock file is listed; %s there would regenerate from the unfiltered diff.", k(keyMap.Unfilter)),
+		},
+		{
+			state:   stateShowFiltered,
+			binding: keyMap.Quit,
+			task:    fmt.Sprintf("Press %s to go back.", k(keyMap.Quit)),
+			effect:  "Back to the message.",
+		},
+		{
+			state:   stateShowCommit,
+			binding: keyMap.Help,
+			task:    fmt.Sprintf("Press %s to list every key.", k(keyMap.Help)),
+			effect:  fmt.Sprintf("The overlay lists the keys of each screen, this one first. Press %s or esc to close it.", k(keyMap.Help)),
+		},
+		{
+			state:   stateShowCommit,
+			binding: key.NewBinding(key.WithKeys(append(keyMap.Commit.Keys(), "enter")...)),
+			task:    fmt.Sprintf("Press %s (or enter) to commit. In the tutorial nothing is written to git.", k(keyMap.Commit)),
+			effect:  "The commit would be created with the message shown.",
+		},
+	}
+}
+
+// NewTutorialModel returns a model that walks through the keybindings using a
+// sample diff and an offline mock provider. It never touches the repository.
+func NewTutorialModel() Model {
+	filterReport := &git.FilterReport{Items: []git.FilteredItem{{Path: "go.sum", Reason: git.FilterLockFile, Lines: 2}}}
+	diff, _, _ := strings.Cut(tutorialRawDiff, "diff --git a/go.sum")
+	client := &tutorialClient{BaseAIClient: ai.BaseAIClient{Provider: "tutorial", Model: "mock"}}
+	promptText := prompt.BuildCommitPrompt(diff, "english", "", "", "", "cache")
+
+	m := NewUIModel(tutorialReplies[0], diff, "english", promptText, "fix", "", "", false, client, false, "", "", "cache").
+		WithFilterReport(filterReport).
+		WithRawDiff(tutorialRawDiff)
+	m.tutorial = true
+	m.maxRegens = 10
+	return m
+}
+
+// advanceTutorial completes the current step when msg is its key in its state.
+func (m Model) advanceTutorial(msg tea.KeyMsg) Model {
+	steps := tutorialSteps()
+	if !m.tutorial || m.tutorialStep >= len(steps) {
+		return m
+	}
+	if step := steps[m.tutorialStep]; step.state == m.state && key.Matches(msg, step.binding) {
+		m.tutorialStep++
+	}
+	return m
+}
+
+var tutorialBoxStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("212")).
+	Padding(0, 1).
+	Margin(0, 1)
+
+// viewTutorial renders the lesson panel: the effect of the key just pressed and the next task.
+func (m Model) viewTutorial() string {
+	steps := tutorialSteps()
+	var b strings.Builder
+	if m.tutorialStep > 0 {
+		b.WriteString(infoLineStyle.Render("✓ "+steps[m.tutorialStep-1].effect) + "\n")
+	}
+	if m.tutorialStep < len(steps) {
+		b.WriteString(fmt.Sprintf("Tutorial %d/%d: %s", m.tutorialStep+1, len(steps), highlightStyle.Render(steps[m.tutorialStep].task)))
+	} else {
+		b.WriteString(highlightStyle.Render("Tutorial complete. Run ai-commit in a repository with staged changes to use it for real."))
+	}
+	return tutorialBoxStyle.Width(min(m.width-4, 100)).Render(b.String())
+}
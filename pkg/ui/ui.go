@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -11,14 +12,21 @@ import (
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/rs/zerolog/log"
 
 	"github.com/renatogalera/ai-commit/pkg/ai"
 	"github.com/renatogalera/ai-commit/pkg/committypes"
+	"github.com/renatogalera/ai-commit/pkg/events"
 	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/guard"
+	"github.com/renatogalera/ai-commit/pkg/lint"
+	"github.com/renatogalera/ai-commit/pkg/plugin"
 	"github.com/renatogalera/ai-commit/pkg/prompt"
+	"github.com/renatogalera/ai-commit/pkg/report"
+	"github.com/renatogalera/ai-commit/pkg/session"
 	"github.com/renatogalera/ai-commit/pkg/template"
 )
 
@@ -33,23 +41,64 @@ const (
 	stateSelectType
 	stateEditing
 	stateEditingPrompt
+	stateEditingScope
+	stateEditingIntent
+	stateConfirmQuit
+	stateReview
 	stateShowDiff
+	stateShowFiltered
+	statePreview
 )
 
 type (
 	commitResultMsg struct{ err error }
-	regenMsg        struct {
-		msg string
-		err error
+	// regenMsg and candidatesMsg carry the messages of one request; postGenerated
+	// is set once the post-generate plugins have seen them.
+	regenMsg struct {
+		msg           string
+		err           error
+		postGenerated bool
 	}
+	candidatesMsg struct {
+		msgs          []string
+		err           error
+		postGenerated bool
+	}
+	pluginErrMsg struct{ err error }
 	streamStartedMsg struct {
 		deltaCh <-chan string
 		doneCh  <-chan error
+		cancel  context.CancelFunc
+	}
+	// streamDeltaMsg and streamDoneMsg carry the channel they were read from so
+	// messages of an abandoned stream can be told apart.
+	streamDeltaMsg struct {
+		delta string
+		from  <-chan string
+	}
+	sessionSavedMsg struct {
+		path string
+		err  error
+	}
+	streamDoneMsg struct {
+		err  error
+		from <-chan error
+	}
+	// maxWaitMsg fires when the stream reading from stream has run for maxWait.
+	maxWaitMsg    struct{ stream <-chan error }
+	autoQuitMsg   struct{}
+	autosaveMsg   struct{}
+	viewDiffMsg   struct{}
+	unfilteredMsg struct {
+		diff string
+		err  error
+	}
+	reviewMsg struct {
+		diff   string
+		review string
+		source report.Source
+		err    error
 	}
-	streamDeltaMsg struct{ delta string }
-	streamDoneMsg  struct{ err error }
-	autoQuitMsg    struct{}
-	viewDiffMsg    struct{}
 )
 
 var (
@@ -90,15 +139,31 @@ var (
 )
 
 type keys struct {
-	Commit     key.Binding
-	Regenerate key.Binding
-	Edit       key.Binding
-	TypeSelect key.Binding
-	PromptEdit key.Binding
-	Quit       key.Binding
-	ViewDiff   key.Binding
-	Help       key.Binding
-	Enter      key.Binding
+	Commit      key.Binding
+	Regenerate  key.Binding
+	Improve     key.Binding
+	Edit        key.Binding
+	TypeSelect  key.Binding
+	Scope       key.Binding
+	Intent      key.Binding
+	PromptEdit  key.Binding
+	Quit        key.Binding
+	ViewDiff    key.Binding
+	Filtered    key.Binding
+	Preview     key.Binding
+	Review      key.Binding
+	Quality     key.Binding
+	Todos       key.Binding
+	FixStyle    key.Binding
+	SaveSession key.Binding
+	Unfilter    key.Binding
+	ExcludeHunk key.Binding
+	ExcludeFile key.Binding
+	IncludeHunk key.Binding
+	IncludeFile key.Binding
+	Focus       key.Binding
+	Help        key.Binding
+	Enter       key.Binding
 }
 
 var keyMap = keys{
@@ -110,6 +175,10 @@ var keyMap = keys{
 		key.WithKeys("r"),
 		key.WithHelp("r", "regenerate"),
 	),
+	Improve: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "improve draft"),
+	),
 	Edit: key.NewBinding(
 		key.WithKeys("e"),
 		key.WithHelp("e", "edit message"),
@@ -118,6 +187,14 @@ var keyMap = keys{
 		key.WithKeys("t"),
 		key.WithHelp("t", "change type"),
 	),
+	Scope: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "edit scope"),
+	),
+	Intent: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "state intent"),
+	),
 	PromptEdit: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "edit prompt"),
@@ -126,10 +203,62 @@ var keyMap = keys{
 		key.WithKeys("l"),
 		key.WithHelp("l", "view diff"),
 	),
+	Filtered: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "show filtered"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "preview prompt"),
+	),
+	Review: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "code review"),
+	),
+	Quality: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "quality breakdown"),
+	),
+	Todos: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "toggle TODO section"),
+	),
+	FixStyle: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "apply style review"),
+	),
+	SaveSession: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "save session"),
+	),
+	Unfilter: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "use unfiltered diff"),
+	),
+	ExcludeHunk: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "exclude hunk"),
+	),
+	ExcludeFile: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "exclude file"),
+	),
+	IncludeHunk: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "force-include hunk"),
+	),
+	IncludeFile: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "force-include file"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c", "esc"),
 		key.WithHelp("q", "quit"),
 	),
+	Focus: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch pane"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
@@ -140,6 +269,13 @@ var keyMap = keys{
 	),
 }
 
+// candidateKeys switch between the alternative messages of WithChoices. The
+// arrow keys are reserved, so they cannot be rebound.
+var candidateKeys = key.NewBinding(
+	key.WithKeys("left", "right"),
+	key.WithHelp("←/→", "switch candidate"),
+)
+
 type Model struct {
 	state       uiState
 	commitMsg   string
@@ -157,6 +293,15 @@ type Model struct {
 	startStreaming bool
 	streamDeltaCh  <-chan string
 	streamDoneCh   <-chan error
+	streamCancel   context.CancelFunc
+	// streamRaw is the unprocessed text streamed so far; commitMsg holds its preview.
+	streamRaw string
+
+	// maxWait bounds how long a stream runs before its partial text is offered
+	// (partial is then set) or, if nothing arrived, fallback takes over.
+	maxWait  time.Duration
+	fallback ai.AIClient
+	partial  bool
 
 	// animation
 	progress     progress.Model
@@ -173,6 +318,10 @@ type Model struct {
 
 	textarea textarea.Model
 	help     help.Model
+	// scopeInput edits the scope of the message header in stateEditingScope.
+	scopeInput textinput.Model
+	// intentInput edits the author's intent in stateEditingIntent.
+	intentInput textinput.Model
 
 	// promptTemplate stores the configured prompt template so regeneration preserves it.
 	promptTemplate string
@@ -181,6 +330,93 @@ type Model struct {
 	// scopeHint stores the auto-detected scope suggestion for the AI prompt.
 	scopeHint string
 
+	// commitOpts is forwarded to git when the commit is created.
+	commitOpts git.CommitOptions
+	// filterReport lists what was left out of the diff sent to the AI.
+	filterReport *git.FilterReport
+
+	// rawDiff is the unfiltered staged diff and baseDiff the filtered one the
+	// session started from; overrides from the diff view are applied on top.
+	rawDiff    string
+	baseDiff   string
+	overrides  git.HunkOverrides
+	hunkCursor int
+
+	// userContext is the extra prompt text (a prompt edit) kept across regenerations.
+	userContext string
+	// contextFiles is the repository's context notes, added to every prompt
+	// ahead of userContext.
+	contextFiles string
+	// intent is the author's stated purpose of the change, put at the top of the
+	// prompt (see prompt.WithIntent).
+	intent        string
+	previewCursor int
+
+	// draftSource names the draft the message started from (see WithDraft);
+	// while set, the improve key sends the message back to the AI as a draft.
+	draftSource string
+	// autosaveHash is the git.DiffHash of the staged diff; while set, the message
+	// (including an edit in progress) is autosaved every autosaveInterval.
+	// autosaved is the text saved last.
+	autosaveHash string
+	autosaved    string
+
+	// edited is set once the user changes the generated message by hand; quitting
+	// then asks for confirmation unless instantQuit is set.
+	edited      bool
+	instantQuit bool
+
+	// showHelp shows the full keymap over the current screen.
+	showHelp bool
+	// showQuality expands the quality badge into its per-rule breakdown.
+	showQuality bool
+
+	// todos are the TODO-style markers the diff adds; todoBody appends them to
+	// the committed message as a "TODO:" section.
+	todos    []git.Todo
+	todoBody bool
+
+	// review holds the code review of reviewDiff shown in stateReview, scrolled
+	// by reviewScroll lines; reviewSource says who wrote it and when, and
+	// reviewing is set while it is requested.
+	review       string
+	reviewDiff   string
+	reviewScroll int
+	reviewSource report.Source
+	reviewing    bool
+
+	// candidates holds every generated message so a saved session keeps them.
+	candidates  []string
+	// choices are the alternatives of the last request for choiceCount messages
+	// (see WithChoices), and choice the one shown.
+	choices     []string
+	choice      int
+	choiceCount int
+	sessionName string
+	// notice is a transient status line (e.g. "session saved").
+	notice string
+
+	// guard blocks committing messages with secrets, denied hosts, or profanity;
+	// guardHint explains the last violation to the next regeneration.
+	guard     *guard.Scanner
+	guardHint string
+
+	// bus receives a MessageReady event for every new message and a
+	// CommitCreated event for the commit.
+	bus *events.Bus
+	// plugins run on every generated message before it is shown.
+	plugins *plugin.Runner
+
+	// focusDiff moves keyboard focus to the diff pane of the wide two-pane
+	// layout, where up/down scroll it by diffScroll lines.
+	focusDiff  bool
+	diffScroll int
+
+	// tutorial runs the model against a sample diff and mock provider;
+	// tutorialStep indexes tutorialSteps.
+	tutorial     bool
+	tutorialStep int
+
 	// styleReview holds optional suggestions from AI for commit style:
 	styleReview string
 	// last error message to display prominently
@@ -219,6 +455,16 @@ func NewUIModel(
 	ta.SetHeight(10)
 	ta.ShowLineNumbers = false
 
+	si := textinput.New()
+	si.Prompt = "scope: "
+	si.Placeholder = "none"
+	si.CharLimit = 40
+
+	ii := textinput.New()
+	ii.Prompt = "intent: "
+	ii.Placeholder = "why this change is made"
+	ii.CharLimit = 200
+
 	if commitType == "" {
 		if guessed := committypes.GuessCommitType(commitMsg); guessed != "" {
 			commitType = guessed
@@ -243,6 +489,8 @@ func NewUIModel(
 		maxRegens:     3,
 		textarea:      ta,
 		help:          help.New(),
+		scopeInput:    si,
+		intentInput:   ii,
 
 		promptTemplate: promptTemplate,
 		ticketPattern:  ticketPattern,
@@ -257,6 +505,191 @@ func NewUIModel(
 	}
 }
 
+// WithCommitOptions returns a copy of the model that commits using opts.
+func (m Model) WithCommitOptions(opts git.CommitOptions) Model {
+	m.commitOpts = opts
+	return m
+}
+
+// WithFilterReport returns a copy of the model that can show report in the filtered panel.
+func (m Model) WithFilterReport(report *git.FilterReport) Model {
+	m.filterReport = report
+	return m
+}
+
+// WithRawDiff returns a copy of the model whose diff view lets the user include or
+// exclude hunks of raw (the unfiltered staged diff) before regenerating.
+func (m Model) WithRawDiff(raw string) Model {
+	m.rawDiff = raw
+	m.baseDiff = m.diff
+	m.overrides = git.HunkOverrides{}
+	return m
+}
+
+// WithContextFiles returns a copy of the model whose prompt includes text, the
+// fenced context files, as additional text whenever it is rebuilt.
+func (m Model) WithContextFiles(text string) Model {
+	m.contextFiles = text
+	return m
+}
+
+// WithIntent returns a copy of the model whose prompt starts with the author's
+// intent when it is rebuilt; the intent key changes it.
+func (m Model) WithIntent(intent string) Model {
+	m.intent = intent
+	return m
+}
+
+// WithTodos returns a copy of the model that lists todos, the markers the
+// commit adds, and appends them to the message body when body is set; the
+// todos key toggles the section.
+func (m Model) WithTodos(todos []git.Todo, body bool) Model {
+	m.todos = todos
+	m.todoBody = body && len(todos) > 0
+	return m
+}
+
+// finalMessage is the message as committed: the edited message with the TODO
+// section when it is on.
+func (m Model) finalMessage() string {
+	if !m.todoBody {
+		return m.commitMsg
+	}
+	return git.AppendTodoSection(m.commitMsg, m.todos)
+}
+
+// WithGuard returns a copy of the model that refuses to commit messages s flags.
+func (m Model) WithGuard(s *guard.Scanner) Model {
+	m.guard = s
+	return m
+}
+
+// WithEvents returns a copy of the model that publishes on bus when a
+// regenerated message is ready and when the commit is created.
+func (m Model) WithEvents(bus *events.Bus) Model {
+	m.bus = bus
+	return m
+}
+
+// WithPlugins returns a copy of the model that passes generated messages
+// through the post-generate plugins of r.
+func (m Model) WithPlugins(r *plugin.Runner) Model {
+	m.plugins = r
+	return m
+}
+
+// WithMaxWait returns a copy of the model that, once a stream has run for d,
+// offers the text received so far for acceptance, or switches to fallback (when
+// not nil) if nothing has arrived yet.
+func (m Model) WithMaxWait(d time.Duration, fallback ai.AIClient) Model {
+	m.maxWait = d
+	m.fallback = fallback
+	return m
+}
+
+// WithDraft returns a copy of the model that starts from the message of d instead
+// of generating one, offering to improve it.
+func (m Model) WithDraft(d git.Draft) Model {
+	m.commitMsg = d.Message
+	m.displayedMsg = d.Message
+	m.startStreaming = false
+	m.draftSource = d.Source
+	if m.commitType == "" {
+		m.commitType = committypes.GuessCommitType(d.Message)
+	}
+	m.notice = fmt.Sprintf("Loaded the %s draft. Press %s to improve it, or %s to write a new message.",
+		d.Source, keyMap.Improve.Help().Key, keyMap.Regenerate.Help().Key)
+	return m
+}
+
+// WithChoices returns a copy of the model that shows the first of several
+// alternative messages and switches between them with the arrow keys.
+// Regenerating asks for n new alternatives in one request.
+func (m Model) WithChoices(choices []string, n int) Model {
+	m.choiceCount = n
+	if len(choices) == 0 {
+		return m
+	}
+	m.choices = append([]string(nil), choices...)
+	m.choice = 0
+	m.commitMsg = choices[0]
+	m.displayedMsg = choices[0]
+	m.startStreaming = false
+	m.candidates = append(m.candidates, choices...)
+	return m
+}
+
+// switchChoice shows the alternative delta places away, wrapping around. Edits
+// to the message shown are kept with it.
+func (m Model) switchChoice(delta int) Model {
+	n := len(m.choices)
+	m.choices[m.choice] = m.commitMsg
+	m.choice = (m.choice + delta + n) % n
+	m.commitMsg = m.choices[m.choice]
+	m.displayedMsg = m.commitMsg
+	if guessed := committypes.GuessCommitType(m.commitMsg); guessed != "" {
+		m.commitType = guessed
+	}
+	m.errMsg = ""
+	m.guardHint = ""
+	// The style review was about the first message.
+	m.styleReview = ""
+	return m
+}
+
+// autosaveInterval is how often the message is autosaved (see WithAutosave).
+const autosaveInterval = 3 * time.Second
+
+// WithAutosave returns a copy of the model that periodically saves the message,
+// including an edit in progress, for the staged diff with hash diffHash, so it
+// can be restored after a crash or an accidental quit.
+func (m Model) WithAutosave(diffHash string) Model {
+	m.autosaveHash = diffHash
+	m.autosaved = m.commitMsg
+	return m
+}
+
+// WithInstantQuit returns a copy of the model that quits right away, even when
+// the message has unsaved manual edits.
+func (m Model) WithInstantQuit(instant bool) Model {
+	m.instantQuit = instant
+	return m
+}
+
+// WithSession returns a copy of the model restored from a saved session.
+func (m Model) WithSession(s *session.Session) Model {
+	m.sessionName = s.Name
+	m.diff = s.Diff
+	m.baseDiff = s.BaseDiff
+	m.rawDiff = s.RawDiff
+	m.overrides = s.Overrides
+	if m.overrides == nil {
+		m.overrides = git.HunkOverrides{}
+	}
+	m.userContext = s.UserContext
+	m.intent = s.Intent
+	m.candidates = append([]string(nil), s.Candidates...)
+	return m
+}
+
+// Snapshot captures the current state as a session named name.
+func (m Model) Snapshot(name string) session.Session {
+	return session.Session{
+		Name:        name,
+		Diff:        m.diff,
+		BaseDiff:    m.baseDiff,
+		RawDiff:     m.rawDiff,
+		Overrides:   m.overrides,
+		Prompt:      m.prompt,
+		Language:    m.language,
+		CommitType:  m.commitType,
+		UserContext: m.userContext,
+		Intent:      m.intent,
+		Message:     m.commitMsg,
+		Candidates:  m.candidates,
+	}
+}
+
 // NewProgram creates a new Bubble Tea program with the given model.
 func NewProgram(m Model) *tea.Program {
 	return tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
@@ -269,6 +702,9 @@ func (m Model) Init() tea.Cmd {
 		// kick off streaming immediately
 		cmds = append(cmds, startStreamCmd(m.aiClient, m.prompt))
 	}
+	if m.autosaveHash != "" && !m.tutorial {
+		cmds = append(cmds, autosaveTick())
+	}
 	// initialize progress bar animation frames
 	if initCmd := m.progress.Init(); initCmd != nil {
 		cmds = append(cmds, initCmd)
@@ -302,6 +738,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		m = m.advanceTutorial(msg)
+
+		if m.showHelp {
+			if key.Matches(msg, keyMap.Help, keyMap.Quit) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
 		// Handle editing states first to prevent key conflicts
 		if m.state == stateEditing || m.state == stateEditingPrompt {
 			var tcmd tea.Cmd
@@ -311,15 +756,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "ctrl+s":
 				if m.state == stateEditing {
-					m.commitMsg = m.textarea.Value()
+					if value := m.textarea.Value(); value != m.commitMsg {
+						m.commitMsg = value
+						m.edited = true
+					}
 					m.state = stateShowCommit
 				} else if m.state == stateEditingPrompt {
 					userPrompt := m.textarea.Value()
+					m.userContext = userPrompt
 					m.state = stateGenerating
 					m.spinner = spinner.New()
 					m.spinner.Spinner = spinner.Dot
 					m.regenCount++
-					m.prompt = prompt.BuildCommitPrompt(m.diff, m.language, m.commitType, userPrompt, m.promptTemplate, m.scopeHint)
+					m.prompt = m.commitPrompt(m.diff, userPrompt)
 					return m, regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern)
 				}
 			case "esc":
@@ -327,25 +776,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tcmd
 		}
+		if m.state == stateEditingScope {
+			switch msg.String() {
+			case "enter":
+				return m.applyScope(m.scopeInput.Value()), nil
+			case "esc":
+				m.state = stateShowCommit
+				return m, nil
+			}
+			var icmd tea.Cmd
+			m.scopeInput, icmd = m.scopeInput.Update(msg)
+			return m, icmd
+		}
+		if m.state == stateEditingIntent {
+			switch msg.String() {
+			case "enter":
+				return m.applyIntent(m.intentInput.Value())
+			case "esc":
+				m.state = stateShowCommit
+				return m, nil
+			}
+			var icmd tea.Cmd
+			m.intentInput, icmd = m.intentInput.Update(msg)
+			return m, icmd
+		}
+		if m.state == stateConfirmQuit {
+			switch msg.String() {
+			case "y", "enter", "ctrl+c":
+				if m.autosaveHash != "" && m.commitMsg != m.autosaved {
+					return m, tea.Sequence(autosaveCmd(m.autosaveHash, m.commitMsg), tea.Quit)
+				}
+				return m, tea.Quit
+			case "n", "esc":
+				m.state = stateShowCommit
+			}
+			return m, nil
+		}
 
 		// Handle global keys for non-editing states
 		if key.Matches(msg, keyMap.Quit) {
+			if m.state == stateShowDiff || m.state == stateShowFiltered || m.state == statePreview || m.state == stateReview {
+				m.state = stateShowCommit
+				return m, nil
+			}
+			if m.edited && !m.instantQuit && m.state != stateResult {
+				m.state = stateConfirmQuit
+				return m, nil
+			}
 			return m, tea.Quit
 		}
 		if key.Matches(msg, keyMap.Help) {
-			m.help.ShowAll = !m.help.ShowAll
+			m.showHelp = true
 			return m, nil
 		}
 
 		switch m.state {
 		case stateShowCommit:
+			if key.Matches(msg, keyMap.Focus) && m.splitPane() {
+				m.focusDiff = !m.focusDiff
+				return m, nil
+			}
+			if m.focusDiff && m.splitPane() {
+				switch msg.String() {
+				case "up", "k":
+					return m.scrollDiff(-1), nil
+				case "down", "j":
+					return m.scrollDiff(1), nil
+				case "pgup":
+					return m.scrollDiff(-m.diffPaneHeight()), nil
+				case "pgdown":
+					return m.scrollDiff(m.diffPaneHeight()), nil
+				}
+			}
+			if len(m.choices) > 1 && key.Matches(msg, candidateKeys) {
+				delta := 1
+				if msg.String() == "left" {
+					delta = -1
+				}
+				return m.switchChoice(delta), nil
+			}
+			if m.partial && key.Matches(msg, keyMap.Commit, keyMap.Enter, keyMap.Regenerate, keyMap.Improve, keyMap.Edit, keyMap.TypeSelect, keyMap.Scope, keyMap.Intent, keyMap.PromptEdit) {
+				// Acting on a partial message stops the stream and keeps what arrived.
+				m = m.abandonStream()
+				m.commitMsg = m.finalizeStreamed(m.streamRaw)
+				m.notice = ""
+			}
 			if key.Matches(msg, keyMap.Commit, keyMap.Enter) {
+				if findings := m.guard.Scan(m.finalMessage()); len(findings) > 0 {
+					m.errMsg = "Commit blocked:\n" + guard.Explain(findings) + "\nPress r to regenerate without them, or e to edit."
+					m.guardHint = guard.RegenerationHint(findings)
+					return m, nil
+				}
 				m.state = stateCommitting
 				m.errMsg = ""
 				// Ensure spinner animates while committing
 				m.spinner = spinner.New()
 				m.spinner.Spinner = spinner.Dot
-				return m, tea.Batch(m.spinner.Tick, commitCmd(m.commitMsg))
+				if m.tutorial {
+					return m, tea.Batch(m.spinner.Tick, func() tea.Msg { return commitResultMsg{} })
+				}
+				return m, tea.Batch(m.spinner.Tick, commitCmd(m.finalMessage(), m.commitOpts, m.bus))
 			}
 			if key.Matches(msg, keyMap.Regenerate) {
 				if m.regenCount >= m.maxRegens {
@@ -358,14 +888,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.spinner.Spinner = spinner.Dot
 				m.regenCount++
 				m.errMsg = ""
+				regenPrompt := m.prompt + m.guardHint
+				m.guardHint = ""
+				if m.choiceCount > 1 {
+					return m, tea.Batch(m.spinner.Tick,
+						candidatesCmd(m.aiClient, regenPrompt, m.choiceCount, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+				}
 				return m, tea.Batch(m.spinner.Tick,
-					regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+					regenCmd(m.aiClient, regenPrompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+			}
+			if key.Matches(msg, keyMap.Improve) && m.draftSource != "" && strings.TrimSpace(m.commitMsg) != "" {
+				if m.regenCount >= m.maxRegens {
+					m.errMsg = fmt.Sprintf("Maximum regenerations (%d) reached.", m.maxRegens)
+					return m, nil
+				}
+				m.state = stateGenerating
+				m.spinner = spinner.New()
+				m.spinner.Spinner = spinner.Dot
+				m.regenCount++
+				m.errMsg = ""
+				m.notice = ""
+				improvePrompt := m.prompt + prompt.DraftHint(m.commitMsg) + m.guardHint
+				m.guardHint = ""
+				return m, tea.Batch(m.spinner.Tick,
+					regenCmd(m.aiClient, improvePrompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+			}
+			if key.Matches(msg, keyMap.FixStyle) && prompt.HasStyleIssues(m.styleReview) && !m.partial {
+				if m.regenCount >= m.maxRegens {
+					m.errMsg = fmt.Sprintf("Maximum regenerations (%d) reached.", m.maxRegens)
+					return m, nil
+				}
+				m.state = stateGenerating
+				m.spinner = spinner.New()
+				m.spinner.Spinner = spinner.Dot
+				m.regenCount++
+				m.errMsg = ""
+				fixPrompt := m.prompt + prompt.StyleFixHint(m.commitMsg, m.styleReview) + m.guardHint
+				m.guardHint = ""
+				// The suggestions are about the message being replaced.
+				m.styleReview = ""
+				return m, tea.Batch(m.spinner.Tick,
+					regenCmd(m.aiClient, fixPrompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
 			}
 			if key.Matches(msg, keyMap.TypeSelect) {
 				m.state = stateSelectType
 				m.errMsg = ""
 				return m, nil
 			}
+			if key.Matches(msg, keyMap.Scope) {
+				m.state = stateEditingScope
+				m.errMsg = ""
+				scope := git.MessageScope(m.commitMsg)
+				if scope == "" {
+					scope = m.scopeHint
+				}
+				m.scopeInput.SetValue(scope)
+				m.scopeInput.CursorEnd()
+				return m, m.scopeInput.Focus()
+			}
+			if key.Matches(msg, keyMap.Intent) {
+				m.state = stateEditingIntent
+				m.errMsg = ""
+				m.intentInput.SetValue(m.intent)
+				m.intentInput.CursorEnd()
+				return m, m.intentInput.Focus()
+			}
 			if key.Matches(msg, keyMap.Edit) {
 				m.state = stateEditing
 				m.errMsg = ""
@@ -385,6 +972,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, viewDiffCmd(m.diff)
 			}
+			if key.Matches(msg, keyMap.Filtered) {
+				m.state = stateShowFiltered
+				m.errMsg = ""
+				return m, nil
+			}
+			if key.Matches(msg, keyMap.SaveSession) {
+				if m.tutorial {
+					m.notice = "Sessions are not saved in the tutorial."
+					return m, nil
+				}
+				if m.sessionName == "" {
+					m.sessionName = session.DefaultName(time.Now())
+				}
+				m.errMsg = ""
+				return m, saveSessionCmd(m.Snapshot(m.sessionName))
+			}
+			if key.Matches(msg, keyMap.Quality) {
+				m.showQuality = !m.showQuality
+				return m, nil
+			}
+			if key.Matches(msg, keyMap.Todos) && len(m.todos) > 0 {
+				m.todoBody = !m.todoBody
+				return m, nil
+			}
+			if key.Matches(msg, keyMap.Review) && !m.partial && strings.TrimSpace(m.diff) != "" {
+				m.state = stateReview
+				m.errMsg = ""
+				if m.reviewing || (m.review != "" && m.reviewDiff == m.diff) {
+					return m, nil
+				}
+				return m.startReview()
+			}
+			if key.Matches(msg, keyMap.Preview) && m.prompt != "" {
+				m.state = statePreview
+				m.previewCursor = 0
+				m.errMsg = ""
+				return m, nil
+			}
 
 		case stateSelectType:
 			switch msg.String() {
@@ -403,7 +1028,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.spinner.Spinner = spinner.Dot
 				m.regenCount++
 				// Rebuild the prompt with the newly selected commit type
-				m.prompt = prompt.BuildCommitPrompt(m.diff, m.language, m.commitType, "", m.promptTemplate, m.scopeHint)
+				m.prompt = m.commitPrompt(m.diff, m.userContext)
 				return m, tea.Batch(m.spinner.Tick,
 					regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
 			case "esc", "q":
@@ -416,36 +1041,145 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = stateShowCommit
 				return m, nil
 			}
+			chunks := m.rawChunks()
+			if len(chunks) == 0 {
+				return m, nil
+			}
+			switch {
+			case msg.String() == "up" || msg.String() == "k":
+				if m.hunkCursor > 0 {
+					m.hunkCursor--
+				}
+			case msg.String() == "down" || msg.String() == "j":
+				if m.hunkCursor < len(chunks)-1 {
+					m.hunkCursor++
+				}
+			case key.Matches(msg, keyMap.ExcludeHunk, keyMap.ExcludeFile, keyMap.IncludeHunk, keyMap.IncludeFile):
+				c := chunks[min(m.hunkCursor, len(chunks)-1)]
+				target := git.HunkKey(c)
+				if key.Matches(msg, keyMap.ExcludeFile, keyMap.IncludeFile) {
+					target = git.FileKey(c.FilePath)
+				}
+				want := git.HunkExclude
+				if key.Matches(msg, keyMap.IncludeHunk, keyMap.IncludeFile) {
+					want = git.HunkInclude
+				}
+				if m.overrides[target] == want {
+					delete(m.overrides, target)
+				} else {
+					m.overrides[target] = want
+				}
+			case key.Matches(msg, keyMap.Regenerate):
+				return m.regenerateWithOverrides()
+			}
+			return m, nil
+
+		case statePreview:
+			sections := m.previewSections()
+			switch {
+			case msg.String() == "up" || msg.String() == "k":
+				if m.previewCursor > 0 {
+					m.previewCursor--
+				}
+			case msg.String() == "down" || msg.String() == "j":
+				if m.previewCursor < len(sections)-1 {
+					m.previewCursor++
+				}
+			case key.Matches(msg, keyMap.ExcludeHunk, keyMap.ExcludeFile) && m.rawDiff != "":
+				// Trimming a diff section excludes the whole file, as in the diff view.
+				name := sections[min(m.previewCursor, len(sections)-1)].Name
+				if path, ok := strings.CutPrefix(name, "diff: "); ok {
+					target := git.FileKey(path)
+					if m.overrides[target] == git.HunkExclude {
+						delete(m.overrides, target)
+					} else {
+						m.overrides[target] = git.HunkExclude
+					}
+				}
+			case key.Matches(msg, keyMap.Regenerate):
+				return m.regenerateWithOverrides()
+			}
+			return m, nil
+
+		case stateReview:
+			switch {
+			case msg.String() == "up" || msg.String() == "k":
+				return m.scrollReview(-1), nil
+			case msg.String() == "down" || msg.String() == "j":
+				return m.scrollReview(1), nil
+			case msg.String() == "pgup":
+				return m.scrollReview(-m.reviewPaneHeight()), nil
+			case msg.String() == "pgdown":
+				return m.scrollReview(m.reviewPaneHeight()), nil
+			case key.Matches(msg, keyMap.Regenerate) && !m.reviewing:
+				return m.startReview()
+			}
+			return m, nil
+
+		case stateShowFiltered:
+			if key.Matches(msg, keyMap.Unfilter) && !m.filterReport.Empty() {
+				if m.regenCount >= m.maxRegens {
+					m.errMsg = fmt.Sprintf("Maximum regenerations (%d) reached.", m.maxRegens)
+					m.state = stateShowCommit
+					return m, nil
+				}
+				m.state = stateGenerating
+				m.spinner = spinner.New()
+				m.spinner.Spinner = spinner.Dot
+				m.errMsg = ""
+				if m.tutorial {
+					raw := m.rawDiff
+					return m, tea.Batch(m.spinner.Tick, func() tea.Msg { return unfilteredMsg{diff: raw} })
+				}
+				return m, tea.Batch(m.spinner.Tick, unfilteredDiffCmd())
+			}
 		}
 
 	case regenMsg:
 		log.Debug().Msgf("regenMsg received with commit message: %q", msg.msg)
 		if msg.err != nil {
-			m.errMsg = fmt.Sprintf("AI error: %v", msg.err)
+			m.errMsg = errorText("AI error", msg.err)
 			m.state = stateShowCommit
 			return m, nil
 		}
-		m.commitMsg = msg.msg
-		if m.commitType == "" {
-			if guessed := committypes.GuessCommitType(m.commitMsg); guessed != "" {
-				m.commitType = guessed
-			}
+		if !msg.postGenerated && m.plugins.Has(plugin.PostGenerate) {
+			return m, postGenerateCmd(m.plugins, m.diff, []string{msg.msg}, func(msgs []string) tea.Msg {
+				return regenMsg{msg: msgs[0], postGenerated: true}
+			})
 		}
-		// Animate reveal for non-streaming providers
-		m.revealActive = true
-		m.displayedMsg = ""
-		m.state = stateGenerating
-		m.spinner = spinner.New()
-		m.spinner.Spinner = spinner.Dot
-		return m, m.spinner.Tick
+		m.choices = nil
+		m.candidates = append(m.candidates, msg.msg)
+		return m.revealMessage(msg.msg, cmds)
+
+	case candidatesMsg:
+		if msg.err != nil {
+			m.errMsg = errorText("AI error", msg.err)
+			m.state = stateShowCommit
+			return m, nil
+		}
+		if !msg.postGenerated && m.plugins.Has(plugin.PostGenerate) {
+			return m, postGenerateCmd(m.plugins, m.diff, msg.msgs, func(msgs []string) tea.Msg {
+				return candidatesMsg{msgs: msgs, postGenerated: true}
+			})
+		}
+		m.choices, m.choice = msg.msgs, 0
+		m.candidates = append(m.candidates, msg.msgs...)
+		return m.revealMessage(msg.msgs[0], cmds)
+
+	case pluginErrMsg:
+		m.errMsg = errorText("Plugin", msg.err)
+		m.state = stateShowCommit
+		return m, nil
 
 	case commitResultMsg:
 		if msg.err != nil {
 			m.errMsg = fmt.Sprintf("Commit failed: %v", msg.err)
 			m.state = stateShowCommit
 			return m, nil
+		} else if m.tutorial {
+			m.result = "Tutorial finished; nothing was committed."
 		} else {
-			m.result = "Commit created successfully!"
+			m.result = "Commit created successfully!" + todoList(m.todos)
 		}
 		m.state = stateResult
 		return m, autoQuitCmd()
@@ -453,10 +1187,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case autoQuitMsg:
 		return m, tea.Quit
 
+	case reviewMsg:
+		m.reviewing = false
+		if msg.err != nil {
+			m.review = ""
+			m.errMsg = errorText("Code review failed", msg.err)
+			if m.state == stateReview {
+				m.state = stateShowCommit
+			}
+			return m, nil
+		}
+		m.review, m.reviewDiff, m.reviewScroll = strings.TrimSpace(msg.review), msg.diff, 0
+		m.reviewSource = msg.source
+		return m, nil
+
+	case autosaveMsg:
+		text := m.commitMsg
+		if m.state == stateEditing {
+			text = m.textarea.Value()
+		}
+		// Nothing is saved mid-generation, and a committed message is done.
+		busy := m.state == stateGenerating || m.state == stateCommitting || m.state == stateResult || m.partial
+		if busy || strings.TrimSpace(text) == "" || text == m.autosaved {
+			return m, autosaveTick()
+		}
+		m.autosaved = text
+		return m, tea.Batch(autosaveCmd(m.autosaveHash, text), autosaveTick())
+
+	case sessionSavedMsg:
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Saving session failed: %v", msg.err)
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Session %q saved to %s (resume with: ai-commit session load %s)", m.sessionName, msg.path, m.sessionName)
+		return m, nil
+
 	case viewDiffMsg:
 		m.state = stateShowDiff
 		return m, nil
 
+	case unfilteredMsg:
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Could not read unfiltered diff: %v", msg.err)
+			m.state = stateShowCommit
+			return m, nil
+		}
+		m.diff = msg.diff
+		m.baseDiff = msg.diff
+		m.filterReport = &git.FilterReport{}
+		m.regenCount++
+		m.prompt = m.commitPrompt(m.diff, m.userContext)
+		return m, regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern)
+
 	case streamStartedMsg:
 		// IMPORTANT: start spinner ticks so we get spinner.TickMsg,
 		// which we use as the heartbeat to advance the progress bar.
@@ -465,40 +1247,89 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner.Spinner = spinner.Dot
 		m.streamDeltaCh = msg.deltaCh
 		m.streamDoneCh = msg.doneCh
+		m.streamCancel = msg.cancel
+		m.streamRaw, m.commitMsg = "", ""
 		m.errMsg = ""
-		return m, tea.Batch(
+		m.choices = nil
+		cmds = append(cmds,
 			m.spinner.Tick,                  // <— start ticks here (fix)
 			readDeltaCmd(m.streamDeltaCh),
 			waitDoneCmd(m.streamDoneCh),
 		)
+		if m.maxWait > 0 {
+			done := msg.doneCh
+			cmds = append(cmds, tea.Tick(m.maxWait, func(time.Time) tea.Msg { return maxWaitMsg{stream: done} }))
+		}
+		return m, tea.Batch(cmds...)
 
 	case streamDeltaMsg:
-		m.commitMsg += msg.delta
+		if msg.from != m.streamDeltaCh {
+			return m, nil
+		}
+		m.streamRaw += msg.delta
+		m.commitMsg = m.previewStreamed(m.streamRaw)
 		// keep waiting for more deltas
 		return m, readDeltaCmd(m.streamDeltaCh)
 
+	case maxWaitMsg:
+		if m.streamDoneCh == nil || msg.stream != m.streamDoneCh {
+			return m, nil // finished or abandoned in time
+		}
+		provider := m.aiClient.ProviderName()
+		if strings.TrimSpace(m.streamRaw) != "" {
+			m.partial = true
+			m.revealActive = false
+			m.state = stateShowCommit
+			m.notice = fmt.Sprintf("%s is still writing after %s. Press %s to accept the partial message as is, or wait for the rest.",
+				provider, m.maxWait, keyMap.Commit.Help().Key)
+			return m, nil
+		}
+		if m.fallback != nil {
+			m = m.abandonStream()
+			m.aiClient, m.fallback = m.fallback, nil
+			m.notice = fmt.Sprintf("%s sent nothing within %s; switched to %s.", provider, m.maxWait, m.aiClient.ProviderName())
+			return m, regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern)
+		}
+		m.notice = fmt.Sprintf("%s has not answered after %s; still waiting (no fallback provider configured).", provider, m.maxWait)
+		return m, nil
+
 	case streamDoneMsg:
-		// finalize message: sanitize, prepend type, apply template
-		final := m.commitMsg
-		final = m.aiClient.SanitizeResponse(final, m.commitType)
-		if m.commitType != "" {
-			final = git.PrependCommitType(final, m.commitType, m.enableEmoji)
+		if msg.from != m.streamDoneCh {
+			return m, nil
 		}
-		if m.template != "" {
-			if res, err := template.ApplyTemplate(m.template, final, m.ticketPattern); err == nil {
-				final = res
-			}
+		if m.partial {
+			m.notice = ""
+		}
+		m = m.abandonStream()
+		m.commitMsg = m.finalizeStreamed(m.streamRaw)
+		m.edited = false
+		if err := ai.ValidateCommitMessage(m.commitMsg); m.commitMsg != "" && err != nil {
+			m.errMsg = fmt.Sprintf("Rejected AI output: %v (press r to regenerate)", err)
+			m.commitMsg = ""
+		} else if warning := ai.MessageWarning(m.commitMsg); warning != "" {
+			m.notice = "Check the message: " + warning
+		}
+		if m.commitMsg != "" && msg.err == nil && m.plugins.Has(plugin.PostGenerate) {
+			// The plugins' version is shown once they are done.
+			m.state = stateGenerating
+			return m, tea.Batch(append(cmds, m.spinner.Tick,
+				postGenerateCmd(m.plugins, m.diff, []string{m.commitMsg}, func(msgs []string) tea.Msg {
+					return regenMsg{msg: msgs[0], postGenerated: true}
+				}))...)
+		}
+		if m.commitMsg != "" {
+			m.candidates = append(m.candidates, m.commitMsg)
+			cmds = append(cmds, m.readyCmd())
 		}
-		m.commitMsg = strings.TrimSpace(final)
 		if msg.err != nil {
-			m.errMsg = fmt.Sprintf("AI streaming error: %v", msg.err)
+			m.errMsg = errorText("AI streaming error", msg.err)
 		}
 		m.state = stateShowCommit
-		return m, nil
+		return m, tea.Batch(cmds...)
 
 	case spinner.TickMsg:
 		// Keep spinner and animations going while in generating or committing
-		if m.state == stateGenerating || m.state == stateCommitting {
+		if m.state == stateGenerating || m.state == stateCommitting || m.reviewing {
 			m.spinner, cmd = m.spinner.Update(msg)
 			cmds = append(cmds, cmd)
 			// Indefinite progress and typing indicator heartbeat
@@ -534,6 +1365,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // --- VIEWS -------------------------------------------------------------------
 
 func (m Model) View() string {
+	var views []string
+	if m.tutorial {
+		views = append(views, m.viewTutorial())
+	}
+	if m.showHelp {
+		views = append(views, m.viewHelpOverlay())
+	} else {
+		views = append(views, m.viewState())
+	}
+	if bar := m.viewStatusBar(); bar != "" {
+		views = append(views, bar)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
+}
+
+func (m Model) viewState() string {
 	switch m.state {
 	case stateShowCommit:
 		return m.viewShowCommit()
@@ -549,15 +1396,28 @@ func (m Model) View() string {
 		return m.viewEditing("Editing commit message (Ctrl+S to save, ESC to cancel):")
 	case stateEditingPrompt:
 		return m.viewEditing("Editing prompt text (Ctrl+S to apply, ESC to cancel):")
+	case stateEditingScope:
+		return m.viewEditingScope()
+	case stateEditingIntent:
+		return m.viewEditingIntent()
+	case stateConfirmQuit:
+		return m.viewConfirmQuit()
+	case stateReview:
+		return m.viewReview()
 	case stateShowDiff:
 		return m.viewDiff()
+	case stateShowFiltered:
+		return m.viewFiltered()
+	case statePreview:
+		return m.viewPreview()
 	default:
 		return "Unknown state."
 	}
 }
 
 // viewShowCommit has been updated to present the info line in smaller text
-// above the main commit message box, in a single vertical layout.
+// above the main commit message box, in a single vertical layout. Wide terminals
+// get the diff in a pane to the left of the message.
 func (m Model) viewShowCommit() string {
 	// 1) The TUI Banner
 	header := logoStyle.Render(logoText)
@@ -565,7 +1425,14 @@ func (m Model) viewShowCommit() string {
 	// 2) A subtle info line
 	infoText := fmt.Sprintf("Type: %s | Regens Left: %d/%d | Language: %s",
 		m.commitType, (m.maxRegens - m.regenCount), m.maxRegens, m.language)
-	infoLine := infoLineStyle.Render(infoText)
+	if len(m.choices) > 1 {
+		infoText += fmt.Sprintf(" | Candidate: %d/%d", m.choice+1, len(m.choices))
+	}
+	quality := lint.Score(m.commitMsg, m.diff, lint.Options{EnableEmoji: m.enableEmoji})
+	infoLine := lipgloss.JoinHorizontal(lipgloss.Top, infoLineStyle.Render(infoText), qualityBadge(quality))
+	if m.notice != "" {
+		infoLine += "\n" + infoLineStyle.Render(m.notice)
+	}
 
 	// 3) Optional error box
 	errSection := ""
@@ -576,24 +1443,47 @@ func (m Model) viewShowCommit() string {
 
 	// 4) The commit box - adjust width based on terminal size
 	boxWidth := min(m.width-4, 100) // Leave some margin, max 100 chars
+	if m.splitPane() {
+		boxWidth = m.messagePaneWidth()
+	}
 	commitBoxStyleAdaptive := commitBoxStyle.Width(boxWidth)
-	content := commitBoxStyleAdaptive.Render(m.commitMsg)
+	if m.splitPane() && !m.focusDiff {
+		commitBoxStyleAdaptive = commitBoxStyleAdaptive.BorderForeground(lipgloss.Color("212"))
+	}
+	content := commitBoxStyleAdaptive.Render(m.finalMessage())
+	if m.partial {
+		content = highlightStyle.Render("  [PARTIAL — still streaming]") + "\n" + content
+	}
 
-	// 5) If styleReview is not trivial or "no issues found", show it
+	// 5) If the style review found issues, show them
 	styleReviewSection := ""
-	if trimmed := strings.TrimSpace(m.styleReview); trimmed != "" &&
-		!strings.Contains(strings.ToLower(trimmed), "no issues found") {
-		boxWidth := min(m.width-4, 100) // Same width as commit box
+	if prompt.HasStyleIssues(m.styleReview) {
 		styleReviewSection = lipgloss.NewStyle().
 			BorderStyle(lipgloss.NormalBorder()).
 			BorderForeground(lipgloss.Color("204")).
 			Padding(1, 2).
 			Margin(1, 1).
 			Width(boxWidth).
-			Render("Style Review Suggestions:\n\n" + trimmed)
+			Render(fmt.Sprintf("Style Review Suggestions:\n\n%s\n\nPress %s to regenerate the message with them.",
+				strings.TrimSpace(m.styleReview), keyMap.FixStyle.Help().Key))
 	}
 
-	// 6) The help view
+	// 6) The quality breakdown, when toggled
+	if m.showQuality {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, viewQuality(quality, boxWidth))
+	}
+	if len(m.todos) > 0 {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.viewTodos(boxWidth))
+	}
+
+	// Two-pane layout: diff on the left, message and style review on the right
+	if m.splitPane() {
+		right := lipgloss.JoinVertical(lipgloss.Left, content, styleReviewSection)
+		content = lipgloss.JoinHorizontal(lipgloss.Top, m.viewDiffPane(), right)
+		styleReviewSection = ""
+	}
+
+	// 7) The help view
 	helpView := m.help.View(m)
 
 	// Merge everything in one vertical column
@@ -681,65 +1571,315 @@ func (m Model) viewEditing(title string) string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, body, helpView)
 }
 
+func (m Model) viewEditingScope() string {
+	header := logoStyle.Render(logoText)
+	subject, _, _ := strings.Cut(m.commitMsg, "\n")
+	body := lipgloss.NewStyle().Margin(1, 2).Render(fmt.Sprintf(
+		"Scope for %s\n\n%s\n\nEnter to apply (empty removes the scope), ESC to cancel.",
+		highlightStyle.Render(subject), m.scopeInput.View()))
+	helpView := m.help.View(m)
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, helpView)
+}
+
+func (m Model) viewEditingIntent() string {
+	header := logoStyle.Render(logoText)
+	body := lipgloss.NewStyle().Margin(1, 2).Render(fmt.Sprintf(
+		"Why is this change made? The intent leads the prompt, so the message explains it.\n\n%s\n\nEnter to regenerate with it (empty removes it), ESC to cancel.",
+		m.intentInput.View()))
+	helpView := m.help.View(m)
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, helpView)
+}
+
+func (m Model) viewConfirmQuit() string {
+	header := logoStyle.Render(logoText)
+	question := "Quit and discard them?"
+	if m.autosaveHash != "" {
+		question = "Quit? They are autosaved and offered again on the next run with the same staged changes."
+	}
+	body := errorBoxStyle.Width(min(m.width-4, 100)).Render(
+		"The message has manual edits.\n\n" + question + "\ny/Enter to quit, n/ESC to go back.")
+	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+}
+
 func (m Model) viewDiff() string {
 	header := logoStyle.Render(logoText)
+	if chunks := m.rawChunks(); len(chunks) > 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, m.viewHunks(chunks), m.help.View(m))
+	}
 	diffTextView := diffStyle.Render(m.diff)
 	body := lipgloss.NewStyle().Margin(1, 2).Render(
-		fmt.Sprintf("Git Diff:\n\n%s\n\nPress ESC/q to return.", diffTextView),
+		fmt.Sprintf("Git Diff:\n\n%s\n\nPress ESC/%s to return.", diffTextView, keyMap.Quit.Help().Key),
+	)
+	helpView := m.help.View(m)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, helpView)
+}
+
+// commitPrompt builds the commit prompt for diff with the model's settings,
+// the context files and userContext as additional text, and the author's intent.
+func (m Model) commitPrompt(diff, userContext string) string {
+	return prompt.WithIntent(prompt.BuildCommitPrompt(diff, m.language, m.commitType, m.additionalText(userContext), m.promptTemplate, m.scopeHint), m.intent)
+}
+
+// additionalText joins the context files and userContext for the prompt.
+func (m Model) additionalText(userContext string) string {
+	if m.contextFiles == "" || userContext == "" {
+		return m.contextFiles + userContext
+	}
+	return m.contextFiles + "\n\n" + userContext
+}
+
+// pendingPrompt returns the diff and prompt the next regeneration will send,
+// taking per-session overrides into account.
+func (m Model) pendingPrompt() (string, string) {
+	if len(m.overrides) == 0 {
+		return m.diff, m.prompt
+	}
+	diff := git.ApplyHunkOverrides(m.baseDiff, m.rawDiff, m.overrides)
+	return diff, m.commitPrompt(diff, m.userContext)
+}
+
+// previewSections breaks the pending prompt down by section for the preview screen.
+func (m Model) previewSections() []prompt.Section {
+	diff, promptText := m.pendingPrompt()
+	return prompt.Breakdown(promptText, diff, m.additionalText(m.userContext))
+}
+
+func (m Model) viewPreview() string {
+	header := logoStyle.Render(logoText)
+	_, promptText := m.pendingPrompt()
+	sections := m.previewSections()
+	totalChars := 0
+	for _, sec := range sections {
+		totalChars += sec.Chars
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Prompt preview: %d chars, ~%d tokens\n\n", totalChars, prompt.EstimateTokens(promptText)))
+	for i, sec := range sections {
+		pointer := " "
+		if i == m.previewCursor {
+			pointer = highlightStyle.Render(">")
+		}
+		share := 0
+		if totalChars > 0 {
+			share = sec.Chars * 100 / totalChars
+		}
+		b.WriteString(fmt.Sprintf("%s %-40s %7d chars %6d tokens %3d%%\n", pointer, sec.Name, sec.Chars, sec.Tokens, share))
+	}
+
+	lines := strings.Split(promptText, "\n")
+	const maxLines = 25
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines:maxLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxLines))
+	}
+	b.WriteString("\n" + diffStyle.Render(strings.Join(lines, "\n")))
+	b.WriteString(fmt.Sprintf("\n\nUse up/down (or j/k) to move, %s to trim a diff section, %s to regenerate with this prompt, ESC/%s to return.",
+		keyMap.ExcludeHunk.Help().Key, keyMap.Regenerate.Help().Key, keyMap.Quit.Help().Key))
+
+	body := lipgloss.NewStyle().Margin(1, 2).Render(b.String())
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, m.help.View(m))
+}
+
+// viewHunks lists the hunks of the raw diff with their prompt status and shows the selected one.
+func (m Model) viewHunks(chunks []git.DiffChunk) string {
+	inPrompt := make(map[string]bool)
+	if base, err := git.ParseDiffToChunks(m.baseDiff); err == nil {
+		for _, c := range base {
+			inPrompt[git.HunkKey(c)] = true
+		}
+	}
+	cursor := min(m.hunkCursor, len(chunks)-1)
+
+	var b strings.Builder
+	b.WriteString("Hunks ([ ] sent, [-] filtered, [x] excluded, [+] force-included):\n\n")
+	const window = 12
+	start := cursor - window/2
+	if start < 0 {
+		start = 0
+	}
+	end := min(start+window, len(chunks))
+	for i := start; i < end; i++ {
+		c := chunks[i]
+		mark := "[-]"
+		switch m.overrides.Resolve(c) {
+		case git.HunkExclude:
+			mark = "[x]"
+		case git.HunkInclude:
+			mark = "[+]"
+		default:
+			if inPrompt[git.HunkKey(c)] {
+				mark = "[ ]"
+			}
+		}
+		pointer := " "
+		if i == cursor {
+			pointer = highlightStyle.Render(">")
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s %s\n", pointer, mark, c.FilePath, c.HunkHeader))
+	}
+
+	selected := chunks[cursor].Lines
+	if len(selected) > 20 {
+		selected = append(selected[:20:20], "...")
+	}
+	b.WriteString("\n" + diffStyle.Render(strings.Join(selected, "\n")))
+	b.WriteString(fmt.Sprintf("\n\nUse up/down (or j/k) to move, %s/%s to exclude hunk/file, %s/%s to force-include, %s to regenerate, ESC/%s to return.",
+		keyMap.ExcludeHunk.Help().Key, keyMap.ExcludeFile.Help().Key, keyMap.IncludeHunk.Help().Key, keyMap.IncludeFile.Help().Key,
+		keyMap.Regenerate.Help().Key, keyMap.Quit.Help().Key))
+	return lipgloss.NewStyle().Margin(1, 2).Render(b.String())
+}
+
+// rawChunks returns the hunks of the unfiltered diff, or nil when it is unavailable.
+func (m Model) rawChunks() []git.DiffChunk {
+	if m.rawDiff == "" {
+		return nil
+	}
+	chunks, err := git.ParseDiffToChunks(m.rawDiff)
+	if err != nil {
+		return nil
+	}
+	return chunks
+}
+
+func (m Model) viewFiltered() string {
+	header := logoStyle.Render(logoText)
+	footer := fmt.Sprintf("Press ESC/%s to return.", keyMap.Quit.Help().Key)
+	if !m.filterReport.Empty() {
+		footer = fmt.Sprintf("Press '%s' to regenerate from the unfiltered diff, ESC/%s to return.", keyMap.Unfilter.Help().Key, keyMap.Quit.Help().Key)
+	}
+	body := lipgloss.NewStyle().Margin(1, 2).Render(
+		fmt.Sprintf("Filtered from the prompt:\n\n%s\n\n%s", diffStyle.Render(m.filterReport.String()), footer),
 	)
 	helpView := m.help.View(m)
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, body, helpView)
 }
 
+// revealMessage shows msg, a new message from a non-streaming request, with the
+// reveal animation.
+func (m Model) revealMessage(msg string, cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	m.commitMsg = msg
+	m.edited = false
+	if warning := ai.MessageWarning(msg); warning != "" {
+		m.notice = "Check the message: " + warning
+	}
+	cmds = append(cmds, m.readyCmd())
+	if m.commitType == "" {
+		if guessed := committypes.GuessCommitType(m.commitMsg); guessed != "" {
+			m.commitType = guessed
+		}
+	}
+	// Animate reveal for non-streaming providers
+	m.revealActive = true
+	m.displayedMsg = ""
+	m.state = stateGenerating
+	m.spinner = spinner.New()
+	m.spinner.Spinner = spinner.Dot
+	return m, tea.Batch(append(cmds, m.spinner.Tick)...)
+}
+
+// regenerateWithOverrides rebuilds the prompt from the diff overrides and asks the AI again.
+func (m Model) regenerateWithOverrides() (tea.Model, tea.Cmd) {
+	if m.regenCount >= m.maxRegens {
+		m.errMsg = fmt.Sprintf("Maximum regenerations (%d) reached.", m.maxRegens)
+		m.state = stateShowCommit
+		return m, nil
+	}
+	diff, promptText := m.pendingPrompt()
+	if strings.TrimSpace(diff) == "" {
+		m.errMsg = "Every hunk is excluded; include at least one before regenerating."
+		m.state = stateShowCommit
+		return m, nil
+	}
+	m.diff, m.prompt = diff, promptText
+	m.state = stateGenerating
+	m.spinner = spinner.New()
+	m.spinner.Spinner = spinner.Dot
+	m.regenCount++
+	m.errMsg = ""
+	return m, tea.Batch(m.spinner.Tick,
+		regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+}
+
 // --- COMMANDS ----------------------------------------------------------------
 
 // commitCmd executes "git commit" with a timeout and returns the result as a msg.
-func commitCmd(commitMsg string) tea.Cmd {
+func commitCmd(commitMsg string, opts git.CommitOptions, bus *events.Bus) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
-		err := git.CommitChanges(ctx, commitMsg)
+		err := git.CommitChangesWithOptions(ctx, commitMsg, opts)
+		// A failed commit keeps the message as a draft for the next run.
+		if err != nil {
+			if saveErr := git.SaveDraft(ctx, commitMsg); saveErr != nil {
+				log.Debug().Err(saveErr).Msg("Cannot save the draft")
+			}
+		} else {
+			if clearErr := git.ClearDraft(ctx); clearErr != nil {
+				log.Debug().Err(clearErr).Msg("Cannot remove the saved draft")
+			}
+			hash, _ := git.GetHeadCommitHash(ctx)
+			bus.Publish(events.CommitCreated{Hash: hash, Message: commitMsg, Interactive: true})
+		}
 		return commitResultMsg{err: err}
 	}
 }
 
+func autosaveTick() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg { return autosaveMsg{} })
+}
+
+// autosaveCmd writes text as the autosaved message for the staged diff diffHash.
+func autosaveCmd(diffHash, text string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := git.Autosave(ctx, diffHash, text); err != nil {
+			log.Debug().Err(err).Msg("Cannot autosave the message")
+		}
+		return nil
+	}
+}
+
 // regenCmd calls the AI client to (re)generate a commit message.
 // If the client supports streaming, it wires channels and returns streamStartedMsg.
 func regenCmd(client ai.AIClient, prompt, commitType, tmpl string, enableEmoji bool, ticketPattern string) tea.Cmd {
 	return func() tea.Msg {
 		// Try streaming if available
 		if sc, ok := client.(ai.StreamingAIClient); ok {
-			deltaCh := make(chan string, 64)
-			doneCh := make(chan error, 1)
-			go func() {
-				_, err := sc.StreamCommitMessage(context.Background(), prompt, func(d string) {
-					deltaCh <- d
-				})
-				close(deltaCh)
-				doneCh <- err
-				close(doneCh)
-			}()
-			return streamStartedMsg{deltaCh: deltaCh, doneCh: doneCh}
+			return streamMessage(sc, prompt)
 		}
 		msg, err := regenerate(prompt, client, commitType, tmpl, enableEmoji, ticketPattern)
 		return regenMsg{msg: msg, err: err}
 	}
 }
 
+// candidatesCmd asks the AI for n alternative commit messages in one request.
+func candidatesCmd(client ai.AIClient, prompt string, n int, commitType, tmpl string, enableEmoji bool, ticketPattern string) tea.Cmd {
+	return func() tea.Msg {
+		msgs, err := regenerateCandidates(prompt, n, client, commitType, tmpl, enableEmoji, ticketPattern)
+		return candidatesMsg{msgs: msgs, err: err}
+	}
+}
+
+// postGenerateCmd passes msgs through the post-generate plugins of r and
+// wraps the result with done.
+func postGenerateCmd(r *plugin.Runner, diff string, msgs []string, done func([]string) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		out, err := r.PostGenerate(context.Background(), diff, msgs)
+		if err != nil {
+			return pluginErrMsg{err: err}
+		}
+		return done(out)
+	}
+}
+
 // startStreamCmd is used to fire the first streaming call on program start.
 func startStreamCmd(client ai.AIClient, prompt string) tea.Cmd {
 	return func() tea.Msg {
 		if sc, ok := client.(ai.StreamingAIClient); ok {
-			deltaCh := make(chan string, 64)
-			doneCh := make(chan error, 1)
-			go func() {
-				_, err := sc.StreamCommitMessage(context.Background(), prompt, func(d string) { deltaCh <- d })
-				close(deltaCh)
-				doneCh <- err
-				close(doneCh)
-			}()
-			return streamStartedMsg{deltaCh: deltaCh, doneCh: doneCh}
+			return streamMessage(sc, prompt)
 		}
 		// fallback
 		msg, err := regenerate(prompt, client, "", "", false, "")
@@ -747,6 +1887,135 @@ func startStreamCmd(client ai.AIClient, prompt string) tea.Cmd {
 	}
 }
 
+// streamMessage starts sc in the background and returns the channels the TUI
+// reads; the stream stops when the returned cancel func is called.
+func streamMessage(sc ai.StreamingAIClient, prompt string) streamStartedMsg {
+	ctx, cancel := context.WithCancel(context.Background())
+	deltaCh := make(chan string, 64)
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := sc.StreamCommitMessage(ctx, prompt, func(d string) {
+			select {
+			case deltaCh <- d:
+			case <-ctx.Done():
+			}
+		})
+		close(deltaCh)
+		doneCh <- err
+		close(doneCh)
+	}()
+	return streamStartedMsg{deltaCh: deltaCh, doneCh: doneCh, cancel: cancel}
+}
+
+// abandonStream stops the current stream; its remaining messages are ignored.
+func (m Model) abandonStream() Model {
+	if m.streamCancel != nil {
+		m.streamCancel()
+	}
+	m.streamCancel, m.streamDeltaCh, m.streamDoneCh = nil, nil, nil
+	m.partial = false
+	return m
+}
+
+// applyScope sets the scope of the message header, adding the selected type
+// first when the message has none, and steers later regenerations towards it.
+func (m Model) applyScope(scope string) Model {
+	m.state = stateShowCommit
+	msg := m.commitMsg
+	if m.commitType != "" && committypes.BuildRegexPatternWithEmoji().FindString(strings.TrimSpace(msg)) == "" {
+		msg = git.PrependCommitType(msg, m.commitType, m.enableEmoji)
+	}
+	scoped, err := git.SetScope(msg, scope)
+	if err != nil {
+		m.errMsg = fmt.Sprintf("Cannot set the scope: %v. Press t to pick a type, or e to edit the message.", err)
+		return m
+	}
+	m.edited = m.edited || scoped != m.commitMsg
+	m.commitMsg = scoped
+	m.scopeHint = strings.TrimSpace(scope)
+	if m.prompt != "" {
+		m.prompt = m.commitPrompt(m.diff, m.userContext)
+	}
+	return m
+}
+
+// applyIntent sets the author's intent and regenerates the message with it at
+// the top of the prompt.
+func (m Model) applyIntent(intent string) (tea.Model, tea.Cmd) {
+	m.state = stateShowCommit
+	m.intent = strings.TrimSpace(intent)
+	m.prompt = m.commitPrompt(m.diff, m.userContext)
+	if m.regenCount >= m.maxRegens {
+		m.errMsg = fmt.Sprintf("Maximum regenerations (%d) reached; the intent applies to the next session.", m.maxRegens)
+		return m, nil
+	}
+	m.state = stateGenerating
+	m.spinner = spinner.New()
+	m.spinner.Spinner = spinner.Dot
+	m.regenCount++
+	m.errMsg = ""
+	return m, tea.Batch(m.spinner.Tick,
+		regenCmd(m.aiClient, m.prompt, m.commitType, m.template, m.enableEmoji, m.ticketPattern))
+}
+
+// finalizeStreamed sanitizes streamed text, prepends the commit type, and applies the template.
+func (m Model) finalizeStreamed(text string) string {
+	// The model's type prefix is left to PrependCommitType, which keeps its scope.
+	final := m.aiClient.SanitizeResponse(text, "")
+	if m.commitType != "" {
+		final = git.PrependCommitType(final, m.commitType, m.enableEmoji)
+	}
+	if m.template != "" {
+		if res, err := template.ApplyTemplate(m.template, final, m.ticketPattern); err == nil {
+			final = res
+		}
+	}
+	return strings.TrimSpace(final)
+}
+
+// previewStreamed cleans up the text streamed so far the way finalizeStreamed
+// will, so the live preview does not jump when the stream ends. The template,
+// which may call git, is only applied to the final message.
+func (m Model) previewStreamed(raw string) string {
+	preview := m.aiClient.SanitizeResponse(m.stableStreamText(raw), "")
+	if preview == "" {
+		return ""
+	}
+	if m.commitType != "" {
+		preview = git.PrependCommitType(preview, m.commitType, m.enableEmoji)
+	}
+	return preview
+}
+
+// streamTypePrefix matches a first line that may still grow into an
+// "<emoji> type(scope): " prefix.
+var streamTypePrefix = regexp.MustCompile(`^(?:(?:\p{So}|\p{Sk}|:\w*:?)\s*)?([A-Za-z]*)(?:\([^)]*\)?)?!?$`)
+
+// stableStreamText returns the part of raw whose cleanup later deltas cannot
+// change: trailing backticks and an unfinished opening fence line are held back
+// and, with a commit type, so is a first line that may still become a type
+// prefix, which the cleanup replaces.
+func (m Model) stableStreamText(raw string) string {
+	text := strings.TrimRight(raw, "`")
+	if strings.HasPrefix(strings.TrimSpace(raw), "```") && !strings.Contains(raw, "\n") {
+		return "" // the opening fence line may still carry a language tag
+	}
+	if m.commitType == "" || strings.TrimRight(text, " \t\r\n") != text {
+		return text
+	}
+	cleaned := m.aiClient.SanitizeResponse(text, "")
+	match := streamTypePrefix.FindStringSubmatch(cleaned)
+	if match == nil || strings.Contains(cleaned, "\n") {
+		return text
+	}
+	for _, t := range strings.Split(committypes.TypesRegexPattern(), "|") {
+		if strings.HasPrefix(t, match[1]) {
+			return ""
+		}
+	}
+	return text
+}
+
 // readDeltaCmd reads a single delta from the channel (if available).
 func readDeltaCmd(ch <-chan string) tea.Cmd {
 	return func() tea.Msg {
@@ -754,7 +2023,7 @@ func readDeltaCmd(ch <-chan string) tea.Cmd {
 		if !ok {
 			return nil
 		}
-		return streamDeltaMsg{delta: d}
+		return streamDeltaMsg{delta: d, from: ch}
 	}
 }
 
@@ -763,9 +2032,9 @@ func waitDoneCmd(done <-chan error) tea.Cmd {
 	return func() tea.Msg {
 		err, ok := <-done
 		if !ok {
-			return streamDoneMsg{err: nil}
+			return streamDoneMsg{err: nil, from: done}
 		}
-		return streamDoneMsg{err: err}
+		return streamDoneMsg{err: err, from: done}
 	}
 }
 
@@ -781,8 +2050,29 @@ func regenerate(prompt string, client ai.AIClient, commitType, tmpl string, enab
 		return "", err
 	}
 	log.Debug().Msg("Received response from AI client")
+	return normalizeReply(result, client, commitType, tmpl, enableEmoji, ticketPattern)
+}
 
-	result = client.SanitizeResponse(result, commitType)
+// regenerateCandidates asks for n alternative messages in one non-streaming
+// call and normalizes each, dropping those that fail validation.
+func regenerateCandidates(promptText string, n int, client ai.AIClient, commitType, tmpl string, enableEmoji bool, ticketPattern string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	msgs, err := prompt.GenerateCandidates(ctx, client, promptText, n, func(reply string) (string, error) {
+		return normalizeReply(reply, client, commitType, tmpl, enableEmoji, ticketPattern)
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Candidate generation failed")
+	}
+	return msgs, err
+}
+
+// normalizeReply sanitizes a reply of client and applies the commit type and
+// template, rejecting output that is not a commit message.
+func normalizeReply(result string, client ai.AIClient, commitType, tmpl string, enableEmoji bool, ticketPattern string) (string, error) {
+	var err error
+	result = client.SanitizeResponse(result, "")
 	if commitType != "" {
 		result = git.PrependCommitType(result, commitType, enableEmoji)
 	}
@@ -792,8 +2082,46 @@ func regenerate(prompt string, client ai.AIClient, commitType, tmpl string, enab
 			return "", err
 		}
 	}
+	result = strings.TrimSpace(result)
+	if err := ai.ValidateCommitMessage(result); err != nil {
+		return "", fmt.Errorf("rejected AI output: %w", err)
+	}
+	return result, nil
+}
+
+// unfilteredDiffCmd reads the staged diff without comment, move, or lock-file filtering.
+func unfilteredDiffCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		diff, err := git.GetStagedDiff(ctx)
+		return unfilteredMsg{diff: diff, err: err}
+	}
+}
+
+// saveSessionCmd writes s to the repository's session directory.
+func saveSessionCmd(s session.Session) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := session.Dir()
+		if err != nil {
+			return sessionSavedMsg{err: err}
+		}
+		path, err := session.Save(dir, s)
+		return sessionSavedMsg{path: path, err: err}
+	}
+}
 
-	return strings.TrimSpace(result), nil
+// readyCmd publishes a new message, with the latency of the request behind it.
+func (m Model) readyCmd() tea.Cmd {
+	if m.bus == nil {
+		return nil
+	}
+	bus, msg := m.bus, m.commitMsg
+	stats, _ := ai.StatsOf(m.aiClient)
+	return func() tea.Msg {
+		bus.Publish(events.MessageReady{Message: msg, Elapsed: stats.Latency})
+		return nil
+	}
 }
 
 func autoQuitCmd() tea.Cmd {
@@ -813,22 +2141,61 @@ func viewDiffCmd(_ string) tea.Cmd {
 // -------------------------------------------------------------------------------------
 
 func (m Model) ShortHelp() []key.Binding {
-	return []key.Binding{
+	bindings := []key.Binding{
 		keyMap.Commit,
 		keyMap.Regenerate,
+	}
+	if m.draftSource != "" {
+		bindings = append(bindings, keyMap.Improve)
+	}
+	bindings = append(bindings,
 		keyMap.Edit,
 		keyMap.TypeSelect,
+		keyMap.Scope,
+		keyMap.Intent,
 		keyMap.PromptEdit,
 		keyMap.ViewDiff,
+		keyMap.Filtered,
+		keyMap.Preview,
+		keyMap.Review,
+		keyMap.Quality,
+	)
+	if len(m.todos) > 0 {
+		bindings = append(bindings, keyMap.Todos)
+	}
+	if prompt.HasStyleIssues(m.styleReview) {
+		bindings = append(bindings, keyMap.FixStyle)
+	}
+	bindings = append(bindings, keyMap.SaveSession)
+	if len(m.choices) > 1 {
+		bindings = append(bindings, candidateKeys)
+	}
+	if m.splitPane() {
+		bindings = append(bindings, keyMap.Focus)
+	}
+	return append(bindings,
 		keyMap.Help,
 		keyMap.Quit,
 		keyMap.Enter,
-	}
+	)
 }
 
+// FullHelp lists the bindings of every screen, one column each in the order of
+// helpGroups, starting with the message screen's ShortHelp.
 func (m Model) FullHelp() [][]key.Binding {
+	move := reservedBinding("move", "up", "down", "k", "j")
+	back := relabel(keyMap.Quit, "back")
 	return [][]key.Binding{
 		m.ShortHelp(),
+		{move, keyMap.ExcludeHunk, keyMap.ExcludeFile, keyMap.IncludeHunk, keyMap.IncludeFile,
+			relabel(keyMap.Regenerate, "regenerate with the changes"), back},
+		{move, relabel(keyMap.ExcludeHunk, "trim the file of a diff section"), relabel(keyMap.Regenerate, "regenerate with the changes"), back},
+		{reservedBinding("scroll", "up", "down", "k", "j", "pgup", "pgdown"), relabel(keyMap.Regenerate, "review again"), back},
+		{keyMap.Unfilter, back},
+		{move, reservedBinding("pick the type and regenerate", "enter"), reservedBinding("back", "esc")},
+		{reservedBinding("", "ctrl+s"), reservedBinding("cancel", "esc")},
+		{reservedBinding("apply (empty removes the scope)", "enter"), reservedBinding("cancel", "esc")},
+		{reservedBinding("regenerate with it (empty removes it)", "enter"), reservedBinding("cancel", "esc")},
 	}
 }
 
@@ -850,3 +2217,13 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// errorText formats a failed request for the status line, adding the advice
+// ai.Hint has for its kind of error.
+func errorText(prefix string, err error) string {
+	text := fmt.Sprintf("%s: %v", prefix, err)
+	if hint := ai.Hint(err); hint != "" {
+		text += " (" + hint + ")"
+	}
+	return text
+}
@@ -0,0 +1,99 @@
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+// trackedClient publishes every request made through the wrapped client on a bus.
+type trackedClient struct {
+	ai.Wrapper
+	bus      *events.Bus
+	provider string
+	model    string
+
+	mu   sync.Mutex
+	last ai.CallStats
+	used bool
+}
+
+// Track wraps client so each request is published on bus as GenerationStarted
+// and GenerationFinished, the latter with its latency and tokens (as reported by
+// the provider, else estimated), and its stats are kept for LastCallStats.
+// Streaming and embedding support of the underlying client are preserved.
+func Track(client ai.AIClient, bus *events.Bus, provider, model string) ai.AIClient {
+	return ai.Decorate(&trackedClient{Wrapper: ai.Wrapper{AIClient: client}, bus: bus, provider: provider, model: model}, client)
+}
+
+// started announces a request for prompt and returns its start time.
+func (t *trackedClient) started(prompt string) time.Time {
+	t.bus.Publish(events.GenerationStarted{Provider: t.provider, Model: t.model, Prompt: prompt})
+	return time.Now()
+}
+
+// record stores the stats of a request that started at start and publishes them.
+func (t *trackedClient) record(start time.Time, input, output string, err error) {
+	stats := ai.CallStats{Provider: t.provider, Model: t.model, Latency: time.Since(start), Sampling: ai.SamplingOf(t.AIClient)}
+	if u, ok := t.AIClient.(ai.UsageAIClient); ok {
+		stats.Usage, ok = u.LastUsage()
+		stats.Estimated = !ok
+	} else {
+		stats.Estimated = true
+	}
+	if stats.Estimated {
+		stats.Usage = ai.Usage{InputTokens: EstimateTokens(input), OutputTokens: EstimateTokens(output)}
+	}
+	t.mu.Lock()
+	t.last, t.used = stats, true
+	t.mu.Unlock()
+	t.bus.Publish(events.GenerationFinished{Stats: stats, Prompt: input, Output: output, Err: err})
+}
+
+// LastCallStats returns the provider, latency, and token usage of the last request.
+func (t *trackedClient) LastCallStats() (ai.CallStats, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last, t.used
+}
+
+func (t *trackedClient) ModelName() string {
+	return t.model
+}
+
+func (t *trackedClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	start := t.started(prompt)
+	msg, err := t.AIClient.GetCommitMessage(ctx, prompt)
+	t.record(start, prompt, msg, err)
+	return msg, err
+}
+
+// EmbedThrough publishes an embedding request made through next.
+func (t *trackedClient) EmbedThrough(ctx context.Context, next ai.EmbeddingAIClient, model string, texts []string) ([][]float64, error) {
+	start := time.Now()
+	vectors, err := next.Embed(ctx, model, texts)
+	tokens := 0
+	for _, text := range texts {
+		tokens += EstimateTokens(text)
+	}
+	// Embeddings are not kept for LastCallStats, which describes generations.
+	t.bus.Publish(events.GenerationFinished{
+		Stats: ai.CallStats{Provider: t.provider, Model: t.model, Latency: time.Since(start), Usage: ai.Usage{InputTokens: tokens}, Estimated: true},
+		Err:   err,
+	})
+	return vectors, err
+}
+
+// StreamThrough records a streamed request made through next.
+func (t *trackedClient) StreamThrough(ctx context.Context, next ai.StreamingAIClient, prompt string, onDelta func(string)) (string, error) {
+	start := t.started(prompt)
+	msg, err := next.StreamCommitMessage(ctx, prompt, onDelta)
+	t.record(start, prompt, msg, err)
+	return msg, err
+}
+
+var _ ai.Decorator = (*trackedClient)(nil)
+var _ ai.StatsAIClient = (*trackedClient)(nil)
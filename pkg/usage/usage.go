@@ -0,0 +1,197 @@
+// Package usage records AI requests in a per-user ledger and enforces the
+// daily and monthly budgets configured under "budget:".
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+// charsPerToken is the rough ratio used to estimate tokens from text length.
+const charsPerToken = 4
+
+// Entry is one AI request recorded in the ledger.
+type Entry struct {
+	Time         time.Time `json:"time"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model,omitempty"`
+	InputTokens  int       `json:"inputTokens"`
+	OutputTokens int       `json:"outputTokens"`
+	// Cost is the estimated cost in USD; zero when no price is configured.
+	Cost float64 `json:"cost"`
+}
+
+// Ledger is an append-only JSON-lines file of Entries.
+type Ledger struct {
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultPath returns the ledger location next to config.yaml.
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+// NewLedger returns a ledger stored at path.
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Record appends e to the ledger.
+func (l *Ledger) Record(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create usage ledger directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage entry: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage ledger: %w", err)
+	}
+	return nil
+}
+
+// Subscribe records every provider request published on bus in l, with its
+// cost estimated from prices.
+func (l *Ledger) Subscribe(bus *events.Bus, prices map[string]config.ModelPrice) {
+	events.On(bus, func(e events.GenerationFinished) {
+		s := e.Stats
+		entry := Entry{
+			Time:         time.Now(),
+			Provider:     s.Provider,
+			Model:        s.Model,
+			InputTokens:  s.InputTokens,
+			OutputTokens: s.OutputTokens,
+			Cost:         EstimateCost(prices, s.Provider, s.Model, s.InputTokens, s.OutputTokens),
+		}
+		if err := l.Record(entry); err != nil {
+			log.Debug().Err(err).Msg("Failed to record usage")
+		}
+	})
+}
+
+// Since returns the entries recorded at or after t. Malformed lines are skipped.
+func (l *Ledger) Since(t time.Time) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		if !e.Time.Before(t) {
+			entries = append(entries, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+	return entries, nil
+}
+
+// EstimateTokens approximates the token count of text.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// PriceFor returns the "provider:model" entry of prices, falling back to the
+// "provider" entry; ok is false when neither is configured.
+func PriceFor(prices map[string]config.ModelPrice, provider, model string) (price config.ModelPrice, ok bool) {
+	if price, ok = prices[provider+":"+model]; !ok {
+		price, ok = prices[provider]
+	}
+	return price, ok
+}
+
+// EstimateCost prices a request using the "provider:model" entry of prices,
+// falling back to the "provider" entry.
+func EstimateCost(prices map[string]config.ModelPrice, provider, model string, inputTokens, outputTokens int) float64 {
+	price, _ := PriceFor(prices, provider, model)
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6
+}
+
+// Totals sums requests and estimated cost.
+type Totals struct {
+	Requests int
+	Cost     float64
+}
+
+func sum(entries []Entry, since time.Time) Totals {
+	var t Totals
+	for _, e := range entries {
+		if e.Time.Before(since) {
+			continue
+		}
+		t.Requests++
+		t.Cost += e.Cost
+	}
+	return t
+}
+
+// Exceeded reports whether entries break a daily or monthly limit as of now, with
+// a human-readable reason. Days and months follow now's location.
+func Exceeded(entries []Entry, budget config.BudgetSettings, now time.Time) (bool, string) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := MonthStart(now)
+	periods := []struct {
+		name  string
+		limit config.BudgetLimit
+		since time.Time
+	}{
+		{"daily", budget.Daily, dayStart},
+		{"monthly", budget.Monthly, monthStart},
+	}
+	for _, p := range periods {
+		t := sum(entries, p.since)
+		if p.limit.MaxRequests > 0 && t.Requests >= p.limit.MaxRequests {
+			return true, fmt.Sprintf("%s request limit reached (%d/%d)", p.name, t.Requests, p.limit.MaxRequests)
+		}
+		if p.limit.MaxCost > 0 && t.Cost >= p.limit.MaxCost {
+			return true, fmt.Sprintf("%s cost limit reached ($%.2f/$%.2f)", p.name, t.Cost, p.limit.MaxCost)
+		}
+	}
+	return false, ""
+}
+
+// MonthStart returns the first instant of now's month, the oldest entry Exceeded needs.
+func MonthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// Enabled reports whether any limit is configured.
+func Enabled(budget config.BudgetSettings) bool {
+	return budget.Daily != (config.BudgetLimit{}) || budget.Monthly != (config.BudgetLimit{})
+}
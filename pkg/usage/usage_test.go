@@ -0,0 +1,172 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/config"
+	"github.com/renatogalera/ai-commit/pkg/events"
+)
+
+func TestLedgerRecordAndSince(t *testing.T) {
+	t.Parallel()
+	l := NewLedger(filepath.Join(t.TempDir(), "cfg", "usage.jsonl"))
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Time: now.AddDate(0, -1, 0), Provider: "openai"},
+		{Time: now.Add(-time.Hour), Provider: "openai", Cost: 0.5},
+		{Time: now, Provider: "ollama"},
+	} {
+		if err := l.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := l.Since(MonthStart(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Cost != 0.5 || entries[1].Provider != "ollama" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	missing, err := NewLedger(filepath.Join(t.TempDir(), "none.jsonl")).Since(time.Time{})
+	if err != nil || missing != nil {
+		t.Errorf("expected no entries for a missing ledger, got %+v, %v", missing, err)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	t.Parallel()
+	prices := map[string]config.ModelPrice{
+		"openai":             {Input: 2, Output: 8},
+		"openai:gpt-4o-mini": {Input: 0.15, Output: 0.6},
+	}
+	if got := EstimateCost(prices, "openai", "gpt-4o", 1_000_000, 500_000); got != 6 {
+		t.Errorf("provider price: got %f, want 6", got)
+	}
+	if got := EstimateCost(prices, "openai", "gpt-4o-mini", 1_000_000, 0); got != 0.15 {
+		t.Errorf("model price: got %f, want 0.15", got)
+	}
+	if got := EstimateCost(prices, "ollama", "llama3", 1_000_000, 1_000_000); got != 0 {
+		t.Errorf("unpriced provider: got %f, want 0", got)
+	}
+	if _, ok := PriceFor(prices, "ollama", "llama3"); ok {
+		t.Error("PriceFor(ollama) found a price")
+	}
+	if price, ok := PriceFor(prices, "openai", "gpt-4o"); !ok || price.Input != 2 {
+		t.Errorf("PriceFor(openai:gpt-4o) = %+v, %v; want the provider price", price, ok)
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: now.AddDate(0, 0, -3), Cost: 4},
+		{Time: now.Add(-2 * time.Hour), Cost: 0.5},
+		{Time: now.Add(-time.Hour), Cost: 0.5},
+	}
+	tests := []struct {
+		name   string
+		budget config.BudgetSettings
+		want   bool
+		reason string
+	}{
+		{name: "no limits", budget: config.BudgetSettings{}},
+		{name: "daily requests", budget: config.BudgetSettings{Daily: config.BudgetLimit{MaxRequests: 2}}, want: true, reason: "daily request limit reached (2/2)"},
+		{name: "daily cost under", budget: config.BudgetSettings{Daily: config.BudgetLimit{MaxCost: 2}}},
+		{name: "monthly cost", budget: config.BudgetSettings{Monthly: config.BudgetLimit{MaxCost: 5}}, want: true, reason: "monthly cost limit reached ($5.00/$5.00)"},
+	}
+	for _, tc := range tests {
+		got, reason := Exceeded(entries, tc.budget, now)
+		if got != tc.want || reason != tc.reason {
+			t.Errorf("%s: Exceeded() = %v, %q; want %v, %q", tc.name, got, reason, tc.want, tc.reason)
+		}
+	}
+}
+
+type fakeClient struct {
+	ai.BaseAIClient
+	reply string
+	err   error
+}
+
+func (f *fakeClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return f.reply, f.err
+}
+
+type fakeReportingClient struct {
+	fakeClient
+	ai.UsageRecorder
+}
+
+func (f *fakeReportingClient) GetCommitMessage(ctx context.Context, prompt string) (string, error) {
+	f.SetLastUsage(ai.Usage{InputTokens: 100, OutputTokens: 10, CachedTokens: 80})
+	return f.reply, nil
+}
+
+type fakeStreamingClient struct{ fakeClient }
+
+func (f *fakeStreamingClient) StreamCommitMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	onDelta(f.reply)
+	return f.reply, nil
+}
+
+func TestTrack(t *testing.T) {
+	t.Parallel()
+	l := NewLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+	prices := map[string]config.ModelPrice{"openai": {Input: 1e6, Output: 1e6}}
+	bus := events.New()
+	l.Subscribe(bus, prices)
+	started := 0
+	events.On(bus, func(events.GenerationStarted) { started++ })
+
+	plain := Track(&fakeClient{reply: "feat: x", err: errors.New("boom")}, bus, "openai", "gpt")
+	if _, ok := plain.(ai.StreamingAIClient); ok {
+		t.Error("expected a non-streaming client to stay non-streaming")
+	}
+	if _, err := plain.GetCommitMessage(context.Background(), strings.Repeat("a", 8)); err == nil {
+		t.Error("expected the underlying error to be returned")
+	}
+
+	streaming := Track(&fakeStreamingClient{fakeClient{reply: "fix: y"}}, bus, "openai", "gpt")
+	s, ok := streaming.(ai.StreamingAIClient)
+	if !ok {
+		t.Fatal("expected streaming support to be preserved")
+	}
+	if _, err := s.StreamCommitMessage(context.Background(), "prompt", func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := l.Since(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || started != 2 {
+		t.Fatalf("expected 2 started and recorded requests, got %d and %+v", started, entries)
+	}
+	if entries[0].InputTokens != 2 || entries[0].OutputTokens != 2 || entries[0].Cost != 4 {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if stats, ok := ai.StatsOf(streaming); !ok || !stats.Estimated || stats.Provider != "openai" || stats.Model != "gpt" {
+		t.Errorf("unexpected stats %+v, %v", stats, ok)
+	}
+
+	reporting := Track(&fakeReportingClient{fakeClient: fakeClient{reply: "feat: z"}}, bus, "anthropic", "claude")
+	if _, ok := ai.StatsOf(reporting); ok {
+		t.Error("expected no stats before the first request")
+	}
+	if _, err := reporting.GetCommitMessage(context.Background(), "prompt"); err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := ai.StatsOf(reporting)
+	if !ok || stats.Estimated || stats.InputTokens != 100 || stats.OutputTokens != 10 || stats.CachedTokens != 80 {
+		t.Errorf("expected reported usage, got %+v", stats)
+	}
+}
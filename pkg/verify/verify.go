@@ -0,0 +1,227 @@
+// Package verify checks pushed commits for "ai-commit verify-server": every
+// message must follow the commit conventions and describe its diff.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/git"
+	"github.com/renatogalera/ai-commit/pkg/lint"
+	"github.com/renatogalera/ai-commit/pkg/prompt"
+)
+
+// Verdict keywords the consistency check asks the model to reply with.
+const (
+	Consistent   = "CONSISTENT"
+	Inconsistent = "INCONSISTENT"
+)
+
+// maxRequestBytes bounds the body of a verification request.
+const maxRequestBytes = 32 << 20
+
+// MaxRequestCommits bounds the commits of one verification request, so a
+// single call cannot run an unbounded number of AI checks; clients split
+// larger pushes into several requests.
+const MaxRequestCommits = 100
+
+// Commit is one pushed commit to verify.
+type Commit struct {
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// Request is the body of POST /verify.
+type Request struct {
+	Commits []Commit `json:"commits"`
+}
+
+// Result is the verdict for one commit.
+type Result struct {
+	Hash     string   `json:"hash"`
+	Subject  string   `json:"subject"`
+	Pass     bool     `json:"pass"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Response is the verdict for a whole push; it passes when every commit does.
+type Response struct {
+	Pass    bool     `json:"pass"`
+	Commits []Result `json:"commits"`
+}
+
+// Report renders the failing commits for the pusher, one problem per line.
+func (r Response) Report() string {
+	var b strings.Builder
+	failed := 0
+	for _, c := range r.Commits {
+		if c.Pass {
+			continue
+		}
+		failed++
+		hash := c.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		fmt.Fprintf(&b, "%s %s\n", hash, c.Subject)
+		for _, p := range c.Problems {
+			fmt.Fprintf(&b, "    - %s\n", p)
+		}
+	}
+	if failed == 0 {
+		return fmt.Sprintf("All %d commits passed verification.", len(r.Commits))
+	}
+	return fmt.Sprintf("%d of %d commits failed verification:\n%s", failed, len(r.Commits), strings.TrimRight(b.String(), "\n"))
+}
+
+// Verifier checks commits against the lint rules and, when Client is set, asks
+// the model whether each message describes its diff.
+type Verifier struct {
+	Client ai.AIClient
+	Lint   lint.Options
+	// MaxDiffChars summarizes longer diffs before the consistency check (0 = never).
+	MaxDiffChars int
+	// Timeout bounds the consistency check of one commit (0 = no limit).
+	Timeout time.Duration
+	// FailOpen accepts commits whose consistency check could not be completed.
+	FailOpen bool
+}
+
+// Verify checks every commit and reports whether the push may be accepted.
+func (v *Verifier) Verify(ctx context.Context, commits []Commit) Response {
+	resp := Response{Pass: true, Commits: make([]Result, 0, len(commits))}
+	for _, c := range commits {
+		result := v.verifyCommit(ctx, c)
+		resp.Pass = resp.Pass && result.Pass
+		resp.Commits = append(resp.Commits, result)
+	}
+	return resp
+}
+
+func (v *Verifier) verifyCommit(ctx context.Context, c Commit) Result {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	result := Result{Hash: c.Hash, Subject: subject, Problems: lint.Check(c.Message, v.Lint)}
+	if v.Client != nil && strings.TrimSpace(c.Diff) != "" {
+		consistent, reason, err := v.checkConsistency(ctx, c)
+		switch {
+		case err != nil && !v.FailOpen:
+			result.Problems = append(result.Problems, "message-vs-diff check failed: "+err.Error())
+		case err == nil && !consistent:
+			result.Problems = append(result.Problems, "message does not match the diff: "+reason)
+		}
+	}
+	result.Pass = len(result.Problems) == 0
+	return result
+}
+
+func (v *Verifier) checkConsistency(ctx context.Context, c Commit) (bool, string, error) {
+	// The diff comes from the client, which may not have redacted it.
+	diff := git.RedactPrivateFiles(c.Diff)
+	if v.MaxDiffChars > 0 {
+		if summarized, did := v.Client.MaybeSummarizeDiff(diff, v.MaxDiffChars); did {
+			diff = summarized
+		}
+	}
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+	reply, err := v.Client.GetCommitMessage(ctx, prompt.BuildVerifyPrompt(diff, c.Message, Consistent, Inconsistent))
+	if err != nil {
+		return false, "", err
+	}
+	return ParseVerdict(reply)
+}
+
+// ParseVerdict reads a consistency reply: "CONSISTENT", or "INCONSISTENT: reason".
+func ParseVerdict(reply string) (consistent bool, reason string, err error) {
+	text := strings.TrimSpace(strings.Trim(strings.TrimSpace(reply), "*`\"'"))
+	upper := strings.ToUpper(text)
+	switch {
+	case strings.HasPrefix(upper, Inconsistent):
+		reason, _, _ = strings.Cut(text[len(Inconsistent):], "\n")
+		reason = strings.TrimSpace(strings.TrimLeft(reason, ":-–— "))
+		if reason == "" {
+			reason = "the message does not describe the diff"
+		}
+		return false, reason, nil
+	case strings.HasPrefix(upper, Consistent):
+		return true, "", nil
+	}
+	if len(text) > 80 {
+		text = text[:80] + "…"
+	}
+	return false, "", fmt.Errorf("unexpected verdict %q", text)
+}
+
+// Handler serves POST /verify, which takes a JSON Request and answers with a
+// JSON Response, and GET /healthz. When token is set, /verify requires the
+// header "Authorization: Bearer <token>"; an empty token disables the check.
+// Requests with more than MaxRequestCommits commits are rejected.
+func (v *Verifier) Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("POST /verify", func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(req.Commits) > MaxRequestCommits {
+			http.Error(w, fmt.Sprintf("too many commits: %d (at most %d per request)", len(req.Commits), MaxRequestCommits), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v.Verify(r.Context(), req.Commits))
+	})
+	return mux
+}
+
+// Post sends req to the /verify endpoint of the server at serverURL.
+func Post(ctx context.Context, serverURL, token string, req Request) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(serverURL, "/")+"/verify", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("verify server unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("verify server: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var out Response
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse the verify response: %w", err)
+	}
+	return &out, nil
+}
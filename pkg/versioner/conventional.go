@@ -0,0 +1,98 @@
+package versioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/changelog"
+)
+
+// Bump strategies of ReleaseOptions.Strategy.
+const (
+	// StrategyAI asks the AI for the next version (the default).
+	StrategyAI = "ai"
+	// StrategyConventional computes the bump from the Conventional Commits types
+	// and BREAKING CHANGE footers of the commits, without the AI.
+	StrategyConventional = "conventional"
+	// StrategyHybrid is StrategyConventional, with the AI deciding whether the
+	// commits without a recognizable type call for a larger bump.
+	StrategyHybrid = "hybrid"
+)
+
+// Strategies lists the valid bump strategies.
+var Strategies = []string{StrategyAI, StrategyConventional, StrategyHybrid}
+
+// bumpRank orders bumps from smallest to largest.
+var bumpRank = map[string]int{"patch": 1, "minor": 2, "major": 3}
+
+// ConventionalBump returns the bump that messages call for by their
+// Conventional Commits types: "major" for a breaking change ("!" or a BREAKING
+// CHANGE footer), "minor" for a feature, and "patch" otherwise. The reason names
+// the commit that decided it; untyped are the messages without a recognizable
+// type, which count as patches.
+func ConventionalBump(messages []string) (bump, reason string, untyped []string) {
+	bump = "patch"
+	for _, m := range messages {
+		e := changelog.NormalizeMessage(m)
+		kind := "patch"
+		switch {
+		case e.Breaking:
+			kind = "major"
+		case e.Type == "feat":
+			kind = "minor"
+		case e.Type == "other":
+			untyped = append(untyped, m)
+		}
+		if reason == "" || bumpRank[kind] > bumpRank[bump] {
+			bump, reason = kind, bumpReason(kind, m)
+		}
+	}
+	return bump, reason, untyped
+}
+
+func bumpReason(kind, message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	switch kind {
+	case "major":
+		return fmt.Sprintf("breaking change in %q", subject)
+	case "minor":
+		return fmt.Sprintf("new feature in %q", subject)
+	}
+	return fmt.Sprintf("no features or breaking changes (e.g. %q)", subject)
+}
+
+// applyBump increments the part of current (vX.Y.Z) that bump names.
+func applyBump(current, bump string) string {
+	clean := strings.TrimPrefix(semver.Canonical(current), "v")
+	clean, _, _ = strings.Cut(clean, "-")
+	major, minor, patch := parseVersionTriplet(clean)
+	switch bump {
+	case "major":
+		return fmt.Sprintf("v%d.0.0", major+1)
+	case "minor":
+		return fmt.Sprintf("v%d.%d.0", major, minor+1)
+	}
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch+1)
+}
+
+// conventionalVersion returns the next core version after current for
+// messages under StrategyConventional or StrategyHybrid, and the reason for the
+// bump. With StrategyHybrid, the AI sees the commits without a recognizable
+// type and may raise the bump; when it fails, the local bump stands.
+func conventionalVersion(ctx context.Context, client ai.AIClient, current string, messages []string, strategy string) (string, string) {
+	bump, reason, untyped := ConventionalBump(messages)
+	if strategy == StrategyHybrid && len(untyped) > 0 && bump != "major" {
+		reply, err := client.GetCommitMessage(ctx, buildPlanPrompt(current, untyped))
+		if err != nil {
+			reason += fmt.Sprintf("; the AI tiebreaker failed (%v)", err)
+		} else if core, aiReason := parsePlanReply(reply, current); bumpRank[bumpKind(current, core)] > bumpRank[bump] {
+			bump = bumpKind(current, core)
+			reason = fmt.Sprintf("AI tiebreaker on %d untyped commit(s): %s", len(untyped), aiReason)
+		}
+	}
+	return applyBump(current, bump), reason
+}
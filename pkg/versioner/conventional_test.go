@@ -0,0 +1,75 @@
+package versioner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConventionalBump(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		messages []string
+		want     string
+		untyped  int
+	}{
+		{"fixes", []string{"fix: a", "docs: b", "chore(deps): c"}, "patch", 0},
+		{"feature", []string{"fix: a", "feat(ui): b"}, "minor", 0},
+		{"bang", []string{"feat: a", "refactor(api)!: drop v1"}, "major", 0},
+		{"footer", []string{"fix: a\n\nBREAKING CHANGE: the flag is gone"}, "major", 0},
+		{"gitmoji", []string{"✨ add dark mode"}, "minor", 0},
+		{"untyped", []string{"fix: a", "Update stuff"}, "patch", 1},
+	}
+	for _, tt := range tests {
+		bump, reason, untyped := ConventionalBump(tt.messages)
+		if bump != tt.want || len(untyped) != tt.untyped || reason == "" {
+			t.Errorf("%s: ConventionalBump() = %q, %q, %d untyped; want %q, %d untyped", tt.name, bump, reason, len(untyped), tt.want, tt.untyped)
+		}
+	}
+	if _, reason, _ := ConventionalBump([]string{"fix: a", "feat: b\n\nBody."}); reason != `new feature in "feat: b"` {
+		t.Errorf("reason = %q", reason)
+	}
+}
+
+func TestApplyBump(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{"major": "v2.0.0", "minor": "v1.3.0", "patch": "v1.2.4"}
+	for bump, want := range tests {
+		if got := applyBump("v1.2.3", bump); got != want {
+			t.Errorf("applyBump(v1.2.3, %s) = %s, want %s", bump, got, want)
+		}
+	}
+	if got := applyBump("v0.0.0", "minor"); got != "v0.1.0" {
+		t.Errorf("applyBump(v0.0.0, minor) = %s", got)
+	}
+}
+
+func TestConventionalVersion(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	messages := []string{"fix: a", "Rework the storage layer"}
+
+	client := &mockAIClient{response: "v2.0.0\nThe storage rework breaks the file format."}
+	if version, _ := conventionalVersion(ctx, client, "v1.2.3", messages, StrategyConventional); version != "v1.2.4" || client.prompt != "" {
+		t.Errorf("conventional: version = %s, prompt = %q; want a local patch bump", version, client.prompt)
+	}
+
+	version, reason := conventionalVersion(ctx, client, "v1.2.3", messages, StrategyHybrid)
+	if version != "v2.0.0" || !strings.Contains(reason, "breaks the file format") {
+		t.Errorf("hybrid: version = %s, reason = %q; want the AI's major bump", version, reason)
+	}
+	if !strings.Contains(client.prompt, "Rework the storage layer") || strings.Contains(client.prompt, "fix: a") {
+		t.Errorf("hybrid prompt should only list the untyped commits:\n%s", client.prompt)
+	}
+
+	lower := &mockAIClient{response: "v1.2.4"}
+	if version, _ := conventionalVersion(ctx, lower, "v1.2.3", []string{"feat: b", "Tweak"}, StrategyHybrid); version != "v1.3.0" {
+		t.Errorf("the AI should not lower the bump: %s", version)
+	}
+	failing := &mockAIClient{err: errors.New("offline")}
+	if version, reason := conventionalVersion(ctx, failing, "v1.2.3", messages, StrategyHybrid); version != "v1.2.4" || !strings.Contains(reason, "offline") {
+		t.Errorf("a failed tiebreaker should keep the local bump: %s, %q", version, reason)
+	}
+}
@@ -0,0 +1,273 @@
+package versioner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"golang.org/x/mod/semver"
+
+	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/changelog"
+	gitrepo "github.com/renatogalera/ai-commit/pkg/git"
+)
+
+// Plan describes the tag a semantic release of HEAD would create.
+type Plan struct {
+	// Package is the monorepo package name, "" for the whole repository.
+	Package string
+	// CurrentVersion is the latest stable tag the bump starts from ("" if none).
+	CurrentVersion string
+	// Since is the tag the commits are counted from ("" if none): the latest
+	// stable tag, or the latest tag of any kind on a pre-release channel.
+	Since string
+	// Commits are the analyzed commit messages, newest first.
+	Commits []string
+	// Bump is "major", "minor", or "patch"; empty when nothing changed or the
+	// manual selection was cancelled.
+	Bump   string
+	Reason string
+	Tag    string
+}
+
+// PlanSemanticRelease computes what PerformSemanticRelease would tag for HEAD,
+// one Plan per package (or one for the repository), without creating anything.
+// The AI (or, under opts.Strategy, the Conventional Commits types) analyzes
+// every commit since the latest tag and explains the bump; with manual, the
+// user picks it as in the release.
+func PlanSemanticRelease(ctx context.Context, client ai.AIClient, manual bool, opts ReleaseOptions) ([]Plan, error) {
+	if strings.Contains(opts.BuildMetadata, "{COMMIT}") {
+		head, err := headShortHash()
+		if err != nil {
+			return nil, err
+		}
+		opts.BuildMetadata = strings.ReplaceAll(opts.BuildMetadata, "{COMMIT}", head)
+	}
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+
+	packages := opts.Packages
+	if len(packages) == 0 {
+		packages = []Package{{TagPrefix: opts.TagPrefix}}
+	}
+	plans := make([]Plan, 0, len(packages))
+	for _, p := range packages {
+		pkgOpts := opts
+		pkgOpts.TagPrefix, pkgOpts.Packages = p.TagPrefix, nil
+		plan, err := planRelease(ctx, client, repo, head.Hash(), p, manual, pkgOpts)
+		if err != nil {
+			if p.Name != "" {
+				return nil, fmt.Errorf("package %s: %w", p.Name, err)
+			}
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func planRelease(ctx context.Context, client ai.AIClient, repo *git.Repository, head plumbing.Hash, p Package, manual bool, opts ReleaseOptions) (Plan, error) {
+	tags, err := listVersionTags(opts.TagPrefix)
+	if err != nil {
+		return Plan{}, fmt.Errorf("could not retrieve current version: %w", err)
+	}
+	plan := Plan{Package: p.Name}
+	if stable := latestVersion(tags, true); stable != "" {
+		plan.CurrentVersion = tagName(opts.TagPrefix, stable)
+	}
+	since := plumbing.ZeroHash
+	if latest := sinceVersion(tags, opts); latest != "" {
+		plan.Since = tagName(opts.TagPrefix, latest)
+		if since, err = resolveTag(repo, plan.Since); err != nil {
+			return Plan{}, err
+		}
+	}
+	if plan.Commits, err = changedMessages(repo, head, since, p.Path); err != nil {
+		return Plan{}, err
+	}
+	if len(plan.Commits) == 0 {
+		return plan, nil
+	}
+	if plan.Tag, plan.Bump, plan.Reason, err = nextReleaseTag(ctx, client, tags, plan.Commits, manual, opts); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// nextReleaseTag decides the tag that follows tags (under opts.TagPrefix) for
+// the commit messages, newest first, and returns it with the bump kind and its
+// reason. The user picks the bump when manual; an empty tag means they
+// cancelled. The release and its plan both go through here, so --dry-run
+// prints what the release would tag.
+func nextReleaseTag(ctx context.Context, client ai.AIClient, tags, messages []string, manual bool, opts ReleaseOptions) (string, string, string, error) {
+	current := latestVersion(tags, true)
+	if current == "" {
+		current = "v0.0.0"
+	}
+	var core, reason string
+	var err error
+	if manual {
+		core, err = pickVersion(tags, current, opts)
+		reason = "selected manually"
+	} else {
+		core, reason, err = suggestVersion(ctx, client, current, messages, opts.Strategy)
+	}
+	if err != nil || core == "" {
+		return "", "", "", err
+	}
+	version, err := NextVersion(tags, core, opts)
+	if err != nil {
+		return "", "", "", err
+	}
+	return tagName(opts.TagPrefix, version), bumpKind(current, core), reason, nil
+}
+
+// suggestVersion proposes the release version (vX.Y.Z) after current for the
+// commit messages, newest first, and explains the bump: from their Conventional
+// Commits types under StrategyConventional and StrategyHybrid, otherwise from
+// the AI's reading of them.
+func suggestVersion(ctx context.Context, client ai.AIClient, current string, messages []string, strategy string) (string, string, error) {
+	if strategy == StrategyConventional || strategy == StrategyHybrid {
+		core, reason := conventionalVersion(ctx, client, current, messages, strategy)
+		return core, reason, nil
+	}
+	reply, err := client.GetCommitMessage(ctx, buildPlanPrompt(current, messages))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get version suggestion: %w", err)
+	}
+	core, reason := parsePlanReply(reply, current)
+	return core, reason, nil
+}
+
+func buildPlanPrompt(currentVersion string, commits []string) string {
+	return fmt.Sprintf(`
+We use semantic versioning: MAJOR.MINOR.PATCH.
+The current version is %s.
+Commits since the last release, newest first:
+%s
+
+Based on these commits, determine if the next version should be:
+- MAJOR: breaking changes,
+- MINOR: new features,
+- PATCH: bug fixes or minor improvements.
+
+Reply with the next version in format vX.Y.Z on the first line, then one or two
+sentences explaining which commits drove the bump.
+`, currentVersion, "- "+strings.Join(commitSubjects(commits), "\n- "))
+}
+
+// parsePlanReply returns the version and the explanation of a plan reply; a
+// reply without a version falls back to a patch bump.
+func parsePlanReply(reply, current string) (string, string) {
+	reply = strings.TrimSpace(reply)
+	version, _ := parseAiVersionSuggestion(reply, current)
+	first, rest, _ := strings.Cut(reply, "\n")
+	if strings.TrimPrefix(strings.Trim(first, "*` "), "v") != strings.TrimPrefix(version, "v") {
+		rest = reply // the version is part of the explanation
+	}
+	return version, strings.Join(strings.Fields(rest), " ")
+}
+
+// bumpKind names the part of current that next increments.
+func bumpKind(current, next string) string {
+	switch {
+	case semver.Major(next) != semver.Major(current):
+		return "major"
+	case semver.MajorMinor(next) != semver.MajorMinor(current):
+		return "minor"
+	default:
+		return "patch"
+	}
+}
+
+func commitSubjects(messages []string) []string {
+	subjects := make([]string, len(messages))
+	for i, m := range messages {
+		subjects[i], _, _ = strings.Cut(m, "\n")
+	}
+	return subjects
+}
+
+// Changelog previews the release notes: the commit subjects grouped by their
+// normalized Conventional Commits type.
+func (p Plan) Changelog() string {
+	grouped := make(map[string][]string)
+	for _, m := range p.Commits {
+		e := changelog.NormalizeMessage(m)
+		line := e.Subject
+		if e.Scope != "" {
+			line = "**" + e.Scope + ":** " + line
+		}
+		if e.Breaking {
+			line += " (BREAKING)"
+		}
+		grouped[e.Type] = append(grouped[e.Type], line)
+	}
+	order := []string{"feat", "fix", "perf", "refactor", "docs", "test", "chore", "build", "ci", "style", "revert"}
+	var rest []string
+	for typ := range grouped {
+		if !slices.Contains(order, typ) {
+			rest = append(rest, typ)
+		}
+	}
+	sort.Strings(rest)
+
+	var b strings.Builder
+	for _, typ := range append(order, rest...) {
+		if len(grouped[typ]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n", typ)
+		for _, line := range grouped[typ] {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// String renders the plan for --dry-run.
+func (p Plan) String() string {
+	var b strings.Builder
+	if p.Package != "" {
+		fmt.Fprintf(&b, "Package: %s\n", p.Package)
+	}
+	current := p.CurrentVersion
+	if current == "" {
+		current = "(none)"
+	}
+	fmt.Fprintf(&b, "Current version: %s\n", current)
+	if len(p.Commits) == 0 {
+		b.WriteString("No changes since the last release; nothing would be tagged.")
+		return b.String()
+	}
+	if p.Tag == "" {
+		b.WriteString("No version selected; nothing would be tagged.")
+		return b.String()
+	}
+	if p.Since != "" {
+		fmt.Fprintf(&b, "Commits analyzed (%d since %s):\n", len(p.Commits), p.Since)
+	} else {
+		fmt.Fprintf(&b, "Commits analyzed (%d, no previous tag):\n", len(p.Commits))
+	}
+	for _, s := range commitSubjects(p.Commits) {
+		fmt.Fprintf(&b, "  - %s\n", s)
+	}
+	fmt.Fprintf(&b, "Proposed bump: %s\n", p.Bump)
+	if p.Reason != "" {
+		fmt.Fprintf(&b, "Reasoning: %s\n", p.Reason)
+	}
+	fmt.Fprintf(&b, "Tag: %s\n", p.Tag)
+	fmt.Fprintf(&b, "Changelog preview:\n%s", p.Changelog())
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package versioner
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParsePlanReply(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		reply, version, reason string
+	}{
+		{"v1.3.0\nThe feat commit adds a new flag.", "v1.3.0", "The feat commit adds a new flag."},
+		{"**v2.0.0**\n\nThe API change is breaking,\nso this is a major bump.", "v2.0.0", "The API change is breaking, so this is a major bump."},
+		{"The fixes only warrant v1.2.4.", "v1.2.4", "The fixes only warrant v1.2.4."},
+		{"No idea.", "v1.2.4", "No idea."},
+	}
+	for _, tt := range tests {
+		version, reason := parsePlanReply(tt.reply, "v1.2.3")
+		if version != tt.version || reason != tt.reason {
+			t.Errorf("parsePlanReply(%q) = %q, %q; want %q, %q", tt.reply, version, reason, tt.version, tt.reason)
+		}
+	}
+}
+
+func TestNextReleaseTag(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tags := []string{"v1.2.3", "v1.3.0-rc.1"}
+	messages := []string{"feat: add --json\n\nBody.", "fix: typo"}
+
+	client := &mockAIClient{response: "v1.3.0\nThe feat commit adds a flag."}
+	tag, bump, reason, err := nextReleaseTag(ctx, client, tags, messages, false, ReleaseOptions{Prerelease: "rc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "v1.3.0-rc.2" || bump != "minor" || reason != "The feat commit adds a flag." {
+		t.Errorf("nextReleaseTag() = %q, %q, %q", tag, bump, reason)
+	}
+	for _, subject := range []string{"feat: add --json", "fix: typo"} {
+		if !strings.Contains(client.prompt, subject) {
+			t.Errorf("prompt misses %q:\n%s", subject, client.prompt)
+		}
+	}
+
+	client = &mockAIClient{response: "v9.9.9"}
+	tag, bump, _, err = nextReleaseTag(ctx, client, tags, messages[1:], false, ReleaseOptions{TagPrefix: "app/v", Strategy: StrategyConventional})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "app/v1.2.4" || bump != "patch" || client.prompt != "" {
+		t.Errorf("conventional nextReleaseTag() = %q, %q (prompt %q)", tag, bump, client.prompt)
+	}
+}
+
+func TestBumpKind(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ current, next, want string }{
+		{"v1.2.3", "v2.0.0", "major"},
+		{"v1.2.3", "v1.3.0", "minor"},
+		{"v1.2.3", "v1.2.4", "patch"},
+		{"v0.0.0", "v0.1.0", "minor"},
+	}
+	for _, tt := range tests {
+		if got := bumpKind(tt.current, tt.next); got != tt.want {
+			t.Errorf("bumpKind(%q, %q) = %q, want %q", tt.current, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestPlanString(t *testing.T) {
+	t.Parallel()
+	plan := Plan{
+		Package:        "ui",
+		CurrentVersion: "ui/v1.2.3",
+		Since:          "ui/v1.3.0-rc.1",
+		Commits: []string{
+			"fix(ui): handle resize\n\nDetails.",
+			"✨ feat!: drop the legacy theme",
+			"Update readme",
+		},
+		Bump:   "major",
+		Reason: "Dropping the legacy theme is breaking.",
+		Tag:    "ui/v2.0.0",
+	}
+	got := plan.String()
+	for _, want := range []string{
+		"Package: ui",
+		"Current version: ui/v1.2.3",
+		"Commits analyzed (3 since ui/v1.3.0-rc.1):\n  - fix(ui): handle resize\n",
+		"Proposed bump: major\nReasoning: Dropping the legacy theme is breaking.\nTag: ui/v2.0.0",
+		"### feat\n- drop the legacy theme (BREAKING)\n\n### fix\n- **ui:** handle resize\n\n### other\n- Update readme",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Plan.String() = %q, missing %q", got, want)
+		}
+	}
+
+	empty := Plan{}.String()
+	if !strings.Contains(empty, "Current version: (none)") || !strings.Contains(empty, "nothing would be tagged") {
+		t.Errorf("Plan{}.String() = %q", empty)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,86 +14,305 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/renatogalera/ai-commit/pkg/ai"
+	"github.com/renatogalera/ai-commit/pkg/changelog"
+	"github.com/renatogalera/ai-commit/pkg/events"
+	gitrepo "github.com/renatogalera/ai-commit/pkg/git"
 )
 
-// GetCurrentVersionTag retrieves the latest semantic version tag.
+// ReleaseOptions selects the pre-release channel and build metadata of the
+// next tag.
+type ReleaseOptions struct {
+	// Prerelease is a pre-release identifier such as "rc": the next tag becomes
+	// vX.Y.Z-rc.N, numbered after the highest existing vX.Y.Z-rc tag.
+	Prerelease string
+	// BuildMetadata is appended as "+metadata"; "{COMMIT}" is replaced with the
+	// short HEAD hash.
+	BuildMetadata string
+	// TagPrefix precedes X.Y.Z in tag names; it defaults to "v" (v1.2.3).
+	TagPrefix string
+	// Packages switches to per-package releases for monorepos: every package whose
+	// path changed since its latest tag is bumped and tagged with its own prefix.
+	Packages []Package
+	// Force skips the clean-worktree and upstream checks and moves existing tags.
+	Force bool
+	// Quiet suppresses the per-package progress lines.
+	Quiet bool
+	// Events receives a ReleaseTagged event for every tag created.
+	Events *events.Bus
+	// Notes asks the AI for release notes of the commits since the previous tag
+	// and creates an annotated tag with them as its message.
+	Notes bool
+	// Language is the language of the release notes (default English).
+	Language string
+	// Push pushes every new tag to Remote ("origin" when empty), authenticating
+	// HTTP(S) remotes with Token and SSH remotes through the SSH agent.
+	Push   bool
+	Remote string
+	Token  string
+	// Strategy decides the bump: StrategyAI (when empty), StrategyConventional,
+	// or StrategyHybrid.
+	Strategy string
+}
+
+// Package is a separately versioned directory of a monorepo.
+type Package struct {
+	Name string
+	// Path is the directory (or file) relative to the repository root.
+	Path string
+	// TagPrefix precedes X.Y.Z in the package's tags, e.g. "pkg-ui/v".
+	TagPrefix string
+}
+
+// tagName returns the tag for version (vX.Y.Z...) under prefix ("" means "v").
+func tagName(prefix, version string) string {
+	if prefix == "" {
+		prefix = "v"
+	}
+	return prefix + strings.TrimPrefix(version, "v")
+}
+
+// tagVersion returns the version (vX.Y.Z...) of tag under prefix, undoing
+// tagName.
+func tagVersion(prefix, tag string) string {
+	if prefix == "" {
+		prefix = "v"
+	}
+	return "v" + strings.TrimPrefix(tag, prefix)
+}
+
+// GetCurrentVersionTag retrieves the latest semantic version tag, pre-releases included.
 func GetCurrentVersionTag(ctx context.Context) (string, error) {
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	tags, err := listVersionTags("v")
 	if err != nil {
-		return "", fmt.Errorf("failed to open repository: %w", err)
+		return "", err
+	}
+	return latestVersion(tags, false), nil
+}
+
+// listVersionTags returns the versions (as vX.Y.Z...) of the tags named prefix
+// followed by a semantic version.
+func listVersionTags(prefix string) ([]string, error) {
+	if prefix == "" {
+		prefix = "v"
+	}
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
 	tagIter, err := repo.Tags()
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve tags: %w", err)
+		return nil, fmt.Errorf("failed to retrieve tags: %w", err)
 	}
-	var latestTag string
+	var tags []string
 	err = tagIter.ForEach(func(ref *plumbing.Reference) error {
-		tagName := ref.Name().Short()
-		if strings.HasPrefix(tagName, "v") && semver.IsValid(tagName) {
-			if latestTag == "" || semver.Compare(tagName, latestTag) > 0 {
-				latestTag = tagName
-			}
+		rest, ok := strings.CutPrefix(ref.Name().Short(), prefix)
+		if version := "v" + rest; ok && semver.IsValid(version) {
+			tags = append(tags, version)
 		}
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return tags, nil
+}
+
+// latestVersion returns the tag with the highest semver precedence (build
+// metadata is ignored, and v1.4.0-rc.1 < v1.4.0), or "" when there is none.
+// stableOnly skips pre-releases.
+func latestVersion(tags []string, stableOnly bool) string {
+	var latest string
+	for _, tag := range tags {
+		if stableOnly && semver.Prerelease(tag) != "" {
+			continue
+		}
+		if latest == "" || semver.Compare(tag, latest) > 0 {
+			latest = tag
+		}
+	}
+	return latest
+}
+
+// coreVersion strips the pre-release and build metadata: v1.4.0-rc.1+b7 -> v1.4.0.
+func coreVersion(version string) string {
+	v := semver.Canonical(version)
+	if pre := semver.Prerelease(v); pre != "" {
+		v = strings.TrimSuffix(v, pre)
 	}
-	return latestTag, nil
+	return v
 }
 
-// SuggestNextVersion uses AI to suggest the next semantic version.
+// NextVersion returns the tag to create for the release version core (vX.Y.Z)
+// given the existing tags. With a pre-release identifier, a higher core that
+// already has tags on that channel is kept (the channel accumulates changes
+// until it is promoted), and the tag is numbered after its highest pre-release.
+func NextVersion(tags []string, core string, opts ReleaseOptions) (string, error) {
+	version := coreVersion(core)
+	if version == "" {
+		return "", fmt.Errorf("invalid version %q", core)
+	}
+	if opts.Prerelease != "" {
+		for _, tag := range tags {
+			if c := coreVersion(tag); strings.HasPrefix(semver.Prerelease(tag), "-"+opts.Prerelease+".") && semver.Compare(c, version) > 0 {
+				version = c
+			}
+		}
+		n := 0
+		prefix := version + "-" + opts.Prerelease + "."
+		for _, tag := range tags {
+			if rest, ok := strings.CutPrefix(semver.Canonical(tag), prefix); ok {
+				if k, err := strconv.Atoi(rest); err == nil && k > n {
+					n = k
+				}
+			}
+		}
+		version = fmt.Sprintf("%s%d", prefix, n+1)
+	}
+	if opts.BuildMetadata != "" {
+		version += "+" + opts.BuildMetadata
+	}
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("invalid version %q (pre-release and build metadata may only contain [0-9A-Za-z-] and dots)", version)
+	}
+	return version, nil
+}
+
+// SuggestNextVersion uses AI to suggest the next semantic version after a
+// single commit message.
 func SuggestNextVersion(ctx context.Context, currentVersion, commitMsg string, client ai.AIClient) (string, error) {
 	if currentVersion == "" {
 		currentVersion = "v0.0.0"
 	}
-	prompt := buildVersionPrompt(currentVersion, commitMsg)
-	aiResponse, err := client.GetCommitMessage(ctx, prompt)
-	if err != nil {
-		return "", fmt.Errorf("failed to get version suggestion: %w", err)
-	}
-	suggested, err := parseAiVersionSuggestion(aiResponse, currentVersion)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse version suggestion: %w", err)
-	}
-	return suggested, nil
+	suggested, _, err := suggestVersion(ctx, client, currentVersion, []string{commitMsg}, StrategyAI)
+	return suggested, err
 }
 
 // CreateLocalTag creates a new Git tag with the provided version.
 func CreateLocalTag(ctx context.Context, newVersionTag string) error {
-	if newVersionTag == "" {
-		return errors.New("version tag is empty")
-	}
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	repo, err := gitrepo.DiscoverRepo()
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
+	return createTag(repo, newVersionTag, false, "")
+}
+
+// createTag tags HEAD, with an annotated tag when message is set. An existing
+// tag is an error unless force is set, in which case the tag is moved to HEAD.
+func createTag(repo *git.Repository, name string, force bool, message string) error {
+	if name == "" {
+		return errors.New("version tag is empty")
+	}
 	headRef, err := repo.Head()
 	if err != nil {
 		return fmt.Errorf("failed to get HEAD reference: %w", err)
 	}
-	_, err = repo.CreateTag(newVersionTag, headRef.Hash(), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create tag %s: %w", newVersionTag, err)
+	if existing, err := repo.Tag(name); err == nil {
+		if !force {
+			return fmt.Errorf("tag %s already exists (at %s); use --force-tag to move it", name, existing.Hash().String()[:7])
+		}
+		if err := repo.DeleteTag(name); err != nil {
+			return fmt.Errorf("failed to delete tag %s: %w", name, err)
+		}
+	} else if !errors.Is(err, git.ErrTagNotFound) {
+		return fmt.Errorf("failed to look up tag %s: %w", name, err)
+	}
+	var tagOpts *git.CreateTagOptions
+	if message != "" {
+		tagger, err := gitrepo.TaggerSignature()
+		if err != nil {
+			return err
+		}
+		tagOpts = &git.CreateTagOptions{Tagger: tagger, Message: message}
+	}
+	if _, err := repo.CreateTag(name, headRef.Hash(), tagOpts); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
 	}
 	return nil
 }
 
-func buildVersionPrompt(currentVersion, commitMsg string) string {
-	return fmt.Sprintf(`
-We use semantic versioning: MAJOR.MINOR.PATCH.
-The current version is %s.
-Latest commit message:
-"%s"
+// CheckReleasable reports why HEAD should not be tagged without --force-tag.
+func CheckReleasable() error {
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	return checkReleasable(repo)
+}
 
-Based on the commit message, determine if the next version should be:
-- MAJOR: breaking changes,
-- MINOR: new features,
-- PATCH: bug fixes or minor improvements.
+// checkReleasable verifies that HEAD is safe to tag: tracked files have no
+// uncommitted changes, and HEAD contains every commit of its upstream branch
+// as last fetched (being ahead, e.g. right after committing, is fine).
+func checkReleasable(repo *git.Repository) error {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	for file, fs := range status {
+		if fs.Worktree != git.Untracked && (fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified) {
+			return fmt.Errorf("uncommitted changes present (%s); commit or stash them before releasing, or use --force-tag", file)
+		}
+	}
 
-Provide the next version in format vX.Y.Z without extra explanation.
-`, currentVersion, commitMsg)
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	upstream, err := upstreamRef(repo, head.Name())
+	if err != nil || upstream == "" {
+		return err
+	}
+	remoteRef, err := repo.Reference(upstream, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil // the upstream branch was never fetched
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", upstream.Short(), err)
+	}
+	if remoteRef.Hash() == head.Hash() {
+		return nil
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	remoteCommit, err := repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", upstream.Short(), err)
+	}
+	ok, err := remoteCommit.IsAncestor(headCommit)
+	if err != nil {
+		return fmt.Errorf("failed to compare HEAD with %s: %w", upstream.Short(), err)
+	}
+	if !ok {
+		return fmt.Errorf("HEAD is behind or has diverged from %s; pull before releasing, or use --force-tag", upstream.Short())
+	}
+	return nil
+}
+
+// upstreamRef returns the remote-tracking ref of branch, or "" when HEAD is
+// detached or the branch has no upstream.
+func upstreamRef(repo *git.Repository, branch plumbing.ReferenceName) (plumbing.ReferenceName, error) {
+	if !branch.IsBranch() {
+		return "", nil
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("failed to read repository config: %w", err)
+	}
+	b, ok := cfg.Branches[branch.Short()]
+	if !ok || b.Remote == "" || b.Merge == "" {
+		return "", nil
+	}
+	if b.Remote == "." {
+		return b.Merge, nil
+	}
+	return plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short()), nil
 }
 
 func parseAiVersionSuggestion(aiResponse, fallback string) (string, error) {
@@ -228,7 +448,10 @@ func parseVersionTriplet(ver string) (int, int, int) {
 
 // RunSemVerTUI launches the semantic version TUI and returns the selected version.
 func RunSemVerTUI(ctx context.Context, currentVersion string) (string, error) {
-	model := NewSemverModel(currentVersion)
+	return runSemverModel(NewSemverModel(currentVersion))
+}
+
+func runSemverModel(model semverModel) (string, error) {
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := program.Run()
 	if err != nil {
@@ -241,32 +464,321 @@ func RunSemVerTUI(ctx context.Context, currentVersion string) (string, error) {
 	return m.selectedValue, nil
 }
 
-// PerformSemanticRelease performs the semantic version bump process.
-func PerformSemanticRelease(ctx context.Context, client ai.AIClient, commitMsg string, manual bool) error {
-	currentVersion, err := GetCurrentVersionTag(ctx)
+// pickVersion lets the user choose the release version after current, showing
+// each choice as the tag it would create under opts; "" means they cancelled.
+func pickVersion(tags []string, current string, opts ReleaseOptions) (string, error) {
+	model := NewSemverModel(current)
+	model.currentVer = tagName(opts.TagPrefix, current)
+	cores := make(map[string]string, len(model.choices))
+	for i, choice := range model.choices {
+		version, err := NextVersion(tags, choice.detail, opts)
+		if err != nil {
+			return "", err
+		}
+		model.choices[i].detail = tagName(opts.TagPrefix, version)
+		cores[model.choices[i].detail] = choice.detail
+	}
+	tag, err := runSemverModel(model)
 	if err != nil {
-		return fmt.Errorf("could not retrieve current version: %w", err)
+		return "", fmt.Errorf("manual semantic version selection failed: %w", err)
 	}
-	if currentVersion == "" {
-		currentVersion = "v0.0.0"
+	return cores[tag], nil
+}
+
+// PerformSemanticRelease performs the semantic version bump process. The bump is
+// computed from the latest stable tag; opts then turns it into a pre-release
+// and/or adds build metadata. With opts.Packages, each changed package is
+// released separately, using the messages of the commits that touched it.
+func PerformSemanticRelease(ctx context.Context, client ai.AIClient, manual bool, opts ReleaseOptions) error {
+	if !opts.Force {
+		if err := CheckReleasable(); err != nil {
+			return err
+		}
 	}
-	var nextVersion string
-	if manual {
-		nextVersion, err = RunSemVerTUI(ctx, currentVersion)
+	if strings.Contains(opts.BuildMetadata, "{COMMIT}") {
+		head, err := headShortHash()
 		if err != nil {
-			return fmt.Errorf("manual semantic version selection failed: %w", err)
+			return err
+		}
+		opts.BuildMetadata = strings.ReplaceAll(opts.BuildMetadata, "{COMMIT}", head)
+	}
+	if len(opts.Packages) > 0 {
+		return performPackageReleases(ctx, client, manual, opts)
+	}
+	tags, err := listVersionTags(opts.TagPrefix)
+	if err != nil {
+		return fmt.Errorf("could not retrieve current version: %w", err)
+	}
+	messages, err := messagesSince(tags, "", opts)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		if !opts.Quiet {
+			fmt.Println("No changes since the last release; nothing tagged.")
 		}
-		if nextVersion == "" {
-			return nil
+		return nil
+	}
+	_, err = release(ctx, client, messages, "", manual, opts)
+	return err
+}
+
+// release creates the next tag under opts.TagPrefix for the commit messages
+// since the latest tag, newest first, and returns it ("" when the manual
+// selection was cancelled). The release notes of opts.Notes cover the commits
+// changing dir ("" for the whole repository).
+func release(ctx context.Context, client ai.AIClient, messages []string, dir string, manual bool, opts ReleaseOptions) (string, error) {
+	tags, err := listVersionTags(opts.TagPrefix)
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve current version: %w", err)
+	}
+	previous := ""
+	if current := latestVersion(tags, true); current != "" {
+		previous = tagName(opts.TagPrefix, current)
+	}
+	nextTag, _, _, err := nextReleaseTag(ctx, client, tags, messages, manual, opts)
+	if err != nil || nextTag == "" {
+		return "", err
+	}
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	var notes string
+	if opts.Notes {
+		if notes, err = releaseNotes(ctx, client, repo, previous, nextTag, dir, opts.Language); err != nil {
+			return "", err
 		}
-	} else {
-		nextVersion, err = SuggestNextVersion(ctx, currentVersion, commitMsg, client)
+	}
+	if err := createTag(repo, nextTag, opts.Force, notes); err != nil {
+		return "", err
+	}
+	if opts.Push {
+		remote := opts.Remote
+		if remote == "" {
+			remote = "origin"
+		}
+		pushed, err := gitrepo.PushTag(ctx, remote, nextTag, opts.Token, opts.Force)
 		if err != nil {
-			return fmt.Errorf("AI version suggestion failed: %w", err)
+			return "", fmt.Errorf("tag %s was created locally but not pushed: %w", nextTag, err)
+		}
+		if !opts.Quiet {
+			fmt.Printf("Pushed %s\n", pushed)
+		}
+	}
+	if head, err := repo.Head(); err == nil {
+		opts.Events.Publish(events.ReleaseTagged{
+			Tag:        nextTag,
+			Previous:   previous,
+			Commit:     head.Hash().String(),
+			Notes:      notes,
+			Prerelease: semver.Prerelease(tagVersion(opts.TagPrefix, nextTag)) != "",
+		})
+	}
+	return nextTag, nil
+}
+
+// releaseNotes asks the AI for the notes of tag from the commits changing dir
+// since the previous tag ("" for the whole history).
+func releaseNotes(ctx context.Context, client ai.AIClient, repo *git.Repository, previous, tag, dir, language string) (string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	since := plumbing.ZeroHash
+	if previous != "" {
+		if since, err = resolveTag(repo, previous); err != nil {
+			return "", err
 		}
 	}
-	if err := CreateLocalTag(ctx, nextVersion); err != nil {
-		return fmt.Errorf("failed to create tag %s: %w", nextVersion, err)
+	commits, err := changedCommits(repo, head.Hash(), since, dir)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commits since %s for the release notes", previous)
+	}
+	if language == "" {
+		language = "english"
+	}
+	notes, err := changelog.Notes(ctx, client, commits, previous, tag, language, "", 0)
+	if err != nil {
+		return "", fmt.Errorf("release notes: %w", err)
+	}
+	return notes, nil
+}
+
+// performPackageReleases releases every package whose path changed on the
+// first-parent history since the package's latest tag.
+func performPackageReleases(ctx context.Context, client ai.AIClient, manual bool, opts ReleaseOptions) error {
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	for _, p := range opts.Packages {
+		tags, err := listVersionTags(p.TagPrefix)
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.Name, err)
+		}
+		since := plumbing.ZeroHash
+		if latest := sinceVersion(tags, opts); latest != "" {
+			if since, err = resolveTag(repo, tagName(p.TagPrefix, latest)); err != nil {
+				return fmt.Errorf("package %s: %w", p.Name, err)
+			}
+		}
+		messages, err := changedMessages(repo, head.Hash(), since, p.Path)
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.Name, err)
+		}
+		if len(messages) == 0 {
+			if !opts.Quiet {
+				fmt.Printf("%s: no changes in %s since its last release\n", p.Name, p.Path)
+			}
+			continue
+		}
+		pkgOpts := opts
+		pkgOpts.TagPrefix, pkgOpts.Packages = p.TagPrefix, nil
+		tag, err := release(ctx, client, messages, p.Path, manual, pkgOpts)
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.Name, err)
+		}
+		if tag != "" && !opts.Quiet {
+			fmt.Printf("%s: tagged %s (%d commits)\n", p.Name, tag, len(messages))
+		}
 	}
 	return nil
 }
+
+// sinceVersion returns the version of tags a release with opts counts the
+// commits from: the latest tag on a pre-release channel, otherwise the latest
+// stable one, so promoting vX.Y.Z-rc.N to vX.Y.Z takes in what the release
+// candidates contained.
+func sinceVersion(tags []string, opts ReleaseOptions) string {
+	return latestVersion(tags, opts.Prerelease == "")
+}
+
+// messagesSince returns the messages of the commits changing dir since the
+// sinceVersion of tags (under opts.TagPrefix), newest first, as planRelease
+// analyzes them.
+func messagesSince(tags []string, dir string, opts ReleaseOptions) ([]string, error) {
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	since := plumbing.ZeroHash
+	if latest := sinceVersion(tags, opts); latest != "" {
+		if since, err = resolveTag(repo, tagName(opts.TagPrefix, latest)); err != nil {
+			return nil, err
+		}
+	}
+	return changedMessages(repo, head.Hash(), since, dir)
+}
+
+// resolveTag returns the commit a tag points to, peeling annotated tags.
+func resolveTag(repo *git.Repository, name string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision("refs/tags/" + name))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("cannot resolve %s: %w", name, err)
+	}
+	return *hash, nil
+}
+
+// changedMessages returns the messages of the first-parent commits from head back
+// to (excluding) since that change dir, newest first. A zero since walks the
+// whole history.
+func changedMessages(repo *git.Repository, head, since plumbing.Hash, dir string) ([]string, error) {
+	commits, err := changedCommits(repo, head, since, dir)
+	if err != nil {
+		return nil, err
+	}
+	var messages []string
+	for _, c := range commits {
+		messages = append(messages, strings.TrimSpace(c.Message))
+	}
+	return messages, nil
+}
+
+// changedCommits returns the commits of changedMessages.
+func changedCommits(repo *git.Repository, head, since plumbing.Hash, dir string) ([]*object.Commit, error) {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	c, err := repo.CommitObject(head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	var commits []*object.Commit
+	for c.Hash != since {
+		changed, err := changesPath(c, dir)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			commits = append(commits, c)
+		}
+		if c.NumParents() == 0 {
+			break
+		}
+		if c, err = c.Parent(0); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+	return commits, nil
+}
+
+// changesPath reports whether c changes dir compared with its first parent.
+func changesPath(c *object.Commit, dir string) (bool, error) {
+	current, err := pathHash(c, dir)
+	if err != nil {
+		return false, err
+	}
+	if c.NumParents() == 0 {
+		return !current.IsZero(), nil
+	}
+	parent, err := c.Parent(0)
+	if err != nil {
+		return false, err
+	}
+	previous, err := pathHash(parent, dir)
+	if err != nil {
+		return false, err
+	}
+	return current != previous, nil
+}
+
+// pathHash returns the object hash of dir in c's tree, zero when it is absent.
+func pathHash(c *object.Commit, dir string) (plumbing.Hash, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if dir == "" {
+		return tree.Hash, nil
+	}
+	entry, err := tree.FindEntry(dir)
+	if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return entry.Hash, nil
+}
+
+func headShortHash() (string, error) {
+	repo, err := gitrepo.DiscoverRepo()
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	return head.Hash().String()[:7], nil
+}
@@ -3,7 +3,20 @@ package versioner
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 func TestIncrementPatch(t *testing.T) {
@@ -141,21 +154,6 @@ func TestParseAiVersionSuggestion(t *testing.T) {
 	}
 }
 
-func TestBuildVersionPrompt(t *testing.T) {
-	t.Parallel()
-	prompt := buildVersionPrompt("v1.0.0", "feat: add new feature")
-
-	if !containsStr(prompt, "v1.0.0") {
-		t.Error("expected current version in prompt")
-	}
-	if !containsStr(prompt, "feat: add new feature") {
-		t.Error("expected commit message in prompt")
-	}
-	if !containsStr(prompt, "MAJOR") || !containsStr(prompt, "MINOR") || !containsStr(prompt, "PATCH") {
-		t.Error("expected semver categories in prompt")
-	}
-}
-
 func TestNewSemverModel(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -214,6 +212,299 @@ func TestNewSemverModel(t *testing.T) {
 	}
 }
 
+func TestLatestVersion(t *testing.T) {
+	t.Parallel()
+	tags := []string{"v1.3.0", "v1.10.0-rc.1", "v1.9.0+build.7", "v1.10.0-rc.2", "v1.2.0"}
+	if got := latestVersion(tags, false); got != "v1.10.0-rc.2" {
+		t.Errorf("latestVersion() = %q, want v1.10.0-rc.2", got)
+	}
+	if got := latestVersion(tags, true); got != "v1.9.0+build.7" {
+		t.Errorf("latestVersion(stableOnly) = %q, want v1.9.0+build.7", got)
+	}
+	if got := latestVersion(nil, true); got != "" {
+		t.Errorf("latestVersion(nil) = %q, want empty", got)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	t.Parallel()
+	tags := []string{"v1.3.0", "v1.4.0-rc.1", "v1.4.0-rc.2", "v1.4.0-beta.5"}
+	tests := []struct {
+		name string
+		core string
+		opts ReleaseOptions
+		want string
+	}{
+		{"stable", "v1.3.1", ReleaseOptions{}, "v1.3.1"},
+		{"stable promotes a pre-release", "v1.4.0", ReleaseOptions{}, "v1.4.0"},
+		{"next rc", "v1.4.0", ReleaseOptions{Prerelease: "rc"}, "v1.4.0-rc.3"},
+		{"channel keeps its higher version", "v1.3.1", ReleaseOptions{Prerelease: "rc"}, "v1.4.0-rc.3"},
+		{"new channel starts at 1", "v1.3.1", ReleaseOptions{Prerelease: "alpha"}, "v1.3.1-alpha.1"},
+		{"higher bump starts a new line", "v2.0.0", ReleaseOptions{Prerelease: "rc"}, "v2.0.0-rc.1"},
+		{"build metadata", "v1.3.1", ReleaseOptions{BuildMetadata: "abc1234"}, "v1.3.1+abc1234"},
+		{"both", "v1.4.0", ReleaseOptions{Prerelease: "beta", BuildMetadata: "ci.42"}, "v1.4.0-beta.6+ci.42"},
+	}
+	for _, tt := range tests {
+		got, err := NextVersion(tags, tt.core, tt.opts)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: NextVersion(%q) = %q, %v; want %q", tt.name, tt.core, got, err, tt.want)
+		}
+	}
+
+	if _, err := NextVersion(tags, "v1.3.1", ReleaseOptions{Prerelease: "rc_1"}); err == nil {
+		t.Error("expected an error for an invalid pre-release identifier")
+	}
+	if _, err := NextVersion(tags, "latest", ReleaseOptions{}); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestTagName(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ prefix, version, want string }{
+		{"", "v1.2.3", "v1.2.3"},
+		{"v", "v1.2.3-rc.1", "v1.2.3-rc.1"},
+		{"pkg-ui/v", "v1.2.3", "pkg-ui/v1.2.3"},
+		{"release-", "v2.0.0", "release-2.0.0"},
+		{"ui2-v", "v1.2.0", "ui2-v1.2.0"},
+	}
+	for _, tt := range tests {
+		if got := tagName(tt.prefix, tt.version); got != tt.want {
+			t.Errorf("tagName(%q, %q) = %q, want %q", tt.prefix, tt.version, got, tt.want)
+		}
+		if got := tagVersion(tt.prefix, tt.want); got != tt.version {
+			t.Errorf("tagVersion(%q, %q) = %q, want %q", tt.prefix, tt.want, got, tt.version)
+		}
+	}
+}
+
+// newTestRepo returns an in-memory repository and a helper that writes file
+// and commits it with message.
+func newTestRepo(t *testing.T) (*git.Repository, func(file, message string) plumbing.Hash) {
+	t.Helper()
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, func(file, message string) plumbing.Hash {
+		t.Helper()
+		if err := util.WriteFile(wt.Filesystem, file, []byte(message), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Unix(1700000000, 0)}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+}
+
+func TestChangedMessages(t *testing.T) {
+	t.Parallel()
+	repo, commit := newTestRepo(t)
+	commit("ui/app.go", "feat(ui): add app")
+	tagged := commit("api/server.go", "feat(api): add server")
+	commit("ui/app.go", "fix(ui): handle resize")
+	head := commit("README.md", "docs: update readme")
+
+	tests := []struct {
+		dir   string
+		since plumbing.Hash
+		want  []string
+	}{
+		{"ui", plumbing.ZeroHash, []string{"fix(ui): handle resize", "feat(ui): add app"}},
+		{"ui/", tagged, []string{"fix(ui): handle resize"}},
+		{"api", tagged, nil},
+		{"docs", plumbing.ZeroHash, nil},
+		{"", tagged, []string{"docs: update readme", "fix(ui): handle resize"}},
+	}
+	for _, tt := range tests {
+		got, err := changedMessages(repo, head, tt.since, tt.dir)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("changedMessages(%q) = %q, %v; want %q", tt.dir, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	t.Parallel()
+	repo, commit := newTestRepo(t)
+	first := commit("a.txt", "feat: first")
+	if err := createTag(repo, "v1.0.0", false, ""); err != nil {
+		t.Fatal(err)
+	}
+	second := commit("a.txt", "fix: second")
+
+	err := createTag(repo, "v1.0.0", false, "")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("createTag() on an existing tag: err = %v", err)
+	}
+	if ref, _ := repo.Tag("v1.0.0"); ref.Hash() != first {
+		t.Errorf("tag moved without force")
+	}
+	if err := createTag(repo, "v1.0.0", true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if ref, _ := repo.Tag("v1.0.0"); ref.Hash() != second {
+		t.Errorf("forced tag = %s, want %s", ref.Hash(), second)
+	}
+}
+
+func TestReleaseNotes(t *testing.T) {
+	t.Parallel()
+	repo, commit := newTestRepo(t)
+	commit("a.txt", "feat: first")
+	if err := createTag(repo, "v1.0.0", false, ""); err != nil {
+		t.Fatal(err)
+	}
+	commit("a.txt", "fix: second")
+	commit("b.txt", "feat: third")
+
+	client := &mockAIClient{response: "## Features\n- Third"}
+	notes, err := releaseNotes(context.Background(), client, repo, "v1.0.0", "v1.1.0", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notes != "## Features\n- Third" {
+		t.Errorf("notes = %q", notes)
+	}
+	if !strings.Contains(client.prompt, "fix: second") || !strings.Contains(client.prompt, "feat: third") || strings.Contains(client.prompt, "feat: first") {
+		t.Errorf("prompt does not cover exactly the commits since v1.0.0:\n%s", client.prompt)
+	}
+
+	if err := createTag(repo, "v1.1.0", false, notes); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := repo.Tag("v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := repo.TagObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("v1.1.0 is not an annotated tag: %v", err)
+	}
+	if strings.TrimSpace(tag.Message) != notes {
+		t.Errorf("tag message = %q, want the notes", tag.Message)
+	}
+}
+
+func TestPerformSemanticReleasePromotesPrerelease(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		extra []string // commits after v1.1.0-rc.1
+	}{
+		{name: "no new commits"},
+		{name: "a fix after the candidate", extra: []string{"fix: handle empty query"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo, err := git.PlainInit(dir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wt, err := repo.Worktree()
+			if err != nil {
+				t.Fatal(err)
+			}
+			commit := func(message string) {
+				t.Helper()
+				if err := os.WriteFile(filepath.Join(dir, "log.txt"), []byte(message), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := wt.Add("log.txt"); err != nil {
+					t.Fatal(err)
+				}
+				sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Unix(1700000000, 0)}
+				if _, err := wt.Commit(message, &git.CommitOptions{Author: sig}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			commit("feat: first")
+			if err := createTag(repo, "v1.0.0", false, ""); err != nil {
+				t.Fatal(err)
+			}
+			commit("feat: add search")
+			if err := createTag(repo, "v1.1.0-rc.1", false, ""); err != nil {
+				t.Fatal(err)
+			}
+			for _, message := range tc.extra {
+				commit(message)
+			}
+			t.Chdir(dir)
+
+			opts := ReleaseOptions{Force: true, Quiet: true, Strategy: StrategyConventional}
+			if err := PerformSemanticRelease(context.Background(), &mockAIClient{}, false, opts); err != nil {
+				t.Fatal(err)
+			}
+			head, _ := repo.Head()
+			ref, err := repo.Tag("v1.1.0")
+			if err != nil {
+				t.Fatalf("v1.1.0 was not tagged: %v", err)
+			}
+			if ref.Hash() != head.Hash() {
+				t.Errorf("v1.1.0 = %s, want HEAD %s", ref.Hash(), head.Hash())
+			}
+		})
+	}
+}
+
+func TestCheckReleasable(t *testing.T) {
+	t.Parallel()
+	repo, commit := newTestRepo(t)
+	base := commit("a.txt", "feat: base")
+	if err := checkReleasable(repo); err != nil {
+		t.Fatalf("clean repository: %v", err)
+	}
+
+	// Untracked files do not block a release; modified tracked files do.
+	wt, _ := repo.Worktree()
+	if err := util.WriteFile(wt.Filesystem, "notes.txt", []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkReleasable(repo); err != nil {
+		t.Errorf("untracked file: %v", err)
+	}
+	if err := util.WriteFile(wt.Filesystem, "a.txt", []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkReleasable(repo); err == nil || !strings.Contains(err.Error(), "a.txt") {
+		t.Errorf("modified file: err = %v", err)
+	}
+	head := commit("a.txt", "fix: change a")
+
+	// Track origin/master: being ahead is fine, being behind is not.
+	cfg, _ := repo.Config()
+	cfg.Branches["master"] = &config.Branch{Name: "master", Remote: "origin", Merge: plumbing.Master}
+	if err := repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	remote := plumbing.NewRemoteReferenceName("origin", "master")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(remote, base)); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkReleasable(repo); err != nil {
+		t.Errorf("ahead of upstream: %v", err)
+	}
+	behind := commit("b.txt", "feat: only on the remote")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(remote, behind)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: head, Mode: git.HardReset}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkReleasable(repo); err == nil || !strings.Contains(err.Error(), "origin/master") {
+		t.Errorf("behind upstream: err = %v", err)
+	}
+}
+
 func TestSuggestNextVersion(t *testing.T) {
 	t.Parallel()
 
@@ -263,9 +554,11 @@ func TestSuggestNextVersion_AIError(t *testing.T) {
 type mockAIClient struct {
 	response string
 	err      error
+	prompt   string
 }
 
-func (m *mockAIClient) GetCommitMessage(_ context.Context, _ string) (string, error) {
+func (m *mockAIClient) GetCommitMessage(_ context.Context, prompt string) (string, error) {
+	m.prompt = prompt
 	return m.response, m.err
 }
 